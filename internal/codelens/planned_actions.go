@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package codelens
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+)
+
+// PlannedActionsFunc returns the actions planned for each resource
+// or module call (keyed by its address) within the module at the given path
+type PlannedActionsFunc func(modPath string) (map[string][]string, error)
+
+// actionOrder reflects the order in which actions appear in a lens title
+var actionOrder = []string{"create", "read", "update", "replace", "delete", "move", "import", "remove"}
+
+// PlannedActions returns code lenses showing the actions planned
+// by the last `terraform plan` above each resource and module block.
+func PlannedActions(plannedActions PlannedActionsFunc) lang.CodeLensFunc {
+	return func(ctx context.Context, path lang.Path, file string) ([]lang.CodeLens, error) {
+		lenses := make([]lang.CodeLens, 0)
+
+		if path.LanguageID != ilsp.Terraform.String() {
+			return lenses, nil
+		}
+
+		actions, err := plannedActions(path.Path)
+		if err != nil || len(actions) == 0 {
+			return lenses, nil
+		}
+
+		localCtx, err := decoder.PathCtx(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// A block can be targetable in more than one way
+		// but we only want to show a single lens for it
+		seen := make(map[hcl.Range]struct{}, 0)
+		for _, refTarget := range localCtx.ReferenceTargets.OutermostInFile(file) {
+			if refTarget.RangePtr == nil {
+				continue
+			}
+			rng := *refTarget.RangePtr
+			if _, ok := seen[rng]; ok {
+				continue
+			}
+
+			addrActions, ok := actions[refTarget.Addr.String()]
+			if !ok {
+				continue
+			}
+			seen[rng] = struct{}{}
+
+			lenses = append(lenses, lang.CodeLens{
+				Range: rng,
+				Command: lang.Command{
+					Title: plannedActionsTitle(addrActions),
+				},
+			})
+		}
+
+		sort.SliceStable(lenses, func(i, j int) bool {
+			return lenses[i].Range.Start.Byte < lenses[j].Range.Start.Byte
+		})
+
+		return lenses, nil
+	}
+}
+
+func plannedActionsTitle(actions []string) string {
+	counts := make(map[string]int, 0)
+	for _, action := range actions {
+		counts[action]++
+	}
+
+	parts := make([]string, 0)
+	for _, action := range actionOrder {
+		if n, ok := counts[action]; ok {
+			parts = append(parts, fmt.Sprintf("%d to %s", n, action))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "Plan: no changes"
+	}
+	return "Plan: " + strings.Join(parts, ", ")
+}
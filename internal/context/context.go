@@ -5,6 +5,7 @@ package context
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-ls/internal/langserver/diagnostics"
@@ -194,24 +195,38 @@ func (ctxData Document) IsDidChangeRequest() bool {
 	return ctxData.Method == "textDocument/didChange"
 }
 
+func (ctxData Document) IsDidOpenRequest() bool {
+	return ctxData.Method == "textDocument/didOpen"
+}
+
 func WithValidationOptions(ctx context.Context, validationOptions *settings.ValidationOptions) context.Context {
 	return context.WithValue(ctx, ctxValidationOptions, validationOptions)
 }
 
+// validationOptionsMu guards validation options, which can be
+// set again (e.g. when config files change) while jobs read them
+var validationOptionsMu sync.RWMutex
+
 func SetValidationOptions(ctx context.Context, validationOptions settings.ValidationOptions) error {
 	e, ok := ctx.Value(ctxValidationOptions).(*settings.ValidationOptions)
 	if !ok {
 		return missingContextErr(ctxValidationOptions)
 	}
 
+	validationOptionsMu.Lock()
+	defer validationOptionsMu.Unlock()
 	*e = validationOptions
 	return nil
 }
 
+// ValidationOptions returns a copy of the validation options
 func ValidationOptions(ctx context.Context) (settings.ValidationOptions, error) {
 	validationOptions, ok := ctx.Value(ctxValidationOptions).(*settings.ValidationOptions)
 	if !ok {
 		return settings.ValidationOptions{}, missingContextErr(ctxValidationOptions)
 	}
+
+	validationOptionsMu.RLock()
+	defer validationOptionsMu.RUnlock()
 	return *validationOptions, nil
 }
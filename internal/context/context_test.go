@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package context
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-ls/internal/settings"
+)
+
+func TestSetValidationOptions_concurrentReads(t *testing.T) {
+	ctx := WithValidationOptions(context.Background(), &settings.ValidationOptions{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			err := SetValidationOptions(ctx, settings.ValidationOptions{
+				EnableEnhancedValidation: true,
+				Rules:                    map[string]string{"deprecated-attribute": "off"},
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := ValidationOptions(ctx)
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	opts, err := ValidationOptions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.EnableEnhancedValidation {
+		t.Fatal("expected validation options to be set")
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package eventbus
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-ls/internal/document"
+	"github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+// VersionFileChangeEvent is an event that should be fired whenever a file
+// pinning the Terraform version (such as .terraform-version) changes.
+//
+// Dir is the directory containing the file. Any directory within it
+// may be affected by the change.
+type VersionFileChangeEvent struct {
+	Context context.Context
+
+	Dir        document.DirHandle
+	ChangeType protocol.FileChangeType
+}
+
+func (n *EventBus) OnVersionFileChange(identifier string, doneChannel DoneChannel) <-chan VersionFileChangeEvent {
+	n.logger.Printf("bus: %q subscribed to OnVersionFileChange", identifier)
+	return n.versionFileChangeTopic.Subscribe(doneChannel)
+}
+
+func (n *EventBus) VersionFileChange(e VersionFileChangeEvent) {
+	n.logger.Printf("bus: -> VersionFileChange %s", e.Dir)
+	n.versionFileChangeTopic.Publish(e)
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/opentofu"
+	"github.com/hashicorp/terraform-ls/internal/terraform/parser"
 )
 
 type ModFilename string
@@ -26,7 +28,8 @@ func (mf ModFilename) IsIgnored() bool {
 
 func IsModuleFilename(name string) bool {
 	return strings.HasSuffix(name, ".tf") ||
-		strings.HasSuffix(name, ".tf.json")
+		strings.HasSuffix(name, ".tf.json") ||
+		opentofu.IsModuleFilename(name)
 }
 
 type ModFiles map[ModFilename]*hcl.File
@@ -55,6 +58,18 @@ func (mf ModFiles) Copy() ModFiles {
 	return m
 }
 
+// ModFileHashes maps module files to hashes of their content,
+// as it was when the files were last parsed
+type ModFileHashes map[ModFilename]parser.ContentHash
+
+func (mh ModFileHashes) Copy() ModFileHashes {
+	m := make(ModFileHashes, len(mh))
+	for name, hash := range mh {
+		m[name] = hash
+	}
+	return m
+}
+
 type ModDiags map[ModFilename]hcl.Diagnostics
 
 func ModDiagsFromMap(m map[string]hcl.Diagnostics) ModDiags {
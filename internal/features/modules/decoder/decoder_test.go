@@ -37,6 +37,14 @@ func (r RootReaderMock) TerraformVersion(modPath string) *version.Version {
 	return nil
 }
 
+func (r RootReaderMock) PinnedTerraformVersion(modPath string) *version.Version {
+	return nil
+}
+
+func (r RootReaderMock) IsOpenTofu(modPath string) bool {
+	return false
+}
+
 func (r RootReaderMock) InstalledModulePath(rootPath string, normalizedSource string) (string, bool) {
 	return "", false
 }
@@ -83,7 +91,7 @@ func TestDecoder_CodeLensesForFile_concurrencyBug(t *testing.T) {
 			t.Error(err)
 		}
 		ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-		err = jobs.ParseModuleConfiguration(ctx, mapFs, ss, dirName)
+		err = jobs.ParseModuleConfiguration(ctx, mapFs, ss, dirName, false)
 		if err != nil {
 			t.Error(err)
 		}
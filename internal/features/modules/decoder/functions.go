@@ -12,15 +12,17 @@ import (
 )
 
 func functionsForModule(mod *state.ModuleRecord, stateReader CombinedReader) (map[string]schema.FunctionSignature, error) {
-	resolvedVersion := tfschema.ResolveVersion(stateReader.TerraformVersion(mod.Path()), mod.Meta.CoreRequirements)
+	resolvedVersion := ResolveTerraformVersion(mod, stateReader)
 	sm := tfschema.NewFunctionsMerger(mustFunctionsForVersion(resolvedVersion))
 	sm.SetTerraformVersion(resolvedVersion)
 	sm.SetStateReader(stateReader)
 
+	pReqs, pRefs := providerAddrsForModule(mod, stateReader.IsOpenTofu(mod.Path()))
+
 	meta := &tfmodule.Meta{
 		Path:                 mod.Path(),
-		ProviderRequirements: mod.Meta.ProviderRequirements,
-		ProviderReferences:   mod.Meta.ProviderReferences,
+		ProviderRequirements: pReqs,
+		ProviderReferences:   pRefs,
 	}
 
 	return sm.FunctionsForModule(meta)
@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	"github.com/hashicorp/terraform-ls/internal/graph"
+)
+
+// DependencyGraph builds the dependency graph of a module from its
+// decoded reference targets and origins and declared module calls,
+// similar to terraform graph, but without evaluating any expression.
+//
+// Resources, data sources, module calls, variables, locals and outputs
+// are represented as nodes. References between them (including those
+// in depends_on) are represented as edges. References of a node to itself
+// (e.g. in a variable validation or a postcondition) are left out,
+// since these don't make the node depend on anything.
+//
+// It relies on reference targets and origins being decoded already,
+// i.e. references within bodies without known schema are not represented.
+func DependencyGraph(mod *state.ModuleRecord) *graph.Graph {
+	g := graph.NewGraph()
+
+	for _, target := range mod.RefTargets {
+		if target.Addr == nil || target.RangePtr == nil {
+			continue
+		}
+		address, kind, ok := nodeAddress(target.Addr)
+		if !ok || address != target.Addr.String() {
+			// nested targets (e.g. attributes) are not nodes
+			continue
+		}
+		g.AddNode(graph.Node{
+			Address: address,
+			Kind:    kind,
+			Range:   *target.RangePtr,
+		})
+	}
+
+	// Module calls are also targets, but we can't rely
+	// on these when the called module isn't installed
+	for name, mc := range mod.Meta.ModuleCalls {
+		address := "module." + name
+		if mc.RangePtr == nil || g.HasNode(address) {
+			continue
+		}
+		g.AddNode(graph.Node{
+			Address: address,
+			Kind:    "module",
+			Range:   *mc.RangePtr,
+		})
+	}
+
+	nodes := g.Nodes()
+	for _, origin := range mod.RefOrigins {
+		localOrigin, ok := origin.(reference.LocalOrigin)
+		if !ok {
+			continue
+		}
+		to, _, ok := nodeAddress(localOrigin.Addr)
+		if !ok {
+			continue
+		}
+		from, ok := enclosingNode(nodes, localOrigin.Range)
+		if !ok || from == to {
+			continue
+		}
+		g.AddEdge(graph.Edge{
+			From:  from,
+			To:    to,
+			Range: localOrigin.Range,
+		})
+	}
+
+	return g
+}
+
+// nodeAddress returns the address and kind of the node referenced
+// by the given address, e.g. aws_instance.web for
+// aws_instance.web[0].id
+func nodeAddress(addr lang.Address) (string, string, bool) {
+	if len(addr) == 0 {
+		return "", "", false
+	}
+
+	length, kind := 2, "resource"
+	switch addr[0].String() {
+	case "count", "each", "self", "path", "terraform":
+		// these don't refer to any other node
+		return "", "", false
+	case "var":
+		kind = "variable"
+	case "local":
+		kind = "local"
+	case "module":
+		kind = "module"
+	case "output":
+		kind = "output"
+	case "data":
+		length, kind = 3, "data"
+	}
+
+	if len(addr) < length {
+		return "", "", false
+	}
+	for _, step := range addr[1:length] {
+		if _, ok := step.(lang.AttrStep); !ok {
+			return "", "", false
+		}
+	}
+	return addr[:length].String(), kind, true
+}
+
+// enclosingNode returns the address of the innermost node
+// whose range contains the given range
+func enclosingNode(nodes []graph.Node, rng hcl.Range) (string, bool) {
+	var enclosing *graph.Node
+	for i, node := range nodes {
+		if node.Range.Filename != rng.Filename ||
+			node.Range.Start.Byte > rng.Start.Byte ||
+			node.Range.End.Byte < rng.End.Byte {
+			continue
+		}
+		if enclosing == nil || node.Range.End.Byte-node.Range.Start.Byte < enclosing.Range.End.Byte-enclosing.Range.Start.Byte {
+			enclosing = &nodes[i]
+		}
+	}
+	if enclosing == nil {
+		return "", false
+	}
+	return enclosing.Address, true
+}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	mod := &state.ModuleRecord{
+		RefTargets: reference.Targets{
+			testTarget(t, "var.name", 0, 100),
+			testTarget(t, "local.prefix", 110, 140),
+			testTarget(t, "local.cyclic", 141, 170),
+			testTarget(t, "local.other", 171, 200),
+			testTarget(t, "aws_vpc.main", 210, 300),
+			testTarget(t, "aws_subnet.private", 310, 400),
+			testTarget(t, "data.aws_ami.ubuntu", 410, 430),
+			testTarget(t, "output.instance_ids", 600, 650),
+		},
+		RefOrigins: reference.Origins{
+			// variable validation
+			testOrigin(t, "var.name", 50, 58),
+			testOrigin(t, "var.name", 120, 128),
+			testOrigin(t, "local.other", 150, 161),
+			testOrigin(t, "local.cyclic", 180, 192),
+			// postcondition
+			testOrigin(t, "aws_vpc.main.id", 250, 265),
+			testOrigin(t, "aws_vpc.main.id", 320, 335),
+			testOrigin(t, "local.prefix", 350, 362),
+			testOrigin(t, "count.index", 365, 376),
+			testOrigin(t, "aws_subnet.private", 460, 478),
+			testOrigin(t, "data.aws_ami.ubuntu.id", 480, 502),
+			testOrigin(t, "aws_vpc.main", 510, 522),
+			testOrigin(t, "module.compute.instance_ids", 610, 637),
+		},
+		Meta: state.ModuleMetadata{
+			ModuleCalls: map[string]tfmod.DeclaredModuleCall{
+				"compute": {
+					LocalName: "compute",
+					RangePtr:  testRange(450, 550).Ptr(),
+				},
+			},
+		},
+	}
+
+	g := DependencyGraph(mod)
+
+	nodes := make([]string, 0)
+	for _, node := range g.Nodes() {
+		nodes = append(nodes, node.Kind+":"+node.Address)
+	}
+	expectedNodes := []string{
+		"resource:aws_subnet.private",
+		"resource:aws_vpc.main",
+		"data:data.aws_ami.ubuntu",
+		"local:local.cyclic",
+		"local:local.other",
+		"local:local.prefix",
+		"module:module.compute",
+		"output:output.instance_ids",
+		"variable:var.name",
+	}
+	if diff := cmp.Diff(expectedNodes, nodes); diff != "" {
+		t.Fatalf("unexpected nodes: %s", diff)
+	}
+
+	edges := make([]string, 0)
+	for _, edge := range g.Edges() {
+		edges = append(edges, edge.From+" -> "+edge.To)
+	}
+	expectedEdges := []string{
+		"aws_subnet.private -> aws_vpc.main",
+		"aws_subnet.private -> local.prefix",
+		"local.cyclic -> local.other",
+		"local.other -> local.cyclic",
+		"local.prefix -> var.name",
+		"module.compute -> aws_subnet.private",
+		"module.compute -> aws_vpc.main",
+		"module.compute -> data.aws_ami.ubuntu",
+		"output.instance_ids -> module.compute",
+	}
+	if diff := cmp.Diff(expectedEdges, edges); diff != "" {
+		t.Fatalf("unexpected edges: %s", diff)
+	}
+
+	expectedCycles := [][]string{
+		{"local.cyclic", "local.other"},
+	}
+	if diff := cmp.Diff(expectedCycles, g.Cycles()); diff != "" {
+		t.Fatalf("unexpected cycles: %s", diff)
+	}
+}
+
+func TestDependencyGraph_validatedVariable(t *testing.T) {
+	mod := &state.ModuleRecord{
+		RefTargets: reference.Targets{
+			testTarget(t, "var.name", 0, 100),
+		},
+		RefOrigins: reference.Origins{
+			testOrigin(t, "var.name", 50, 58),
+		},
+	}
+
+	g := DependencyGraph(mod)
+
+	if len(g.Edges()) != 0 {
+		t.Fatalf("expected no edges, given: %#v", g.Edges())
+	}
+	if len(g.Cycles()) != 0 {
+		t.Fatalf("expected no cycles, given: %#v", g.Cycles())
+	}
+}
+
+func testTarget(t *testing.T, address string, startByte, endByte int) reference.Target {
+	return reference.Target{
+		Addr:     testAddress(t, address),
+		RangePtr: testRange(startByte, endByte).Ptr(),
+	}
+}
+
+func testOrigin(t *testing.T, address string, startByte, endByte int) reference.LocalOrigin {
+	return reference.LocalOrigin{
+		Addr:  testAddress(t, address),
+		Range: testRange(startByte, endByte),
+	}
+}
+
+func testAddress(t *testing.T, address string) lang.Address {
+	traversal, diags := hclsyntax.ParseTraversalAbs([]byte(address), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	addr, err := lang.TraversalToAddress(traversal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+func testRange(startByte, endByte int) hcl.Range {
+	return hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 1, Column: startByte + 1, Byte: startByte},
+		End:      hcl.Pos{Line: 1, Column: endByte + 1, Byte: endByte},
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+)
+
+// InstalledModulePath returns the path (relative to the given module)
+// of the installed copy of the module with the given normalized source.
+//
+// The copy installed by the module itself (if it is an initialized root
+// module) takes precedence. Otherwise any parsed copy installed by another
+// root module in the workspace is used, so that modules from git or private
+// registry sources can be served to uninitialized roots too.
+func (r CombinedReader) InstalledModulePath(modPath string, normalizedSource string) (string, bool) {
+	if dir, ok := r.RootReader.InstalledModulePath(modPath, normalizedSource); ok {
+		return dir, true
+	}
+	// The manifest is keyed by raw source addresses,
+	// which may differ from the normalized ones
+	installed, err := r.RootReader.InstalledModuleCalls(modPath)
+	if err == nil {
+		for _, mc := range installed {
+			if mc.SourceAddr != nil && mc.SourceAddr.String() == normalizedSource {
+				if relDir, err := filepath.Rel(modPath, mc.Path); err == nil {
+					return relDir, true
+				}
+			}
+		}
+	}
+
+	var cons version.Constraints
+	declared, err := r.StateReader.DeclaredModuleCalls(modPath)
+	if err == nil {
+		for _, mc := range declared {
+			if mc.SourceAddr != nil && mc.SourceAddr.String() == normalizedSource {
+				cons = mc.Version
+				break
+			}
+		}
+	}
+
+	dir, ok := SharedInstalledModuleDir(r.StateReader, r.RootReader, normalizedSource, cons)
+	if !ok {
+		return "", false
+	}
+	relDir, err := filepath.Rel(modPath, dir)
+	if err != nil {
+		return "", false
+	}
+	return relDir, true
+}
+
+type installedCopy struct {
+	dir     string
+	version *version.Version
+}
+
+// SharedInstalledModuleDir looks up installed copies of the module
+// with the given normalized source (and version matching the given
+// constraints, if any) across all root modules in the workspace.
+//
+// Only copies which have been parsed already are considered,
+// so that each copy is only parsed once and shared between roots.
+// It returns the absolute path of the copy with the highest version.
+func SharedInstalledModuleDir(sr StateReader, rr RootReader, normalizedSource string, cons version.Constraints) (string, bool) {
+	mods, err := sr.List()
+	if err != nil {
+		return "", false
+	}
+
+	copies := make([]installedCopy, 0)
+	seen := make(map[string]bool)
+	for _, mod := range mods {
+		installed, err := rr.InstalledModuleCalls(mod.Path())
+		if err != nil {
+			// not an (initialized) root module
+			continue
+		}
+		for _, mc := range installed {
+			if mc.SourceAddr == nil || mc.SourceAddr.String() != normalizedSource || seen[mc.Path] {
+				continue
+			}
+			seen[mc.Path] = true
+			if len(cons) > 0 && mc.Version != nil && !cons.Check(mc.Version) {
+				continue
+			}
+			if _, err := sr.LocalModuleMeta(mc.Path); err != nil {
+				continue
+			}
+			copies = append(copies, installedCopy{
+				dir:     mc.Path,
+				version: mc.Version,
+			})
+		}
+	}
+	if len(copies) == 0 {
+		return "", false
+	}
+
+	sort.SliceStable(copies, func(i, j int) bool {
+		vi, vj := copies[i].version, copies[j].version
+		if vi != nil && vj != nil && !vi.Equal(vj) {
+			return vi.GreaterThan(vj)
+		}
+		if (vi == nil) != (vj == nil) {
+			return vi != nil
+		}
+		return copies[i].dir < copies[j].dir
+	})
+
+	return copies[0].dir, true
+}
@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	fdecoder "github.com/hashicorp/terraform-ls/internal/features/modules/decoder"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	globalState "github.com/hashicorp/terraform-ls/internal/state"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+)
+
+type installedRootReaderMock struct {
+	RootReaderMock
+	installed map[string]map[string]tfmod.InstalledModuleCall
+}
+
+func (r installedRootReaderMock) InstalledModuleCalls(modPath string) (map[string]tfmod.InstalledModuleCall, error) {
+	calls, ok := r.installed[modPath]
+	if !ok {
+		return nil, &globalState.RecordNotFoundError{Source: modPath}
+	}
+	return calls, nil
+}
+
+func TestCombinedReader_InstalledModulePath(t *testing.T) {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := state.NewModuleStore(globalStore.ProviderSchemas, globalStore.RegistryModules, globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	rootA := filepath.Join(tmpDir, "a")
+	rootB := filepath.Join(tmpDir, "b")
+	uninitialized := filepath.Join(tmpDir, "c")
+
+	gitSource := tfmod.ParseModuleSourceAddr("git::https://example.com/vpc.git?ref=v1.0.0")
+	consulSource := tfmod.ParseModuleSourceAddr("hashicorp/consul/aws")
+	unparsedSource := tfmod.ParseModuleSourceAddr("github.com/acme/dns")
+
+	installedCall := func(root, key string, source tfmod.ModuleSourceAddr, v string) tfmod.InstalledModuleCall {
+		mc := tfmod.InstalledModuleCall{
+			LocalName:  key,
+			SourceAddr: source,
+			Path:       filepath.Join(root, ".terraform", "modules", key),
+		}
+		if v != "" {
+			mc.Version = version.Must(version.NewVersion(v))
+		}
+		return mc
+	}
+	rr := installedRootReaderMock{
+		installed: map[string]map[string]tfmod.InstalledModuleCall{
+			rootA: {
+				"vpc":    installedCall(rootA, "vpc", gitSource, ""),
+				"consul": installedCall(rootA, "consul", consulSource, "0.1.0"),
+				"dns":    installedCall(rootA, "dns", unparsedSource, ""),
+			},
+			rootB: {
+				"consul": installedCall(rootB, "consul", consulSource, "0.2.0"),
+			},
+		},
+	}
+
+	moduleCalls := map[string]tfmod.DeclaredModuleCall{
+		"vpc": {
+			LocalName:     "vpc",
+			RawSourceAddr: gitSource.String(),
+			SourceAddr:    gitSource,
+		},
+		"consul": {
+			LocalName:     "consul",
+			RawSourceAddr: consulSource.String(),
+			SourceAddr:    consulSource,
+			Version:       version.MustConstraints(version.NewConstraint("~> 0.1.0")),
+		},
+	}
+	metas := map[string]*tfmod.Meta{
+		rootA:                              {ModuleCalls: moduleCalls},
+		rootB:                              {},
+		uninitialized:                      {ModuleCalls: moduleCalls},
+		rr.installed[rootA]["vpc"].Path:    {},
+		rr.installed[rootA]["consul"].Path: {},
+		rr.installed[rootB]["consul"].Path: {},
+	}
+	for path, meta := range metas {
+		err := ss.Add(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		meta.Path = path
+		err = ss.UpdateMetadata(path, 0, meta, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// installed, but not parsed yet
+	err = ss.Add(rr.installed[rootA]["dns"].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := fdecoder.CombinedReader{
+		StateReader: ss,
+		RootReader:  rr,
+	}
+
+	testCases := []struct {
+		name        string
+		modPath     string
+		source      tfmod.ModuleSourceAddr
+		expectedDir string
+		expectedOk  bool
+	}{
+		{
+			"own installed copy",
+			rootA,
+			gitSource,
+			filepath.Join(".terraform", "modules", "vpc"),
+			true,
+		},
+		{
+			"copy shared from other root",
+			uninitialized,
+			gitSource,
+			filepath.Join("..", "a", ".terraform", "modules", "vpc"),
+			true,
+		},
+		{
+			"copy matching version constraint",
+			uninitialized,
+			consulSource,
+			filepath.Join("..", "a", ".terraform", "modules", "consul"),
+			true,
+		},
+		{
+			"own copy by normalized source",
+			rootB,
+			consulSource,
+			filepath.Join(".terraform", "modules", "consul"),
+			true,
+		},
+		{
+			"unparsed copy",
+			uninitialized,
+			unparsedSource,
+			"",
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, ok := reader.InstalledModulePath(tc.modPath, tc.source.String())
+			if ok != tc.expectedOk {
+				t.Fatalf("expected ok: %t, given: %t", tc.expectedOk, ok)
+			}
+			if dir != tc.expectedDir {
+				t.Fatalf("expected dir: %q, given: %q", tc.expectedDir, dir)
+			}
+		})
+	}
+}
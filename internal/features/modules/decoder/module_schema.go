@@ -5,29 +5,106 @@ package decoder
 
 import (
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/backends"
+	"github.com/hashicorp/terraform-ls/internal/terraform/opentofu"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfversion"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
 	tfmodule "github.com/hashicorp/terraform-schema/module"
 	tfschema "github.com/hashicorp/terraform-schema/schema"
 )
 
 func schemaForModule(mod *state.ModuleRecord, stateReader CombinedReader) (*schema.BodySchema, error) {
-	resolvedVersion := tfschema.ResolveVersion(stateReader.TerraformVersion(mod.Path()), mod.Meta.CoreRequirements)
+	resolvedVersion := ResolveTerraformVersion(mod, stateReader)
 	sm := tfschema.NewSchemaMerger(mustCoreSchemaForVersion(resolvedVersion))
 	sm.SetTerraformVersion(resolvedVersion)
 	sm.SetStateReader(stateReader)
 
+	isOpenTofu := stateReader.IsOpenTofu(mod.Path())
+	pReqs, pRefs := providerAddrsForModule(mod, isOpenTofu)
+
 	meta := &tfmodule.Meta{
 		Path:                 mod.Path(),
 		CoreRequirements:     mod.Meta.CoreRequirements,
-		ProviderRequirements: mod.Meta.ProviderRequirements,
-		ProviderReferences:   mod.Meta.ProviderReferences,
+		ProviderRequirements: pReqs,
+		ProviderReferences:   pRefs,
 		Variables:            mod.Meta.Variables,
 		Filenames:            mod.Meta.Filenames,
 		ModuleCalls:          mod.Meta.ModuleCalls,
 	}
 
-	return sm.SchemaForModule(meta)
+	bodySchema, err := sm.SchemaForModule(meta)
+	if err != nil {
+		return nil, err
+	}
+	bodySchema = backends.PatchModuleSchema(bodySchema, resolvedVersion)
+	bodySchema = withRemoteModuleSources(bodySchema)
+	if isOpenTofu {
+		return opentofu.PatchModuleSchema(bodySchema), nil
+	}
+	return bodySchema, nil
+}
+
+// withRemoteModuleSources returns a copy of the given module schema
+// with completion of remote (go-getter) source addresses enabled
+// for the source attribute of module blocks
+func withRemoteModuleSources(bs *schema.BodySchema) *schema.BodySchema {
+	modBlock, ok := bs.Blocks["module"]
+	if !ok || modBlock.Body == nil {
+		return bs
+	}
+	sourceAttr, ok := modBlock.Body.Attributes["source"]
+	if !ok {
+		return bs
+	}
+
+	attr := *sourceAttr
+	attr.CompletionHooks = make(lang.CompletionHooks, 0, len(sourceAttr.CompletionHooks)+1)
+	attr.CompletionHooks = append(attr.CompletionHooks, sourceAttr.CompletionHooks...)
+	attr.CompletionHooks = append(attr.CompletionHooks, lang.CompletionHook{Name: "CompleteRemoteModuleSources"})
+
+	body := *modBlock.Body
+	body.Attributes = make(map[string]*schema.AttributeSchema, len(modBlock.Body.Attributes))
+	for name, a := range modBlock.Body.Attributes {
+		body.Attributes[name] = a
+	}
+	body.Attributes["source"] = &attr
+
+	block := *modBlock
+	block.Body = &body
+
+	patched := *bs
+	patched.Blocks = make(map[string]*schema.BlockSchema, len(bs.Blocks))
+	for name, b := range bs.Blocks {
+		patched.Blocks[name] = b
+	}
+	patched.Blocks["module"] = &block
+
+	return &patched
+}
+
+// providerAddrsForModule returns provider requirements and references
+// of the module, with any implied provider hostnames resolved
+// to the OpenTofu registry if the module targets OpenTofu.
+func providerAddrsForModule(mod *state.ModuleRecord, isOpenTofu bool) (tfmodule.ProviderRequirements, map[tfmodule.ProviderRef]tfaddr.Provider) {
+	if !isOpenTofu {
+		return mod.Meta.ProviderRequirements, mod.Meta.ProviderReferences
+	}
+
+	explicitlyHosted := opentofu.ExplicitlyHostedProviders(mod.ParsedModuleFiles.AsMap())
+
+	pReqs := make(tfmodule.ProviderRequirements, len(mod.Meta.ProviderRequirements))
+	for pAddr, cons := range mod.Meta.ProviderRequirements {
+		pReqs[opentofu.ProviderAddr(pAddr, explicitlyHosted)] = cons
+	}
+	pRefs := make(map[tfmodule.ProviderRef]tfaddr.Provider, len(mod.Meta.ProviderReferences))
+	for ref, pAddr := range mod.Meta.ProviderReferences {
+		pRefs[ref] = opentofu.ProviderAddr(pAddr, explicitlyHosted)
+	}
+
+	return pReqs, pRefs
 }
 
 func mustCoreSchemaForVersion(v *version.Version) *schema.BodySchema {
@@ -38,3 +115,11 @@ func mustCoreSchemaForVersion(v *version.Version) *schema.BodySchema {
 	}
 	return s
 }
+
+// ResolveTerraformVersion resolves the version of Terraform
+// which the module targets, see [tfversion.Resolve]
+func ResolveTerraformVersion(mod *state.ModuleRecord, stateReader CombinedReader) *version.Version {
+	modPath := mod.Path()
+	return tfversion.Resolve(stateReader.PinnedTerraformVersion(modPath),
+		mod.Meta.CoreRequirements, stateReader.TerraformVersion(modPath))
+}
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// OutputReference represents an output whose value
+// is a plain reference, such as aws_vpc.this.id
+type OutputReference struct {
+	// Addr is the address which the value refers to
+	Addr lang.Address
+	// Sensitive is true if the output is declared with sensitive = true,
+	// in which case its value must not be shown, regardless of whether
+	// the referenced value itself is sensitive
+	Sensitive bool
+}
+
+// OutputValueReference returns the reference which the value
+// of the named output consists of, if it is a plain reference
+func OutputValueReference(files ast.ModFiles, outputName string) (OutputReference, bool) {
+	for name, f := range files {
+		if name.IsIgnored() {
+			continue
+		}
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "output" || len(block.Labels) != 1 || block.Labels[0] != outputName {
+				continue
+			}
+			attr, ok := block.Body.Attributes["value"]
+			if !ok {
+				return OutputReference{}, false
+			}
+			traversal, diags := hcl.AbsTraversalForExpr(attr.Expr)
+			if diags.HasErrors() {
+				return OutputReference{}, false
+			}
+			addr, err := lang.TraversalToAddress(traversal)
+			if err != nil {
+				return OutputReference{}, false
+			}
+			return OutputReference{
+				Addr:      addr,
+				Sensitive: isSensitive(block.Body),
+			}, true
+		}
+	}
+
+	return OutputReference{}, false
+}
+
+// isSensitive returns true unless the sensitive attribute
+// of the block is absent or statically known to be false.
+// Any value which cannot be evaluated is treated as sensitive.
+func isSensitive(body *hclsyntax.Body) bool {
+	attr, ok := body.Attributes["sensitive"]
+	if !ok {
+		return false
+	}
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !val.IsKnown() || val.IsNull() || val.Type() != cty.Bool {
+		return true
+	}
+	return val.True()
+}
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+)
+
+func TestOutputValueReference(t *testing.T) {
+	cfg := `output "vpc_id" {
+  value = aws_vpc.this.id
+}
+
+output "first_subnet" {
+  value = aws_subnet.private[0]
+}
+
+output "password" {
+  value     = aws_db_instance.this.password
+  sensitive = true
+}
+
+output "not_sensitive" {
+  value     = aws_db_instance.this.address
+  sensitive = false
+}
+
+output "computed" {
+  value = "${aws_vpc.this.id}-suffix"
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "outputs.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	files := ast.ModFiles{
+		"outputs.tf": f,
+	}
+
+	testCases := []struct {
+		outputName        string
+		expectedFound     bool
+		expectedAddr      string
+		expectedSensitive bool
+	}{
+		{"vpc_id", true, "aws_vpc.this.id", false},
+		{"first_subnet", true, "aws_subnet.private[0]", false},
+		{"password", true, "aws_db_instance.this.password", true},
+		{"not_sensitive", true, "aws_db_instance.this.address", false},
+		{"computed", false, "", false},
+		{"unknown", false, "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.outputName, func(t *testing.T) {
+			ref, found := OutputValueReference(files, tc.outputName)
+			if found != tc.expectedFound {
+				t.Fatalf("expected found: %t, given: %t", tc.expectedFound, found)
+			}
+			if !found {
+				return
+			}
+			if ref.Addr.String() != tc.expectedAddr {
+				t.Fatalf("expected address %q, given: %q", tc.expectedAddr, ref.Addr.String())
+			}
+			if ref.Sensitive != tc.expectedSensitive {
+				t.Fatalf("expected sensitive: %t, given: %t", tc.expectedSensitive, ref.Sensitive)
+			}
+		})
+	}
+}
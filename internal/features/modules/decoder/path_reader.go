@@ -30,6 +30,8 @@ type StateReader interface {
 type RootReader interface {
 	InstalledModuleCalls(modPath string) (map[string]tfmod.InstalledModuleCall, error)
 	TerraformVersion(modPath string) *version.Version
+	PinnedTerraformVersion(modPath string) *version.Version
+	IsOpenTofu(modPath string) bool
 	InstalledModulePath(rootPath string, normalizedSource string) (string, bool)
 }
 
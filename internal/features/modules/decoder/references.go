@@ -11,7 +11,7 @@ import (
 
 func referencesForModule(mod *state.ModuleRecord, stateReader CombinedReader) reference.Targets {
 	modPath := mod.Path()
-	resolvedVersion := tfschema.ResolveVersion(stateReader.TerraformVersion(modPath), mod.Meta.CoreRequirements)
+	resolvedVersion := ResolveTerraformVersion(mod, stateReader)
 
 	return tfschema.BuiltinReferencesForVersion(resolvedVersion, modPath)
 }
@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
+)
+
+// ResourceAddresses returns sorted addresses of all managed resources
+// declared in the given files, such as aws_instance.web
+func ResourceAddresses(files ast.ModFiles) []string {
+	addrs := make([]string, 0)
+	for name, f := range files {
+		if name.IsIgnored() {
+			continue
+		}
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			if block.Type != "resource" {
+				continue
+			}
+			if addr, ok := globalAst.BlockAddress(block); ok {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	sort.Strings(addrs)
+
+	return addrs
+}
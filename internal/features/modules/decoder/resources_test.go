@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+)
+
+func TestResourceAddresses(t *testing.T) {
+	cfg := `resource "aws_vpc" "this" {}
+
+resource "aws_subnet" "private" {
+  count = 2
+}
+
+data "aws_ami" "ubuntu" {}
+
+module "nested" {
+  source = "./nested"
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	addrs := ResourceAddresses(ast.ModFiles{"main.tf": f})
+
+	expectedAddrs := []string{"aws_subnet.private", "aws_vpc.this"}
+	if diff := cmp.Diff(expectedAddrs, addrs); diff != "" {
+		t.Fatalf("unexpected addresses: %s", diff)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/hashicorp/terraform-ls/internal/rules"
+	"github.com/hashicorp/terraform-ls/internal/terraform/backends"
+)
+
+// Backend validates backend and cloud blocks within terraform blocks,
+// i.e. that they are not both declared, that child modules do not
+// declare a backend, and that mutually exclusive arguments of backends
+// available in the given Terraform version are not set together.
+//
+// Required, deprecated and unknown arguments are validated
+// via the backend schemas, see [backends.PatchModuleSchema].
+func Backend(files ast.ModFiles, tfVersion *version.Version, isChildModule bool) lang.DiagnosticsMap {
+	diagsMap := make(lang.DiagnosticsMap)
+
+	backendBlocks := make([]*hclsyntax.Block, 0)
+	hasCloud := false
+
+	filenames := make([]string, 0, len(files))
+	for name := range files {
+		if name.IsIgnored() {
+			continue
+		}
+		filenames = append(filenames, name.String())
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		body, ok := files[ast.ModFilename(filename)].Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "terraform" {
+				continue
+			}
+			for _, nested := range block.Body.Blocks {
+				switch nested.Type {
+				case "backend":
+					if len(nested.Labels) == 1 {
+						backendBlocks = append(backendBlocks, nested)
+					}
+				case "cloud":
+					hasCloud = true
+				}
+			}
+		}
+	}
+
+	schemas := backends.ForVersion(tfVersion)
+	for _, block := range backendBlocks {
+		rng := block.DefRange()
+
+		if hasCloud {
+			diagsMap[rng.Filename] = append(diagsMap[rng.Filename], &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Both a backend and a cloud block are declared",
+				Detail: "State is stored either via a `backend` block or via a `cloud` block " +
+					"(HCP Terraform), but a module cannot declare both.",
+				Subject: rng.Ptr(),
+				Extra:   rules.BackendConfig,
+			})
+		}
+		if isChildModule {
+			diagsMap[rng.Filename] = append(diagsMap[rng.Filename], &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Backend configuration is ignored in child modules",
+				Detail: "This module is called by another module. Terraform only uses " +
+					"the backend configuration of the root module.",
+				Subject: rng.Ptr(),
+				Extra:   rules.BackendConfig,
+			})
+		}
+
+		backend, ok := schemas[block.Labels[0]]
+		if !ok {
+			continue
+		}
+		for _, group := range backend.ExclusiveArguments {
+			args := make([]string, 0, len(group))
+			ranges := make([]hcl.Range, 0, len(group))
+			for _, arg := range group {
+				if argRng, ok := argumentRange(block.Body, arg); ok {
+					args = append(args, arg)
+					ranges = append(ranges, argRng)
+				}
+			}
+			if len(args) < 2 {
+				continue
+			}
+			for _, argRng := range ranges[1:] {
+				diagsMap[argRng.Filename] = append(diagsMap[argRng.Filename], &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Conflicting backend arguments",
+					Detail: fmt.Sprintf("Only one of `%s` can be set for the %q backend.",
+						strings.Join(group, "`, `"), block.Labels[0]),
+					Subject: argRng.Ptr(),
+					Extra:   rules.BackendConfig,
+				})
+			}
+		}
+	}
+
+	return diagsMap
+}
+
+// argumentRange returns the range of the attribute or block
+// at the given (dot-separated) path within the body, if it is set
+func argumentRange(body *hclsyntax.Body, path string) (hcl.Range, bool) {
+	name, rest, nested := strings.Cut(path, ".")
+	if !nested {
+		if attr, ok := body.Attributes[name]; ok {
+			return attr.NameRange, true
+		}
+	}
+	for _, block := range body.Blocks {
+		if block.Type != name {
+			continue
+		}
+		if !nested {
+			return block.TypeRange, true
+		}
+		return argumentRange(block.Body, rest)
+	}
+	return hcl.Range{}, false
+}
@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+)
+
+func TestBackend(t *testing.T) {
+	testCases := []struct {
+		name              string
+		cfg               string
+		tfVersion         string
+		isChildModule     bool
+		expectedSummaries []string
+	}{
+		{
+			"valid",
+			`terraform {
+  backend "s3" {
+    bucket     = "state"
+    key        = "prod.tfstate"
+    kms_key_id = "arn:aws:kms:us-east-1:123:key/abc"
+  }
+}
+`,
+			"1.6.0",
+			false,
+			[]string{},
+		},
+		{
+			"backend and cloud",
+			`terraform {
+  backend "local" {}
+  cloud {
+    organization = "acme"
+  }
+}
+`,
+			"1.6.0",
+			false,
+			[]string{"Both a backend and a cloud block are declared"},
+		},
+		{
+			"child module",
+			`terraform {
+  backend "local" {}
+}
+`,
+			"1.6.0",
+			true,
+			[]string{"Backend configuration is ignored in child modules"},
+		},
+		{
+			"exclusive arguments",
+			`terraform {
+  backend "s3" {
+    bucket           = "state"
+    key              = "prod.tfstate"
+    kms_key_id       = "arn:aws:kms:us-east-1:123:key/abc"
+    sse_customer_key = "secret"
+    role_arn         = "arn:aws:iam::123:role/state"
+    assume_role {
+      role_arn = "arn:aws:iam::123:role/state"
+    }
+  }
+}
+`,
+			"1.6.0",
+			false,
+			[]string{
+				"Conflicting backend arguments",
+				"Conflicting backend arguments",
+			},
+		},
+		{
+			"exclusive arguments of nested blocks",
+			`terraform {
+  backend "remote" {
+    organization = "acme"
+    workspaces {
+      name   = "prod"
+      prefix = "app-"
+    }
+  }
+}
+`,
+			"1.6.0",
+			false,
+			[]string{"Conflicting backend arguments"},
+		},
+		{
+			"exclusive arguments of later versions",
+			`terraform {
+  backend "s3" {
+    bucket              = "state"
+    key                 = "prod.tfstate"
+    region              = "us-east-1"
+    allowed_account_ids = ["123"]
+    forbidden_account_ids = ["456"]
+  }
+}
+`,
+			"1.5.0",
+			false,
+			[]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.cfg), "main.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			diagsMap := Backend(ast.ModFiles{"main.tf": f}, version.Must(version.NewVersion(tc.tfVersion)), tc.isChildModule)
+
+			summaries := make([]string, 0)
+			for _, diag := range diagsMap["main.tf"] {
+				summaries = append(summaries, diag.Summary)
+			}
+			if diff := cmp.Diff(tc.expectedSummaries, summaries); diff != "" {
+				t.Fatalf("unexpected diagnostics: %s", diff)
+			}
+		})
+	}
+}
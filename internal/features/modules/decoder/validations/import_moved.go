@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/hashicorp/terraform-ls/internal/rules"
+	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
+)
+
+type moveStatement struct {
+	from, to string
+	rng      hcl.Range
+}
+
+// ImportAndMovedBlocks validates addresses within import and moved blocks,
+// i.e. that import targets are declared and that moved blocks do not
+// declare ambiguous or cyclic moves.
+func ImportAndMovedBlocks(files ast.ModFiles) lang.DiagnosticsMap {
+	diagsMap := make(lang.DiagnosticsMap)
+
+	declared := make(map[string]bool, 0)
+	importBlocks := make([]*hclsyntax.Block, 0)
+	movedBlocks := make([]*hclsyntax.Block, 0)
+
+	filenames := make([]string, 0, len(files))
+	for name := range files {
+		if name.IsIgnored() {
+			continue
+		}
+		filenames = append(filenames, name.String())
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		body, ok := files[ast.ModFilename(filename)].Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			if addr, ok := globalAst.BlockAddress(block); ok {
+				declared[addr] = true
+				continue
+			}
+			switch block.Type {
+			case "import":
+				importBlocks = append(importBlocks, block)
+			case "moved":
+				movedBlocks = append(movedBlocks, block)
+			}
+		}
+	}
+
+	for _, block := range importBlocks {
+		attr, ok := block.Body.Attributes["to"]
+		if !ok {
+			continue
+		}
+		addr, ok := configAddress(attr.Expr)
+		if !ok {
+			continue
+		}
+
+		// Missing targets are reported as warnings, whether the target
+		// is a resource or a resource within a module call
+		var summary, detail string
+		switch {
+		case len(addr) >= 2 && addr[0] == "module":
+			if declared["module."+addr[1]] {
+				continue
+			}
+			summary = fmt.Sprintf("No module call found for import target %q", strings.Join(addr, "."))
+			detail = fmt.Sprintf("Declare the module %q in the configuration.", addr[1])
+		case len(addr) == 2:
+			if declared[strings.Join(addr, ".")] {
+				continue
+			}
+			summary = fmt.Sprintf("No resource declared for import target %q", strings.Join(addr, "."))
+			detail = "Declare the resource in the configuration, or generate the configuration " +
+				"with `terraform plan -generate-config-out=<file>`."
+		default:
+			continue
+		}
+		diagsMap[attr.Expr.Range().Filename] = append(diagsMap[attr.Expr.Range().Filename], &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  summary,
+			Detail:   detail,
+			Subject:  attr.Expr.Range().Ptr(),
+			Extra:    rules.ImportMovedBlocks,
+		})
+	}
+
+	statements := make([]moveStatement, 0, len(movedBlocks))
+	for _, block := range movedBlocks {
+		from, ok := globalAst.AttributeAddress(block.Body.Attributes["from"])
+		if !ok {
+			continue
+		}
+		to, ok := globalAst.AttributeAddress(block.Body.Attributes["to"])
+		if !ok {
+			continue
+		}
+		statements = append(statements, moveStatement{
+			from: from,
+			to:   to,
+			rng:  block.Body.Attributes["from"].Expr.Range(),
+		})
+	}
+
+	// Each object can only be moved to a single destination
+	movesFrom := make(map[string]int, len(statements))
+	for i, stmt := range statements {
+		first, ok := movesFrom[stmt.from]
+		if !ok {
+			movesFrom[stmt.from] = i
+			continue
+		}
+		if statements[first].to == stmt.to {
+			continue
+		}
+		diagsMap[stmt.rng.Filename] = append(diagsMap[stmt.rng.Filename], &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Ambiguous move statements",
+			Detail: fmt.Sprintf("A statement at %s:%d declared that %s moved to %s, "+
+				"but this statement instead declares that it moved to %s. "+
+				"Each item can move to only one destination object.",
+				statements[first].rng.Filename, statements[first].rng.Start.Line, stmt.from, statements[first].to, stmt.to),
+			Subject: stmt.rng.Ptr(),
+			Extra:   rules.ImportMovedBlocks,
+		})
+	}
+
+	// Chained moves must end in a final location
+	inCycle := make(map[int]bool, 0)
+	for i, stmt := range statements {
+		if inCycle[i] || stmt.from == stmt.to {
+			continue
+		}
+
+		chain := []int{i}
+		next, ok := movesFrom[stmt.to]
+		for ok && len(chain) <= len(statements) {
+			if next == i {
+				break
+			}
+			chain = append(chain, next)
+			next, ok = movesFrom[statements[next].to]
+		}
+		if !ok || next != i {
+			continue
+		}
+
+		addrs := make([]string, 0, len(chain)+1)
+		for _, idx := range chain {
+			inCycle[idx] = true
+			addrs = append(addrs, statements[idx].from)
+		}
+		addrs = append(addrs, stmt.from)
+
+		for _, idx := range chain {
+			rng := statements[idx].rng
+			diagsMap[rng.Filename] = append(diagsMap[rng.Filename], &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Cyclic dependency in move statements",
+				Detail: fmt.Sprintf("Chained move statements form a cycle (%s), "+
+					"so there is no final location to move objects to.",
+					strings.Join(addrs, " -> ")),
+				Subject: rng.Ptr(),
+				Extra:   rules.ImportMovedBlocks,
+			})
+		}
+	}
+
+	return diagsMap
+}
+
+// configAddress returns names of the address steps of the given
+// expression, without any instance keys, e.g. [aws_instance web]
+// for aws_instance.web[each.key]
+func configAddress(expr hcl.Expression) ([]string, bool) {
+	for {
+		indexExpr, ok := expr.(*hclsyntax.IndexExpr)
+		if !ok {
+			break
+		}
+		expr = indexExpr.Collection
+	}
+
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() {
+		return nil, false
+	}
+
+	names := make([]string, 0, len(traversal))
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, s.Name)
+		case hcl.TraverseAttr:
+			names = append(names, s.Name)
+		}
+	}
+	return names, true
+}
@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+)
+
+func TestImportAndMovedBlocks(t *testing.T) {
+	testCases := []struct {
+		name              string
+		cfg               string
+		expectedSummaries []string
+		expectedSeverity  hcl.DiagnosticSeverity
+	}{
+		{
+			"valid",
+			`resource "aws_instance" "web" {}
+module "vpc" {
+  source = "./vpc"
+}
+import {
+  to = aws_instance.web
+  id = "i-123"
+}
+import {
+  to = module.vpc.aws_vpc.this
+  id = "vpc-123"
+}
+moved {
+  from = aws_instance.old
+  to   = aws_instance.web
+}
+`,
+			[]string{},
+			hcl.DiagInvalid,
+		},
+		{
+			"undeclared import targets",
+			`import {
+  to = aws_instance.web[each.key]
+  id = "i-123"
+}
+import {
+  to = module.vpc.aws_vpc.this
+  id = "vpc-123"
+}
+`,
+			[]string{
+				`No resource declared for import target "aws_instance.web"`,
+				`No module call found for import target "module.vpc.aws_vpc.this"`,
+			},
+			hcl.DiagWarning,
+		},
+		{
+			"ambiguous moves",
+			`moved {
+  from = aws_instance.a
+  to   = aws_instance.b
+}
+moved {
+  from = aws_instance.a
+  to   = aws_instance.c
+}
+`,
+			[]string{"Ambiguous move statements"},
+			hcl.DiagError,
+		},
+		{
+			"cyclic moves",
+			`moved {
+  from = aws_instance.a
+  to   = aws_instance.b
+}
+moved {
+  from = aws_instance.b
+  to   = aws_instance.c
+}
+moved {
+  from = aws_instance.c
+  to   = aws_instance.a
+}
+moved {
+  from = aws_instance.d
+  to   = aws_instance.a
+}
+`,
+			[]string{
+				"Cyclic dependency in move statements",
+				"Cyclic dependency in move statements",
+				"Cyclic dependency in move statements",
+			},
+			hcl.DiagError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.cfg), "main.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			diagsMap := ImportAndMovedBlocks(ast.ModFiles{"main.tf": f})
+
+			summaries := make([]string, 0)
+			for _, diag := range diagsMap["main.tf"] {
+				summaries = append(summaries, diag.Summary)
+			}
+			if tc.expectedSeverity != hcl.DiagInvalid {
+				for _, diag := range diagsMap["main.tf"] {
+					if diag.Severity != tc.expectedSeverity {
+						t.Fatalf("expected severity %d for %q, given: %d", tc.expectedSeverity, diag.Summary, diag.Severity)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.expectedSummaries, summaries); diff != "" {
+				t.Fatalf("unexpected diagnostics: %s", diff)
+			}
+		})
+	}
+}
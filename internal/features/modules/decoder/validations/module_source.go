@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/hashicorp/terraform-ls/internal/rules"
+	"github.com/hashicorp/terraform-ls/internal/terraform/getter"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const moduleSourcesDocsUrl = "https://developer.hashicorp.com/terraform/language/modules/sources"
+
+// ModuleSources validates remote (i.e. neither local nor registry)
+// source addresses of module calls, i.e. that they are in one of
+// the forms supported by go-getter.
+//
+// If the module calls were installed (i.e. installed is not empty),
+// it also reports module calls which are missing from the manifest,
+// or whose installed copy comes from a different source or ref.
+func ModuleSources(files ast.ModFiles, installed map[string]tfmod.InstalledModuleCall) lang.DiagnosticsMap {
+	diagsMap := make(lang.DiagnosticsMap)
+
+	filenames := make([]string, 0, len(files))
+	for name := range files {
+		if name.IsIgnored() {
+			continue
+		}
+		filenames = append(filenames, name.String())
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		body, ok := files[ast.ModFilename(filename)].Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "module" || len(block.Labels) != 1 {
+				continue
+			}
+			attr, ok := block.Body.Attributes["source"]
+			if !ok {
+				continue
+			}
+			val, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || !val.Type().Equals(cty.String) {
+				continue
+			}
+			rawSource := val.AsString()
+
+			switch tfmod.ParseModuleSourceAddr(rawSource).(type) {
+			case tfmod.LocalSourceAddr, tfaddr.Module:
+				continue
+			}
+
+			rng := attr.Expr.Range()
+			src, err := getter.Parse(rawSource)
+			if err != nil {
+				diagsMap[rng.Filename] = append(diagsMap[rng.Filename], &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid module source address",
+					Detail:   fmt.Sprintf("%s. See %s for supported forms.", err, moduleSourcesDocsUrl),
+					Subject:  rng.Ptr(),
+					Extra:    rules.ModuleSourceAddress,
+				})
+				continue
+			}
+
+			if len(installed) == 0 {
+				continue
+			}
+			if diag := installedSourceDiagnostic(block.Labels[0], rawSource, src, installed, rng); diag != nil {
+				diagsMap[rng.Filename] = append(diagsMap[rng.Filename], diag)
+			}
+		}
+	}
+
+	return diagsMap
+}
+
+// installedSourceDiagnostic compares the source of the module call
+// with the source recorded in the module manifest
+func installedSourceDiagnostic(name, rawSource string, src getter.Source, installed map[string]tfmod.InstalledModuleCall, rng hcl.Range) *hcl.Diagnostic {
+	mc, ok := installed[name]
+	if !ok || mc.SourceAddr == nil {
+		return &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Module not installed",
+			Detail: fmt.Sprintf("Module %q was added after modules were installed. "+
+				"Run `terraform init` to install it.", name),
+			Subject: rng.Ptr(),
+			Extra:   rules.ModuleInstallation,
+		}
+	}
+
+	if mc.SourceAddr.String() == tfmod.ParseModuleSourceAddr(rawSource).String() {
+		return nil
+	}
+
+	installedSrc, err := getter.Parse(mc.SourceAddr.String())
+	if err == nil && installedSrc.SamePackage(src) {
+		if installedSrc.Ref == src.Ref {
+			// only other arguments (e.g. depth) differ
+			return nil
+		}
+		installedRef := "the default branch"
+		if installedSrc.Ref != "" {
+			installedRef = fmt.Sprintf("ref %q", installedSrc.Ref)
+		}
+		configuredRef := "the default branch"
+		if src.Ref != "" {
+			configuredRef = fmt.Sprintf("ref %q", src.Ref)
+		}
+		return &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Installed module ref does not match",
+			Detail: fmt.Sprintf("Module %q is configured with %s, but %s is installed. "+
+				"Run `terraform init` to install the configured version.", name, configuredRef, installedRef),
+			Subject: rng.Ptr(),
+			Extra:   rules.ModuleInstallation,
+		}
+	}
+
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Installed module is out of date",
+		Detail: fmt.Sprintf("Module %q was installed from %q, which differs from the configured source. "+
+			"Run `terraform init` to update the installed copy.", name, mc.SourceAddr.ForDisplay()),
+		Subject: rng.Ptr(),
+		Extra:   rules.ModuleInstallation,
+	}
+}
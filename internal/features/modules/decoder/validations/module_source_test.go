@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+)
+
+func TestModuleSources(t *testing.T) {
+	installed := map[string]tfmod.InstalledModuleCall{
+		"vpc": {
+			LocalName:  "vpc",
+			SourceAddr: tfmod.ParseModuleSourceAddr("git::https://example.com/vpc.git?ref=v1.2.0"),
+		},
+		"dns": {
+			LocalName:  "dns",
+			SourceAddr: tfmod.ParseModuleSourceAddr("github.com/acme/dns"),
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		cfg               string
+		installed         map[string]tfmod.InstalledModuleCall
+		expectedSummaries []string
+	}{
+		{
+			"valid sources",
+			`module "vpc" {
+  source = "git::https://example.com/vpc.git//modules/subnet?ref=v1.2.0"
+}
+module "dns" {
+  source = "github.com/acme/dns"
+}
+module "bucket" {
+  source = "s3::https://s3-eu-west-1.amazonaws.com/acme/bucket.zip"
+}
+module "local" {
+  source = "./modules/local"
+}
+module "registry" {
+  source = "hashicorp/consul/aws"
+}
+`,
+			nil,
+			[]string{},
+		},
+		{
+			"malformed sources",
+			`module "vpc" {
+  source = "git::https://example.com/vpc.git?ref="
+}
+module "dns" {
+  source = "github.com/acme"
+}
+module "bucket" {
+  source = "foo::https://example.com/bucket.zip"
+}
+`,
+			nil,
+			[]string{
+				"Invalid module source address",
+				"Invalid module source address",
+				"Invalid module source address",
+			},
+		},
+		{
+			"installed",
+			`module "vpc" {
+  source = "git::https://example.com/vpc.git?ref=v1.2.0"
+}
+module "dns" {
+  source = "git::https://github.com/acme/dns.git"
+}
+`,
+			installed,
+			[]string{},
+		},
+		{
+			"stale manifest",
+			`module "vpc" {
+  source = "git::https://example.com/vpc.git?ref=v1.3.0"
+}
+module "dns" {
+  source = "git::https://example.com/dns.git"
+}
+module "bucket" {
+  source = "gcs::https://www.googleapis.com/storage/v1/acme/bucket.zip"
+}
+`,
+			installed,
+			[]string{
+				"Installed module ref does not match",
+				"Installed module is out of date",
+				"Module not installed",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.cfg), "main.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			diagsMap := ModuleSources(ast.ModFiles{"main.tf": f}, tc.installed)
+
+			summaries := make([]string, 0)
+			for _, diag := range diagsMap["main.tf"] {
+				summaries = append(summaries, diag.Summary)
+			}
+			if diff := cmp.Diff(tc.expectedSummaries, summaries); diff != "" {
+				t.Fatalf("unexpected diagnostics: %s", diff)
+			}
+		})
+	}
+}
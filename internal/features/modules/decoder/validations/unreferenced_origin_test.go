@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 )
 
 func TestUnreferencedOrigins(t *testing.T) {
@@ -46,6 +47,7 @@ func TestUnreferencedOrigins(t *testing.T) {
 							Start:    hcl.Pos{},
 							End:      hcl.Pos{},
 						},
+						Extra: rules.UnreferencedOrigin,
 					},
 				},
 			},
@@ -75,6 +77,7 @@ func TestUnreferencedOrigins(t *testing.T) {
 							Start:    hcl.Pos{},
 							End:      hcl.Pos{},
 						},
+						Extra: rules.UnreferencedOrigin,
 					},
 				},
 			},
@@ -155,6 +158,7 @@ func TestUnreferencedOrigins(t *testing.T) {
 							Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
 							End:      hcl.Pos{Line: 1, Column: 10, Byte: 10},
 						},
+						Extra: rules.UnreferencedOrigin,
 					},
 					&hcl.Diagnostic{
 						Severity: hcl.DiagError,
@@ -164,6 +168,7 @@ func TestUnreferencedOrigins(t *testing.T) {
 							Start:    hcl.Pos{Line: 2, Column: 1, Byte: 0},
 							End:      hcl.Pos{Line: 2, Column: 10, Byte: 10},
 						},
+						Extra: rules.UnreferencedOrigin,
 					},
 				},
 			},
@@ -6,15 +6,16 @@ package decoder
 import (
 	"github.com/hashicorp/hcl-lang/validator"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/decoder/validations"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 )
 
 var moduleValidators = []validator.Validator{
-	validator.BlockLabelsLength{},
-	validator.DeprecatedAttribute{},
-	validator.DeprecatedBlock{},
-	validator.MaxBlocks{},
-	validator.MinBlocks{},
-	validations.MissingRequiredAttribute{},
-	validator.UnexpectedAttribute{},
-	validator.UnexpectedBlock{},
+	rules.Validator(rules.BlockLabelsLength, validator.BlockLabelsLength{}),
+	rules.Validator(rules.DeprecatedAttribute, validator.DeprecatedAttribute{}),
+	rules.Validator(rules.DeprecatedBlock, validator.DeprecatedBlock{}),
+	rules.Validator(rules.MaxBlocks, validator.MaxBlocks{}),
+	rules.Validator(rules.MinBlocks, validator.MinBlocks{}),
+	rules.Validator(rules.MissingRequiredAttribute, validations.MissingRequiredAttribute{}),
+	rules.Validator(rules.UnexpectedAttribute, validator.UnexpectedAttribute{}),
+	rules.Validator(rules.UnexpectedBlock, validator.UnexpectedBlock{}),
 }
@@ -13,13 +13,15 @@ import (
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
 	"github.com/hashicorp/terraform-ls/internal/document"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	fdecoder "github.com/hashicorp/terraform-ls/internal/features/modules/decoder"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/jobs"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/lint"
 	"github.com/hashicorp/terraform-ls/internal/job"
 	"github.com/hashicorp/terraform-ls/internal/protocol"
-	"github.com/hashicorp/terraform-ls/internal/schemas"
 	globalState "github.com/hashicorp/terraform-ls/internal/state"
 	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfversion"
 	tfaddr "github.com/hashicorp/terraform-registry-address"
 	tfmod "github.com/hashicorp/terraform-schema/module"
 )
@@ -158,6 +160,158 @@ func (f *ModulesFeature) didChangeWatched(ctx context.Context, rawPath string, c
 	return ids, nil
 }
 
+func (f *ModulesFeature) versionFileChange(ctx context.Context, dir document.DirHandle) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	records, err := f.Store.List()
+	if err != nil {
+		return ids, err
+	}
+
+	for _, record := range records {
+		if !tfversion.IsAffectedBy(record.Path(), dir.Path()) {
+			continue
+		}
+		modHandle := document.DirHandleFromPath(record.Path())
+
+		// Only open modules need decoding against the new schema,
+		// others will pick up the version when they're opened
+		hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(modHandle)
+		if err != nil {
+			return ids, err
+		}
+		if !hasOpenDocs {
+			continue
+		}
+
+		modIds, err := f.decodeModule(ctx, modHandle, true, true)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, modIds...)
+	}
+
+	return ids, nil
+}
+
+func (f *ModulesFeature) manifestChange(ctx context.Context, dir document.DirHandle) (job.IDs, error) {
+	if !f.Store.Exists(dir.Path()) {
+		return job.IDs{}, nil
+	}
+
+	// The root modules feature handles events first,
+	// so parsing of the manifest is scheduled already
+	pendingIds, err := f.stateStore.JobStore.ListIncompleteJobsForDir(dir)
+	if err != nil {
+		return job.IDs{}, err
+	}
+
+	validationOptions, _ := lsctx.ValidationOptions(ctx)
+	if !validationOptions.EnableEnhancedValidation {
+		return job.IDs{}, nil
+	}
+
+	// Sources of module calls are validated against installed modules
+	return f.revalidateReferences(ctx, dir, pendingIds)
+}
+
+// revalidateReferences schedules reference validation of an open module,
+// e.g. when anything it is validated against changed outside of it.
+// It expects enhanced validation to be enabled.
+func (f *ModulesFeature) revalidateReferences(ctx context.Context, dir document.DirHandle, dependsOn job.IDs) (job.IDs, error) {
+	hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(dir)
+	if err != nil || !hasOpenDocs {
+		return job.IDs{}, err
+	}
+
+	id, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return jobs.ReferenceValidation(ctx, f.Store, f.rootFeature, dir.Path())
+		},
+		Type:        op.OpTypeReferenceValidation.String(),
+		DependsOn:   dependsOn,
+		IgnoreState: true,
+	})
+	if err != nil {
+		return job.IDs{}, err
+	}
+
+	return job.IDs{id}, nil
+}
+
+// checkCustomRules schedules checking the module against custom rules.
+// Custom rules may be served by plugins which take a while,
+// so we check them separately to not hold up schema validation.
+func (f *ModulesFeature) checkCustomRules(ctx context.Context, dir document.DirHandle, customRules lint.Rules, dependsOn job.IDs, ignoreState bool) (job.ID, error) {
+	return f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return jobs.CustomRulesValidation(ctx, f.Store, f.rootFeature, dir.Path(), customRules)
+		},
+		Type:        op.OpTypeCustomRulesValidation.String(),
+		Priority:    job.LowPriority,
+		DependsOn:   dependsOn,
+		IgnoreState: ignoreState,
+	})
+}
+
+// localModuleCallees returns paths of modules which the module
+// at modPath calls via local source addresses
+func (f *ModulesFeature) localModuleCallees(modPath string) map[string]bool {
+	callees := make(map[string]bool, 0)
+
+	declared, err := f.Store.DeclaredModuleCalls(modPath)
+	if err != nil {
+		return callees
+	}
+	for _, mc := range declared {
+		if source, ok := mc.SourceAddr.(tfmod.LocalSourceAddr); ok {
+			callees[filepath.Join(modPath, filepath.FromSlash(source.String()))] = true
+		}
+	}
+
+	return callees
+}
+
+// revalidateChangedCallees schedules reference validation of modules
+// which started or stopped being called, since they are validated
+// differently as child modules (e.g. backend configuration)
+func (f *ModulesFeature) revalidateChangedCallees(ctx context.Context, previous, current map[string]bool) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	changed := make([]string, 0)
+	for calleePath := range previous {
+		if !current[calleePath] {
+			changed = append(changed, calleePath)
+		}
+	}
+	for calleePath := range current {
+		if !previous[calleePath] {
+			changed = append(changed, calleePath)
+		}
+	}
+
+	for _, calleePath := range changed {
+		if !f.Store.Exists(calleePath) {
+			continue
+		}
+		calleeDir := document.DirHandleFromPath(calleePath)
+
+		pendingIds, err := f.stateStore.JobStore.ListIncompleteJobsForDir(calleeDir)
+		if err != nil {
+			return ids, err
+		}
+		calleeIds, err := f.revalidateReferences(ctx, calleeDir, pendingIds)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, calleeIds...)
+	}
+
+	return ids, nil
+}
+
 func (f *ModulesFeature) removeIndexedModule(rawPath string) {
 	modHandle := document.DirHandleFromPath(rawPath)
 
@@ -185,27 +339,8 @@ func (f *ModulesFeature) decodeDeclaredModuleCalls(ctx context.Context, dir docu
 	var errs *multierror.Error
 
 	for _, mc := range declared {
-		var mcPath string
-		switch source := mc.SourceAddr.(type) {
-		// For local module sources, we can construct the path directly from the configuration
-		case tfmod.LocalSourceAddr:
-			mcPath = filepath.Join(dir.Path(), filepath.FromSlash(source.String()))
-		// For registry modules, we need to find the local installation path (if installed)
-		case tfaddr.Module:
-			installedDir, ok := f.rootFeature.InstalledModulePath(dir.Path(), source.String())
-			if !ok {
-				continue
-			}
-			mcPath = filepath.Join(dir.Path(), filepath.FromSlash(installedDir))
-		// For other remote modules, we need to find the local installation path (if installed)
-		case tfmod.RemoteSourceAddr:
-			installedDir, ok := f.rootFeature.InstalledModulePath(dir.Path(), source.String())
-			if !ok {
-				continue
-			}
-			mcPath = filepath.Join(dir.Path(), filepath.FromSlash(installedDir))
-		default:
-			// Unknown source address, we can't resolve the path
+		mcPath, ok := f.moduleCallPath(dir.Path(), mc)
+		if !ok {
 			continue
 		}
 
@@ -236,6 +371,39 @@ func (f *ModulesFeature) decodeDeclaredModuleCalls(ctx context.Context, dir docu
 	return jobIds, errs.ErrorOrNil()
 }
 
+// moduleCallPath returns the path to the directory of the module
+// called via the given module call, if it can be resolved
+func (f *ModulesFeature) moduleCallPath(modPath string, mc tfmod.DeclaredModuleCall) (string, bool) {
+	// Installed copies may be shared with other root modules
+	reader := fdecoder.CombinedReader{
+		StateReader: f.Store,
+		RootReader:  f.rootFeature,
+	}
+
+	switch source := mc.SourceAddr.(type) {
+	// For local module sources, we can construct the path directly from the configuration
+	case tfmod.LocalSourceAddr:
+		return filepath.Join(modPath, filepath.FromSlash(source.String())), true
+	// For registry modules, we need to find the local installation path (if installed)
+	case tfaddr.Module:
+		installedDir, ok := reader.InstalledModulePath(modPath, source.String())
+		if !ok {
+			return "", false
+		}
+		return filepath.Join(modPath, filepath.FromSlash(installedDir)), true
+	// For other remote modules, we need to find the local installation path (if installed)
+	case tfmod.RemoteSourceAddr:
+		installedDir, ok := reader.InstalledModulePath(modPath, source.String())
+		if !ok {
+			return "", false
+		}
+		return filepath.Join(modPath, filepath.FromSlash(installedDir)), true
+	}
+
+	// Unknown source address, we can't resolve the path
+	return "", false
+}
+
 func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandle, ignoreState bool, isFirstLevel bool) (job.IDs, error) {
 	ids := make(job.IDs, 0)
 	path := dir.Path()
@@ -243,7 +411,7 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 	parseId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
 		Dir: dir,
 		Func: func(ctx context.Context) error {
-			return jobs.ParseModuleConfiguration(ctx, f.fs, f.Store, path)
+			return jobs.ParseModuleConfiguration(ctx, f.fs, f.Store, path, f.rootFeature.IsOpenTofu(path))
 		},
 		Type:        op.OpTypeParseModuleConfiguration.String(),
 		IgnoreState: ignoreState,
@@ -262,14 +430,24 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 	// by default. So we don't run the validation jobs.
 	validationOptions, _ := lsctx.ValidationOptions(ctx)
 
+	// Parsing resets the state of anything derived from the AST
+	// when the content changes, so jobs depending on it can avoid
+	// any work when the content of a changed document didn't change.
+	dependentIgnoreState := ignoreState && !lsctx.DocumentContext(ctx).IsDidChangeRequest()
+
+	// Modules called via local source addresses are validated as child
+	// modules, so we need to know which ones the metadata changes affect
+	var previousCallees map[string]bool
+
 	metaId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
 		Dir: dir,
 		Func: func(ctx context.Context) error {
+			previousCallees = f.localModuleCallees(path)
 			return jobs.LoadModuleMetadata(ctx, f.Store, path)
 		},
 		Type:        op.OpTypeLoadModuleMetadata.String(),
 		DependsOn:   job.IDs{parseId},
-		IgnoreState: ignoreState,
+		IgnoreState: dependentIgnoreState,
 		Defer: func(ctx context.Context, jobErr error) (job.IDs, error) {
 			deferIds := make(job.IDs, 0)
 			if jobErr != nil {
@@ -292,11 +470,11 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 			eSchemaId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
 				Dir: dir,
 				Func: func(ctx context.Context) error {
-					return jobs.PreloadEmbeddedSchema(ctx, f.logger, schemas.FS,
+					return jobs.PreloadEmbeddedSchema(ctx, f.logger, f.schemaFS,
 						f.Store, f.stateStore.ProviderSchemas, path)
 				},
 				Type:        op.OpTypePreloadEmbeddedSchema.String(),
-				IgnoreState: ignoreState,
+				IgnoreState: dependentIgnoreState,
 			})
 			if err != nil {
 				return deferIds, err
@@ -310,7 +488,7 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 				},
 				Type:        op.OpTypeDecodeReferenceTargets.String(),
 				DependsOn:   append(modCalls, eSchemaId),
-				IgnoreState: ignoreState,
+				IgnoreState: dependentIgnoreState,
 			})
 			if err != nil {
 				return deferIds, err
@@ -324,7 +502,7 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 				},
 				Type:        op.OpTypeDecodeReferenceOrigins.String(),
 				DependsOn:   append(modCalls, eSchemaId),
-				IgnoreState: ignoreState,
+				IgnoreState: dependentIgnoreState,
 			})
 			if err != nil {
 				return deferIds, err
@@ -340,12 +518,19 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 					},
 					Type:        op.OpTypeSchemaModuleValidation.String(),
 					DependsOn:   append(modCalls, eSchemaId),
-					IgnoreState: ignoreState,
+					IgnoreState: dependentIgnoreState,
 				})
 				if err != nil {
 					return deferIds, err
 				}
 
+				if customRules := f.customRules(); len(customRules) > 0 {
+					_, err = f.checkCustomRules(ctx, dir, customRules, append(modCalls, eSchemaId), dependentIgnoreState)
+					if err != nil {
+						return deferIds, err
+					}
+				}
+
 				_, err = f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
 					Dir: dir,
 					Func: func(ctx context.Context) error {
@@ -353,7 +538,7 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 					},
 					Type:        op.OpTypeReferenceValidation.String(),
 					DependsOn:   job.IDs{refOriginsId, refTargetsId},
-					IgnoreState: ignoreState,
+					IgnoreState: dependentIgnoreState,
 				})
 				if err != nil {
 					return deferIds, err
@@ -367,13 +552,21 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 				},
 				Type:        op.OpTypeDecodeWriteOnlyAttributes.String(),
 				DependsOn:   append(modCalls, eSchemaId),
-				IgnoreState: ignoreState,
+				IgnoreState: dependentIgnoreState,
 			})
 			if err != nil {
 				return deferIds, err
 			}
 			deferIds = append(deferIds, woAttributesId)
 
+			if validationOptions.EnableEnhancedValidation {
+				calleeIds, err := f.revalidateChangedCallees(ctx, previousCallees, f.localModuleCallees(path))
+				if err != nil {
+					return deferIds, err
+				}
+				deferIds = append(deferIds, calleeIds...)
+			}
+
 			return deferIds, nil
 		},
 	})
@@ -388,6 +581,17 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 		return ids, nil
 	}
 
+	// Linting is skipped if results for the content are known,
+	// so it only runs again for reopened modules if they changed.
+	// Other changes are linted when saved (see [ModulesFeature.TFLint]),
+	// since tflint reads files from disk.
+	if f.linter != nil && lsctx.DocumentContext(ctx).IsDidOpenRequest() {
+		_, err = f.lintModule(ctx, dir, job.IDs{parseId})
+		if err != nil {
+			return ids, err
+		}
+	}
+
 	// This job may make an HTTP request, and we schedule it in
 	// the low-priority queue, so we don't want to wait for it.
 	_, err = f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
@@ -406,3 +610,30 @@ func (f *ModulesFeature) decodeModule(ctx context.Context, dir document.DirHandl
 
 	return ids, nil
 }
+
+func (f *ModulesFeature) lintModule(ctx context.Context, dir document.DirHandle, dependsOn job.IDs) (job.IDs, error) {
+	// Any pending jobs (including a running tflint job) are waited for,
+	// so that changes made while tflint is running are linted afterwards
+	pendingIds, err := f.stateStore.JobStore.ListIncompleteJobsForDir(dir)
+	if err != nil {
+		return job.IDs{}, err
+	}
+
+	// tflint may take a while, so we schedule it in
+	// the low-priority queue to not hold up other jobs
+	lintId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dir,
+		Func: func(ctx context.Context) error {
+			return jobs.TFLint(ctx, f.Store, f.linter, dir.Path())
+		},
+		Priority:    job.LowPriority,
+		DependsOn:   append(dependsOn, pendingIds...),
+		Type:        op.OpTypeTFLint.String(),
+		IgnoreState: true,
+	})
+	if err != nil {
+		return job.IDs{}, err
+	}
+
+	return job.IDs{lintId}, nil
+}
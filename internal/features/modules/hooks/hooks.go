@@ -9,14 +9,14 @@ package hooks
 import (
 	"log"
 
-	"github.com/algolia/algoliasearch-client-go/v3/algolia/search"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	"github.com/hashicorp/terraform-ls/internal/modulesearch"
 	"github.com/hashicorp/terraform-ls/internal/registry"
 )
 
 type Hooks struct {
 	ModStore       *state.ModuleStore
 	RegistryClient registry.Client
-	AlgoliaClient  *search.Client
+	ModuleSearch   modulesearch.Backend
 	Logger         *log.Logger
 }
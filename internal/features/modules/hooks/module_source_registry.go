@@ -7,43 +7,11 @@ import (
 	"context"
 	"strings"
 
-	"github.com/algolia/algoliasearch-client-go/v3/algolia/opt"
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/zclconf/go-cty/cty"
 )
 
-type RegistryModule struct {
-	FullName    string `json:"full-name"`
-	Description string `json:"description"`
-}
-
-const algoliaModuleIndex = "tf-registry:prod:modules"
-
-func (h *Hooks) fetchModulesFromAlgolia(ctx context.Context, term string) ([]RegistryModule, error) {
-	modules := make([]RegistryModule, 0)
-
-	index := h.AlgoliaClient.InitIndex(algoliaModuleIndex)
-	params := []interface{}{
-		ctx, // transport.Request will magically extract the context from here
-		opt.AttributesToRetrieve("full-name", "description"),
-		opt.HitsPerPage(10),
-	}
-
-	res, err := index.Search(term, params...)
-	if err != nil {
-		return modules, err
-	}
-
-	err = res.UnmarshalHits(&modules)
-	if err != nil {
-		return modules, err
-
-	}
-
-	return modules, nil
-}
-
 func (h *Hooks) RegistryModuleSources(ctx context.Context, value cty.Value) ([]decoder.Candidate, error) {
 	candidates := make([]decoder.Candidate, 0)
 	prefix := value.AsString()
@@ -54,14 +22,13 @@ func (h *Hooks) RegistryModuleSources(ctx context.Context, value cty.Value) ([]d
 		return candidates, nil
 	}
 
-	if h.AlgoliaClient == nil {
+	if h.ModuleSearch == nil {
 		return candidates, nil
 	}
 
-	modules, err := h.fetchModulesFromAlgolia(ctx, prefix)
+	modules, err := h.ModuleSearch.Search(ctx, prefix)
 	if err != nil {
-		h.Logger.Printf("Error fetching modules from Algolia: %#v", err)
-		return candidates, err
+		h.Logger.Printf("Error searching modules: %#v", err)
 	}
 
 	for _, mod := range modules {
@@ -73,5 +40,5 @@ func (h *Hooks) RegistryModuleSources(ctx context.Context, value cty.Value) ([]d
 		candidates = append(candidates, c)
 	}
 
-	return candidates, nil
+	return candidates, err
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	"github.com/hashicorp/terraform-ls/internal/modulesearch"
 	globalState "github.com/hashicorp/terraform-ls/internal/state"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -110,9 +111,9 @@ func TestHooks_RegistryModuleSources(t *testing.T) {
 	}))
 
 	h := &Hooks{
-		ModStore:      store,
-		AlgoliaClient: searchClient,
-		Logger:        log.New(io.Discard, "", 0),
+		ModStore:     store,
+		ModuleSearch: modulesearch.NewAlgolia(searchClient),
+		Logger:       log.New(io.Discard, "", 0),
 	}
 
 	tests := []struct {
@@ -192,9 +193,9 @@ func TestHooks_RegistryModuleSourcesCtxCancel(t *testing.T) {
 	}))
 
 	h := &Hooks{
-		ModStore:      store,
-		AlgoliaClient: searchClient,
-		Logger:        log.New(io.Discard, "", 0),
+		ModStore:     store,
+		ModuleSearch: modulesearch.NewAlgolia(searchClient),
+		Logger:       log.New(io.Discard, "", 0),
 	}
 
 	_, err = h.RegistryModuleSources(ctx, cty.StringVal("aws"))
@@ -225,9 +226,9 @@ func TestHooks_RegistryModuleSourcesIgnore(t *testing.T) {
 	}))
 
 	h := &Hooks{
-		ModStore:      store,
-		AlgoliaClient: searchClient,
-		Logger:        log.New(io.Discard, "", 0),
+		ModStore:     store,
+		ModuleSearch: modulesearch.NewAlgolia(searchClient),
+		Logger:       log.New(io.Discard, "", 0),
 	}
 
 	tests := []struct {
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hooks
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/terraform-ls/internal/terraform/getter"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type remoteSourceForm struct {
+	prefix      string
+	detail      string
+	description string
+}
+
+var remoteSourceForms = []remoteSourceForm{
+	{"git::https://", "git", "Generic Git repository over HTTPS, e.g. `git::https://example.com/vpc.git?ref=v1.2.0`"},
+	{"git::ssh://", "git", "Generic Git repository over SSH, e.g. `git::ssh://git@example.com/vpc.git`"},
+	{"github.com/", "GitHub", "GitHub repository, e.g. `github.com/hashicorp/example//modules/vpc`"},
+	{"bitbucket.org/", "Bitbucket", "Bitbucket repository, e.g. `bitbucket.org/hashicorp/example`"},
+	{"s3::https://", "S3", "Archive in an S3 bucket, e.g. `s3::https://s3-eu-west-1.amazonaws.com/examplecorp/vpc.zip`"},
+	{"gcs::https://www.googleapis.com/storage/v1/", "GCS", "Archive in a GCS bucket, e.g. `gcs::https://www.googleapis.com/storage/v1/modules/vpc.zip`"},
+	{"https://", "http", "Archive or URL redirecting via the `X-Terraform-Get` header"},
+}
+
+// RemoteModuleSources provides completion of remote source addresses,
+// i.e. the forms supported by go-getter, remote sources
+// of other module calls in the workspace and the ref argument
+// of Git sources.
+func (h *Hooks) RemoteModuleSources(ctx context.Context, value cty.Value) ([]decoder.Candidate, error) {
+	candidates := make([]decoder.Candidate, 0)
+	prefix := value.AsString()
+
+	if strings.HasPrefix(prefix, ".") {
+		// We're likely dealing with a local module source here
+		return candidates, nil
+	}
+
+	if src, err := getter.Parse(prefix); err == nil && src.Getter == getter.GitGetter && !strings.Contains(prefix, "?") {
+		candidates = append(candidates, decoder.ExpressionCompletionCandidate(decoder.ExpressionCandidate{
+			Value:       cty.StringVal(prefix + "?ref="),
+			Detail:      "git ref",
+			Description: lang.Markdown("Branch, tag or commit of the repository to use"),
+		}))
+	}
+
+	for _, form := range remoteSourceForms {
+		candidates = append(candidates, decoder.ExpressionCompletionCandidate(decoder.ExpressionCandidate{
+			Value:       cty.StringVal(form.prefix),
+			Detail:      form.detail,
+			Description: lang.Markdown(form.description),
+		}))
+	}
+
+	sources, err := h.workspaceRemoteSources()
+	if err != nil {
+		return candidates, err
+	}
+	for _, source := range sources {
+		candidates = append(candidates, decoder.ExpressionCompletionCandidate(decoder.ExpressionCandidate{
+			Value:  cty.StringVal(source),
+			Detail: "remote",
+		}))
+	}
+
+	return candidates, nil
+}
+
+// workspaceRemoteSources returns valid remote source addresses
+// of module calls declared in any module within the workspace
+func (h *Hooks) workspaceRemoteSources() ([]string, error) {
+	modules, err := h.ModStore.List()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	sources := make([]string, 0)
+	for _, mod := range modules {
+		for _, mc := range mod.Meta.ModuleCalls {
+			if _, ok := mc.SourceAddr.(tfmod.RemoteSourceAddr); !ok {
+				continue
+			}
+			if _, err := getter.Parse(mc.RawSourceAddr); err != nil {
+				continue
+			}
+			if !seen[mc.RawSourceAddr] {
+				seen[mc.RawSourceAddr] = true
+				sources = append(sources, mc.RawSourceAddr)
+			}
+		}
+	}
+	sort.Strings(sources)
+
+	return sources, nil
+}
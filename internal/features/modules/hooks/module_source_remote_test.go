@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hooks
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	globalState "github.com/hashicorp/terraform-ls/internal/state"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestHooks_RemoteModuleSources(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+
+	s, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := state.NewModuleStore(s.ProviderSchemas, s.RegistryModules, s.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &Hooks{
+		ModStore: store,
+	}
+
+	modPath := filepath.Join(tmpDir, "alpha")
+	err = store.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moduleCalls := make(map[string]tfmod.DeclaredModuleCall)
+	for name, source := range map[string]string{
+		"vpc":      "git::https://example.com/vpc.git?ref=v1.2.0",
+		"dns":      "github.com/acme/dns",
+		"local":    "./modules/local",
+		"registry": "hashicorp/consul/aws",
+	} {
+		moduleCalls[name] = tfmod.DeclaredModuleCall{
+			LocalName:     name,
+			RawSourceAddr: source,
+			SourceAddr:    tfmod.ParseModuleSourceAddr(source),
+		}
+	}
+	err = store.UpdateMetadata(modPath, 0, &tfmod.Meta{
+		Path:        modPath,
+		ModuleCalls: moduleCalls,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedLabels := []string{
+		`"github.com/acme/dns?ref="`,
+		`"git::https://"`,
+		`"git::ssh://"`,
+		`"github.com/"`,
+		`"bitbucket.org/"`,
+		`"s3::https://"`,
+		`"gcs::https://www.googleapis.com/storage/v1/"`,
+		`"https://"`,
+		`"git::https://example.com/vpc.git?ref=v1.2.0"`,
+		`"github.com/acme/dns"`,
+	}
+
+	candidates, err := h.RemoteModuleSources(ctx, cty.StringVal("github.com/acme/dns"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		labels = append(labels, c.Label)
+	}
+	if diff := cmp.Diff(expectedLabels, labels); diff != "" {
+		t.Fatalf("mismatched candidates: %s", diff)
+	}
+
+	candidates, err = h.RemoteModuleSources(ctx, cty.StringVal("./mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 0 {
+		t.Fatalf("expected no candidates for local source, given: %#v", candidates)
+	}
+}
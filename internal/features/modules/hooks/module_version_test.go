@@ -99,7 +99,7 @@ func TestHooks_RegistryModuleVersions(t *testing.T) {
 			},
 		},
 	}
-	err = store.UpdateMetadata(tmpDir, metadata, nil)
+	err = store.UpdateMetadata(tmpDir, 0, metadata, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -37,6 +37,13 @@ func LoadModuleMetadata(ctx context.Context, modStore *state.ModuleStore, modPat
 		return err
 	}
 
+	// Read the module again, so that the result is tied to the AST
+	// as it was when the job started (see [state.ModuleRecord.ParseGeneration])
+	mod, err = modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
 	var mErr error
 	meta, diags := earlydecoder.LoadModule(mod.Path(), mod.ParsedModuleFiles.AsMap())
 	if len(diags) > 0 {
@@ -57,7 +64,7 @@ func LoadModuleMetadata(ctx context.Context, modStore *state.ModuleStore, modPat
 	}
 	meta.ProviderReferences = providerRefs
 
-	sErr := modStore.UpdateMetadata(modPath, meta, mErr)
+	sErr := modStore.UpdateMetadata(modPath, mod.ParseGeneration, meta, mErr)
 	if sErr != nil {
 		return sErr
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
 	"github.com/hashicorp/terraform-ls/internal/job"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	"github.com/hashicorp/terraform-ls/internal/pathcmp"
 	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
 	"github.com/hashicorp/terraform-ls/internal/uri"
@@ -21,14 +22,19 @@ import (
 
 // ParseModuleConfiguration parses the module configuration,
 // i.e. turns bytes of `*.tf` files into AST ([*hcl.File]).
-func ParseModuleConfiguration(ctx context.Context, fs ReadOnlyFS, modStore *state.ModuleStore, modPath string) error {
+//
+// Only files whose content changed since they were last parsed
+// are parsed again. If the content didn't change, anything derived
+// from the AST is kept, so that dependent jobs can skip their work.
+//
+// Files are parsed as the distribution targeted by the module
+// (Terraform or OpenTofu) would parse them.
+func ParseModuleConfiguration(ctx context.Context, fs ReadOnlyFS, modStore *state.ModuleStore, modPath string, isOpenTofu bool) error {
 	mod, err := modStore.ModuleRecordByPath(modPath)
 	if err != nil {
 		return err
 	}
 
-	// TODO: Avoid parsing if the content matches existing AST
-
 	// Avoid parsing if it is already in progress or already known
 	if mod.ModuleDiagnosticsState[globalAst.HCLParsingSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
 		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(modPath)}
@@ -36,28 +42,39 @@ func ParseModuleConfiguration(ctx context.Context, fs ReadOnlyFS, modStore *stat
 
 	var files ast.ModFiles
 	var diags ast.ModDiags
+	var hashes ast.ModFileHashes
+	var changed bool
 	rpcContext := lsctx.DocumentContext(ctx)
-	// Only parse the file that's being changed/opened, unless this is 1st-time parsing
-	if mod.ModuleDiagnosticsState[globalAst.HCLParsingSource] == op.OpStateLoaded && rpcContext.IsDidChangeRequest() && rpcContext.LanguageID == ilsp.Terraform.String() {
+	isParsed := mod.ModuleDiagnosticsState[globalAst.HCLParsingSource] == op.OpStateLoaded
+
+	err = modStore.SetModuleDiagnosticsState(modPath, globalAst.HCLParsingSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	// Only parse the file that's being changed, unless this is 1st-time parsing
+	if isParsed && rpcContext.IsDidChangeRequest() && rpcContext.LanguageID == ilsp.Terraform.String() && isFileInModule(rpcContext.URI, modPath) {
 		// the file has already been parsed, so only examine this file and not the whole module
-		err = modStore.SetModuleDiagnosticsState(modPath, globalAst.HCLParsingSource, op.OpStateLoading)
+		filePath, err := uri.PathFromURI(rpcContext.URI)
 		if err != nil {
 			return err
 		}
+		fileName := ast.ModFilename(filepath.Base(filePath))
 
-		filePath, err := uri.PathFromURI(rpcContext.URI)
-		if err != nil {
-			return err
+		if parser.IsIgnoredModuleFile(fileName.String(), parsedFileNames(mod.ParsedModuleFiles), isOpenTofu) {
+			return modStore.SetModuleDiagnosticsState(modPath, globalAst.HCLParsingSource, op.OpStateLoaded)
 		}
-		fileName := filepath.Base(filePath)
 
-		f, fDiags, err := parser.ParseModuleFile(fs, filePath)
+		f, fDiags, hash, err := parser.ParseModuleFile(fs, filePath)
 		if err != nil {
 			return err
 		}
-		existingFiles := mod.ParsedModuleFiles.Copy()
-		existingFiles[ast.ModFilename(fileName)] = f
-		files = existingFiles
+
+		files = mod.ParsedModuleFiles.Copy()
+		files[fileName] = f
+
+		hashes = mod.ParsedModuleFileHashes.Copy()
+		hashes[fileName] = hash
 
 		existingDiags, ok := mod.ModuleDiagnostics[globalAst.HCLParsingSource]
 		if !ok {
@@ -65,23 +82,24 @@ func ParseModuleConfiguration(ctx context.Context, fs ReadOnlyFS, modStore *stat
 		} else {
 			existingDiags = existingDiags.Copy()
 		}
-		existingDiags[ast.ModFilename(fileName)] = fDiags
+		existingDiags[fileName] = fDiags
 		diags = existingDiags
 	} else {
-		// this is the first time file is opened so parse the whole module
-		err = modStore.SetModuleDiagnosticsState(modPath, globalAst.HCLParsingSource, op.OpStateLoading)
+		// Parse the whole module, but only files which changed since last parsed
+		files, diags, hashes, changed, err = parser.ParseChangedModuleFiles(fs, modPath, isOpenTofu,
+			mod.ParsedModuleFiles, mod.ModuleDiagnostics[globalAst.HCLParsingSource], mod.ParsedModuleFileHashes)
 		if err != nil {
 			return err
 		}
 
-		files, diags, err = parser.ParseModuleFiles(fs, modPath)
-	}
-
-	if err != nil {
-		return err
+		if isParsed && !changed {
+			// Leave the AST and anything derived from it untouched,
+			// so that dependent jobs can skip their work as well
+			return modStore.SetModuleDiagnosticsState(modPath, globalAst.HCLParsingSource, op.OpStateLoaded)
+		}
 	}
 
-	sErr := modStore.UpdateParsedModuleFiles(modPath, files, err)
+	sErr := modStore.UpdateParsedModuleFiles(modPath, files, hashes, err)
 	if sErr != nil {
 		return sErr
 	}
@@ -93,3 +111,21 @@ func ParseModuleConfiguration(ctx context.Context, fs ReadOnlyFS, modStore *stat
 
 	return err
 }
+
+func parsedFileNames(files ast.ModFiles) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name.String())
+	}
+	return names
+}
+
+// isFileInModule reports whether the file with the given URI
+// is located directly in the module directory.
+func isFileInModule(fileUri, modPath string) bool {
+	filePath, err := uri.PathFromURI(fileUri)
+	if err != nil {
+		return false
+	}
+	return pathcmp.PathEquals(filepath.Dir(filePath), modPath)
+}
@@ -9,12 +9,14 @@ import (
 	"testing"
 
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/document"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
 	"github.com/hashicorp/terraform-ls/internal/filesystem"
 	"github.com/hashicorp/terraform-ls/internal/job"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	globalState "github.com/hashicorp/terraform-ls/internal/state"
 	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
 	"github.com/hashicorp/terraform-ls/internal/uri"
 )
 
@@ -43,7 +45,7 @@ func TestParseModuleConfiguration(t *testing.T) {
 	}
 
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseModuleConfiguration(ctx, testFs, ms, singleFileModulePath)
+	err = ParseModuleConfiguration(ctx, testFs, ms, singleFileModulePath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -67,7 +69,7 @@ func TestParseModuleConfiguration(t *testing.T) {
 		URI:        uri.FromPath(fooURI),
 	}
 	ctx = lsctx.WithDocumentContext(ctx, x)
-	err = ParseModuleConfiguration(ctx, testFs, ms, singleFileModulePath)
+	err = ParseModuleConfiguration(ctx, testFs, ms, singleFileModulePath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -123,7 +125,7 @@ func TestParseModuleConfiguration_ignore_tfvars(t *testing.T) {
 	}
 
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseModuleConfiguration(ctx, testFs, ms, singleFileModulePath)
+	err = ParseModuleConfiguration(ctx, testFs, ms, singleFileModulePath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -147,7 +149,7 @@ func TestParseModuleConfiguration_ignore_tfvars(t *testing.T) {
 		LanguageID: ilsp.Tfvars.String(),
 		URI:        uri.FromPath(fooURI),
 	})
-	err = ParseModuleConfiguration(ctx, testFs, ms, singleFileModulePath)
+	err = ParseModuleConfiguration(ctx, testFs, ms, singleFileModulePath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -172,3 +174,96 @@ func TestParseModuleConfiguration_ignore_tfvars(t *testing.T) {
 		t.Fatal("there should be no diags for tfvars files right now")
 	}
 }
+
+func TestParseModuleConfiguration_unchangedContent(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFs := filesystem.NewFilesystem(gs.DocumentStore)
+
+	modPath := filepath.Join(testData, "single-file-change-module")
+
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseModuleConfiguration(ctx, testFs, ms, modPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadModuleMetadata(ctx, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// parse the whole module again, e.g. as a result of a watched file change
+	ctx = job.WithIgnoreState(ctx, true)
+	err = ParseModuleConfiguration(ctx, testFs, ms, modPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// AST should be reused as-is
+	if before.ParsedModuleFiles["main.tf"] != after.ParsedModuleFiles["main.tf"] {
+		t.Fatal("main.tf should not be parsed again")
+	}
+	if after.ModuleDiagnosticsState[ast.HCLParsingSource] != op.OpStateLoaded {
+		t.Fatalf("expected parsing state to be loaded, given: %s", after.ModuleDiagnosticsState[ast.HCLParsingSource])
+	}
+	// state of anything derived from the AST should be kept
+	if after.MetaState != op.OpStateLoaded {
+		t.Fatalf("expected meta state to be loaded, given: %s", after.MetaState)
+	}
+
+	// change the content of a single file
+	mainURI := uri.FromPath(filepath.Join(modPath, "main.tf"))
+	err = gs.DocumentStore.OpenDocument(document.HandleFromURI(mainURI),
+		ilsp.Terraform.String(), 1, []byte("variable \"changed\" {}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ParseModuleConfiguration(ctx, testFs, ms, modPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.ParsedModuleFiles["foo.tf"] != changed.ParsedModuleFiles["foo.tf"] {
+		t.Fatal("unchanged foo.tf should not be parsed again")
+	}
+	if before.ParsedModuleFiles["main.tf"] == changed.ParsedModuleFiles["main.tf"] {
+		t.Fatal("changed main.tf should be parsed again")
+	}
+	if changed.MetaState != op.OpStateUnknown {
+		t.Fatalf("expected meta state to be reset, given: %s", changed.MetaState)
+	}
+	if changed.ParseGeneration == before.ParseGeneration {
+		t.Fatal("expected a new parse generation")
+	}
+}
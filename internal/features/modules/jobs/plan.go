@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	"github.com/hashicorp/terraform-ls/internal/langserver/diagnostics"
+	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+)
+
+// TerraformPlan uses Terraform CLI to run plan subcommand with the given
+// var files. Diagnostics (including failed check assertions) are
+// associated with the relevant parts of code and the planned actions
+// of resources and module calls are stored for code lenses.
+//
+// Human-readable progress messages are passed to report as they
+// are streamed by Terraform.
+func TerraformPlan(ctx context.Context, modStore *state.ModuleStore, modPath string, varFiles []string, report func(msg string)) error {
+	mod, err := modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
+	// Plan is always triggered explicitly by the user, so we don't
+	// check the state here, other than to avoid running it concurrently
+	if mod.ModuleDiagnosticsState[globalAst.TerraformPlanSource] == op.OpStateLoading {
+		return nil
+	}
+
+	err = modStore.SetModuleDiagnosticsState(modPath, globalAst.TerraformPlanSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	tfExec, err := module.TerraformExecutorForModule(ctx, mod.Path())
+	if err != nil {
+		modStore.SetModuleDiagnosticsState(modPath, globalAst.TerraformPlanSource, op.OpStateUnknown)
+		return err
+	}
+
+	jsonDiags := make([]tfjson.Diagnostic, 0)
+	actions := make(state.PlannedActions, 0)
+	w := exec.NewPlanMessageWriter(func(msg exec.PlanMessage) {
+		switch msg.Type {
+		case "diagnostic":
+			if msg.Diagnostic != nil {
+				jsonDiags = append(jsonDiags, *msg.Diagnostic)
+			}
+		case "planned_change":
+			if msg.Change != nil {
+				addr := configAddr(msg.Change.Resource)
+				actions[addr] = append(actions[addr], msg.Change.Action)
+			}
+			report(msg.Message)
+		case "refresh_start", "change_summary":
+			report(msg.Message)
+		}
+	})
+
+	_, planErr := tfExec.Plan(ctx, w, varFiles)
+	w.Flush()
+
+	// A failed plan is expected to come with diagnostics explaining
+	// the failure, so we only surface the error if there are none
+	if planErr != nil && len(jsonDiags) == 0 {
+		modStore.SetModuleDiagnosticsState(modPath, globalAst.TerraformPlanSource, op.OpStateUnknown)
+		return planErr
+	}
+
+	err = modStore.UpdatePlannedActions(modPath, actions)
+	if err != nil {
+		modStore.SetModuleDiagnosticsState(modPath, globalAst.TerraformPlanSource, op.OpStateUnknown)
+		return err
+	}
+
+	planDiags := diagnostics.HCLDiagsFromJSON(jsonDiags)
+
+	err = modStore.UpdateModuleDiagnostics(modPath, globalAst.TerraformPlanSource, ast.ModDiagsFromMap(planDiags))
+	if err != nil {
+		modStore.SetModuleDiagnosticsState(modPath, globalAst.TerraformPlanSource, op.OpStateUnknown)
+		return err
+	}
+
+	return nil
+}
+
+// configAddr returns the address of the block in the module which
+// the planned resource instance belongs to, i.e. aws_instance.foo[0]
+// becomes aws_instance.foo and any resource within a child module
+// (e.g. module.net["a"].aws_vpc.main) is attributed to the module call
+// (module.net).
+func configAddr(resource exec.PlannedResource) string {
+	if resource.Module != "" {
+		name := strings.TrimPrefix(resource.Module, "module.")
+		if idx := strings.IndexAny(name, ".["); idx >= 0 {
+			name = name[:idx]
+		}
+		return "module." + name
+	}
+
+	idx := strings.Index(resource.Addr, "[")
+	if idx < 0 {
+		return resource.Addr
+	}
+	return resource.Addr[:idx]
+}
@@ -0,0 +1,160 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	globalState "github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTerraformPlan(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modPath := t.TempDir()
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	planOutput := `{"@level":"info","@message":"Terraform 1.9.0","type":"version"}
+{"@level":"info","@message":"aws_instance.web[0]: Plan to create","type":"planned_change","change":{"resource":{"addr":"aws_instance.web[0]","module":"","resource_type":"aws_instance","resource_name":"web"},"action":"create"}}
+{"@level":"info","@message":"aws_instance.web[1]: Plan to create","type":"planned_change","change":{"resource":{"addr":"aws_instance.web[1]","module":"","resource_type":"aws_instance","resource_name":"web"},"action":"create"}}
+{"@level":"info","@message":"module.net.aws_vpc.main: Plan to replace","type":"planned_change","change":{"resource":{"addr":"module.net.aws_vpc.main","module":"module.net","resource_type":"aws_vpc","resource_name":"main"},"action":"replace"}}
+{"@level":"warn","@message":"Warning: Check block assertion failed","type":"diagnostic","diagnostic":{"severity":"warning","summary":"Check block assertion failed","detail":"Website is not healthy","range":{"filename":"main.tf","start":{"line":3,"column":5,"byte":30},"end":{"line":3,"column":20,"byte":45}}}}
+not a JSON line
+{"@level":"info","@message":"Plan: 2 to add, 0 to change, 0 to destroy.","type":"change_summary"}
+`
+
+	ctx = exec.WithExecutorOpts(ctx, &exec.ExecutorOpts{
+		ExecPath: "mock",
+	})
+	ctx = exec.WithExecutorFactory(ctx, exec.NewMockExecutor(&exec.TerraformMockCalls{
+		AnyWorkDir: []*mock.Call{
+			{
+				Method:        "Plan",
+				Repeatability: 1,
+				Arguments: []interface{}{
+					mock.AnythingOfType(""),
+					mock.Anything,
+					[]string{"dev.tfvars"},
+				},
+				ReturnArguments: []interface{}{
+					true,
+					nil,
+				},
+				RunFn: func(args mock.Arguments) {
+					w := args.Get(1).(io.Writer)
+					w.Write([]byte(planOutput))
+				},
+			},
+		},
+	}))
+
+	reported := make([]string, 0)
+	err = TerraformPlan(ctx, ms, modPath, []string{"dev.tfvars"}, func(msg string) {
+		reported = append(reported, msg)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedActions := state.PlannedActions{
+		"aws_instance.web": {"create", "create"},
+		"module.net":       {"replace"},
+	}
+	if diff := cmp.Diff(expectedActions, mod.PlannedActions); diff != "" {
+		t.Fatalf("unexpected planned actions: %s", diff)
+	}
+
+	expectedReported := []string{
+		"aws_instance.web[0]: Plan to create",
+		"aws_instance.web[1]: Plan to create",
+		"module.net.aws_vpc.main: Plan to replace",
+		"Plan: 2 to add, 0 to change, 0 to destroy.",
+	}
+	if diff := cmp.Diff(expectedReported, reported); diff != "" {
+		t.Fatalf("unexpected reported messages: %s", diff)
+	}
+
+	diags := mod.ModuleDiagnostics[ast.TerraformPlanSource]
+	if len(diags.AsMap()["main.tf"]) != 1 {
+		t.Fatalf("expected 1 diagnostic for main.tf, given: %#v", diags)
+	}
+}
+
+func TestTerraformPlan_failed(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modPath := t.TempDir()
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = exec.WithExecutorOpts(ctx, &exec.ExecutorOpts{
+		ExecPath: "mock",
+	})
+	ctx = exec.WithExecutorFactory(ctx, exec.NewMockExecutor(&exec.TerraformMockCalls{
+		AnyWorkDir: []*mock.Call{
+			{
+				Method:        "Plan",
+				Repeatability: 1,
+				Arguments: []interface{}{
+					mock.AnythingOfType(""),
+					mock.Anything,
+					[]string(nil),
+				},
+				ReturnArguments: []interface{}{
+					false,
+					errors.New("plan failed"),
+				},
+			},
+		},
+	}))
+
+	err = TerraformPlan(ctx, ms, modPath, nil, func(string) {})
+	if err == nil {
+		t.Fatal("expected plan to fail")
+	}
+
+	mod, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the plan can be retried after failure
+	if mod.ModuleDiagnosticsState[ast.TerraformPlanSource] != op.OpStateUnknown {
+		t.Fatalf("expected state to be reset, given: %s", mod.ModuleDiagnosticsState[ast.TerraformPlanSource])
+	}
+}
@@ -43,6 +43,13 @@ func DecodeReferenceTargets(ctx context.Context, modStore *state.ModuleStore, ro
 		return err
 	}
 
+	// Read the module again, so that the result is tied to the AST
+	// as it was when the job started (see [state.ModuleRecord.ParseGeneration])
+	mod, err = modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
 	d := decoder.NewDecoder(&fdecoder.PathReader{
 		StateReader: modStore,
 		RootReader:  rootFeature,
@@ -58,7 +65,7 @@ func DecodeReferenceTargets(ctx context.Context, modStore *state.ModuleStore, ro
 	}
 	targets, rErr := pd.CollectReferenceTargets()
 
-	sErr := modStore.UpdateReferenceTargets(modPath, targets, rErr)
+	sErr := modStore.UpdateReferenceTargets(modPath, mod.ParseGeneration, targets, rErr)
 	if sErr != nil {
 		return sErr
 	}
@@ -92,6 +99,13 @@ func DecodeReferenceOrigins(ctx context.Context, modStore *state.ModuleStore, ro
 		return err
 	}
 
+	// Read the module again, so that the result is tied to the AST
+	// as it was when the job started (see [state.ModuleRecord.ParseGeneration])
+	mod, err = modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
 	d := decoder.NewDecoder(&fdecoder.PathReader{
 		StateReader: modStore,
 		RootReader:  rootFeature,
@@ -108,7 +122,7 @@ func DecodeReferenceOrigins(ctx context.Context, modStore *state.ModuleStore, ro
 
 	origins, rErr := moduleDecoder.CollectReferenceOrigins()
 
-	sErr := modStore.UpdateReferenceOrigins(modPath, origins, rErr)
+	sErr := modStore.UpdateReferenceOrigins(modPath, mod.ParseGeneration, origins, rErr)
 	if sErr != nil {
 		return sErr
 	}
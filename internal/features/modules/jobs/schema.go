@@ -47,7 +47,14 @@ func PreloadEmbeddedSchema(ctx context.Context, logger *log.Logger, fs fs.ReadDi
 	if err != nil {
 		return err
 	}
-	defer modStore.SetPreloadEmbeddedSchemaState(modPath, op.OpStateLoaded)
+
+	// Read the module again, so that the result is tied to the AST
+	// as it was when the job started (see [state.ModuleRecord.ParseGeneration])
+	mod, err = modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+	defer modStore.SetPreloadEmbeddedSchemaLoaded(modPath, mod.ParseGeneration)
 
 	pReqs, err := modStore.ProviderRequirementsForModule(modPath)
 	if err != nil {
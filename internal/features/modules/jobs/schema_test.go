@@ -59,7 +59,7 @@ func TestGetModuleDataFromRegistry_singleModule(t *testing.T) {
 
 	fs := filesystem.NewFilesystem(gs.DocumentStore)
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseModuleConfiguration(ctx, fs, ms, modPath)
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -136,7 +136,7 @@ func TestGetModuleDataFromRegistry_submodule(t *testing.T) {
 
 	fs := filesystem.NewFilesystem(gs.DocumentStore)
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseModuleConfiguration(ctx, fs, ms, modPath)
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -213,7 +213,7 @@ func TestGetModuleDataFromRegistry_unreliableInputs(t *testing.T) {
 
 	fs := filesystem.NewFilesystem(gs.DocumentStore)
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseModuleConfiguration(ctx, fs, ms, modPath)
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -309,7 +309,7 @@ func TestGetModuleDataFromRegistry_moduleNotFound(t *testing.T) {
 
 	fs := filesystem.NewFilesystem(gs.DocumentStore)
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseModuleConfiguration(ctx, fs, ms, modPath)
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -414,7 +414,7 @@ func TestGetModuleDataFromRegistry_apiTimeout(t *testing.T) {
 
 	fs := filesystem.NewFilesystem(gs.DocumentStore)
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseModuleConfiguration(ctx, fs, ms, modPath)
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -629,7 +629,7 @@ func TestPreloadEmbeddedSchema_basic(t *testing.T) {
 		t.Fatal(err)
 	}
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseModuleConfiguration(ctx, cfgFS, ms, modPath)
+	err = ParseModuleConfiguration(ctx, cfgFS, ms, modPath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -704,7 +704,7 @@ func TestPreloadEmbeddedSchema_unknownProviderOnly(t *testing.T) {
 		t.Fatal(err)
 	}
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseModuleConfiguration(ctx, cfgFS, ms, modPath)
+	err = ParseModuleConfiguration(ctx, cfgFS, ms, modPath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -772,7 +772,7 @@ func TestPreloadEmbeddedSchema_idempotency(t *testing.T) {
 		t.Fatal(err)
 	}
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseModuleConfiguration(ctx, cfgFS, ms, modPath)
+	err = ParseModuleConfiguration(ctx, cfgFS, ms, modPath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -856,7 +856,7 @@ func TestPreloadEmbeddedSchema_raceCondition(t *testing.T) {
 		t.Fatal(err)
 	}
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = ParseModuleConfiguration(ctx, cfgFS, ms, modPath)
+	err = ParseModuleConfiguration(ctx, cfgFS, ms, modPath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
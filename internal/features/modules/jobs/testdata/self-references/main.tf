@@ -0,0 +1,23 @@
+variable "name" {
+  type = string
+
+  validation {
+    condition     = length(var.name) > 0
+    error_message = "Name must not be empty."
+  }
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = var.name
+  }
+
+  lifecycle {
+    postcondition {
+      condition     = aws_vpc.main.id != ""
+      error_message = "VPC must have an ID."
+    }
+  }
+}
@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	"github.com/hashicorp/terraform-ls/internal/langserver/diagnostics"
+	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/terraform/parser"
+	"github.com/hashicorp/terraform-ls/internal/tflint"
+)
+
+// TFLint uses tflint to lint the module and turns the reported
+// issues into diagnostics associated with the relevant parts of code.
+//
+// Results are kept for the content of module files and the tflint
+// config file on disk (which is what tflint reads, as opposed to any
+// unsaved changes), so that tflint only runs again once any of them changed.
+// Diagnostics of any previous run are cleared if tflint fails.
+func TFLint(ctx context.Context, modStore *state.ModuleStore, linter tflint.Linter, modPath string) error {
+	mod, err := modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
+	hash, err := lintContentHash(modPath)
+	if err != nil {
+		return err
+	}
+	if mod.ModuleDiagnosticsState[globalAst.TFLintSource] == op.OpStateLoaded && mod.TFLintContentHash == hash {
+		return nil
+	}
+
+	err = modStore.SetModuleDiagnosticsState(modPath, globalAst.TFLintSource, op.OpStateLoading)
+	if err != nil {
+		return err
+	}
+
+	output, err := linter.Lint(ctx, modPath)
+	if err != nil {
+		// Previous issues may no longer apply to the changed files
+		clearErr := modStore.ClearTFLintDiagnostics(modPath)
+		if clearErr != nil {
+			return clearErr
+		}
+		return err
+	}
+
+	lintDiags := diagnostics.HCLDiagsFromTFLint(output)
+	err = modStore.UpdateTFLintDiagnostics(modPath, hash, ast.ModDiagsFromMap(lintDiags))
+	if err != nil {
+		return err
+	}
+
+	// Errors which can't be associated with any code
+	// (e.g. failure to install plugins) are surfaced here
+	msgs := make([]string, 0)
+	for _, tflintErr := range output.Errors {
+		if tflintErr.Range == nil {
+			msgs = append(msgs, tflintErr.Message)
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("tflint reported errors: %s", strings.Join(msgs, "; "))
+	}
+
+	return nil
+}
+
+// lintContentHash returns a hash of the content of all module files
+// and the tflint config file as saved on disk
+func lintContentHash(modPath string) (parser.ContentHash, error) {
+	var hash parser.ContentHash
+
+	entries, err := os.ReadDir(modPath)
+	if err != nil {
+		return hash, err
+	}
+
+	h := sha256.New()
+	// entries are sorted by name
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (!ast.IsModuleFilename(name) && name != tflint.ConfigFileName) {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(modPath, name))
+		if err != nil {
+			return hash, err
+		}
+		fileHash := parser.HashContent(src)
+		h.Write([]byte(name))
+		h.Write(fileHash[:])
+	}
+
+	copy(hash[:], h.Sum(nil))
+	return hash, nil
+}
@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/document"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	"github.com/hashicorp/terraform-ls/internal/filesystem"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	globalState "github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/tflint"
+)
+
+type linterMock struct {
+	output *tflint.Output
+	err    error
+	calls  int
+}
+
+func (l *linterMock) Lint(ctx context.Context, modPath string) (*tflint.Output, error) {
+	l.calls++
+	return l.output, l.err
+}
+
+func TestTFLint_cached(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modPath := t.TempDir()
+	err = os.WriteFile(filepath.Join(modPath, "main.tf"), []byte(`variable "Name" {}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := filesystem.NewFilesystem(gs.DocumentStore)
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{
+		Method:     "textDocument/didOpen",
+		LanguageID: ilsp.Terraform.String(),
+		URI:        "file:///test/main.tf",
+	})
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linter := &linterMock{
+		output: &tflint.Output{
+			Issues: []tflint.Issue{
+				{
+					Rule: tflint.Rule{
+						Name:     "terraform_naming_convention",
+						Severity: "notice",
+					},
+					Message: "variable name `Name` must match the following format: snake_case",
+					Range: tflint.Range{
+						Filename: "main.tf",
+						Start:    tflint.Pos{Line: 1, Column: 1},
+						End:      tflint.Pos{Line: 1, Column: 16},
+					},
+				},
+			},
+		},
+	}
+
+	err = TFLint(ctx, ms, linter, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mod.ModuleDiagnostics[ast.TFLintSource].Count(); got != 1 {
+		t.Fatalf("expected 1 diagnostic, %d given", got)
+	}
+
+	// Linting the same content again is skipped
+	err = TFLint(ctx, ms, linter, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linter.calls != 1 {
+		t.Fatalf("expected tflint to run once, ran %d times", linter.calls)
+	}
+
+	// Unsaved changes are not linted, since tflint reads files from disk
+	err = gs.DocumentStore.OpenDocument(document.HandleFromPath(filepath.Join(modPath, "main.tf")),
+		ilsp.Terraform.String(), 1, []byte(`variable "Unsaved" {}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = TFLint(ctx, ms, linter, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linter.calls != 1 {
+		t.Fatalf("expected tflint to run once, ran %d times", linter.calls)
+	}
+
+	// Changes to the config file are linted
+	err = os.WriteFile(filepath.Join(modPath, tflint.ConfigFileName), []byte(`plugin "terraform" {
+  enabled = true
+}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = TFLint(ctx, ms, linter, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linter.calls != 2 {
+		t.Fatalf("expected tflint to run twice, ran %d times", linter.calls)
+	}
+}
+
+func TestTFLint_failure(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modPath := t.TempDir()
+	err = os.WriteFile(filepath.Join(modPath, "main.tf"), []byte(`variable "Name" {}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linter := &linterMock{
+		output: &tflint.Output{
+			Issues: []tflint.Issue{
+				{
+					Rule:    tflint.Rule{Name: "terraform_naming_convention"},
+					Message: "variable name `Name` must match the following format: snake_case",
+					Range: tflint.Range{
+						Filename: "main.tf",
+						Start:    tflint.Pos{Line: 1, Column: 1},
+						End:      tflint.Pos{Line: 1, Column: 16},
+					},
+				},
+			},
+		},
+	}
+	err = TFLint(ctx, ms, linter, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The failed run of the changed file clears previous issues
+	err = os.WriteFile(filepath.Join(modPath, "main.tf"), []byte(`variable "name" {}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linter.err = errors.New("failed to load plugins")
+	err = TFLint(ctx, ms, linter, modPath)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	mod, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mod.ModuleDiagnostics[ast.TFLintSource].Count(); got != 0 {
+		t.Fatalf("expected no diagnostics, %d given", got)
+	}
+	if diagsState := mod.ModuleDiagnosticsState[ast.TFLintSource]; diagsState != op.OpStateUnknown {
+		t.Fatalf("expected unknown state, given: %s", diagsState)
+	}
+}
@@ -6,23 +6,25 @@ package jobs
 import (
 	"context"
 	"path"
+	"path/filepath"
+	"strings"
 
-	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
-	"github.com/hashicorp/hcl/v2"
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
-	idecoder "github.com/hashicorp/terraform-ls/internal/decoder"
 	"github.com/hashicorp/terraform-ls/internal/document"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
 	fdecoder "github.com/hashicorp/terraform-ls/internal/features/modules/decoder"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/decoder/validations"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/lint"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
 	"github.com/hashicorp/terraform-ls/internal/job"
 	"github.com/hashicorp/terraform-ls/internal/langserver/diagnostics"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/datadir"
 	"github.com/hashicorp/terraform-ls/internal/terraform/module"
 	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	tfmod "github.com/hashicorp/terraform-schema/module"
 )
 
 // SchemaModuleValidation does schema-based validation
@@ -33,28 +35,48 @@ import (
 // core schema of appropriate version (as obtained via [GetTerraformVersion])
 // and provider schemas ([PreloadEmbeddedSchema] or [ObtainSchema]).
 func SchemaModuleValidation(ctx context.Context, modStore *state.ModuleStore, rootFeature fdecoder.RootReader, modPath string) error {
+	return checkModuleRules(ctx, modStore, rootFeature, modPath,
+		globalAst.SchemaValidationSource, lint.Rules{lint.SchemaRule{}})
+}
+
+// CustomRulesValidation checks module files (*.tf) against
+// custom rules, such as rule plugins or policy files.
+//
+// It is kept separate from [SchemaModuleValidation], since custom rules
+// may take a while to check and their diagnostics are reported separately.
+func CustomRulesValidation(ctx context.Context, modStore *state.ModuleStore, rootFeature fdecoder.RootReader, modPath string, customRules lint.Rules) error {
+	return checkModuleRules(ctx, modStore, rootFeature, modPath,
+		globalAst.CustomRulesSource, customRules)
+}
+
+func checkModuleRules(ctx context.Context, modStore *state.ModuleStore, rootFeature fdecoder.RootReader, modPath string, source globalAst.DiagnosticSource, moduleRules lint.Rules) error {
 	mod, err := modStore.ModuleRecordByPath(modPath)
 	if err != nil {
 		return err
 	}
 
 	// Avoid validation if it is already in progress or already finished
-	if mod.ModuleDiagnosticsState[globalAst.SchemaValidationSource] != op.OpStateUnknown && !job.IgnoreState(ctx) {
+	if mod.ModuleDiagnosticsState[source] != op.OpStateUnknown && !job.IgnoreState(ctx) {
 		return job.StateNotChangedErr{Dir: document.DirHandleFromPath(modPath)}
 	}
 
-	err = modStore.SetModuleDiagnosticsState(modPath, globalAst.SchemaValidationSource, op.OpStateLoading)
+	err = modStore.SetModuleDiagnosticsState(modPath, source, op.OpStateLoading)
 	if err != nil {
 		return err
 	}
 
-	d := decoder.NewDecoder(&fdecoder.PathReader{
+	// Read the module again, so that the result is tied to the AST
+	// as it was when the job started (see [state.ModuleRecord.ParseGeneration])
+	mod, err = modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
+	pathReader := &fdecoder.PathReader{
 		StateReader: modStore,
 		RootReader:  rootFeature,
-	})
-	d.SetContext(idecoder.DecoderContext(ctx))
-
-	moduleDecoder, err := d.Path(lang.Path{
+	}
+	pathCtx, err := pathReader.PathContext(lang.Path{
 		Path:       modPath,
 		LanguageID: ilsp.Terraform.String(),
 	})
@@ -62,33 +84,39 @@ func SchemaModuleValidation(ctx context.Context, modStore *state.ModuleStore, ro
 		return err
 	}
 
-	var rErr error
+	lintMod := &lint.Module{
+		Path:        modPath,
+		Files:       mod.ParsedModuleFiles,
+		Meta:        mod.Meta,
+		PathContext: pathCtx,
+	}
+
 	rpcContext := lsctx.DocumentContext(ctx)
 	if rpcContext.Method == "textDocument/didChange" && rpcContext.LanguageID == ilsp.Terraform.String() {
 		filename := path.Base(rpcContext.URI)
 		// We only revalidate a single file that changed
-		var fileDiags hcl.Diagnostics
-		fileDiags, rErr = moduleDecoder.ValidateFile(ctx, filename)
+		lintMod.Filenames = []string{filename}
+		diags, rErr := moduleRules.Check(ctx, lintMod)
 
-		modDiags, ok := mod.ModuleDiagnostics[globalAst.SchemaValidationSource]
+		modDiags, ok := mod.ModuleDiagnostics[source]
 		if !ok {
 			modDiags = make(ast.ModDiags)
 		}
-		modDiags[ast.ModFilename(filename)] = fileDiags
+		modDiags[ast.ModFilename(filename)] = diags[filename]
 
-		sErr := modStore.UpdateModuleDiagnostics(modPath, globalAst.SchemaValidationSource, modDiags)
+		sErr := modStore.UpdateValidationDiagnostics(modPath, mod.ParseGeneration, source, modDiags)
 		if sErr != nil {
 			return sErr
 		}
-	} else {
-		// We validate the whole module, e.g. on open
-		var diags lang.DiagnosticsMap
-		diags, rErr = moduleDecoder.Validate(ctx)
+		return rErr
+	}
 
-		sErr := modStore.UpdateModuleDiagnostics(modPath, globalAst.SchemaValidationSource, ast.ModDiagsFromMap(diags))
-		if sErr != nil {
-			return sErr
-		}
+	// We validate the whole module, e.g. on open
+	diags, rErr := moduleRules.Check(ctx, lintMod)
+
+	sErr := modStore.UpdateValidationDiagnostics(modPath, mod.ParseGeneration, source, ast.ModDiagsFromMap(diags))
+	if sErr != nil {
+		return sErr
 	}
 
 	return rErr
@@ -115,6 +143,13 @@ func ReferenceValidation(ctx context.Context, modStore *state.ModuleStore, rootF
 		return err
 	}
 
+	// Read the module again, so that the result is tied to the AST
+	// as it was when the job started (see [state.ModuleRecord.ParseGeneration])
+	mod, err = modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
 	pathReader := &fdecoder.PathReader{
 		StateReader: modStore,
 		RootReader:  rootFeature,
@@ -128,7 +163,54 @@ func ReferenceValidation(ctx context.Context, modStore *state.ModuleStore, rootF
 	}
 
 	diags := validations.UnreferencedOrigins(ctx, pathCtx)
-	return modStore.UpdateModuleDiagnostics(modPath, globalAst.ReferenceValidationSource, ast.ModDiagsFromMap(diags))
+
+	// Terraform cannot build a plan if objects refer to each other in a cycle
+	graph := fdecoder.DependencyGraph(mod)
+	diags = diags.Extend(lang.DiagnosticsMap(graph.CycleDiagnostics()))
+
+	diags = diags.Extend(validations.ImportAndMovedBlocks(mod.ParsedModuleFiles))
+
+	tfVersion := fdecoder.ResolveTerraformVersion(mod, fdecoder.CombinedReader{
+		StateReader: modStore,
+		RootReader:  rootFeature,
+	})
+	diags = diags.Extend(validations.Backend(mod.ParsedModuleFiles, tfVersion, isChildModule(modStore, modPath)))
+
+	// Module calls are only installed for root modules,
+	// i.e. any error can be ignored here
+	installed, _ := rootFeature.InstalledModuleCalls(modPath)
+	diags = diags.Extend(validations.ModuleSources(mod.ParsedModuleFiles, installed))
+
+	return modStore.UpdateValidationDiagnostics(modPath, mod.ParseGeneration, globalAst.ReferenceValidationSource, ast.ModDiagsFromMap(diags))
+}
+
+// isChildModule returns true if the module at the given path is
+// installed as a dependency, or called via a local source address
+// by another module in the workspace
+func isChildModule(modStore *state.ModuleStore, modPath string) bool {
+	if strings.Contains(modPath, filepath.Join(datadir.DataDirName, "modules")) {
+		return true
+	}
+
+	mods, err := modStore.List()
+	if err != nil {
+		return false
+	}
+	for _, mod := range mods {
+		if mod.Path() == modPath {
+			continue
+		}
+		for _, mc := range mod.Meta.ModuleCalls {
+			source, ok := mc.SourceAddr.(tfmod.LocalSourceAddr)
+			if !ok {
+				continue
+			}
+			if filepath.Join(mod.Path(), filepath.FromSlash(source.String())) == filepath.Clean(modPath) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // TerraformValidate uses Terraform CLI to run validate subcommand
@@ -9,12 +9,17 @@ import (
 	"testing"
 
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/lint"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
 	"github.com/hashicorp/terraform-ls/internal/filesystem"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 	globalState "github.com/hashicorp/terraform-ls/internal/state"
 	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
 	tfmod "github.com/hashicorp/terraform-schema/module"
 )
 
@@ -28,6 +33,14 @@ func (r RootReaderMock) TerraformVersion(modPath string) *version.Version {
 	return nil
 }
 
+func (r RootReaderMock) PinnedTerraformVersion(modPath string) *version.Version {
+	return nil
+}
+
+func (r RootReaderMock) IsOpenTofu(modPath string) bool {
+	return false
+}
+
 func (r RootReaderMock) InstalledModulePath(rootPath string, normalizedSource string) (string, bool) {
 	return "", false
 }
@@ -60,7 +73,7 @@ func TestSchemaModuleValidation_FullModule(t *testing.T) {
 		LanguageID: ilsp.Terraform.String(),
 		URI:        "file:///test/variables.tf",
 	})
-	err = ParseModuleConfiguration(ctx, fs, ms, modPath)
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -109,7 +122,7 @@ func TestSchemaModuleValidation_SingleFile(t *testing.T) {
 		LanguageID: ilsp.Terraform.String(),
 		URI:        "file:///test/variables.tf",
 	})
-	err = ParseModuleConfiguration(ctx, fs, ms, modPath)
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -129,3 +142,140 @@ func TestSchemaModuleValidation_SingleFile(t *testing.T) {
 		t.Fatalf("expected %d diagnostics, %d given", expectedCount, diagsCount)
 	}
 }
+
+type ruleMock struct {
+	diags lang.DiagnosticsMap
+}
+
+func (r ruleMock) Check(ctx context.Context, mod *lint.Module) (lang.DiagnosticsMap, error) {
+	return r.diags, nil
+}
+
+func TestCustomRulesValidation(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	modPath := filepath.Join(testData, "invalid-config")
+
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := filesystem.NewFilesystem(gs.DocumentStore)
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{
+		Method:     "textDocument/didOpen",
+		LanguageID: ilsp.Terraform.String(),
+		URI:        "file:///test/variables.tf",
+	})
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	customRules := lint.Rules{
+		ruleMock{
+			diags: lang.DiagnosticsMap{
+				"main.tf": hcl.Diagnostics{
+					{
+						Severity: hcl.DiagError,
+						Summary:  "Missing tags",
+					},
+				},
+			},
+		},
+	}
+	err = CustomRulesValidation(ctx, ms, RootReaderMock{}, modPath, customRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diagsCount := mod.ModuleDiagnostics[ast.CustomRulesSource].Count()
+	if diagsCount != 1 {
+		t.Fatalf("expected 1 diagnostic, %d given", diagsCount)
+	}
+	// schema validation is not affected
+	if mod.ModuleDiagnosticsState[ast.SchemaValidationSource] != operation.OpStateUnknown {
+		t.Fatalf("unexpected schema validation state: %s", mod.ModuleDiagnosticsState[ast.SchemaValidationSource])
+	}
+}
+
+func TestReferenceValidation_selfReferences(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := state.NewModuleStore(gs.ProviderSchemas, gs.RegistryModules, gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	modPath := filepath.Join(testData, "self-references")
+
+	err = ms.Add(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := filesystem.NewFilesystem(gs.DocumentStore)
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{
+		Method:     "textDocument/didOpen",
+		LanguageID: ilsp.Terraform.String(),
+		URI:        "file:///test/main.tf",
+	})
+	err = ParseModuleConfiguration(ctx, fs, ms, modPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadModuleMetadata(ctx, ms, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = DecodeReferenceTargets(ctx, ms, RootReaderMock{}, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = DecodeReferenceOrigins(ctx, ms, RootReaderMock{}, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ReferenceValidation(ctx, ms, RootReaderMock{}, modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := ms.ModuleRecordByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// References within validation blocks and conditions
+	// to their own block don't form any dependency cycle
+	for _, diags := range mod.ModuleDiagnostics[ast.ReferenceValidationSource] {
+		for _, diag := range diags {
+			if id, _ := rules.RuleID(diag); id == rules.DependencyCycle {
+				t.Fatalf("unexpected dependency cycle: %s", diag.Detail)
+			}
+		}
+	}
+}
@@ -41,6 +41,13 @@ func DecodeWriteOnlyAttributes(ctx context.Context, modStore *state.ModuleStore,
 		return err
 	}
 
+	// Read the module again, so that the result is tied to the AST
+	// as it was when the job started (see [state.ModuleRecord.ParseGeneration])
+	mod, err = modStore.ModuleRecordByPath(modPath)
+	if err != nil {
+		return err
+	}
+
 	d := decoder.NewDecoder(&fdecoder.PathReader{
 		StateReader: modStore,
 		RootReader:  rootFeature,
@@ -92,7 +99,7 @@ func DecodeWriteOnlyAttributes(ctx context.Context, modStore *state.ModuleStore,
 		woAttrsMap[*providerAddr][attr.Resource][attr.Name]++
 	}
 
-	sErr := modStore.UpdateWriteOnlyAttributes(modPath, woAttrsMap, rErr)
+	sErr := modStore.UpdateWriteOnlyAttributes(modPath, mod.ParseGeneration, woAttrsMap, rErr)
 	if sErr != nil {
 		return sErr
 	}
@@ -0,0 +1,350 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// decodedBlock is a block decoded against the schema of the module,
+// as used for schema validation. Attributes and nested blocks unknown
+// to the schema are left out and values of attributes are converted
+// to the type declared by the schema.
+//
+// Blocks whose schema is not known (e.g. resources of providers
+// whose schema is not available) are decoded as written.
+type decodedBlock struct {
+	Type     string
+	Labels   []string
+	DefRange hcl.Range
+	Range    hcl.Range
+
+	// Schema is the schema of the body, merged with any dependent
+	// body (e.g. of the resource type), or nil if not known
+	Schema *schema.BodySchema
+
+	Attributes map[string]*decodedAttribute
+	Blocks     []*decodedBlock
+}
+
+type decodedAttribute struct {
+	Expr  hcl.Expression
+	Range hcl.Range
+
+	// Type is the type declared by the schema, if any
+	Type cty.Type
+
+	// Value is the value of the expression converted to the declared type,
+	// if it can be evaluated without any references or functions.
+	// The value is unknown otherwise.
+	Value cty.Value
+}
+
+// decodedFile holds the decoded top-level blocks of a module file
+type decodedFile struct {
+	Name   string
+	Bytes  []byte
+	Blocks []*decodedBlock
+}
+
+// decodeModule decodes all files of the module (sorted by name)
+// against the schema of the module, if known
+func decodeModule(mod *Module) []decodedFile {
+	var bodySchema *schema.BodySchema
+	if mod.PathContext != nil {
+		bodySchema = mod.PathContext.Schema
+	}
+
+	filenames := make([]string, 0, len(mod.Files))
+	for name := range mod.Files {
+		filenames = append(filenames, name.String())
+	}
+	sort.Strings(filenames)
+
+	files := make([]decodedFile, 0, len(filenames))
+	for _, name := range filenames {
+		f := mod.Files[ast.ModFilename(name)]
+		files = append(files, decodedFile{
+			Name:   name,
+			Bytes:  f.Bytes,
+			Blocks: decodeFile(f, bodySchema),
+		})
+	}
+	return files
+}
+
+// decodeFile decodes the top-level blocks of the given file
+// against the schema of the module. Without any schema, blocks
+// are only decoded from the native syntax.
+func decodeFile(f *hcl.File, bodySchema *schema.BodySchema) []*decodedBlock {
+	blocks := make([]*decodedBlock, 0)
+
+	if bodySchema == nil {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			return blocks
+		}
+		for _, block := range body.Blocks {
+			blocks = append(blocks, decodeBlock(block.AsHCLBlock(), nil))
+		}
+		return blocks
+	}
+
+	content, _, _ := f.Body.PartialContent(hclBodySchema(bodySchema))
+	for _, block := range content.Blocks {
+		blocks = append(blocks, decodeBlock(block, bodySchema.Blocks[block.Type]))
+	}
+	return blocks
+}
+
+func decodeBlock(block *hcl.Block, blockSchema *schema.BlockSchema) *decodedBlock {
+	db := &decodedBlock{
+		Type:       block.Type,
+		Labels:     block.Labels,
+		DefRange:   block.DefRange,
+		Range:      blockRange(block),
+		Schema:     mergedBodySchema(block, blockSchema),
+		Attributes: make(map[string]*decodedAttribute, 0),
+		Blocks:     make([]*decodedBlock, 0),
+	}
+	if db.Labels == nil {
+		db.Labels = []string{}
+	}
+
+	switch {
+	case db.Schema == nil:
+		decodeBodyAsWritten(db, block.Body)
+	case db.Schema.AnyAttribute != nil:
+		attrs, _ := block.Body.JustAttributes()
+		for name, attr := range attrs {
+			db.Attributes[name] = decodeAttribute(attr, db.Schema.AnyAttribute)
+		}
+	default:
+		content, _, _ := block.Body.PartialContent(hclBodySchema(db.Schema))
+		for name, attr := range content.Attributes {
+			db.Attributes[name] = decodeAttribute(attr, db.Schema.Attributes[name])
+		}
+		for _, nested := range content.Blocks {
+			db.Blocks = append(db.Blocks, decodeBlock(nested, db.Schema.Blocks[nested.Type]))
+		}
+	}
+
+	return db
+}
+
+// decodeBodyAsWritten decodes all attributes and nested blocks of the body.
+// Nested blocks can only be told apart from attributes in the native syntax.
+func decodeBodyAsWritten(db *decodedBlock, body hcl.Body) {
+	syntaxBody, ok := body.(*hclsyntax.Body)
+	if !ok {
+		attrs, _ := body.JustAttributes()
+		for name, attr := range attrs {
+			db.Attributes[name] = decodeAttribute(attr, nil)
+		}
+		return
+	}
+
+	for name, attr := range syntaxBody.Attributes {
+		db.Attributes[name] = decodeAttribute(attr.AsHCLAttribute(), nil)
+	}
+	for _, nested := range syntaxBody.Blocks {
+		db.Blocks = append(db.Blocks, decodeBlock(nested.AsHCLBlock(), nil))
+	}
+}
+
+func decodeAttribute(attr *hcl.Attribute, attrSchema *schema.AttributeSchema) *decodedAttribute {
+	da := &decodedAttribute{
+		Expr:  attr.Expr,
+		Range: attr.Range,
+		Type:  attributeType(attrSchema),
+	}
+
+	val, ok := staticValue(attr.Expr)
+	if !ok {
+		da.Value = cty.UnknownVal(da.Type)
+		return da
+	}
+	if converted, err := convert.Convert(val, da.Type); err == nil {
+		val = converted
+	}
+	da.Value = val
+
+	return da
+}
+
+// mergedBodySchema returns the schema of the body of the given block,
+// including the dependent body selected by its labels (e.g. the resource
+// type). It returns nil if the block or its dependent body is not known.
+func mergedBodySchema(block *hcl.Block, blockSchema *schema.BlockSchema) *schema.BodySchema {
+	if blockSchema == nil {
+		return nil
+	}
+
+	labels := make([]schema.LabelDependent, 0)
+	for i, label := range blockSchema.Labels {
+		if label.IsDepKey && i < len(block.Labels) {
+			labels = append(labels, schema.LabelDependent{Index: i, Value: block.Labels[i]})
+		}
+	}
+
+	if len(labels) == 0 || len(blockSchema.DependentBody) == 0 {
+		if blockSchema.Body == nil {
+			return &schema.BodySchema{}
+		}
+		return blockSchema.Body
+	}
+
+	depBody, ok := blockSchema.DependentBody[schema.NewSchemaKey(schema.DependencyKeys{Labels: labels})]
+	if !ok {
+		return nil
+	}
+
+	merged := &schema.BodySchema{}
+	if blockSchema.Body != nil {
+		merged = blockSchema.Body.Copy()
+	}
+	if merged.Attributes == nil {
+		merged.Attributes = make(map[string]*schema.AttributeSchema, len(depBody.Attributes))
+	}
+	if merged.Blocks == nil {
+		merged.Blocks = make(map[string]*schema.BlockSchema, len(depBody.Blocks))
+	}
+	for name, attr := range depBody.Attributes {
+		merged.Attributes[name] = attr
+	}
+	for bType, block := range depBody.Blocks {
+		merged.Blocks[bType] = block
+	}
+	if depBody.Extensions != nil {
+		merged.Extensions = depBody.Extensions.Copy()
+	}
+
+	return merged
+}
+
+// hclBodySchema returns the schema to decode bodies with via HCL,
+// including meta-arguments provided by extensions of the schema
+func hclBodySchema(bodySchema *schema.BodySchema) *hcl.BodySchema {
+	hclSchema := &hcl.BodySchema{
+		Attributes: make([]hcl.AttributeSchema, 0, len(bodySchema.Attributes)),
+		Blocks:     make([]hcl.BlockHeaderSchema, 0, len(bodySchema.Blocks)),
+	}
+	for name := range bodySchema.Attributes {
+		hclSchema.Attributes = append(hclSchema.Attributes, hcl.AttributeSchema{Name: name})
+	}
+	if ext := bodySchema.Extensions; ext != nil {
+		if ext.Count {
+			hclSchema.Attributes = append(hclSchema.Attributes, hcl.AttributeSchema{Name: "count"})
+		}
+		if ext.ForEach {
+			hclSchema.Attributes = append(hclSchema.Attributes, hcl.AttributeSchema{Name: "for_each"})
+		}
+	}
+	for bType, block := range bodySchema.Blocks {
+		labelNames := make([]string, 0, len(block.Labels))
+		for _, label := range block.Labels {
+			labelNames = append(labelNames, label.Name)
+		}
+		hclSchema.Blocks = append(hclSchema.Blocks, hcl.BlockHeaderSchema{
+			Type:       bType,
+			LabelNames: labelNames,
+		})
+	}
+	return hclSchema
+}
+
+// blockRange returns the range of the whole block,
+// which is only known in the native syntax
+func blockRange(block *hcl.Block) hcl.Range {
+	if body, ok := block.Body.(*hclsyntax.Body); ok {
+		return hcl.RangeBetween(block.DefRange, body.SrcRange)
+	}
+	return block.DefRange
+}
+
+// value returns the object representing the block, i.e. values
+// of its attributes and nested blocks. Attributes and blocks known
+// to the schema, but absent in the block, are null or empty.
+func (db *decodedBlock) value() cty.Value {
+	return cty.ObjectVal(db.values())
+}
+
+func (db *decodedBlock) values() map[string]cty.Value {
+	vals := make(map[string]cty.Value, len(db.Attributes))
+
+	if db.Schema != nil {
+		for name, attrSchema := range db.Schema.Attributes {
+			vals[name] = cty.NullVal(attributeType(attrSchema))
+		}
+		for bType, blockSchema := range db.Schema.Blocks {
+			vals[bType] = nestedBlocksValue(blockSchema, nil)
+		}
+	}
+
+	for name, attr := range db.Attributes {
+		vals[name] = attr.Value
+	}
+
+	nested := make(map[string][]*decodedBlock, 0)
+	for _, block := range db.Blocks {
+		nested[block.Type] = append(nested[block.Type], block)
+	}
+	for bType, blocks := range nested {
+		var blockSchema *schema.BlockSchema
+		if db.Schema != nil {
+			blockSchema = db.Schema.Blocks[bType]
+		}
+		vals[bType] = nestedBlocksValue(blockSchema, blocks)
+	}
+
+	return vals
+}
+
+// nestedBlocksValue returns the value of nested blocks of a single type
+// depending on the type of the block declared by the schema, i.e. an object
+// for a single block, an object of blocks keyed by their label for maps,
+// and a tuple of objects otherwise, e.g. versioning[0].enabled
+func nestedBlocksValue(blockSchema *schema.BlockSchema, blocks []*decodedBlock) cty.Value {
+	if blockSchema != nil {
+		switch blockSchema.Type {
+		case schema.BlockTypeObject:
+			if len(blocks) == 0 {
+				return cty.NullVal(cty.DynamicPseudoType)
+			}
+			return blocks[0].value()
+		case schema.BlockTypeMap:
+			vals := make(map[string]cty.Value, len(blocks))
+			for _, block := range blocks {
+				if len(block.Labels) > 0 {
+					vals[block.Labels[0]] = block.value()
+				}
+			}
+			return cty.ObjectVal(vals)
+		}
+	}
+
+	vals := make([]cty.Value, 0, len(blocks))
+	for _, block := range blocks {
+		vals = append(vals, block.value())
+	}
+	return cty.TupleVal(vals)
+}
+
+func attributeType(attrSchema *schema.AttributeSchema) cty.Type {
+	if attrSchema != nil {
+		if tc, ok := attrSchema.Constraint.(schema.TypeAwareConstraint); ok {
+			if ty, ok := tc.ConstraintType(); ok {
+				return ty
+			}
+		}
+	}
+	return cty.DynamicPseudoType
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package lint provides the interface of rules checking modules,
+// such as the built-in schema validation or (house) rules served
+// by plugins running as separate processes.
+package lint
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+)
+
+// Module represents a module to be checked by rules
+type Module struct {
+	Path  string
+	Files ast.ModFiles
+	Meta  state.ModuleMetadata
+
+	// PathContext provides the schema the module files are decoded with
+	PathContext *decoder.PathContext
+
+	// Filenames restricts checks to the given files, e.g. to the file which
+	// changed. All files are checked if there are none.
+	Filenames []string
+}
+
+func (m *Module) checksFile(filename string) bool {
+	if len(m.Filenames) == 0 {
+		return true
+	}
+	for _, name := range m.Filenames {
+		if name == filename {
+			return true
+		}
+	}
+	return false
+}
+
+// Rule checks a module and returns diagnostics for any violations
+type Rule interface {
+	Check(ctx context.Context, mod *Module) (lang.DiagnosticsMap, error)
+}
+
+// Rules is a list of rules which are checked together
+type Rules []Rule
+
+// Check checks the module against all rules. Diagnostics are returned
+// from all rules which succeeded, even if other rules returned an error.
+func (rs Rules) Check(ctx context.Context, mod *Module) (lang.DiagnosticsMap, error) {
+	diags := make(lang.DiagnosticsMap)
+	var errs *multierror.Error
+
+	for _, rule := range rs {
+		ruleDiags, err := rule.Check(ctx, mod)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		for filename, fileDiags := range ruleDiags {
+			if !mod.checksFile(filename) {
+				continue
+			}
+			diags[filename] = append(diags[filename], fileDiags...)
+		}
+	}
+
+	return diags, errs.ErrorOrNil()
+}
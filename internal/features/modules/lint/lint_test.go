@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+)
+
+type staticRule struct {
+	diags lang.DiagnosticsMap
+	err   error
+}
+
+func (r staticRule) Check(ctx context.Context, mod *Module) (lang.DiagnosticsMap, error) {
+	return r.diags, r.err
+}
+
+func TestRules_Check(t *testing.T) {
+	diag := &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Violation",
+	}
+	moduleRules := Rules{
+		staticRule{
+			diags: lang.DiagnosticsMap{
+				"main.tf":      hcl.Diagnostics{diag},
+				"variables.tf": hcl.Diagnostics{diag},
+			},
+		},
+		staticRule{
+			diags: lang.DiagnosticsMap{
+				"main.tf": hcl.Diagnostics{diag},
+			},
+			err: errors.New("plugin exited"),
+		},
+	}
+
+	diags, err := moduleRules.Check(context.Background(), &Module{
+		Filenames: []string{"main.tf"},
+	})
+	if err == nil {
+		t.Fatal("expected error of failed rule")
+	}
+
+	if len(diags) != 1 {
+		t.Fatalf("expected diagnostics of a single file, %d given", len(diags))
+	}
+	if len(diags["main.tf"]) != 2 {
+		t.Fatalf("expected 2 diagnostics, %d given", len(diags["main.tf"]))
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/channel"
+	"github.com/hashicorp/hcl-lang/lang"
+)
+
+// checkTimeout limits how long a plugin may take to check a module
+const checkTimeout = 30 * time.Second
+
+// stopGracePeriod limits how long a plugin may take to exit
+// after its stdin was closed, before it is killed
+var stopGracePeriod = 3 * time.Second
+
+// Plugin is a rule served by a separate process, which is started
+// on first use and kept running until closed. The process receives
+// JSON-RPC requests on stdin and responds on stdout, one message per line.
+//
+// A "check" request carries the module path, its files,
+// metadata and parsed (not schema-decoded) blocks. The response lists diagnostics
+// with the IDs of the rules which produced them.
+type Plugin struct {
+	command string
+	args    []string
+	logger  *log.Logger
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	client *jrpc2.Client
+}
+
+func NewPlugin(command string, args ...string) *Plugin {
+	return &Plugin{
+		command: command,
+		args:    args,
+		logger:  log.New(io.Discard, "", 0),
+	}
+}
+
+func (p *Plugin) SetLogger(logger *log.Logger) {
+	p.logger = logger
+}
+
+func (p *Plugin) String() string {
+	return p.command
+}
+
+func (p *Plugin) Check(ctx context.Context, mod *Module) (lang.DiagnosticsMap, error) {
+	client, err := p.start()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	return check(ctx, client, mod)
+}
+
+func check(ctx context.Context, client *jrpc2.Client, mod *Module) (lang.DiagnosticsMap, error) {
+	var result checkResult
+	err := client.CallResult(ctx, checkMethod, newCheckParams(mod), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.diagnostics(mod), nil
+}
+
+// start starts the process unless it is already running,
+// e.g. it (re)starts the process after it exited
+func (p *Plugin) start() (*jrpc2.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil && !p.client.IsStopped() {
+		return p.client, nil
+	}
+	if p.cmd != nil {
+		// The previous process is likely gone already, but we
+		// don't want to wait for it in case it is not
+		go func(client *jrpc2.Client, cmd *exec.Cmd) {
+			err := p.stop(client, cmd)
+			if err != nil {
+				p.logger.Printf("rule plugin %q exited: %s", p.command, err)
+			}
+		}(p.client, p.cmd)
+		p.client, p.cmd = nil, nil
+	}
+
+	cmd := exec.Command(p.command, p.args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start rule plugin %q: %w", p.command, err)
+	}
+	p.logger.Printf("started rule plugin %q (pid %d)", p.command, cmd.Process.Pid)
+
+	p.cmd = cmd
+	p.client = jrpc2.NewClient(channel.Line(stdout, stdin), nil)
+	return p.client, nil
+}
+
+// Close stops the process. The process is killed
+// if it does not exit within a grace period.
+func (p *Plugin) Close() error {
+	p.mu.Lock()
+	client, cmd := p.client, p.cmd
+	p.client, p.cmd = nil, nil
+	p.mu.Unlock()
+
+	return p.stop(client, cmd)
+}
+
+func (p *Plugin) stop(client *jrpc2.Client, cmd *exec.Cmd) error {
+	if cmd == nil {
+		return nil
+	}
+
+	// Closing the client closes stdin, which plugins are expected
+	// to treat as a signal to exit. It returns once stdout is closed,
+	// i.e. usually once the process exited.
+	closed := make(chan struct{})
+	go func() {
+		client.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(stopGracePeriod):
+		p.logger.Printf("rule plugin %q did not exit within %s, killing it", p.command, stopGracePeriod)
+		err := cmd.Process.Kill()
+		if err != nil {
+			return err
+		}
+		<-closed
+	}
+
+	return cmd.Wait()
+}
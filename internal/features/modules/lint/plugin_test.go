@@ -0,0 +1,274 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/channel"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/hashicorp/terraform-ls/internal/rules"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// requiredTags is a plugin rule reporting resources without tags
+func requiredTags(ctx context.Context, params checkParams) (checkResult, error) {
+	result := checkResult{
+		Diagnostics: make([]pluginDiagnostic, 0),
+	}
+	for _, block := range params.Blocks {
+		if block.Type != "resource" {
+			continue
+		}
+		if _, ok := block.Attributes["tags"]; ok {
+			continue
+		}
+		result.Diagnostics = append(result.Diagnostics, pluginDiagnostic{
+			Rule:     "required-tags",
+			Severity: "error",
+			Summary:  fmt.Sprintf("Missing tags on %s", block.Labels[0]),
+			Range:    block.Range,
+		})
+	}
+	return result, nil
+}
+
+func TestCheck(t *testing.T) {
+	src := []byte(`resource "aws_s3_bucket" "a" {
+  bucket = "a"
+  tags   = { team = "platform" }
+}
+
+resource "aws_s3_bucket" "b" {
+  bucket = "b"
+}
+`)
+	f, diags := hclsyntax.ParseConfig(src, "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	mod := &Module{
+		Path: "/test",
+		Files: ast.ModFiles{
+			"main.tf": f,
+		},
+	}
+
+	clientCh, serverCh := channel.Direct()
+	srv := jrpc2.NewServer(handler.Map{
+		checkMethod: handler.New(requiredTags),
+	}, nil).Start(serverCh)
+	defer srv.Stop()
+	client := jrpc2.NewClient(clientCh, nil)
+	defer client.Close()
+
+	checkDiags, err := check(context.Background(), client, mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDiags := hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Missing tags on aws_s3_bucket",
+			Subject: &hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 6, Column: 1, Byte: 82},
+				End:      hcl.Pos{Line: 8, Column: 2, Byte: 129},
+			},
+			Extra: rules.ID("required-tags"),
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, checkDiags["main.tf"]); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
+
+func TestNewCheckParams_values(t *testing.T) {
+	src := []byte(`resource "aws_s3_bucket" "a" {
+  bucket = "a-${var.env}"
+  tags   = { team = "platform" }
+}
+`)
+	f, diags := hclsyntax.ParseConfig(src, "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	params := newCheckParams(&Module{
+		Path: "/test",
+		Files: ast.ModFiles{
+			"main.tf": f,
+		},
+	})
+
+	if len(params.Blocks) != 1 {
+		t.Fatalf("expected 1 block, %d given", len(params.Blocks))
+	}
+	attrs := params.Blocks[0].Attributes
+
+	bucket := attrs["bucket"]
+	if bucket.Expression != `"a-${var.env}"` {
+		t.Fatalf("unexpected expression: %q", bucket.Expression)
+	}
+	if bucket.Value != nil {
+		t.Fatalf("expected no value of expression with references, got %s", bucket.Value)
+	}
+
+	tags := attrs["tags"]
+	if string(tags.Value) != `{"team":"platform"}` {
+		t.Fatalf("unexpected value: %s", tags.Value)
+	}
+}
+
+func TestNewCheckParams_schema(t *testing.T) {
+	src := []byte(`resource "aws_s3_bucket" "a" {
+  bucket  = "logs"
+  unknown = "x"
+  count   = 2
+
+  versioning {
+    enabled = "true"
+  }
+}
+`)
+	f, diags := hclsyntax.ParseConfig(src, "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	jsonSrc := []byte(`{"resource": {"aws_s3_bucket": {"b": {"bucket": "data"}}}}`)
+	jsonFile, diags := hcljson.Parse(jsonSrc, "main.tf.json")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	params := newCheckParams(&Module{
+		Path: "/test",
+		Files: ast.ModFiles{
+			"main.tf":      f,
+			"main.tf.json": jsonFile,
+		},
+		PathContext: &decoder.PathContext{
+			Schema: testModuleSchema(),
+		},
+	})
+
+	if len(params.Blocks) != 2 {
+		t.Fatalf("expected 2 blocks, %d given", len(params.Blocks))
+	}
+
+	attrs := params.Blocks[0].Attributes
+	if _, ok := attrs["unknown"]; ok {
+		t.Fatal("expected attribute unknown to the schema to be left out")
+	}
+	if string(attrs["bucket"].Type) != `"string"` {
+		t.Fatalf("unexpected type of bucket: %s", attrs["bucket"].Type)
+	}
+	if string(attrs["count"].Value) != `2` {
+		t.Fatalf("unexpected value of count: %s", attrs["count"].Value)
+	}
+
+	nested := params.Blocks[0].Blocks
+	if len(nested) != 1 || nested[0].Type != "versioning" {
+		t.Fatalf("unexpected nested blocks: %#v", nested)
+	}
+	if string(nested[0].Attributes["enabled"].Value) != `true` {
+		t.Fatalf("expected value converted to bool, got %s", nested[0].Attributes["enabled"].Value)
+	}
+
+	jsonBlock := params.Blocks[1]
+	if diff := cmp.Diff([]string{"aws_s3_bucket", "b"}, jsonBlock.Labels); diff != "" {
+		t.Fatalf("unexpected labels of JSON block: %s", diff)
+	}
+	if string(jsonBlock.Attributes["bucket"].Value) != `"data"` {
+		t.Fatalf("unexpected value of bucket: %s", jsonBlock.Attributes["bucket"].Value)
+	}
+}
+
+// testModuleSchema returns a schema of resources with the aws_s3_bucket type
+func testModuleSchema() *schema.BodySchema {
+	bucketKey := schema.NewSchemaKey(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "aws_s3_bucket"},
+		},
+	})
+	return &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"resource": {
+				Labels: []*schema.LabelSchema{
+					{Name: "type", IsDepKey: true},
+					{Name: "name"},
+				},
+				Body: &schema.BodySchema{
+					Extensions: &schema.BodyExtensions{
+						Count:   true,
+						ForEach: true,
+					},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					bucketKey: {
+						Attributes: map[string]*schema.AttributeSchema{
+							"bucket": {Constraint: schema.LiteralType{Type: cty.String}},
+							"tags":   {Constraint: schema.Map{Elem: schema.LiteralType{Type: cty.String}}},
+						},
+						Blocks: map[string]*schema.BlockSchema{
+							"versioning": {
+								Type: schema.BlockTypeList,
+								Body: &schema.BodySchema{
+									Attributes: map[string]*schema.AttributeSchema{
+										"enabled": {Constraint: schema.LiteralType{Type: cty.Bool}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPluginClose_hung(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is not available on Windows")
+	}
+
+	gracePeriod := stopGracePeriod
+	stopGracePeriod = 100 * time.Millisecond
+	t.Cleanup(func() {
+		stopGracePeriod = gracePeriod
+	})
+
+	// sleep ignores stdin and would only exit after a minute
+	p := NewPlugin("sleep", "60")
+	_, err := p.start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- p.Close()
+	}()
+
+	select {
+	case err := <-closed:
+		if err == nil {
+			t.Fatal("expected error for killed plugin")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out closing plugin")
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/rules"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// PolicyFile is a rule checking modules against policies declared
+// in an HCL file, which is read again after [PolicyFile.Reload], e.g.
+//
+//	rule "s3-bucket-team-tag" {
+//	  block    = "resource"
+//	  labels   = ["aws_s3_bucket"]
+//	  severity = "error"
+//
+//	  assert {
+//	    condition = contains(keys(try(self.tags, {})), "team")
+//	    message   = "S3 buckets must be tagged with a team"
+//	  }
+//	}
+type PolicyFile struct {
+	path string
+
+	mu       sync.Mutex
+	loaded   bool
+	policies []policy
+	err      error
+}
+
+func NewPolicyFile(path string) *PolicyFile {
+	return &PolicyFile{
+		path: path,
+	}
+}
+
+func (pf *PolicyFile) String() string {
+	return pf.path
+}
+
+// Path returns the path to the policy file
+func (pf *PolicyFile) Path() string {
+	return pf.path
+}
+
+// Reload makes the policy file to be read again
+// on next check, e.g. after its content changed
+func (pf *PolicyFile) Reload() {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	pf.loaded = false
+	pf.policies = nil
+	pf.err = nil
+}
+
+type policyFileSchema struct {
+	Policies []policy `hcl:"rule,block"`
+}
+
+type policy struct {
+	ID       string   `hcl:"id,label"`
+	Block    string   `hcl:"block"`
+	Labels   []string `hcl:"labels,optional"`
+	Severity string   `hcl:"severity,optional"`
+	Asserts  []assert `hcl:"assert,block"`
+}
+
+type assert struct {
+	Condition hcl.Expression `hcl:"condition"`
+	Message   string         `hcl:"message"`
+}
+
+// parsePolicies parses policies from the given source of a policy file
+func parsePolicies(src []byte, filename string) ([]policy, error) {
+	f, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var file policyFileSchema
+	diags = gohcl.DecodeBody(f.Body, nil, &file)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	for _, p := range file.Policies {
+		switch p.Severity {
+		case "", string(rules.SeverityWarning), string(rules.SeverityError):
+		default:
+			return nil, fmt.Errorf("%s: expected severity of rule %q to be %q or %q, got %q",
+				filename, p.ID, rules.SeverityWarning, rules.SeverityError, p.Severity)
+		}
+	}
+
+	return file.Policies, nil
+}
+
+// load returns policies of the file, reading it
+// if it wasn't read yet since it was (re)loaded
+func (pf *PolicyFile) load() ([]policy, error) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	if pf.loaded {
+		return pf.policies, pf.err
+	}
+
+	src, err := os.ReadFile(pf.path)
+	if err == nil {
+		pf.policies, pf.err = parsePolicies(src, pf.path)
+	} else {
+		pf.policies, pf.err = nil, err
+	}
+	pf.loaded = true
+
+	return pf.policies, pf.err
+}
+
+func (pf *PolicyFile) Check(ctx context.Context, mod *Module) (lang.DiagnosticsMap, error) {
+	policies, err := pf.load()
+	if err != nil {
+		return nil, err
+	}
+
+	diags := make(lang.DiagnosticsMap)
+	var errs *multierror.Error
+
+	for _, f := range decodeModule(mod) {
+		if !mod.checksFile(f.Name) {
+			continue
+		}
+
+		fileDiags := make(hcl.Diagnostics, 0)
+		for _, block := range f.Blocks {
+			for _, p := range policies {
+				if !p.selects(block) {
+					continue
+				}
+				blockDiags, err := p.check(block)
+				if err != nil {
+					errs = multierror.Append(errs, err)
+				}
+				fileDiags = append(fileDiags, blockDiags...)
+			}
+		}
+		diags[f.Name] = fileDiags
+	}
+
+	return diags, errs.ErrorOrNil()
+}
+
+// selects returns true if the policy applies to the given block,
+// i.e. its type matches and labels match by position ("*" matching any)
+func (p policy) selects(block *decodedBlock) bool {
+	if block.Type != p.Block {
+		return false
+	}
+	if len(p.Labels) > len(block.Labels) {
+		return false
+	}
+	for i, label := range p.Labels {
+		if label != "*" && label != block.Labels[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p policy) check(block *decodedBlock) (hcl.Diagnostics, error) {
+	diags := make(hcl.Diagnostics, 0)
+	var errs *multierror.Error
+
+	for _, a := range p.Asserts {
+		missingAttrs := missingAttributes(a.Condition, block)
+		val, valDiags := a.Condition.Value(blockEvalContext(block, missingAttrs))
+
+		// Conditions which cannot be evaluated because of attributes missing
+		// in the block are violated, as the block lacks what the rule requires
+		isViolated := len(missingAttrs) > 0 && (valDiags.HasErrors() || val.IsNull())
+		if !isViolated {
+			if valDiags.HasErrors() {
+				errs = multierror.Append(errs, fmt.Errorf("rule %q: %w", p.ID, valDiags))
+				continue
+			}
+			if !val.IsKnown() {
+				// Values which are only known after apply cannot be checked
+				continue
+			}
+			if val.IsNull() || !val.Type().Equals(cty.Bool) {
+				errs = multierror.Append(errs, fmt.Errorf("rule %q: condition must be a boolean", p.ID))
+				continue
+			}
+			if val.True() {
+				continue
+			}
+		}
+
+		severity := hcl.DiagWarning
+		if p.Severity == string(rules.SeverityError) {
+			severity = hcl.DiagError
+		}
+		subject := block.DefRange
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: severity,
+			Summary:  a.Message,
+			Detail:   fmt.Sprintf("Violation of rule %q", p.ID),
+			Subject:  &subject,
+			Extra:    rules.ID(p.ID),
+		})
+	}
+
+	return diags, errs.ErrorOrNil()
+}
+
+// missingAttributes returns names of attributes (or nested blocks)
+// which the given condition refers to via self, but which are missing
+// in the block
+func missingAttributes(condition hcl.Expression, block *decodedBlock) []string {
+	missing := make([]string, 0)
+	for _, traversal := range condition.Variables() {
+		if traversal.RootName() != "self" || len(traversal) < 2 {
+			continue
+		}
+
+		var name string
+		switch step := traversal[1].(type) {
+		case hcl.TraverseAttr:
+			name = step.Name
+		case hcl.TraverseIndex:
+			if !step.Key.Type().Equals(cty.String) || step.Key.IsNull() {
+				continue
+			}
+			name = step.Key.AsString()
+		default:
+			continue
+		}
+
+		if block.has(name) || slices.Contains(missing, name) {
+			continue
+		}
+		missing = append(missing, name)
+	}
+	return missing
+}
+
+// has returns true if the block contains an attribute
+// or nested block of the given name
+func (db *decodedBlock) has(name string) bool {
+	if _, ok := db.Attributes[name]; ok {
+		return true
+	}
+	for _, nested := range db.Blocks {
+		if nested.Type == name {
+			return true
+		}
+	}
+	return false
+}
+
+// blockEvalContext returns the context conditions of policies are
+// evaluated in for the given block. Values of attributes which
+// cannot be evaluated statically (e.g. references) are unknown
+// and values of the given missing attributes are null.
+func blockEvalContext(block *decodedBlock, missingAttrs []string) *hcl.EvalContext {
+	attrNames := make([]string, 0, len(block.Attributes))
+	for name := range block.Attributes {
+		attrNames = append(attrNames, name)
+	}
+	sort.Strings(attrNames)
+
+	self := block.values()
+	for _, name := range missingAttrs {
+		if _, ok := self[name]; !ok {
+			self[name] = cty.NullVal(cty.DynamicPseudoType)
+		}
+	}
+
+	blockTypes := make([]string, 0, len(block.Blocks))
+	for _, nested := range block.Blocks {
+		blockTypes = append(blockTypes, nested.Type)
+	}
+
+	return &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"type":       cty.StringVal(block.Type),
+			"labels":     stringList(block.Labels),
+			"attributes": stringList(attrNames),
+			"blocks":     stringList(blockTypes),
+			"self":       cty.ObjectVal(self),
+		},
+		Functions: policyFunctions,
+	}
+}
+
+func stringList(values []string) cty.Value {
+	if len(values) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	list := make([]cty.Value, 0, len(values))
+	for _, v := range values {
+		list = append(list, cty.StringVal(v))
+	}
+	return cty.ListVal(list)
+}
+
+// staticValue returns the value of the given expression,
+// if it can be evaluated without any references or functions
+func staticValue(expr hcl.Expression) (cty.Value, bool) {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+	return val, true
+}
+
+var policyFunctions = map[string]function.Function{
+	"can":        tryfunc.CanFunc,
+	"contains":   stdlib.ContainsFunc,
+	"join":       stdlib.JoinFunc,
+	"keys":       stdlib.KeysFunc,
+	"length":     stdlib.LengthFunc,
+	"lookup":     stdlib.LookupFunc,
+	"lower":      stdlib.LowerFunc,
+	"regex":      stdlib.RegexFunc,
+	"split":      stdlib.SplitFunc,
+	"try":        tryfunc.TryFunc,
+	"trimprefix": stdlib.TrimPrefixFunc,
+	"trimsuffix": stdlib.TrimSuffixFunc,
+	"upper":      stdlib.UpperFunc,
+	"values":     stdlib.ValuesFunc,
+}
@@ -0,0 +1,308 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/hashicorp/terraform-ls/internal/rules"
+)
+
+func TestPolicyFile_Check(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.hcl")
+	err := os.WriteFile(policyPath, []byte(`
+rule "s3-bucket-team-tag" {
+  block    = "resource"
+  labels   = ["aws_s3_bucket"]
+  severity = "error"
+
+  assert {
+    condition = contains(keys(try(self.tags, {})), "team")
+    message   = "S3 buckets must be tagged with a team"
+  }
+}
+
+rule "resource-naming" {
+  block  = "resource"
+  labels = ["*", "*"]
+
+  assert {
+    condition = can(regex("^[a-z_]+$", labels[1]))
+    message   = "Resource names must be snake_case"
+  }
+}
+
+rule "bucket-name" {
+  block  = "resource"
+  labels = ["aws_s3_bucket"]
+
+  assert {
+    condition = contains(attributes, "bucket")
+    message   = "S3 buckets must be named"
+  }
+}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := []byte(`resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  tags   = { team = "platform" }
+}
+
+resource "aws_s3_bucket" "Assets" {
+  bucket = var.assets_bucket
+}
+
+resource "aws_s3_bucket" "data" {
+  tags = var.tags
+}
+`)
+	f, diags := hclsyntax.ParseConfig(src, "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	mod := &Module{
+		Path: "/test",
+		Files: ast.ModFiles{
+			"main.tf": f,
+		},
+	}
+
+	pf := NewPolicyFile(policyPath)
+	checkDiags, err := pf.Check(context.Background(), mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type ruleAtLine struct {
+		Rule     rules.ID
+		Line     int
+		Severity hcl.DiagnosticSeverity
+	}
+	violations := make([]ruleAtLine, 0)
+	for _, diag := range checkDiags["main.tf"] {
+		id, _ := rules.RuleID(diag)
+		violations = append(violations, ruleAtLine{
+			Rule:     id,
+			Line:     diag.Subject.Start.Line,
+			Severity: diag.Severity,
+		})
+	}
+
+	// Values of references are unknown, i.e. the tags
+	// of the "data" bucket are not reported
+	expectedViolations := []ruleAtLine{
+		{"s3-bucket-team-tag", 6, hcl.DiagError},
+		{"resource-naming", 6, hcl.DiagWarning},
+		{"bucket-name", 10, hcl.DiagWarning},
+	}
+	if diff := cmp.Diff(expectedViolations, violations); diff != "" {
+		t.Fatalf("unexpected violations: %s", diff)
+	}
+}
+
+func TestParsePolicies_invalidSeverity(t *testing.T) {
+	_, err := parsePolicies([]byte(`
+rule "a" {
+  block    = "resource"
+  severity = "hint"
+}
+`), "policy.hcl")
+	if err == nil {
+		t.Fatal("expected invalid severity to result in error")
+	}
+}
+
+func TestPolicyFile_Check_missingAttribute(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.hcl")
+	err := os.WriteFile(policyPath, []byte(`
+rule "encrypted-volumes" {
+  block  = "resource"
+  labels = ["aws_ebs_volume"]
+
+  assert {
+    condition = self.encrypted
+    message   = "Volumes must be encrypted"
+  }
+}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := []byte(`resource "aws_ebs_volume" "encrypted" {
+  encrypted = true
+}
+
+resource "aws_ebs_volume" "missing" {
+  size = 10
+}
+`)
+	f, diags := hclsyntax.ParseConfig(src, "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	mod := &Module{
+		Path: "/test",
+		Files: ast.ModFiles{
+			"main.tf": f,
+		},
+	}
+
+	pf := NewPolicyFile(policyPath)
+	checkDiags, err := pf.Check(context.Background(), mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(checkDiags["main.tf"]) != 1 {
+		t.Fatalf("expected 1 violation, given: %#v", checkDiags["main.tf"])
+	}
+	if line := checkDiags["main.tf"][0].Subject.Start.Line; line != 5 {
+		t.Fatalf("expected violation on line 5, given: %d", line)
+	}
+}
+
+func TestPolicyFile_Check_schema(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.hcl")
+	err := os.WriteFile(policyPath, []byte(`
+rule "s3-bucket-versioning" {
+  block  = "resource"
+  labels = ["aws_s3_bucket"]
+
+  assert {
+    condition = self.versioning[0].enabled
+    message   = "S3 buckets must be versioned"
+  }
+}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := []byte(`resource "aws_s3_bucket" "enabled" {
+  versioning {
+    enabled = "true"
+  }
+}
+
+resource "aws_s3_bucket" "disabled" {
+  versioning {
+    enabled = false
+  }
+}
+
+resource "aws_s3_bucket" "missing" {
+  bucket = "missing"
+}
+`)
+	f, diags := hclsyntax.ParseConfig(src, "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	jsonSrc := []byte(`{
+  "resource": {
+    "aws_s3_bucket": {
+      "json": {
+        "versioning": [{ "enabled": false }]
+      }
+    }
+  }
+}`)
+	jsonFile, diags := hcljson.Parse(jsonSrc, "main.tf.json")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	mod := &Module{
+		Path: "/test",
+		Files: ast.ModFiles{
+			"main.tf":      f,
+			"main.tf.json": jsonFile,
+		},
+		PathContext: &decoder.PathContext{
+			Schema: testModuleSchema(),
+		},
+	}
+
+	pf := NewPolicyFile(policyPath)
+	checkDiags, err := pf.Check(context.Background(), mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := make([]int, 0)
+	for _, diag := range checkDiags["main.tf"] {
+		lines = append(lines, diag.Subject.Start.Line)
+	}
+	if diff := cmp.Diff([]int{7, 13}, lines); diff != "" {
+		t.Fatalf("unexpected violations: %s", diff)
+	}
+	if len(checkDiags["main.tf.json"]) != 1 {
+		t.Fatalf("expected 1 violation in JSON file, given: %#v", checkDiags["main.tf.json"])
+	}
+}
+
+func TestPolicyFile_Reload(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.hcl")
+	writePolicy := func(label string) {
+		err := os.WriteFile(policyPath, []byte(`
+rule "forbidden" {
+  block  = "resource"
+  labels = ["`+label+`"]
+
+  assert {
+    condition = false
+    message   = "Forbidden resource"
+  }
+}
+`), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	writePolicy("aws_instance")
+
+	f, diags := hclsyntax.ParseConfig([]byte(`resource "aws_s3_bucket" "test" {}
+`), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	mod := &Module{
+		Path: "/test",
+		Files: ast.ModFiles{
+			"main.tf": f,
+		},
+	}
+
+	pf := NewPolicyFile(policyPath)
+	checkDiags, err := pf.Check(context.Background(), mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkDiags["main.tf"]) != 0 {
+		t.Fatalf("expected no violations, given: %#v", checkDiags["main.tf"])
+	}
+
+	writePolicy("aws_s3_bucket")
+	pf.Reload()
+
+	checkDiags, err = pf.Check(context.Background(), mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkDiags["main.tf"]) != 1 {
+		t.Fatalf("expected reloaded policy to be violated, given: %#v", checkDiags["main.tf"])
+	}
+}
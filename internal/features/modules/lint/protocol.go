@@ -0,0 +1,245 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/hashicorp/terraform-ls/internal/rules"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// checkMethod is the JSON-RPC method plugins are called with
+// to check a module, with checkParams and returning checkResult
+const checkMethod = "check"
+
+type checkParams struct {
+	Path      string         `json:"path"`
+	Files     []pluginFile   `json:"files"`
+	Metadata  pluginMetadata `json:"metadata"`
+	Blocks    []pluginBlock  `json:"blocks"`
+	Filenames []string       `json:"filenames,omitempty"`
+}
+
+type pluginFile struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+type pluginMetadata struct {
+	CoreRequirements     string            `json:"core_requirements,omitempty"`
+	Backend              string            `json:"backend,omitempty"`
+	ProviderRequirements map[string]string `json:"provider_requirements"`
+	Variables            []string          `json:"variables"`
+	Outputs              []string          `json:"outputs"`
+	ModuleCalls          map[string]string `json:"module_calls"`
+}
+
+// pluginBlock is a block decoded against the schema of the module,
+// with types of its attributes declared by the schema and values
+// where they can be evaluated statically.
+//
+// Attributes and nested blocks unknown to the schema are left out.
+// Blocks whose schema is not known (e.g. resources of providers
+// which were not installed) are passed as written.
+type pluginBlock struct {
+	Type       string                     `json:"type"`
+	Labels     []string                   `json:"labels"`
+	Range      pluginRange                `json:"range"`
+	Attributes map[string]pluginAttribute `json:"attributes"`
+	Blocks     []pluginBlock              `json:"blocks"`
+}
+
+type pluginAttribute struct {
+	Range pluginRange `json:"range"`
+	// Expression is the source code of the expression
+	Expression string `json:"expression"`
+	// Type is the JSON representation of the type declared by the schema, if any
+	Type json.RawMessage `json:"type,omitempty"`
+	// Value is the JSON representation of a known value, if any,
+	// converted to the declared type
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type pluginRange struct {
+	Filename string    `json:"filename"`
+	Start    pluginPos `json:"start"`
+	End      pluginPos `json:"end"`
+}
+
+type pluginPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+type checkResult struct {
+	Diagnostics []pluginDiagnostic `json:"diagnostics"`
+}
+
+type pluginDiagnostic struct {
+	// Rule is the ID of the rule, to configure its severity or suppress it
+	Rule string `json:"rule"`
+	// Severity is either "error" or "warning" (default)
+	Severity string      `json:"severity"`
+	Summary  string      `json:"summary"`
+	Detail   string      `json:"detail"`
+	Range    pluginRange `json:"range"`
+}
+
+func newCheckParams(mod *Module) checkParams {
+	params := checkParams{
+		Path:      mod.Path,
+		Files:     make([]pluginFile, 0, len(mod.Files)),
+		Metadata:  newPluginMetadata(mod),
+		Blocks:    make([]pluginBlock, 0),
+		Filenames: mod.Filenames,
+	}
+
+	for _, f := range decodeModule(mod) {
+		params.Files = append(params.Files, pluginFile{
+			Name:    f.Name,
+			Content: string(f.Bytes),
+		})
+		for _, block := range f.Blocks {
+			params.Blocks = append(params.Blocks, newPluginBlock(block, f.Bytes))
+		}
+	}
+
+	return params
+}
+
+func newPluginMetadata(mod *Module) pluginMetadata {
+	meta := pluginMetadata{
+		ProviderRequirements: make(map[string]string),
+		Variables:            make([]string, 0, len(mod.Meta.Variables)),
+		Outputs:              make([]string, 0, len(mod.Meta.Outputs)),
+		ModuleCalls:          make(map[string]string),
+	}
+	if mod.Meta.CoreRequirements != nil {
+		meta.CoreRequirements = mod.Meta.CoreRequirements.String()
+	}
+	if mod.Meta.Backend != nil {
+		meta.Backend = mod.Meta.Backend.Type
+	}
+	for provider, constraints := range mod.Meta.ProviderRequirements {
+		meta.ProviderRequirements[provider.String()] = constraints.String()
+	}
+	for name := range mod.Meta.Variables {
+		meta.Variables = append(meta.Variables, name)
+	}
+	sort.Strings(meta.Variables)
+	for name := range mod.Meta.Outputs {
+		meta.Outputs = append(meta.Outputs, name)
+	}
+	sort.Strings(meta.Outputs)
+	for name, mc := range mod.Meta.ModuleCalls {
+		meta.ModuleCalls[name] = mc.RawSourceAddr
+	}
+	return meta
+}
+
+func newPluginBlock(block *decodedBlock, src []byte) pluginBlock {
+	pb := pluginBlock{
+		Type:       block.Type,
+		Labels:     block.Labels,
+		Range:      newPluginRange(block.Range),
+		Attributes: make(map[string]pluginAttribute, len(block.Attributes)),
+		Blocks:     make([]pluginBlock, 0, len(block.Blocks)),
+	}
+
+	for name, attr := range block.Attributes {
+		exprRng := attr.Expr.Range()
+		pa := pluginAttribute{
+			Range:      newPluginRange(attr.Range),
+			Expression: string(exprRng.SliceBytes(src)),
+		}
+		if attr.Type != cty.DynamicPseudoType {
+			ty, err := ctyjson.MarshalType(attr.Type)
+			if err == nil {
+				pa.Type = ty
+			}
+		}
+		if attr.Value.IsWhollyKnown() {
+			value, err := ctyjson.SimpleJSONValue{Value: attr.Value}.MarshalJSON()
+			if err == nil {
+				pa.Value = value
+			}
+		}
+		pb.Attributes[name] = pa
+	}
+
+	for _, nested := range block.Blocks {
+		pb.Blocks = append(pb.Blocks, newPluginBlock(nested, src))
+	}
+
+	return pb
+}
+
+func newPluginRange(rng hcl.Range) pluginRange {
+	return pluginRange{
+		Filename: rng.Filename,
+		Start: pluginPos{
+			Line:   rng.Start.Line,
+			Column: rng.Start.Column,
+			Byte:   rng.Start.Byte,
+		},
+		End: pluginPos{
+			Line:   rng.End.Line,
+			Column: rng.End.Column,
+			Byte:   rng.End.Byte,
+		},
+	}
+}
+
+func (r pluginRange) hclRange() hcl.Range {
+	return hcl.Range{
+		Filename: r.Filename,
+		Start: hcl.Pos{
+			Line:   r.Start.Line,
+			Column: r.Start.Column,
+			Byte:   r.Start.Byte,
+		},
+		End: hcl.Pos{
+			Line:   r.End.Line,
+			Column: r.End.Column,
+			Byte:   r.End.Byte,
+		},
+	}
+}
+
+// diagnostics turns the result into diagnostics of the given module,
+// attributed to the rules reported by the plugin. Diagnostics for
+// unknown files are ignored.
+func (r checkResult) diagnostics(mod *Module) lang.DiagnosticsMap {
+	diags := make(lang.DiagnosticsMap)
+	for _, pd := range r.Diagnostics {
+		if _, ok := mod.Files[ast.ModFilename(pd.Range.Filename)]; !ok {
+			continue
+		}
+
+		severity := hcl.DiagWarning
+		if pd.Severity == "error" {
+			severity = hcl.DiagError
+		}
+		rng := pd.Range.hclRange()
+
+		diag := &hcl.Diagnostic{
+			Severity: severity,
+			Summary:  pd.Summary,
+			Detail:   pd.Detail,
+			Subject:  &rng,
+		}
+		if pd.Rule != "" {
+			diag.Extra = rules.ID(pd.Rule)
+		}
+		diags[pd.Range.Filename] = append(diags[pd.Range.Filename], diag)
+	}
+	return diags
+}
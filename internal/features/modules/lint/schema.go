@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+)
+
+// SchemaRule is the built-in rule validating module files against
+// their schema, by walking them with the validators of the path context
+// (such as MissingRequiredAttribute of the validations package).
+type SchemaRule struct{}
+
+func (SchemaRule) Check(ctx context.Context, mod *Module) (lang.DiagnosticsMap, error) {
+	d := decoder.NewDecoder(&modulePathReader{mod: mod})
+	pathDecoder, err := d.Path(mod.lspPath())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(mod.Filenames) == 0 {
+		return pathDecoder.Validate(ctx)
+	}
+
+	diags := make(lang.DiagnosticsMap, len(mod.Filenames))
+	for _, filename := range mod.Filenames {
+		fileDiags, err := pathDecoder.ValidateFile(ctx, filename)
+		if err != nil {
+			return diags, err
+		}
+		diags[filename] = fileDiags
+	}
+	return diags, nil
+}
+
+func (m *Module) lspPath() lang.Path {
+	return lang.Path{
+		Path:       m.Path,
+		LanguageID: ilsp.Terraform.String(),
+	}
+}
+
+// modulePathReader provides the path context of a single module
+// to the decoder, so that it's only built once for all rules
+type modulePathReader struct {
+	mod *Module
+}
+
+func (r *modulePathReader) Paths(ctx context.Context) []lang.Path {
+	return []lang.Path{r.mod.lspPath()}
+}
+
+func (r *modulePathReader) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	return r.mod.PathContext, nil
+}
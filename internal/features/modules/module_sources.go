@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package modules
+
+import (
+	fdecoder "github.com/hashicorp/terraform-ls/internal/features/modules/decoder"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+)
+
+// installedModuleSources lists registry modules installed
+// (i.e. recorded in .terraform/modules/modules.json)
+// by any module in the workspace
+type installedModuleSources struct {
+	modStore    *state.ModuleStore
+	rootFeature fdecoder.RootReader
+}
+
+func (s installedModuleSources) RegistryModuleSources() ([]tfaddr.Module, error) {
+	mods, err := s.modStore.List()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]tfaddr.Module, 0)
+	for _, mod := range mods {
+		installed, err := s.rootFeature.InstalledModuleCalls(mod.Path())
+		if err != nil {
+			// not a root module
+			continue
+		}
+		for _, mc := range installed {
+			if addr, ok := mc.SourceAddr.(tfaddr.Module); ok {
+				sources = append(sources, addr)
+			}
+		}
+	}
+
+	return sources, nil
+}
@@ -7,24 +7,34 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
+	"sort"
+	"sync"
 
 	"github.com/algolia/algoliasearch-client-go/v3/algolia/search"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/terraform-ls/internal/algolia"
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
 	"github.com/hashicorp/terraform-ls/internal/document"
 	"github.com/hashicorp/terraform-ls/internal/eventbus"
 	fdecoder "github.com/hashicorp/terraform-ls/internal/features/modules/decoder"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/hooks"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/jobs"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/lint"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/state"
+	"github.com/hashicorp/terraform-ls/internal/graph"
 	"github.com/hashicorp/terraform-ls/internal/job"
 	"github.com/hashicorp/terraform-ls/internal/langserver/diagnostics"
+	"github.com/hashicorp/terraform-ls/internal/modulesearch"
+	"github.com/hashicorp/terraform-ls/internal/pathcmp"
 	"github.com/hashicorp/terraform-ls/internal/registry"
+	"github.com/hashicorp/terraform-ls/internal/schemas"
 	globalState "github.com/hashicorp/terraform-ls/internal/state"
 	"github.com/hashicorp/terraform-ls/internal/telemetry"
+	"github.com/hashicorp/terraform-ls/internal/tflint"
 	"github.com/hashicorp/terraform-schema/backend"
 	tfmod "github.com/hashicorp/terraform-schema/module"
 )
@@ -41,6 +51,16 @@ type ModulesFeature struct {
 	stateStore     *globalState.StateStore
 	registryClient registry.Client
 	fs             jobs.ReadOnlyFS
+	schemaFS       fs.ReadDirFS
+
+	moduleCatalogFile   string
+	offlineModuleSearch bool
+
+	customRulesMu sync.RWMutex
+	rulePlugins   []*lint.Plugin
+	policyFiles   []*lint.PolicyFile
+
+	linter tflint.Linter
 }
 
 func NewModulesFeature(eventbus *eventbus.EventBus, stateStore *globalState.StateStore, fs jobs.ReadOnlyFS, rootFeature fdecoder.RootReader, registryClient registry.Client) (*ModulesFeature, error) {
@@ -58,6 +78,7 @@ func NewModulesFeature(eventbus *eventbus.EventBus, stateStore *globalState.Stat
 		stateStore:     stateStore,
 		rootFeature:    rootFeature,
 		fs:             fs,
+		schemaFS:       schemas.FS,
 		registryClient: registryClient,
 	}, nil
 }
@@ -67,6 +88,150 @@ func (f *ModulesFeature) SetLogger(logger *log.Logger) {
 	f.Store.SetLogger(logger)
 }
 
+// SetSchemaFS sets the file system provider schemas
+// are preloaded from, which defaults to the embedded schemas
+func (f *ModulesFeature) SetSchemaFS(schemaFS fs.ReadDirFS) {
+	f.schemaFS = schemaFS
+}
+
+// SetModuleCatalogFile sets the path to a file listing (private)
+// registry modules to offer in completion of module sources,
+// see [modulesearch.Local]
+func (f *ModulesFeature) SetModuleCatalogFile(path string) {
+	f.moduleCatalogFile = path
+}
+
+// SetOfflineModuleSearch disables searching the hosted index of
+// registry modules, i.e. only modules known locally are searched
+func (f *ModulesFeature) SetOfflineModuleSearch(offline bool) {
+	f.offlineModuleSearch = offline
+}
+
+// SetRulePlugins sets plugins serving custom rules, which modules
+// are checked against when (enhanced) validation is enabled
+func (f *ModulesFeature) SetRulePlugins(plugins []*lint.Plugin) {
+	for _, plugin := range plugins {
+		plugin.SetLogger(f.logger)
+	}
+
+	f.customRulesMu.Lock()
+	defer f.customRulesMu.Unlock()
+	f.rulePlugins = plugins
+}
+
+// SetPolicyFiles sets paths to files declaring rules, which modules
+// are checked against when (enhanced) validation is enabled
+func (f *ModulesFeature) SetPolicyFiles(paths []string) {
+	policyFiles := make([]*lint.PolicyFile, 0, len(paths))
+	for _, path := range paths {
+		policyFiles = append(policyFiles, lint.NewPolicyFile(path))
+	}
+
+	f.customRulesMu.Lock()
+	defer f.customRulesMu.Unlock()
+	f.policyFiles = policyFiles
+}
+
+// PolicyFilePaths returns paths to files declaring rules,
+// e.g. so that they can be watched for changes
+func (f *ModulesFeature) PolicyFilePaths() []string {
+	f.customRulesMu.RLock()
+	defer f.customRulesMu.RUnlock()
+
+	paths := make([]string, 0, len(f.policyFiles))
+	for _, policyFile := range f.policyFiles {
+		paths = append(paths, policyFile.Path())
+	}
+	return paths
+}
+
+// PolicyFileChange reloads the policy file at the given path, if it is one,
+// and schedules checking open modules against custom rules again.
+// It reports whether the path is a policy file.
+func (f *ModulesFeature) PolicyFileChange(ctx context.Context, path string) (bool, error) {
+	f.customRulesMu.RLock()
+	isPolicyFile := false
+	for _, policyFile := range f.policyFiles {
+		if pathcmp.PathEquals(policyFile.Path(), path) {
+			policyFile.Reload()
+			isPolicyFile = true
+		}
+	}
+	f.customRulesMu.RUnlock()
+
+	if !isPolicyFile {
+		return false, nil
+	}
+
+	return true, f.recheckCustomRules(ctx)
+}
+
+// recheckCustomRules schedules checking open modules against custom rules
+// again, e.g. after a policy file changed
+func (f *ModulesFeature) recheckCustomRules(ctx context.Context) error {
+	validationOptions, _ := lsctx.ValidationOptions(ctx)
+	if !validationOptions.EnableEnhancedValidation {
+		return nil
+	}
+
+	mods, err := f.Store.List()
+	if err != nil {
+		return err
+	}
+	customRules := f.customRules()
+	for _, mod := range mods {
+		dir := document.DirHandleFromPath(mod.Path())
+		hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(dir)
+		if err != nil {
+			return err
+		}
+		if !hasOpenDocs {
+			continue
+		}
+
+		pendingIds, err := f.stateStore.JobStore.ListIncompleteJobsForDir(dir)
+		if err != nil {
+			return err
+		}
+		_, err = f.checkCustomRules(ctx, dir, customRules, pendingIds, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetTFLint sets the linter which open modules are linted with
+// when opened or saved. Modules are not linted if it is nil.
+func (f *ModulesFeature) SetTFLint(linter tflint.Linter) {
+	f.linter = linter
+}
+
+// TFLint schedules linting of the module in the given directory
+// via tflint, if enabled, once any pending jobs for it are done
+func (f *ModulesFeature) TFLint(ctx context.Context, dir document.DirHandle) (job.IDs, error) {
+	if f.linter == nil || !f.Store.Exists(dir.Path()) {
+		return job.IDs{}, nil
+	}
+
+	return f.lintModule(ctx, dir, job.IDs{})
+}
+
+func (f *ModulesFeature) customRules() lint.Rules {
+	f.customRulesMu.RLock()
+	defer f.customRulesMu.RUnlock()
+
+	customRules := make(lint.Rules, 0, len(f.rulePlugins)+len(f.policyFiles))
+	for _, plugin := range f.rulePlugins {
+		customRules = append(customRules, plugin)
+	}
+	for _, policyFile := range f.policyFiles {
+		customRules = append(customRules, policyFile)
+	}
+	return customRules
+}
+
 // Start starts the features separate goroutine.
 // It listens to various events from the EventBus and performs corresponding actions.
 func (f *ModulesFeature) Start(ctx context.Context) {
@@ -85,6 +250,12 @@ func (f *ModulesFeature) Start(ctx context.Context) {
 	didChangeWatchedDone := make(chan job.IDs, 10)
 	didChangeWatched := f.eventbus.OnDidChangeWatched("feature.modules", didChangeWatchedDone)
 
+	versionFileChangeDone := make(chan job.IDs, 10)
+	versionFileChange := f.eventbus.OnVersionFileChange("feature.modules", versionFileChangeDone)
+
+	manifestChangeDone := make(chan job.IDs, 10)
+	manifestChange := f.eventbus.OnManifestChange("feature.modules", manifestChangeDone)
+
 	go func() {
 		for {
 			select {
@@ -104,6 +275,14 @@ func (f *ModulesFeature) Start(ctx context.Context) {
 				// TODO? collect errors
 				spawnedIds, _ := f.didChangeWatched(didChangeWatched.Context, didChangeWatched.RawPath, didChangeWatched.ChangeType, didChangeWatched.IsDir)
 				didChangeWatchedDone <- spawnedIds
+			case versionFileChange := <-versionFileChange:
+				// TODO? collect errors
+				spawnedIds, _ := f.versionFileChange(versionFileChange.Context, versionFileChange.Dir)
+				versionFileChangeDone <- spawnedIds
+			case manifestChange := <-manifestChange:
+				// TODO? collect errors
+				spawnedIds, _ := f.manifestChange(manifestChange.Context, manifestChange.Dir)
+				manifestChangeDone <- spawnedIds
 
 			case <-ctx.Done():
 				return
@@ -114,6 +293,15 @@ func (f *ModulesFeature) Start(ctx context.Context) {
 
 func (f *ModulesFeature) Stop() {
 	f.stopFunc()
+
+	f.customRulesMu.RLock()
+	defer f.customRulesMu.RUnlock()
+	for _, plugin := range f.rulePlugins {
+		err := plugin.Close()
+		if err != nil {
+			f.logger.Printf("failed to stop rule plugin %q: %s", plugin, err)
+		}
+	}
 	f.logger.Print("stopped modules feature")
 }
 
@@ -139,6 +327,18 @@ func (f *ModulesFeature) DeclaredModuleCalls(modPath string) (map[string]tfmod.D
 	return f.Store.DeclaredModuleCalls(modPath)
 }
 
+// DependencyGraph returns the graph of resources, data sources,
+// module calls, variables, locals and outputs of the module
+// at the given path.
+func (f *ModulesFeature) DependencyGraph(modPath string) (*graph.Graph, error) {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return fdecoder.DependencyGraph(mod), nil
+}
+
 func (f *ModulesFeature) ProviderRequirements(modPath string) (tfmod.ProviderRequirements, error) {
 	mod, err := f.Store.ModuleRecordByPath(modPath)
 	if err != nil {
@@ -166,6 +366,80 @@ func (f *ModulesFeature) ModuleInputs(modPath string) (map[string]tfmod.Variable
 	return mod.Meta.Variables, nil
 }
 
+// TerraformPlan runs `terraform plan` for the module at the given path
+// with the given var files, reporting progress messages via report.
+func (f *ModulesFeature) TerraformPlan(ctx context.Context, modPath string, varFiles []string, report func(msg string)) error {
+	return jobs.TerraformPlan(ctx, f.Store, modPath, varFiles, report)
+}
+
+// PlannedActions returns the resource actions from the last
+// `terraform plan` run for the module at the given path, if any.
+func (f *ModulesFeature) PlannedActions(modPath string) (state.PlannedActions, error) {
+	mod, err := f.Store.ModuleRecordByPath(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return mod.PlannedActions, nil
+}
+
+// ModuleOutputValueReference returns the reference which the value of
+// the given output of the called module consists of, relative to the called
+// module (e.g. aws_vpc.this.id for output "vpc_id" of module call "vpc").
+// It is only available if the module is resolvable and the output value
+// is a plain reference.
+func (f *ModulesFeature) ModuleOutputValueReference(modPath, callName, outputName string) (fdecoder.OutputReference, bool) {
+	declared, err := f.Store.DeclaredModuleCalls(modPath)
+	if err != nil {
+		return fdecoder.OutputReference{}, false
+	}
+	mc, ok := declared[callName]
+	if !ok {
+		return fdecoder.OutputReference{}, false
+	}
+	mcPath, ok := f.moduleCallPath(modPath, mc)
+	if !ok {
+		return fdecoder.OutputReference{}, false
+	}
+
+	mod, err := f.Store.ModuleRecordByPath(mcPath)
+	if err != nil {
+		return fdecoder.OutputReference{}, false
+	}
+
+	return fdecoder.OutputValueReference(mod.ParsedModuleFiles, outputName)
+}
+
+// ModuleCallResourceAddresses returns addresses of managed resources
+// declared within modules called from the given module, relative
+// to that module (e.g. module.vpc.aws_vpc.this), as targeted
+// by import and moved blocks.
+func (f *ModulesFeature) ModuleCallResourceAddresses(modPath string) []string {
+	addrs := make([]string, 0)
+
+	declared, err := f.Store.DeclaredModuleCalls(modPath)
+	if err != nil {
+		return addrs
+	}
+
+	for name, mc := range declared {
+		mcPath, ok := f.moduleCallPath(modPath, mc)
+		if !ok {
+			continue
+		}
+		mod, err := f.Store.ModuleRecordByPath(mcPath)
+		if err != nil {
+			continue
+		}
+		for _, addr := range fdecoder.ResourceAddresses(mod.ParsedModuleFiles) {
+			addrs = append(addrs, fmt.Sprintf("module.%s.%s", name, addr))
+		}
+	}
+	sort.Strings(addrs)
+
+	return addrs
+}
+
 func (f *ModulesFeature) AppendCompletionHooks(srvCtx context.Context, decoderContext decoder.DecoderContext) {
 	h := hooks.Hooks{
 		ModStore:       f.Store,
@@ -173,13 +447,21 @@ func (f *ModulesFeature) AppendCompletionHooks(srvCtx context.Context, decoderCo
 		Logger:         f.logger,
 	}
 
+	backends := []modulesearch.Backend{
+		modulesearch.NewLocal(f.moduleCatalogFile, f.stateStore.RegistryModules, installedModuleSources{
+			modStore:    f.Store,
+			rootFeature: f.rootFeature,
+		}),
+	}
 	credentials, ok := algolia.CredentialsFromContext(srvCtx)
-	if ok {
-		h.AlgoliaClient = search.NewClient(credentials.AppID, credentials.APIKey)
+	if ok && !f.offlineModuleSearch {
+		backends = append(backends, modulesearch.NewAlgolia(search.NewClient(credentials.AppID, credentials.APIKey)))
 	}
+	h.ModuleSearch = modulesearch.Combined(backends...)
 
 	decoderContext.CompletionHooks["CompleteLocalModuleSources"] = h.LocalModuleSources
 	decoderContext.CompletionHooks["CompleteRegistryModuleSources"] = h.RegistryModuleSources
+	decoderContext.CompletionHooks["CompleteRemoteModuleSources"] = h.RemoteModuleSources
 	decoderContext.CompletionHooks["CompleteRegistryModuleVersions"] = h.RegistryModuleVersions
 }
 
@@ -4,22 +4,50 @@
 package parser
 
 import (
+	"io/fs"
 	"path/filepath"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/opentofu"
 	"github.com/hashicorp/terraform-ls/internal/terraform/parser"
 )
 
+// ParseModuleFiles parses all module files in modPath, detecting
+// whether the module targets OpenTofu from the files in it.
 func ParseModuleFiles(fs parser.FS, modPath string) (ast.ModFiles, ast.ModDiags, error) {
+	infos, err := fs.ReadDir(modPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	isOpenTofu := opentofu.ContainsOpenTofuFiles(fileNames(infos))
+
+	files, diags, _, _, err := ParseChangedModuleFiles(fs, modPath, isOpenTofu, nil, nil, nil)
+	return files, diags, err
+}
+
+// ParseChangedModuleFiles parses all module files in modPath,
+// reusing AST and diagnostics of existing files if the hash of their
+// content did not change since they were parsed.
+//
+// Files ignored by the distribution which the module targets
+// are skipped (see [IsIgnoredModuleFile]).
+//
+// It also reports whether any file was added, changed or removed,
+// i.e. whether anything derived from the AST needs to be re-computed.
+func ParseChangedModuleFiles(fs parser.FS, modPath string, isOpenTofu bool, existingFiles ast.ModFiles, existingDiags ast.ModDiags, existingHashes ast.ModFileHashes) (ast.ModFiles, ast.ModDiags, ast.ModFileHashes, bool, error) {
 	files := make(ast.ModFiles, 0)
 	diags := make(ast.ModDiags, 0)
+	hashes := make(ast.ModFileHashes, 0)
 
 	infos, err := fs.ReadDir(modPath)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, false, err
 	}
 
+	overridden := opentofu.OverriddenFilenames(fileNames(infos))
+
+	changed := false
 	for _, info := range infos {
 		if info.IsDir() {
 			// We only care about files
@@ -30,6 +58,9 @@ func ParseModuleFiles(fs parser.FS, modPath string) (ast.ModFiles, ast.ModDiags,
 		if !ast.IsModuleFilename(name) {
 			continue
 		}
+		if isIgnoredModuleFile(name, overridden, isOpenTofu) {
+			continue
+		}
 
 		// TODO: overrides
 
@@ -43,6 +74,18 @@ func ParseModuleFiles(fs parser.FS, modPath string) (ast.ModFiles, ast.ModDiags,
 		}
 
 		filename := ast.ModFilename(name)
+		hash := parser.HashContent(src)
+		hashes[filename] = hash
+
+		existingHash, ok := existingHashes[filename]
+		if ok && existingHash == hash {
+			if f, ok := existingFiles[filename]; ok {
+				files[filename] = f
+			}
+			diags[filename] = existingDiags[filename]
+			continue
+		}
+		changed = true
 
 		f, pDiags := parser.ParseFile(src, filename)
 
@@ -52,14 +95,23 @@ func ParseModuleFiles(fs parser.FS, modPath string) (ast.ModFiles, ast.ModDiags,
 		}
 	}
 
-	return files, diags, nil
+	for filename := range existingHashes {
+		if _, ok := hashes[filename]; !ok {
+			// file was removed
+			changed = true
+		}
+	}
+
+	return files, diags, hashes, changed, nil
 }
 
-func ParseModuleFile(fs parser.FS, filePath string) (*hcl.File, hcl.Diagnostics, error) {
+// ParseModuleFile parses a single module file and returns the hash
+// of its content along with the AST.
+func ParseModuleFile(fs parser.FS, filePath string) (*hcl.File, hcl.Diagnostics, parser.ContentHash, error) {
 	src, err := fs.ReadFile(filePath)
 	if err != nil {
 		// If a file isn't accessible, return
-		return nil, nil, err
+		return nil, nil, parser.ContentHash{}, err
 	}
 
 	name := filepath.Base(filePath)
@@ -67,5 +119,30 @@ func ParseModuleFile(fs parser.FS, filePath string) (*hcl.File, hcl.Diagnostics,
 
 	f, pDiags := parser.ParseFile(src, filename)
 
-	return f, pDiags, nil
+	return f, pDiags, parser.HashContent(src), nil
+}
+
+// IsIgnoredModuleFile reports whether the module file with the given name
+// is ignored, given names of all files in the module directory.
+// Terraform ignores *.tofu files, while OpenTofu ignores
+// *.tf files shadowed by *.tofu files of the same name.
+func IsIgnoredModuleFile(name string, names []string, isOpenTofu bool) bool {
+	return isIgnoredModuleFile(name, opentofu.OverriddenFilenames(names), isOpenTofu)
+}
+
+func isIgnoredModuleFile(name string, overridden map[string]bool, isOpenTofu bool) bool {
+	if !isOpenTofu {
+		return opentofu.IsModuleFilename(name)
+	}
+	return overridden[name]
+}
+
+func fileNames(infos []fs.DirEntry) []string {
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if !info.IsDir() {
+			names = append(names, info.Name())
+		}
+	}
+	return names
 }
@@ -86,6 +86,17 @@ func TestParseModuleFiles(t *testing.T) {
 				},
 			},
 		},
+		{
+			"opentofu-mod-files",
+			map[string]struct{}{
+				"main.tofu":  {},
+				"outputs.tf": {},
+			},
+			map[string]hcl.Diagnostics{
+				"main.tofu":  nil,
+				"outputs.tf": nil,
+			},
+		},
 		{
 			"invalid-links",
 			map[string]struct{}{
@@ -145,3 +156,21 @@ func (osfs osFs) ReadDir(name string) ([]fs.DirEntry, error) {
 func (osfs osFs) ReadFile(name string) ([]byte, error) {
 	return os.ReadFile(name)
 }
+
+func TestParseChangedModuleFiles_terraform(t *testing.T) {
+	modPath := filepath.Join("testdata", "opentofu-mod-files")
+
+	// Terraform ignores *.tofu files, so none shadow *.tf files
+	files, _, _, _, err := ParseChangedModuleFiles(osFs{}, modPath, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedFileNames := map[string]struct{}{
+		"main.tf":    {},
+		"outputs.tf": {},
+	}
+	if diff := cmp.Diff(expectedFileNames, mapKeys(files)); diff != "" {
+		t.Fatalf("unexpected file names: %s", diff)
+	}
+}
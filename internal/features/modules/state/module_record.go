@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/features/modules/ast"
 	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/terraform/parser"
 )
 
 // ModuleRecord contains all information about module files
@@ -30,7 +31,13 @@ type ModuleRecord struct {
 	RefOriginsState op.OpState
 
 	ParsedModuleFiles ast.ModFiles
-	ModuleParsingErr  error
+	// ParsedModuleFileHashes tracks hashes of the content of
+	// ParsedModuleFiles, which allows us to only parse changed files
+	ParsedModuleFileHashes ast.ModFileHashes
+	// ParseGeneration is incremented whenever the parsed content changes,
+	// which allows us to discard anything derived from an outdated AST
+	ParseGeneration  uint64
+	ModuleParsingErr error
 
 	Meta      ModuleMetadata
 	MetaErr   error
@@ -42,6 +49,14 @@ type ModuleRecord struct {
 
 	ModuleDiagnostics      ast.SourceModDiags
 	ModuleDiagnosticsState globalAst.DiagnosticSourceState
+
+	// PlannedActions contains the resource actions
+	// from the last `terraform plan` run for this module
+	PlannedActions PlannedActions
+
+	// TFLintContentHash is a hash of the module content as it was
+	// when tflint ran last, which allows us to avoid linting it again
+	TFLintContentHash parser.ContentHash
 }
 
 func (m *ModuleRecord) Copy() *ModuleRecord {
@@ -61,6 +76,7 @@ func (m *ModuleRecord) Copy() *ModuleRecord {
 		RefOriginsErr:   m.RefOriginsErr,
 		RefOriginsState: m.RefOriginsState,
 
+		ParseGeneration:  m.ParseGeneration,
 		ModuleParsingErr: m.ModuleParsingErr,
 
 		Meta:      m.Meta.Copy(),
@@ -72,6 +88,10 @@ func (m *ModuleRecord) Copy() *ModuleRecord {
 		WriteOnlyAttributesState: m.WriteOnlyAttributesState,
 
 		ModuleDiagnosticsState: m.ModuleDiagnosticsState.Copy(),
+
+		PlannedActions: m.PlannedActions.Copy(),
+
+		TFLintContentHash: m.TFLintContentHash,
 	}
 
 	if m.ParsedModuleFiles != nil {
@@ -82,6 +102,10 @@ func (m *ModuleRecord) Copy() *ModuleRecord {
 		}
 	}
 
+	if m.ParsedModuleFileHashes != nil {
+		newMod.ParsedModuleFileHashes = m.ParsedModuleFileHashes.Copy()
+	}
+
 	if m.ModuleDiagnostics != nil {
 		newMod.ModuleDiagnostics = make(ast.SourceModDiags, len(m.ModuleDiagnostics))
 
@@ -115,6 +139,9 @@ func newModule(modPath string) *ModuleRecord {
 			globalAst.SchemaValidationSource:    op.OpStateUnknown,
 			globalAst.ReferenceValidationSource: op.OpStateUnknown,
 			globalAst.TerraformValidateSource:   op.OpStateUnknown,
+			globalAst.TerraformPlanSource:       op.OpStateUnknown,
+			globalAst.TFLintSource:              op.OpStateUnknown,
+			globalAst.CustomRulesSource:         op.OpStateUnknown,
 		},
 	}
 }
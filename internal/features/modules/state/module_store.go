@@ -6,6 +6,7 @@ package state
 import (
 	"fmt"
 	"log"
+	"maps"
 	"path/filepath"
 
 	"github.com/hashicorp/go-memdb"
@@ -16,6 +17,7 @@ import (
 	globalState "github.com/hashicorp/terraform-ls/internal/state"
 	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/terraform/parser"
 	tfaddr "github.com/hashicorp/terraform-registry-address"
 	tfmod "github.com/hashicorp/terraform-schema/module"
 	"github.com/hashicorp/terraform-schema/registry"
@@ -348,7 +350,10 @@ func (s *ModuleStore) SetPreloadEmbeddedSchemaState(path string, state op.OpStat
 	return nil
 }
 
-func (s *ModuleStore) UpdateParsedModuleFiles(path string, pFiles ast.ModFiles, pErr error) error {
+// SetPreloadEmbeddedSchemaLoaded marks schemas as preloaded
+// for the given parse generation, unless the module was parsed
+// again in the meantime.
+func (s *ModuleStore) SetPreloadEmbeddedSchemaLoaded(path string, generation uint64) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
 
@@ -357,10 +362,61 @@ func (s *ModuleStore) UpdateParsedModuleFiles(path string, pFiles ast.ModFiles,
 		return err
 	}
 
+	if mod.ParseGeneration != generation {
+		// requirements may have changed along with the AST
+		return nil
+	}
+
+	mod.PreloadEmbeddedSchemaState = op.OpStateLoaded
+	err = txn.Insert(s.tableName, mod)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// UpdateParsedModuleFiles updates the AST of the module along with hashes
+// of the parsed content. If the content changed, it also starts a new
+// parse generation and resets the state of anything derived from the AST,
+// so that it gets re-computed.
+func (s *ModuleStore) UpdateParsedModuleFiles(path string, pFiles ast.ModFiles, hashes ast.ModFileHashes, pErr error) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	mod, err := moduleCopyByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	contentChanged := !maps.Equal(mod.ParsedModuleFileHashes, hashes)
+
 	mod.ParsedModuleFiles = pFiles
+	mod.ParsedModuleFileHashes = hashes
 
 	mod.ModuleParsingErr = pErr
 
+	if !contentChanged {
+		err = txn.Insert(s.tableName, mod)
+		if err != nil {
+			return err
+		}
+
+		txn.Commit()
+		return nil
+	}
+
+	mod.ParseGeneration++
+	mod.PreloadEmbeddedSchemaState = op.OpStateUnknown
+	mod.MetaState = op.OpStateUnknown
+	mod.RefTargetsState = op.OpStateUnknown
+	mod.RefOriginsState = op.OpStateUnknown
+	mod.WriteOnlyAttributesState = op.OpStateUnknown
+	mod.ModuleDiagnosticsState[globalAst.SchemaValidationSource] = op.OpStateUnknown
+	mod.ModuleDiagnosticsState[globalAst.ReferenceValidationSource] = op.OpStateUnknown
+	mod.ModuleDiagnosticsState[globalAst.CustomRulesSource] = op.OpStateUnknown
+
 	err = txn.Insert(s.tableName, mod)
 	if err != nil {
 		return err
@@ -389,7 +445,7 @@ func (s *ModuleStore) SetMetaState(path string, state op.OpState) error {
 	return nil
 }
 
-func (s *ModuleStore) UpdateMetadata(path string, meta *tfmod.Meta, mErr error) error {
+func (s *ModuleStore) UpdateMetadata(path string, generation uint64, meta *tfmod.Meta, mErr error) error {
 	txn := s.db.Txn(true)
 	txn.Defer(func() {
 		s.SetMetaState(path, op.OpStateLoaded)
@@ -401,6 +457,11 @@ func (s *ModuleStore) UpdateMetadata(path string, meta *tfmod.Meta, mErr error)
 		return err
 	}
 
+	if oldMod.ParseGeneration != generation {
+		// the result was derived from an outdated AST
+		return nil
+	}
+
 	mod := oldMod.Copy()
 	mod.Meta = ModuleMetadata{
 		CoreRequirements:     meta.CoreRequirements,
@@ -461,6 +522,116 @@ func (s *ModuleStore) UpdateModuleDiagnostics(path string, source globalAst.Diag
 	return nil
 }
 
+// UpdateValidationDiagnostics stores diagnostics of validation
+// derived from the AST of the given parse generation. Diagnostics
+// derived from an outdated AST are discarded.
+func (s *ModuleStore) UpdateValidationDiagnostics(path string, generation uint64, source globalAst.DiagnosticSource, diags ast.ModDiags) error {
+	txn := s.db.Txn(true)
+	txn.Defer(func() {
+		s.SetModuleDiagnosticsState(path, source, op.OpStateLoaded)
+	})
+	defer txn.Abort()
+
+	oldMod, err := moduleByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	if oldMod.ParseGeneration != generation {
+		// the result was derived from an outdated AST
+		return nil
+	}
+
+	mod := oldMod.Copy()
+	if mod.ModuleDiagnostics == nil {
+		mod.ModuleDiagnostics = make(ast.SourceModDiags)
+	}
+	mod.ModuleDiagnostics[source] = diags
+
+	err = txn.Insert(s.tableName, mod)
+	if err != nil {
+		return err
+	}
+
+	err = s.queueModuleChange(oldMod, mod)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// UpdateTFLintDiagnostics stores diagnostics reported by tflint
+// along with a hash of the module content they were reported for
+func (s *ModuleStore) UpdateTFLintDiagnostics(path string, hash parser.ContentHash, diags ast.ModDiags) error {
+	txn := s.db.Txn(true)
+	txn.Defer(func() {
+		s.SetModuleDiagnosticsState(path, globalAst.TFLintSource, op.OpStateLoaded)
+	})
+	defer txn.Abort()
+
+	oldMod, err := moduleByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	mod := oldMod.Copy()
+	if mod.ModuleDiagnostics == nil {
+		mod.ModuleDiagnostics = make(ast.SourceModDiags)
+	}
+	mod.ModuleDiagnostics[globalAst.TFLintSource] = diags
+	mod.TFLintContentHash = hash
+
+	err = txn.Insert(s.tableName, mod)
+	if err != nil {
+		return err
+	}
+
+	err = s.queueModuleChange(oldMod, mod)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// ClearTFLintDiagnostics removes diagnostics reported by tflint,
+// e.g. after tflint failed, so that no outdated issues are published
+func (s *ModuleStore) ClearTFLintDiagnostics(path string) error {
+	txn := s.db.Txn(true)
+	txn.Defer(func() {
+		s.SetModuleDiagnosticsState(path, globalAst.TFLintSource, op.OpStateUnknown)
+	})
+	defer txn.Abort()
+
+	oldMod, err := moduleByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	mod := oldMod.Copy()
+	if mod.ModuleDiagnostics == nil {
+		mod.ModuleDiagnostics = make(ast.SourceModDiags)
+	}
+	mod.ModuleDiagnostics[globalAst.TFLintSource] = make(ast.ModDiags)
+	mod.TFLintContentHash = parser.ContentHash{}
+
+	err = txn.Insert(s.tableName, mod)
+	if err != nil {
+		return err
+	}
+
+	err = s.queueModuleChange(oldMod, mod)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
 func (s *ModuleStore) SetModuleDiagnosticsState(path string, source globalAst.DiagnosticSource, state op.OpState) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
@@ -499,7 +670,7 @@ func (s *ModuleStore) SetReferenceTargetsState(path string, state op.OpState) er
 	return nil
 }
 
-func (s *ModuleStore) UpdateReferenceTargets(path string, refs reference.Targets, rErr error) error {
+func (s *ModuleStore) UpdateReferenceTargets(path string, generation uint64, refs reference.Targets, rErr error) error {
 	txn := s.db.Txn(true)
 	txn.Defer(func() {
 		s.SetReferenceTargetsState(path, op.OpStateLoaded)
@@ -511,6 +682,11 @@ func (s *ModuleStore) UpdateReferenceTargets(path string, refs reference.Targets
 		return err
 	}
 
+	if mod.ParseGeneration != generation {
+		// the result was derived from an outdated AST
+		return nil
+	}
+
 	mod.RefTargets = refs
 	mod.RefTargetsErr = rErr
 
@@ -542,7 +718,7 @@ func (s *ModuleStore) SetReferenceOriginsState(path string, state op.OpState) er
 	return nil
 }
 
-func (s *ModuleStore) UpdateReferenceOrigins(path string, origins reference.Origins, roErr error) error {
+func (s *ModuleStore) UpdateReferenceOrigins(path string, generation uint64, origins reference.Origins, roErr error) error {
 	txn := s.db.Txn(true)
 	txn.Defer(func() {
 		s.SetReferenceOriginsState(path, op.OpStateLoaded)
@@ -554,6 +730,11 @@ func (s *ModuleStore) UpdateReferenceOrigins(path string, origins reference.Orig
 		return err
 	}
 
+	if mod.ParseGeneration != generation {
+		// the result was derived from an outdated AST
+		return nil
+	}
+
 	mod.RefOrigins = origins
 	mod.RefOriginsErr = roErr
 
@@ -585,7 +766,7 @@ func (s *ModuleStore) SetWriteOnlyAttributesState(path string, state op.OpState)
 	return nil
 }
 
-func (s *ModuleStore) UpdateWriteOnlyAttributes(path string, woAttrs WriteOnlyAttributes, woAttrsErr error) error {
+func (s *ModuleStore) UpdateWriteOnlyAttributes(path string, generation uint64, woAttrs WriteOnlyAttributes, woAttrsErr error) error {
 	txn := s.db.Txn(true)
 	txn.Defer(func() {
 		s.SetWriteOnlyAttributesState(path, op.OpStateLoaded)
@@ -597,6 +778,11 @@ func (s *ModuleStore) UpdateWriteOnlyAttributes(path string, woAttrs WriteOnlyAt
 		return err
 	}
 
+	if mod.ParseGeneration != generation {
+		// the result was derived from an outdated AST
+		return nil
+	}
+
 	mod.WriteOnlyAttributes = woAttrs
 	mod.WriteOnlyAttributesErr = woAttrsErr
 
@@ -609,6 +795,32 @@ func (s *ModuleStore) UpdateWriteOnlyAttributes(path string, woAttrs WriteOnlyAt
 	return nil
 }
 
+func (s *ModuleStore) UpdatePlannedActions(path string, actions PlannedActions) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	oldMod, err := moduleByPath(txn, path)
+	if err != nil {
+		return err
+	}
+
+	mod := oldMod.Copy()
+	mod.PlannedActions = actions
+
+	err = txn.Insert(s.tableName, mod)
+	if err != nil {
+		return err
+	}
+
+	err = s.queueModuleChange(oldMod, mod)
+	if err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
 func (s *ModuleStore) RegistryModuleMeta(addr tfaddr.Module, cons version.Constraints) (*registry.ModuleData, error) {
 	return s.registryModuleStore.RegistryModuleMeta(addr, cons)
 }
@@ -697,6 +909,19 @@ func (s *ModuleStore) queueModuleChange(oldMod, newMod *ModuleRecord) error {
 		changes.ReferenceTargets = true
 	}
 
+	oldActions, newActions := 0, 0
+	if oldMod != nil {
+		oldActions = len(oldMod.PlannedActions)
+	}
+	if newMod != nil {
+		newActions = len(newMod.PlannedActions)
+	}
+	// Any plan is treated as a change as the actions
+	// may differ even if they concern the same resources
+	if oldActions > 0 || newActions > 0 {
+		changes.PlannedActions = true
+	}
+
 	var modHandle document.DirHandle
 	if oldMod != nil {
 		modHandle = document.DirHandleFromPath(oldMod.Path())
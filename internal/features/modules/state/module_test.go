@@ -19,6 +19,7 @@ import (
 	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	"github.com/hashicorp/terraform-ls/internal/terraform/datadir"
 	"github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/terraform/parser"
 	tfaddr "github.com/hashicorp/terraform-registry-address"
 	tfmod "github.com/hashicorp/terraform-schema/module"
 	"github.com/zclconf/go-cty-debug/ctydebug"
@@ -94,6 +95,9 @@ func TestModuleStore_ModuleByPath(t *testing.T) {
 			globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 			globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 			globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+			globalAst.TerraformPlanSource:       operation.OpStateUnknown,
+			globalAst.TFLintSource:              operation.OpStateUnknown,
+			globalAst.CustomRulesSource:         operation.OpStateUnknown,
 		},
 	}
 	if diff := cmp.Diff(expectedModule, mod, cmpOpts); diff != "" {
@@ -138,6 +142,9 @@ func TestModuleStore_List(t *testing.T) {
 				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 				globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+				globalAst.TerraformPlanSource:       operation.OpStateUnknown,
+				globalAst.TFLintSource:              operation.OpStateUnknown,
+				globalAst.CustomRulesSource:         operation.OpStateUnknown,
 			},
 		},
 		{
@@ -147,6 +154,9 @@ func TestModuleStore_List(t *testing.T) {
 				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 				globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+				globalAst.TerraformPlanSource:       operation.OpStateUnknown,
+				globalAst.TFLintSource:              operation.OpStateUnknown,
+				globalAst.CustomRulesSource:         operation.OpStateUnknown,
 			},
 		},
 		{
@@ -156,6 +166,9 @@ func TestModuleStore_List(t *testing.T) {
 				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 				globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+				globalAst.TerraformPlanSource:       operation.OpStateUnknown,
+				globalAst.TFLintSource:              operation.OpStateUnknown,
+				globalAst.CustomRulesSource:         operation.OpStateUnknown,
 			},
 		},
 	}
@@ -195,7 +208,7 @@ func TestModuleStore_UpdateMetadata(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = s.UpdateMetadata(tmpDir, metadata, nil)
+	err = s.UpdateMetadata(tmpDir, 0, metadata, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -224,6 +237,9 @@ func TestModuleStore_UpdateMetadata(t *testing.T) {
 			globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 			globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 			globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+			globalAst.TerraformPlanSource:       operation.OpStateUnknown,
+			globalAst.TFLintSource:              operation.OpStateUnknown,
+			globalAst.CustomRulesSource:         operation.OpStateUnknown,
 		},
 	}
 
@@ -260,7 +276,7 @@ provider "blah" {
 
 	err = s.UpdateParsedModuleFiles(tmpDir, ast.ModFiles{
 		"test.tf": testFile,
-	}, nil)
+	}, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -278,6 +294,85 @@ provider "blah" {
 	}
 }
 
+func TestModuleStore_UpdateMetadata_outdatedParse(t *testing.T) {
+	globalStore, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewModuleStore(globalStore.ProviderSchemas, globalStore.RegistryModules, globalStore.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	err = s.Add(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := hclparse.NewParser()
+	testFile, diags := p.ParseHCL([]byte(`variable "foo" {}`), "test.tf")
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	err = s.UpdateParsedModuleFiles(tmpDir, ast.ModFiles{"test.tf": testFile},
+		ast.ModFileHashes{"test.tf": parser.HashContent(testFile.Bytes)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := s.ModuleRecordByPath(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a job decoding metadata starts with this generation
+	generation := mod.ParseGeneration
+
+	// the module is parsed again with the same content
+	err = s.UpdateParsedModuleFiles(tmpDir, ast.ModFiles{"test.tf": testFile},
+		ast.ModFileHashes{"test.tf": parser.HashContent(testFile.Bytes)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod, err = s.ModuleRecordByPath(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod.ParseGeneration != generation {
+		t.Fatalf("expected generation %d for unchanged content, given: %d", generation, mod.ParseGeneration)
+	}
+
+	// the module is parsed again with changed content while the job runs
+	changedFile, diags := p.ParseHCL([]byte(`variable "bar" {}`), "changed.tf")
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	err = s.UpdateParsedModuleFiles(tmpDir, ast.ModFiles{"test.tf": changedFile},
+		ast.ModFileHashes{"test.tf": parser.HashContent(changedFile.Bytes)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.UpdateMetadata(tmpDir, generation, &tfmod.Meta{
+		Path:             tmpDir,
+		CoreRequirements: testConstraint(t, "~> 0.15"),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err = s.ModuleRecordByPath(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod.MetaState != operation.OpStateUnknown {
+		t.Fatalf("expected outdated metadata not to be loaded, given state: %s", mod.MetaState)
+	}
+	if mod.Meta.CoreRequirements != nil {
+		t.Fatalf("expected outdated metadata to be discarded, given: %#v", mod.Meta.CoreRequirements)
+	}
+}
+
 func TestModuleStore_UpdateModuleDiagnostics(t *testing.T) {
 	globalStore, err := globalState.NewStateStore()
 	if err != nil {
@@ -369,7 +464,7 @@ func TestProviderRequirementsForModule_cycle(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = s.UpdateMetadata(modHandle.Path(), meta, nil)
+	err = s.UpdateMetadata(modHandle.Path(), 0, meta, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -408,7 +503,7 @@ func TestProviderRequirementsForModule_basic(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = ss.UpdateMetadata(modHandle.Path(), meta, nil)
+	err = ss.UpdateMetadata(modHandle.Path(), 0, meta, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -425,7 +520,7 @@ func TestProviderRequirementsForModule_basic(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = ss.UpdateMetadata(submodHandle.Path(), subMeta, nil)
+	err = ss.UpdateMetadata(submodHandle.Path(), 0, subMeta, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -481,7 +576,7 @@ func BenchmarkModuleByPath(b *testing.B) {
 	}
 
 	mFiles := ast.ModFilesFromMap(pFiles)
-	err = s.UpdateParsedModuleFiles(modPath, mFiles, nil)
+	err = s.UpdateParsedModuleFiles(modPath, mFiles, nil, nil)
 	if err != nil {
 		b.Fatal(err)
 	}
@@ -0,0 +1,25 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+type ResourceAddr = string
+type PlannedAction = string
+
+// PlannedActions maps resource addresses in the configuration
+// (e.g. aws_instance.foo) to the actions planned by the last
+// `terraform plan` for each of its instances
+type PlannedActions map[ResourceAddr][]PlannedAction
+
+func (pa PlannedActions) Copy() PlannedActions {
+	if pa == nil {
+		return nil
+	}
+
+	newActions := make(PlannedActions, len(pa))
+	for addr, actions := range pa {
+		newActions[addr] = make([]PlannedAction, len(actions))
+		copy(newActions[addr], actions)
+	}
+	return newActions
+}
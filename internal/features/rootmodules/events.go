@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/terraform/datadir"
 	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
 	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfversion"
 	"github.com/hashicorp/terraform-ls/internal/uri"
 )
 
@@ -246,3 +247,37 @@ func (f *RootModulesFeature) indexTerraformSourcesDirs(ctx context.Context, dir
 
 	return jobIds, nil
 }
+
+func (f *RootModulesFeature) versionFileChange(ctx context.Context, dir document.DirHandle) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	records, err := f.Store.List()
+	if err != nil {
+		return ids, err
+	}
+
+	for _, record := range records {
+		modPath := record.Path()
+		if !tfversion.IsAffectedBy(modPath, dir.Path()) {
+			continue
+		}
+
+		// The version pin may select a different binary
+		// from the toolchain directory, if one is configured
+		versionId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+			Dir: document.DirHandleFromPath(modPath),
+			Func: func(ctx context.Context) error {
+				ctx = exec.WithExecutorFactory(ctx, f.tfExecFactory)
+				return jobs.GetTerraformVersion(ctx, f.Store, modPath)
+			},
+			Type:        op.OpTypeGetTerraformVersion.String(),
+			IgnoreState: true,
+		})
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, versionId)
+	}
+
+	return ids, nil
+}
@@ -5,18 +5,24 @@ package rootmodules
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/terraform-ls/internal/eventbus"
 	"github.com/hashicorp/terraform-ls/internal/features/rootmodules/jobs"
 	"github.com/hashicorp/terraform-ls/internal/features/rootmodules/state"
 	"github.com/hashicorp/terraform-ls/internal/job"
+	"github.com/hashicorp/terraform-ls/internal/settings"
 	globalState "github.com/hashicorp/terraform-ls/internal/state"
 	"github.com/hashicorp/terraform-ls/internal/telemetry"
 	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/hashicorp/terraform-ls/internal/terraform/opentofu"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfversion"
 	tfaddr "github.com/hashicorp/terraform-registry-address"
 	tfmod "github.com/hashicorp/terraform-schema/module"
 )
@@ -35,9 +41,17 @@ type RootModulesFeature struct {
 	stopFunc context.CancelFunc
 	logger   *log.Logger
 
-	tfExecFactory exec.ExecutorFactory
-	stateStore    *globalState.StateStore
-	fs            jobs.ReadOnlyFS
+	tfExecFactory   exec.ExecutorFactory
+	stateStore      *globalState.StateStore
+	fs              jobs.ReadOnlyFS
+	versionResolver *tfversion.Resolver
+	stateReader     *stateReader
+
+	// distribution forces all modules to target either Terraform
+	// or OpenTofu, regardless of whether they contain any *.tofu files.
+	// Empty value means that it is detected per module.
+	distribution     string
+	openTofuDetector *opentofu.Detector
 }
 
 func NewRootModulesFeature(eventbus *eventbus.EventBus, stateStore *globalState.StateStore, fs jobs.ReadOnlyFS, tfExecFactory exec.ExecutorFactory) (*RootModulesFeature, error) {
@@ -48,13 +62,16 @@ func NewRootModulesFeature(eventbus *eventbus.EventBus, stateStore *globalState.
 	discardLogger := log.New(io.Discard, "", 0)
 
 	return &RootModulesFeature{
-		Store:         store,
-		eventbus:      eventbus,
-		stopFunc:      func() {},
-		logger:        discardLogger,
-		tfExecFactory: tfExecFactory,
-		stateStore:    stateStore,
-		fs:            fs,
+		Store:            store,
+		eventbus:         eventbus,
+		stopFunc:         func() {},
+		logger:           discardLogger,
+		tfExecFactory:    tfExecFactory,
+		stateStore:       stateStore,
+		fs:               fs,
+		versionResolver:  tfversion.NewResolver(fs),
+		stateReader:      newStateReader(fs),
+		openTofuDetector: opentofu.NewDetector(fs),
 	}, nil
 }
 
@@ -63,6 +80,31 @@ func (f *RootModulesFeature) SetLogger(logger *log.Logger) {
 	f.Store.SetLogger(logger)
 }
 
+// SetVersionResolver sets the resolver of pinned Terraform versions,
+// so that it can be shared (and invalidated) across features
+func (f *RootModulesFeature) SetVersionResolver(resolver *tfversion.Resolver) {
+	f.versionResolver = resolver
+}
+
+// SetDistribution forces all modules to target the given distribution
+// (see [RootModulesFeature.IsOpenTofu])
+func (f *RootModulesFeature) SetDistribution(distribution string) {
+	f.distribution = distribution
+}
+
+// SetOpenTofuDetector sets the detector of modules targeting OpenTofu,
+// so that it can be shared (and invalidated) across features
+func (f *RootModulesFeature) SetOpenTofuDetector(detector *opentofu.Detector) {
+	f.openTofuDetector = detector
+}
+
+// SetStateSnapshotFile configures a state snapshot file (state or plan
+// JSON) to read state values from, instead of the local terraform.tfstate.
+// Relative paths are resolved against the directory of each module.
+func (f *RootModulesFeature) SetStateSnapshotFile(path string) {
+	f.stateReader.snapshotFile = path
+}
+
 // Start starts the features separate goroutine.
 // It listens to various events from the EventBus and performs corresponding actions.
 func (f *RootModulesFeature) Start(ctx context.Context) {
@@ -81,6 +123,9 @@ func (f *RootModulesFeature) Start(ctx context.Context) {
 	pluginLockChangeDone := make(chan job.IDs, 10)
 	pluginLockChange := f.eventbus.OnPluginLockChange("feature.rootmodules", pluginLockChangeDone)
 
+	versionFileChangeDone := make(chan job.IDs, 10)
+	versionFileChange := f.eventbus.OnVersionFileChange("feature.rootmodules", versionFileChangeDone)
+
 	go func() {
 		for {
 			select {
@@ -100,6 +145,10 @@ func (f *RootModulesFeature) Start(ctx context.Context) {
 				// TODO? collect errors
 				spawnedIds, _ := f.pluginLockChange(pluginLockChange.Context, pluginLockChange.Dir)
 				pluginLockChangeDone <- spawnedIds
+			case versionFileChange := <-versionFileChange:
+				// TODO? collect errors
+				spawnedIds, _ := f.versionFileChange(versionFileChange.Context, versionFileChange.Dir)
+				versionFileChangeDone <- spawnedIds
 
 			case <-ctx.Done():
 				return
@@ -141,6 +190,50 @@ func (f *RootModulesFeature) TerraformVersion(modPath string) *version.Version {
 	return record.TerraformVersion
 }
 
+// PinnedTerraformVersion returns the Terraform version pinned
+// for the given module path via .terraform-version or .tool-versions
+// in the module directory or any of its parent directories.
+func (f *RootModulesFeature) PinnedTerraformVersion(modPath string) *version.Version {
+	v, _, err := f.versionResolver.PinnedVersion(modPath)
+	if err != nil {
+		f.logger.Printf("failed to read pinned Terraform version for %q: %s", modPath, err)
+		return nil
+	}
+	return v
+}
+
+// IsOpenTofu reports whether the module at the given path targets OpenTofu,
+// i.e. whether the distribution is set to OpenTofu or, if not set,
+// the directory contains any OpenTofu-specific files (*.tofu, *.tofutest.hcl).
+func (f *RootModulesFeature) IsOpenTofu(modPath string) bool {
+	switch f.distribution {
+	case settings.DistributionOpenTofu:
+		return true
+	case settings.DistributionTerraform:
+		return false
+	}
+
+	return f.openTofuDetector.IsOpenTofuDir(modPath)
+}
+
+// StateValue returns the value of the resource (or its attribute) at the
+// given address in the local state snapshot of the module at the given
+// path, with any sensitive values redacted.
+//
+// The snapshot is read from local files only (see [RootModulesFeature.SetStateSnapshotFile])
+// and any errors reading it are treated as no value being available.
+func (f *RootModulesFeature) StateValue(modPath string, addr lang.Address) (interface{}, bool) {
+	snapshot, err := f.stateReader.Snapshot(modPath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			f.logger.Printf("failed to read state snapshot for %q: %s", modPath, err)
+		}
+		return nil, false
+	}
+
+	return snapshot.Value(addr)
+}
+
 // InstalledProviders returns the installed providers for the given module path
 func (f *RootModulesFeature) InstalledProviders(modPath string) (map[tfaddr.Provider]*version.Version, error) {
 	record, err := f.Store.RootRecordByPath(modPath)
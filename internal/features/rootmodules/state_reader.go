@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rootmodules
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-ls/internal/features/rootmodules/jobs"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfstate"
+)
+
+// localStateFilename is where the local backend persists state by default
+const localStateFilename = "terraform.tfstate"
+
+// stateReader reads state snapshots from local files, caching the
+// parsed snapshot until the file changes on disk.
+type stateReader struct {
+	fs jobs.ReadOnlyFS
+
+	// snapshotFile is the path to a user-provided snapshot
+	// (state or plan JSON) to read instead of the local state,
+	// either absolute or relative to the module directory
+	snapshotFile string
+
+	mu    sync.Mutex
+	cache map[string]cachedSnapshot
+}
+
+type cachedSnapshot struct {
+	modTime  time.Time
+	size     int64
+	snapshot *tfstate.Snapshot
+}
+
+func newStateReader(fs jobs.ReadOnlyFS) *stateReader {
+	return &stateReader{
+		fs:    fs,
+		cache: make(map[string]cachedSnapshot, 0),
+	}
+}
+
+func (sr *stateReader) snapshotPath(modPath string) string {
+	if sr.snapshotFile == "" {
+		return filepath.Join(modPath, localStateFilename)
+	}
+	if filepath.IsAbs(sr.snapshotFile) {
+		return sr.snapshotFile
+	}
+	return filepath.Join(modPath, sr.snapshotFile)
+}
+
+// Snapshot returns the state snapshot for the given module path
+func (sr *stateReader) Snapshot(modPath string) (*tfstate.Snapshot, error) {
+	path := sr.snapshotPath(modPath)
+
+	fi, err := sr.fs.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	cached, ok := sr.cache[path]
+	if ok && cached.modTime.Equal(fi.ModTime()) && cached.size == fi.Size() {
+		return cached.snapshot, nil
+	}
+
+	src, err := sr.fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := tfstate.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	sr.cache[path] = cachedSnapshot{
+		modTime:  fi.ModTime(),
+		size:     fi.Size(),
+		snapshot: snapshot,
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/features/stacks/ast"
+	"github.com/hashicorp/terraform-ls/internal/graph"
+)
+
+// DependencyGraph builds the dependency graph of a stack from its parsed
+// files. Components and deployments are represented as nodes and any
+// component.* reference within a component (e.g. in inputs or providers)
+// is represented as an edge.
+func DependencyGraph(files ast.Files) *graph.Graph {
+	g := graph.NewGraph()
+
+	for _, f := range files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		for _, block := range body.Blocks {
+			if len(block.Labels) != 1 {
+				continue
+			}
+
+			switch block.Type {
+			case "component", "deployment":
+				address := block.Type + "." + block.Labels[0]
+				g.AddNode(graph.Node{
+					Address: address,
+					Kind:    block.Type,
+					Range:   block.Range(),
+				})
+
+				if block.Type == "component" {
+					for _, traversal := range bodyTraversals(block.Body) {
+						to, ok := componentAddress(traversal)
+						if !ok {
+							continue
+						}
+						g.AddEdge(graph.Edge{
+							From:  address,
+							To:    to,
+							Range: traversal.SourceRange(),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return g
+}
+
+func bodyTraversals(body *hclsyntax.Body) []hcl.Traversal {
+	traversals := make([]hcl.Traversal, 0)
+	for _, attr := range body.Attributes {
+		traversals = append(traversals, attr.Expr.Variables()...)
+	}
+	for _, block := range body.Blocks {
+		traversals = append(traversals, bodyTraversals(block.Body)...)
+	}
+	return traversals
+}
+
+func componentAddress(traversal hcl.Traversal) (string, bool) {
+	if len(traversal) < 2 || traversal.RootName() != "component" {
+		return "", false
+	}
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	return "component." + attr.Name, true
+}
@@ -114,10 +114,8 @@ func stackPathContext(record *state.StackRecord, stateReader CombinedReader) (*d
 		ReferenceTargets: make(reference.Targets, 0),
 		Files:            make(map[string]*hcl.File, 0),
 		Functions:        functions,
-		Validators:       stackValidators,
 	}
 
-	// TODO: Add reference origins and targets if needed
 	for _, origin := range record.RefOrigins {
 		if ast.IsStackFilename(origin.OriginRange().Filename) {
 			pathCtx.ReferenceOrigins = append(pathCtx.ReferenceOrigins, origin)
@@ -138,6 +136,8 @@ func stackPathContext(record *state.StackRecord, stateReader CombinedReader) (*d
 		}
 	}
 
+	pathCtx.Validators = validatorsForTargets(pathCtx.ReferenceTargets)
+
 	return pathCtx, nil
 }
 
@@ -174,11 +174,9 @@ func deployPathContext(record *state.StackRecord) (*decoder.PathContext, error)
 		ReferenceOrigins: make(reference.Origins, 0),
 		ReferenceTargets: make(reference.Targets, 0),
 		Files:            make(map[string]*hcl.File, 0),
-		Validators:       stackValidators,
 		Functions:        deployFunctionsForVersion(version),
 	}
 
-	// TODO: Add reference origins and targets if needed
 	for _, origin := range record.RefOrigins {
 		if ast.IsDeployFilename(origin.OriginRange().Filename) {
 			pathCtx.ReferenceOrigins = append(pathCtx.ReferenceOrigins, origin)
@@ -199,6 +197,8 @@ func deployPathContext(record *state.StackRecord) (*decoder.PathContext, error)
 		}
 	}
 
+	pathCtx.Validators = validatorsForTargets(pathCtx.ReferenceTargets)
+
 	return pathCtx, nil
 }
 
@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/schemacontext"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// Inputs validates the inputs attribute of component and deployment blocks.
+//
+// The schema mergers in terraform-schema turn the inputs attribute into
+// an object with one attribute per variable, i.e. the variables of the
+// sourced module for components and the stack variables for deployments.
+// This validator checks the declared inputs against that object:
+// unknown and missing required inputs are reported, as well as values
+// which cannot be converted to the type of the corresponding variable.
+//
+// Targets are used to resolve the type of references, such as
+// component.name.output, which are otherwise not evaluable statically.
+type Inputs struct {
+	Targets reference.Targets
+}
+
+func (v Inputs) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	attr, ok := node.(*hclsyntax.Attribute)
+	if !ok || attr.Name != "inputs" {
+		return ctx, diags
+	}
+
+	nestingLvl, nestingOk := schemacontext.BlockNestingLevel(ctx)
+	if !nestingOk || nestingLvl != 1 {
+		return ctx, diags
+	}
+
+	attrSchema, ok := nodeSchema.(*schema.AttributeSchema)
+	if !ok || attrSchema == nil {
+		return ctx, diags
+	}
+	// The core schema declares inputs as a map of any expression, which
+	// gets replaced by an object only once we know the variables.
+	// We cannot tell which inputs are expected otherwise.
+	obj, ok := attrSchema.Constraint.(schema.Object)
+	if !ok {
+		return ctx, diags
+	}
+
+	objExpr, ok := attr.Expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return ctx, diags
+	}
+
+	declared := make(map[string]bool, len(objExpr.Items))
+	for _, item := range objExpr.Items {
+		name, ok := inputName(item.KeyExpr)
+		if !ok {
+			continue
+		}
+		declared[name] = true
+
+		inputSchema, ok := obj.Attributes[name]
+		if !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Unexpected input %q", name),
+				Detail:   fmt.Sprintf("No variable named %q is declared", name),
+				Subject:  item.KeyExpr.Range().Ptr(),
+			})
+			continue
+		}
+
+		typ := inputType(inputSchema)
+		if typ == cty.DynamicPseudoType {
+			continue
+		}
+
+		val, ok := v.expressionValue(item.ValueExpr)
+		if !ok {
+			continue
+		}
+
+		_, err := convert.Convert(val, typ)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Invalid value for input %q", name),
+				Detail:   fmt.Sprintf("Variable %q expects %s: %s", name, typ.FriendlyName(), err),
+				Subject:  item.ValueExpr.Range().Ptr(),
+			})
+		}
+	}
+
+	// Missing inputs are reported in order of their names,
+	// so that diagnostics don't change order between runs
+	names := make([]string, 0, len(obj.Attributes))
+	for name := range obj.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if obj.Attributes[name].IsRequired && !declared[name] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Required input %q not specified", name),
+				Detail:   fmt.Sprintf("Variable %q has no default value and must be set", name),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	return ctx, diags
+}
+
+func inputName(expr hclsyntax.Expression) (string, bool) {
+	if keyExpr, ok := expr.(*hclsyntax.ObjectConsKeyExpr); ok {
+		if name := hcl.ExprAsKeyword(keyExpr.Wrapped); name != "" {
+			return name, true
+		}
+	}
+
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsKnown() || val.IsNull() || val.Type() != cty.String {
+		return "", false
+	}
+	return val.AsString(), true
+}
+
+func inputType(aSchema *schema.AttributeSchema) cty.Type {
+	if aSchema.OriginForTarget == nil || aSchema.OriginForTarget.Constraints.Type == cty.NilType {
+		return cty.DynamicPseudoType
+	}
+	return aSchema.OriginForTarget.Constraints.Type
+}
+
+// expressionValue returns the value of the given expression
+// if it can be determined without evaluation context.
+//
+// References are resolved to an unknown value of the target type.
+func (v Inputs) expressionValue(expr hclsyntax.Expression) (cty.Value, bool) {
+	if ste, ok := expr.(*hclsyntax.ScopeTraversalExpr); ok {
+		addr, err := lang.TraversalToAddress(ste.Traversal)
+		if err != nil {
+			return cty.NilVal, false
+		}
+		target, ok := findTarget(v.Targets, addr)
+		if !ok || target.Type == cty.NilType {
+			return cty.NilVal, false
+		}
+		return cty.UnknownVal(target.Type), true
+	}
+
+	if len(expr.Variables()) > 0 {
+		return cty.NilVal, false
+	}
+
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return cty.NilVal, false
+	}
+	return val, true
+}
+
+func findTarget(targets reference.Targets, addr lang.Address) (reference.Target, bool) {
+	for _, target := range targets {
+		if target.Addr.Equals(addr) {
+			return target, true
+		}
+		if len(target.Addr) < len(addr) && target.Addr.Equals(addr.FirstSteps(uint(len(target.Addr)))) {
+			if nested, ok := findTarget(target.NestedTargets, addr); ok {
+				return nested, true
+			}
+		}
+	}
+	return reference.Target{}, false
+}
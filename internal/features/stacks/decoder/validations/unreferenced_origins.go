@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 )
 
 func UnreferencedOrigins(ctx context.Context, pathCtx *decoder.PathContext) lang.DiagnosticsMap {
@@ -70,6 +71,7 @@ func UnreferencedOrigins(ctx context.Context, pathCtx *decoder.PathContext) lang
 				Severity: hcl.DiagError,
 				Summary:  fmt.Sprintf("No declaration found for %q", address),
 				Subject:  origin.OriginRange().Ptr(),
+				Extra:    rules.UnreferencedOrigin,
 			}
 			diagsMap[fileName] = diagsMap[fileName].Append(d)
 
@@ -4,18 +4,30 @@
 package decoder
 
 import (
+	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl-lang/validator"
 	"github.com/hashicorp/terraform-ls/internal/features/stacks/decoder/validations"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 )
 
 var stackValidators = []validator.Validator{
-	validator.BlockLabelsLength{},
-	validator.DeprecatedAttribute{},
-	validator.DeprecatedBlock{},
-	validator.MaxBlocks{},
-	validator.MinBlocks{},
-	validator.UnexpectedAttribute{},
-	validator.UnexpectedBlock{},
-	validations.MissingRequiredAttribute{},
-	validations.StackBlockValidName{},
+	rules.Validator(rules.BlockLabelsLength, validator.BlockLabelsLength{}),
+	rules.Validator(rules.DeprecatedAttribute, validator.DeprecatedAttribute{}),
+	rules.Validator(rules.DeprecatedBlock, validator.DeprecatedBlock{}),
+	rules.Validator(rules.MaxBlocks, validator.MaxBlocks{}),
+	rules.Validator(rules.MinBlocks, validator.MinBlocks{}),
+	rules.Validator(rules.UnexpectedAttribute, validator.UnexpectedAttribute{}),
+	rules.Validator(rules.UnexpectedBlock, validator.UnexpectedBlock{}),
+	rules.Validator(rules.MissingRequiredAttribute, validations.MissingRequiredAttribute{}),
+	rules.Validator(rules.BlockName, validations.StackBlockValidName{}),
+}
+
+// validatorsForTargets returns the stack validators along with
+// validators which need the collected reference targets
+// to resolve the type of references.
+func validatorsForTargets(targets reference.Targets) []validator.Validator {
+	validators := make([]validator.Validator, 0, len(stackValidators)+1)
+	validators = append(validators, stackValidators...)
+	validators = append(validators, rules.Validator(rules.ComponentInputs, validations.Inputs{Targets: targets}))
+	return validators
 }
@@ -17,9 +17,9 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/job"
 	"github.com/hashicorp/terraform-ls/internal/lsp"
 	"github.com/hashicorp/terraform-ls/internal/protocol"
-	"github.com/hashicorp/terraform-ls/internal/schemas"
 	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	"github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfversion"
 	tfaddr "github.com/hashicorp/terraform-registry-address"
 	tfmod "github.com/hashicorp/terraform-schema/module"
 )
@@ -64,7 +64,7 @@ func (f *StacksFeature) didOpen(ctx context.Context, dir document.DirHandle, lan
 	tfVersion, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
 		Dir: dir,
 		Func: func(ctx context.Context) error {
-			return jobs.LoadTerraformVersion(ctx, f.fs, f.store, path)
+			return jobs.LoadTerraformVersion(ctx, f.versionResolver, f.store, path)
 		},
 		Type: operation.OpTypeLoadStackRequiredTerraformVersion.String(),
 	})
@@ -234,7 +234,7 @@ func (f *StacksFeature) decodeStack(ctx context.Context, dir document.DirHandle,
 			eSchemaId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
 				Dir: dir,
 				Func: func(ctx context.Context) error {
-					return jobs.PreloadEmbeddedSchema(ctx, f.logger, schemas.FS,
+					return jobs.PreloadEmbeddedSchema(ctx, f.logger, f.schemaFS,
 						f.store, f.stateStore.ProviderSchemas, path)
 				},
 				// DependsOn: none required, since we are inside
@@ -390,3 +390,43 @@ func (f *StacksFeature) decodeStackComponentSources(ctx context.Context, stackSt
 
 	return ids, nil
 }
+
+func (f *StacksFeature) versionFileChange(ctx context.Context, dir document.DirHandle) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	records, err := f.store.List()
+	if err != nil {
+		return ids, err
+	}
+
+	for _, record := range records {
+		stackPath := record.Path()
+		if !tfversion.IsAffectedBy(stackPath, dir.Path()) {
+			continue
+		}
+		stackDir := document.DirHandleFromPath(stackPath)
+
+		versionId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
+			Dir: stackDir,
+			Func: func(ctx context.Context) error {
+				return jobs.LoadTerraformVersion(ctx, f.versionResolver, f.store, stackPath)
+			},
+			Type:        operation.OpTypeLoadStackRequiredTerraformVersion.String(),
+			IgnoreState: true,
+			Defer: func(ctx context.Context, jobErr error) (job.IDs, error) {
+				// Only open stacks need validating against the new schema
+				hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(stackDir)
+				if err != nil || !hasOpenDocs {
+					return job.IDs{}, err
+				}
+				return f.decodeStack(ctx, stackDir, true, true)
+			},
+		})
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, versionId)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,23 @@
+component "network" {
+  source = "./network"
+
+  inputs = {
+    instance_ids = component.compute.instance_ids
+  }
+}
+
+component "compute" {
+  source = "./compute"
+
+  inputs = {
+    subnet_id = component.network.subnet_id
+  }
+}
+
+component "dns" {
+  source = "./dns"
+
+  inputs = {
+    instance_ids = component.compute.instance_ids
+  }
+}
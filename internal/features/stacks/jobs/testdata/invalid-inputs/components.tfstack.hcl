@@ -0,0 +1,25 @@
+variable "region" {
+  type = string
+}
+
+variable "instances" {
+  type = number
+}
+
+component "network" {
+  source = "./network"
+
+  inputs = {
+    cidr_block = ["10.0.0.0/16"]
+    region     = var.region
+    unknown    = "foo"
+  }
+}
+
+component "compute" {
+  source = "./compute"
+
+  inputs = {
+    subnet_ids = component.network.subnet_id
+  }
+}
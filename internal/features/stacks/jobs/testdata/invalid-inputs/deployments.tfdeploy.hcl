@@ -0,0 +1,17 @@
+deployment "production" {
+  inputs = {
+    region    = "eu-west-2"
+    instances = "many"
+  }
+}
+
+deployment "development" {
+  inputs = {
+    region  = "eu-west-1"
+    missing = true
+  }
+}
+
+deployment "staging" {
+  inputs = {}
+}
@@ -145,6 +145,7 @@ func ReferenceValidation(ctx context.Context, stackStore *state.StackStore, modu
 		ModuleReader: moduleFeature,
 		RootReader:   rootFeature,
 	}
+	graph := stackDecoder.DependencyGraph(record.ParsedFiles)
 
 	stackDecoder, err := pathReader.PathContext(lang.Path{
 		Path:       stackPath,
@@ -167,5 +168,8 @@ func ReferenceValidation(ctx context.Context, stackStore *state.StackStore, modu
 	deployDiags := validations.UnreferencedOrigins(ctx, deployDecoder)
 	diags = diags.Extend(deployDiags)
 
+	// Components referring to each other in a cycle cannot be planned
+	diags = diags.Extend(lang.DiagnosticsMap(graph.CycleDiagnostics()))
+
 	return stackStore.UpdateDiagnostics(stackPath, globalAst.ReferenceValidationSource, ast.DiagnosticsFromMap(diags))
 }
@@ -5,17 +5,21 @@ package jobs
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/go-version"
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	stackAst "github.com/hashicorp/terraform-ls/internal/features/stacks/ast"
 	"github.com/hashicorp/terraform-ls/internal/features/stacks/state"
 	"github.com/hashicorp/terraform-ls/internal/filesystem"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	globalState "github.com/hashicorp/terraform-ls/internal/state"
 	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	tfmod "github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type ModuleReaderMock struct{}
@@ -34,6 +38,14 @@ func (r RootReaderMock) TerraformVersion(modPath string) *version.Version {
 	return nil
 }
 
+func (r RootReaderMock) PinnedTerraformVersion(modPath string) *version.Version {
+	return nil
+}
+
+func (r RootReaderMock) IsOpenTofu(modPath string) bool {
+	return false
+}
+
 func (r RootReaderMock) InstalledModulePath(rootPath string, normalizedSource string) (string, bool) {
 	return "", false
 }
@@ -80,7 +92,7 @@ func TestSchemaStackValidation_FullStack(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expectedCount := 3
+	expectedCount := 4
 	diagsCount := record.Diagnostics[ast.SchemaValidationSource].Count()
 	if diagsCount != expectedCount {
 		t.Fatalf("expected %d diagnostics, %d given", expectedCount, diagsCount)
@@ -135,3 +147,175 @@ func TestSchemaStackValidation_SingleFile(t *testing.T) {
 		t.Fatalf("expected %d diagnostics, %d given", expectedCount, diagsCount)
 	}
 }
+
+type moduleMetaReaderMock map[string]*tfmod.Meta
+
+func (m moduleMetaReaderMock) LocalModuleMeta(modulePath string) (*tfmod.Meta, error) {
+	meta, ok := m[modulePath]
+	if !ok {
+		return nil, fmt.Errorf("module not found: %s", modulePath)
+	}
+	return meta, nil
+}
+
+func TestSchemaStackValidation_Inputs(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := state.NewStackStore(gs.ChangeStore, gs.ProviderSchemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stackPath := filepath.Join(testData, "invalid-inputs")
+
+	err = ss.Add(stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moduleReader := moduleMetaReaderMock{
+		filepath.Join(stackPath, "network"): {
+			Path:      filepath.Join(stackPath, "network"),
+			Filenames: []string{"main.tf"},
+			Variables: map[string]tfmod.Variable{
+				"cidr_block": {
+					Type: cty.String,
+				},
+				"region": {
+					Type:         cty.String,
+					DefaultValue: cty.StringVal("eu-west-1"),
+				},
+			},
+			Outputs: map[string]tfmod.Output{
+				"subnet_id": {
+					Value: cty.StringVal("subnet-123"),
+				},
+			},
+		},
+		filepath.Join(stackPath, "compute"): {
+			Path:      filepath.Join(stackPath, "compute"),
+			Filenames: []string{"main.tf"},
+			Variables: map[string]tfmod.Variable{
+				"subnet_ids": {
+					Type: cty.List(cty.String),
+				},
+			},
+		},
+	}
+
+	fs := filesystem.NewFilesystem(gs.DocumentStore)
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{
+		Method:     "textDocument/didOpen",
+		LanguageID: ilsp.Stacks.String(),
+		URI:        "file:///test/components.tfstack.hcl",
+	})
+	err = ParseStackConfiguration(ctx, fs, ss, stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadStackMetadata(ctx, ss, stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = DecodeReferenceTargets(ctx, ss, moduleReader, RootReaderMock{}, stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SchemaStackValidation(ctx, ss, moduleReader, RootReaderMock{}, stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := ss.StackRecordByPath(stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSummaries := map[string][]string{
+		"components.tfstack.hcl": {
+			`Invalid value for input "cidr_block"`,
+			`Unexpected input "unknown"`,
+			`Invalid value for input "subnet_ids"`,
+		},
+		"deployments.tfdeploy.hcl": {
+			`Invalid value for input "instances"`,
+			`Unexpected input "missing"`,
+			`Required input "instances" not specified`,
+			`Required input "instances" not specified`,
+			`Required input "region" not specified`,
+		},
+	}
+
+	for filename, summaries := range expectedSummaries {
+		diags := record.Diagnostics[ast.SchemaValidationSource][stackAst.FilenameFromName(filename)]
+		given := make([]string, 0, len(diags))
+		for _, diag := range diags {
+			given = append(given, diag.Summary)
+		}
+		if diff := cmp.Diff(summaries, given); diff != "" {
+			t.Fatalf("unexpected diagnostics for %s: %s", filename, diff)
+		}
+	}
+}
+
+func TestReferenceValidation_componentCycle(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := state.NewStackStore(gs.ChangeStore, gs.ProviderSchemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stackPath := filepath.Join(testData, "component-cycle")
+
+	err = ss.Add(stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := filesystem.NewFilesystem(gs.DocumentStore)
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseStackConfiguration(ctx, fs, ss, stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadStackMetadata(ctx, ss, stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ReferenceValidation(ctx, ss, moduleMetaReaderMock{}, RootReaderMock{}, stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := ss.StackRecordByPath(stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := record.Diagnostics[ast.ReferenceValidationSource][stackAst.StackFilename("components.tfstack.hcl")]
+	lines := make([]int, 0)
+	for _, diag := range diags {
+		if diag.Summary == "Dependency cycle" {
+			lines = append(lines, diag.Subject.Start.Line)
+		}
+	}
+	expectedLines := []int{13, 5}
+	if diff := cmp.Diff(expectedLines, lines); diff != "" {
+		t.Fatalf("unexpected cycle diagnostics: %s", diff)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/filesystem"
 	globalState "github.com/hashicorp/terraform-ls/internal/state"
 	"github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfversion"
 )
 
 func TestLoadTerraformVersion(t *testing.T) {
@@ -41,7 +42,7 @@ func TestLoadTerraformVersion(t *testing.T) {
 	}
 
 	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-	err = LoadTerraformVersion(ctx, testFs, ss, stacksVersionFilePath)
+	err = LoadTerraformVersion(ctx, tfversion.NewResolver(testFs), ss, stacksVersionFilePath)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -64,6 +65,54 @@ func TestLoadTerraformVersion(t *testing.T) {
 	}
 }
 
+func TestLoadTerraformVersion_noVersionFile(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := state.NewStackStore(gs.ChangeStore, gs.ProviderSchemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFs := filesystem.NewFilesystem(gs.DocumentStore)
+
+	stackPath := filepath.Join(testData, "stacks-version-file-none")
+
+	err = ss.Add(stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = LoadTerraformVersion(ctx, tfversion.NewResolver(testFs), ss, stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := ss.StackRecordByPath(stackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record.RequiredTerraformVersion != nil {
+		t.Fatalf("expected nil version, got %s", record.RequiredTerraformVersion.String())
+	}
+
+	if record.RequiredTerraformVersionState != operation.OpStateLoaded {
+		t.Fatalf("expected state %s, got %s", operation.OpStateLoaded, record.RequiredTerraformVersionState)
+	}
+
+	if record.RequiredTerraformVersionErr != nil {
+		t.Fatalf("expected nil error, got %s", record.RequiredTerraformVersionErr)
+	}
+}
+
 func TestLoadTerraformVersion_invalid(t *testing.T) {
 
 	testCases := []struct {
@@ -74,10 +123,6 @@ func TestLoadTerraformVersion_invalid(t *testing.T) {
 			"invalid-version",
 			"stacks-version-file-invalid",
 		},
-		{
-			"no-version-file",
-			"stacks-version-file-none",
-		},
 	}
 
 	for i, tc := range testCases {
@@ -106,7 +151,7 @@ func TestLoadTerraformVersion_invalid(t *testing.T) {
 			}
 
 			ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
-			err = LoadTerraformVersion(ctx, testFs, ss, stacksVersionFilePath)
+			err = LoadTerraformVersion(ctx, tfversion.NewResolver(testFs), ss, stacksVersionFilePath)
 			if err == nil {
 				t.Fatal("expected error, got nil")
 			}
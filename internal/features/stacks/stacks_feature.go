@@ -6,6 +6,7 @@ package stacks
 import (
 	"context"
 	"io"
+	"io/fs"
 	"log"
 
 	"github.com/hashicorp/hcl-lang/decoder"
@@ -14,9 +15,13 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/features/modules/jobs"
 	stackDecoder "github.com/hashicorp/terraform-ls/internal/features/stacks/decoder"
 	"github.com/hashicorp/terraform-ls/internal/features/stacks/state"
+	"github.com/hashicorp/terraform-ls/internal/graph"
 	"github.com/hashicorp/terraform-ls/internal/job"
 	"github.com/hashicorp/terraform-ls/internal/langserver/diagnostics"
+	"github.com/hashicorp/terraform-ls/internal/schemas"
 	globalState "github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfversion"
+	tfstack "github.com/hashicorp/terraform-schema/stack"
 )
 
 type StacksFeature struct {
@@ -24,9 +29,12 @@ type StacksFeature struct {
 	stateStore *globalState.StateStore
 	bus        *eventbus.EventBus
 	fs         jobs.ReadOnlyFS
+	schemaFS   fs.ReadDirFS
 	logger     *log.Logger
 	stopFunc   context.CancelFunc
 
+	versionResolver *tfversion.Resolver
+
 	moduleFeature stackDecoder.ModuleReader
 	rootFeature   stackDecoder.RootReader
 }
@@ -39,14 +47,16 @@ func NewStacksFeature(bus *eventbus.EventBus, stateStore *globalState.StateStore
 	discardLogger := log.New(io.Discard, "", 0)
 
 	return &StacksFeature{
-		store:         store,
-		bus:           bus,
-		fs:            fs,
-		stateStore:    stateStore,
-		logger:        discardLogger,
-		stopFunc:      func() {},
-		moduleFeature: moduleFeature,
-		rootFeature:   rootFeature,
+		store:           store,
+		bus:             bus,
+		fs:              fs,
+		schemaFS:        schemas.FS,
+		versionResolver: tfversion.NewResolver(fs),
+		stateStore:      stateStore,
+		logger:          discardLogger,
+		stopFunc:        func() {},
+		moduleFeature:   moduleFeature,
+		rootFeature:     rootFeature,
 	}, nil
 }
 
@@ -55,6 +65,18 @@ func (f *StacksFeature) SetLogger(logger *log.Logger) {
 	f.store.SetLogger(logger)
 }
 
+// SetSchemaFS sets the file system provider schemas
+// are preloaded from, which defaults to the embedded schemas
+func (f *StacksFeature) SetSchemaFS(schemaFS fs.ReadDirFS) {
+	f.schemaFS = schemaFS
+}
+
+// SetVersionResolver sets the resolver of pinned Terraform versions,
+// so that it can be shared (and invalidated) across features
+func (f *StacksFeature) SetVersionResolver(resolver *tfversion.Resolver) {
+	f.versionResolver = resolver
+}
+
 // Start starts the features separate goroutine.
 // It listens to various events from the EventBus and performs corresponding actions.
 func (f *StacksFeature) Start(ctx context.Context) {
@@ -71,6 +93,8 @@ func (f *StacksFeature) Start(ctx context.Context) {
 	didOpen := f.bus.OnDidOpen(topic, didOpenDone)
 	didChange := f.bus.OnDidChange(topic, didChangeDone)
 	didChangeWatched := f.bus.OnDidChangeWatched(topic, didChangeWatchedDone)
+	versionFileChangeDone := make(chan job.IDs, 10)
+	versionFileChange := f.bus.OnVersionFileChange(topic, versionFileChangeDone)
 
 	go func() {
 		for {
@@ -90,6 +114,10 @@ func (f *StacksFeature) Start(ctx context.Context) {
 				// TODO? collect errors
 				spawnedIds, _ := f.didChangeWatched(didChangeWatched.Context, didChangeWatched.RawPath, didChangeWatched.ChangeType, didChangeWatched.IsDir)
 				didChangeWatchedDone <- spawnedIds
+			case versionFileChange := <-versionFileChange:
+				// TODO? collect errors
+				spawnedIds, _ := f.versionFileChange(versionFileChange.Context, versionFileChange.Dir)
+				versionFileChangeDone <- spawnedIds
 
 			case <-ctx.Done():
 				return
@@ -154,3 +182,24 @@ func (f *StacksFeature) Telemetry(path string) map[string]interface{} {
 
 	return properties
 }
+
+// DependencyGraph returns the graph of components and deployments
+// of the stack at the given path.
+func (f *StacksFeature) DependencyGraph(path string) (*graph.Graph, error) {
+	record, err := f.store.StackRecordByPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return stackDecoder.DependencyGraph(record.ParsedFiles), nil
+}
+
+// Deployments returns the deployments declared for the stack at the given path
+func (f *StacksFeature) Deployments(path string) (map[string]tfstack.Deployment, error) {
+	record, err := f.store.StackRecordByPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return record.Meta.Deployments, nil
+}
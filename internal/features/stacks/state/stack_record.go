@@ -114,6 +114,7 @@ func newStack(stackPath string) *StackRecord {
 			globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 			globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 			globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+			globalAst.TerraformPlanSource:       operation.OpStateUnknown,
 		},
 	}
 }
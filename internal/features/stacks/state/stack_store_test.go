@@ -91,6 +91,7 @@ func TestStackStore_StackRecordByPath(t *testing.T) {
 			globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 			globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 			globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+			globalAst.TerraformPlanSource:       operation.OpStateUnknown,
 		},
 	}
 	if diff := cmp.Diff(expectedRecord, record, cmpOpts); diff != "" {
@@ -135,6 +136,7 @@ func TestStackStore_List(t *testing.T) {
 				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 				globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+				globalAst.TerraformPlanSource:       operation.OpStateUnknown,
 			},
 		},
 		{
@@ -144,6 +146,7 @@ func TestStackStore_List(t *testing.T) {
 				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 				globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+				globalAst.TerraformPlanSource:       operation.OpStateUnknown,
 			},
 		},
 		{
@@ -153,6 +156,7 @@ func TestStackStore_List(t *testing.T) {
 				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 				globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+				globalAst.TerraformPlanSource:       operation.OpStateUnknown,
 			},
 		},
 	}
@@ -211,6 +215,7 @@ func TestStackStore_UpdateMetadata(t *testing.T) {
 			globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 			globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 			globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+			globalAst.TerraformPlanSource:       operation.OpStateUnknown,
 		},
 	}
 
@@ -257,6 +262,7 @@ func TestStackStore_SetTerraformVersion(t *testing.T) {
 			globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 			globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 			globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+			globalAst.TerraformPlanSource:       operation.OpStateUnknown,
 		},
 	}
 
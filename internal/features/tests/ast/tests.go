@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/opentofu"
 )
 
 type Filename interface {
@@ -33,7 +34,8 @@ func (mf TestFilename) IsIgnored() bool {
 
 func IsTestFilename(name string) bool {
 	return strings.HasSuffix(name, ".tftest.hcl") ||
-		strings.HasSuffix(name, ".tftest.json")
+		strings.HasSuffix(name, ".tftest.json") ||
+		opentofu.IsTestFilename(name)
 }
 
 // MockFilename is a custom type for mock configuration files
@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/features/tests/ast"
 	"github.com/hashicorp/terraform-ls/internal/features/tests/state"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfversion"
 	tfaddr "github.com/hashicorp/terraform-registry-address"
 	tfmod "github.com/hashicorp/terraform-schema/module"
 	tfschema "github.com/hashicorp/terraform-schema/schema"
@@ -40,6 +41,8 @@ type ModuleReader interface {
 
 type RootReader interface {
 	TerraformVersion(modPath string) *version.Version
+	PinnedTerraformVersion(modPath string) *version.Version
+	IsOpenTofu(modPath string) bool
 }
 
 type CombinedReader struct {
@@ -77,10 +80,8 @@ func (pr *PathReader) PathContext(path lang.Path) (*decoder.PathContext, error)
 
 func testPathContext(record *state.TestRecord, stateReader CombinedReader) (*decoder.PathContext, error) {
 	// TODO! this should only work for terraform 1.6 and above
-	version := stateReader.TerraformVersion(record.Path())
-	if version == nil {
-		version = tfschema.LatestAvailableVersion
-	}
+	version := tfversion.Resolve(stateReader.PinnedTerraformVersion(record.Path()),
+		nil, stateReader.TerraformVersion(record.Path()))
 
 	schema, err := testschema.CoreTestSchemaForVersion(version)
 	if err != nil {
@@ -131,10 +132,8 @@ func testPathContext(record *state.TestRecord, stateReader CombinedReader) (*dec
 
 func mockPathContext(record *state.TestRecord, stateReader CombinedReader) (*decoder.PathContext, error) {
 	// TODO! this should only work for terraform 1.7 and above
-	version := stateReader.TerraformVersion(record.Path())
-	if version == nil {
-		version = tfschema.LatestAvailableVersion
-	}
+	version := tfversion.Resolve(stateReader.PinnedTerraformVersion(record.Path()),
+		nil, stateReader.TerraformVersion(record.Path()))
 
 	schema, err := testschema.CoreMockSchemaForVersion(version)
 	if err != nil {
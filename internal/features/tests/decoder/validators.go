@@ -5,15 +5,16 @@ package decoder
 
 import (
 	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 )
 
 var validators = []validator.Validator{
-	validator.BlockLabelsLength{},
-	validator.DeprecatedAttribute{},
-	validator.DeprecatedBlock{},
-	validator.MaxBlocks{},
-	validator.MinBlocks{},
-	validator.MissingRequiredAttribute{},
-	validator.UnexpectedAttribute{},
-	validator.UnexpectedBlock{},
+	rules.Validator(rules.BlockLabelsLength, validator.BlockLabelsLength{}),
+	rules.Validator(rules.DeprecatedAttribute, validator.DeprecatedAttribute{}),
+	rules.Validator(rules.DeprecatedBlock, validator.DeprecatedBlock{}),
+	rules.Validator(rules.MaxBlocks, validator.MaxBlocks{}),
+	rules.Validator(rules.MinBlocks, validator.MinBlocks{}),
+	rules.Validator(rules.MissingRequiredAttribute, validator.MissingRequiredAttribute{}),
+	rules.Validator(rules.UnexpectedAttribute, validator.UnexpectedAttribute{}),
+	rules.Validator(rules.UnexpectedBlock, validator.UnexpectedBlock{}),
 }
@@ -167,7 +167,7 @@ func (f *TestsFeature) decodeTest(ctx context.Context, dir document.DirHandle, i
 	parseId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
 		Dir: dir,
 		Func: func(ctx context.Context) error {
-			return jobs.ParseTestConfiguration(ctx, f.fs, f.store, path)
+			return jobs.ParseTestConfiguration(ctx, f.fs, f.store, path, f.rootFeature.IsOpenTofu(path))
 		},
 		Type:        op.OpTypeParseTestConfiguration.String(),
 		IgnoreState: ignoreState,
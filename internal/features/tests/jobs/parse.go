@@ -16,12 +16,13 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/lsp"
 	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	"github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/terraform/opentofu"
 	"github.com/hashicorp/terraform-ls/internal/uri"
 )
 
 // ParseTestConfiguration parses the whole test configuration,
 // i.e. turns bytes of `*.tftest.hcl` & `*.tfmock.hcl` files into AST ([*hcl.File]).
-func ParseTestConfiguration(ctx context.Context, fs ReadOnlyFS, testStore *state.TestStore, testPath string) error {
+func ParseTestConfiguration(ctx context.Context, fs ReadOnlyFS, testStore *state.TestStore, testPath string, isOpenTofu bool) error {
 	record, err := testStore.TestRecordByPath(testPath)
 	if err != nil {
 		return err
@@ -55,6 +56,10 @@ func ParseTestConfiguration(ctx context.Context, fs ReadOnlyFS, testStore *state
 			return err
 		}
 		fileName := filepath.Base(filePath)
+		if !isOpenTofu && opentofu.IsTestFilename(fileName) {
+			// Terraform ignores OpenTofu-specific test files
+			return testStore.SetDiagnosticsState(testPath, globalAst.HCLParsingSource, operation.OpStateLoaded)
+		}
 
 		pFile, fDiags, err := parser.ParseFile(fs, filePath)
 		if err != nil {
@@ -80,7 +85,7 @@ func ParseTestConfiguration(ctx context.Context, fs ReadOnlyFS, testStore *state
 			return err
 		}
 
-		files, diags, err = parser.ParseFiles(fs, testPath)
+		files, diags, err = parser.ParseFiles(fs, testPath, isOpenTofu)
 	}
 
 	sErr := testStore.UpdateParsedFiles(testPath, files, err)
@@ -8,10 +8,15 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/terraform-ls/internal/features/tests/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/opentofu"
 	"github.com/hashicorp/terraform-ls/internal/terraform/parser"
 )
 
-func ParseFiles(fs parser.FS, testPath string) (ast.Files, ast.Diagnostics, error) {
+// ParseFiles parses all test and mock files in testPath.
+// Test files ignored by the distribution which the module targets
+// are skipped, i.e. Terraform ignores *.tofutest.hcl files, while
+// OpenTofu ignores *.tftest.hcl files shadowed by *.tofutest.hcl files.
+func ParseFiles(fs parser.FS, testPath string, isOpenTofu bool) (ast.Files, ast.Diagnostics, error) {
 	files := make(ast.Files, 0)
 	diags := make(ast.Diagnostics, 0)
 
@@ -20,6 +25,14 @@ func ParseFiles(fs parser.FS, testPath string) (ast.Files, ast.Diagnostics, erro
 		return nil, nil, err
 	}
 
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if !info.IsDir() {
+			names = append(names, info.Name())
+		}
+	}
+	overridden := opentofu.OverriddenFilenames(names)
+
 	for _, info := range infos {
 		if info.IsDir() {
 			// We only care about files
@@ -30,6 +43,12 @@ func ParseFiles(fs parser.FS, testPath string) (ast.Files, ast.Diagnostics, erro
 		if !ast.IsTestFilename(name) && !ast.IsMockFilename(name) {
 			continue
 		}
+		if !isOpenTofu && opentofu.IsTestFilename(name) {
+			continue
+		}
+		if isOpenTofu && overridden[name] {
+			continue
+		}
 
 		fullPath := filepath.Join(testPath, name)
 
@@ -101,6 +101,7 @@ func newTest(testPath string) *TestRecord {
 			globalAst.SchemaValidationSource:    op.OpStateUnknown,
 			globalAst.ReferenceValidationSource: op.OpStateUnknown,
 			globalAst.TerraformValidateSource:   op.OpStateUnknown,
+			globalAst.TerraformPlanSource:       op.OpStateUnknown,
 		},
 	}
 }
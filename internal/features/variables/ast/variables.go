@@ -89,6 +89,17 @@ func (vd VarsDiags) AutoloadedOnly() VarsDiags {
 	return diags
 }
 
+// Filter returns only diagnostics of files for which keep returns true
+func (vd VarsDiags) Filter(keep func(name VarsFilename) bool) VarsDiags {
+	diags := make(VarsDiags)
+	for name, f := range vd {
+		if keep(name) {
+			diags[name] = f
+		}
+	}
+	return diags
+}
+
 func (vd VarsDiags) AsMap() map[string]hcl.Diagnostics {
 	m := make(map[string]hcl.Diagnostics, len(vd))
 	for name, diags := range vd {
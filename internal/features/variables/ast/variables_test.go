@@ -40,3 +40,22 @@ func TestVarsDiags_autoloadedOnly(t *testing.T) {
 		t.Fatalf("unexpected diagnostics: %s", diff)
 	}
 }
+
+func TestVarsDiags_filter(t *testing.T) {
+	vd := VarsDiagsFromMap(map[string]hcl.Diagnostics{
+		"alpha.tfvars":     {},
+		"prod.tfvars":      {},
+		"terraform.tfvars": {},
+	})
+	diags := vd.Filter(func(name VarsFilename) bool {
+		return name.IsAutoloaded() || name == "prod.tfvars"
+	}).AsMap()
+	expectedDiags := map[string]hcl.Diagnostics{
+		"prod.tfvars":      {},
+		"terraform.tfvars": {},
+	}
+
+	if diff := cmp.Diff(expectedDiags, diags, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
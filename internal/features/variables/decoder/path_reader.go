@@ -25,9 +25,14 @@ type ModuleReader interface {
 }
 
 type PathReader struct {
-	StateReader  StateReader
-	ModuleReader ModuleReader
-	UseAnySchema bool
+	StateReader    StateReader
+	ModuleReader   ModuleReader
+	VarFileModules VarFileModules
+	UseAnySchema   bool
+
+	// ModulePath restricts path contexts to variable files which
+	// belong to the module at the given path, if set
+	ModulePath string
 }
 
 var _ decoder.PathReader = &PathReader{}
@@ -56,5 +61,5 @@ func (pr *PathReader) PathContext(path lang.Path) (*decoder.PathContext, error)
 	if err != nil {
 		return nil, err
 	}
-	return variablePathContext(mod, pr.ModuleReader, pr.UseAnySchema)
+	return variablePathContext(mod, pr.ModuleReader, pr.VarFileModules, pr.UseAnySchema, pr.ModulePath)
 }
@@ -5,9 +5,10 @@ package decoder
 
 import (
 	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 )
 
 var varsValidators = []validator.Validator{
-	validator.UnexpectedAttribute{},
-	validator.UnexpectedBlock{},
+	rules.Validator(rules.UnexpectedAttribute, validator.UnexpectedAttribute{}),
+	rules.Validator(rules.UnexpectedBlock, validator.UnexpectedBlock{}),
 }
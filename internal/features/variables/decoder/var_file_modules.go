@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/terraform-ls/internal/features/variables/ast"
+	"github.com/hashicorp/terraform-ls/internal/features/variables/state"
+)
+
+// VarFileModule maps variable files which are not loaded
+// automatically (e.g. passed via -var-file) to a root module
+type VarFileModule struct {
+	// Pattern is an absolute path of the variable file(s),
+	// which may contain glob patterns as supported by [filepath.Match]
+	Pattern string
+
+	// ModulePath is an absolute path of the root module
+	ModulePath string
+}
+
+type VarFileModules []VarFileModule
+
+// ModulePath returns the path of the root module which the variable
+// file at the given path is mapped to. The first matching entry wins.
+//
+// Autoloaded files are never mapped, as Terraform
+// always loads them for the module in the same directory.
+func (m VarFileModules) ModulePath(filePath string) (string, bool) {
+	if ast.VarsFilename(filepath.Base(filePath)).IsAutoloaded() {
+		return "", false
+	}
+	for _, vfm := range m {
+		matched, err := filepath.Match(vfm.Pattern, filePath)
+		if err == nil && matched {
+			return vfm.ModulePath, true
+		}
+	}
+	return "", false
+}
+
+// IsMapped reports whether the variable file with the given name
+// in the given directory is mapped to any root module
+func (m VarFileModules) IsMapped(dir string, name ast.VarsFilename) bool {
+	_, ok := m.ModulePath(filepath.Join(dir, name.String()))
+	return ok
+}
+
+// FileModulePath returns the path of the module whose variables apply
+// to the variable file with the given name in the given directory,
+// i.e. the module it is mapped to, or the module in the same directory.
+func FileModulePath(dir string, name ast.VarsFilename, varFileModules VarFileModules) string {
+	modPath, ok := varFileModules.ModulePath(filepath.Join(dir, name.String()))
+	if !ok {
+		return dir
+	}
+	return modPath
+}
+
+// ModulePaths returns paths of all modules whose variables apply
+// to the variable files in the given record, i.e. the module in the same
+// directory first, followed by any modules the files are mapped to.
+func ModulePaths(record *state.VariableRecord, varFileModules VarFileModules) []string {
+	paths := []string{record.Path()}
+
+	mapped := make(map[string]bool)
+	for name := range record.ParsedVarsFiles {
+		modPath := FileModulePath(record.Path(), name, varFileModules)
+		if modPath != record.Path() {
+			mapped[modPath] = true
+		}
+	}
+
+	mappedPaths := make([]string, 0, len(mapped))
+	for modPath := range mapped {
+		mappedPaths = append(mappedPaths, modPath)
+	}
+	sort.Strings(mappedPaths)
+
+	return append(paths, mappedPaths...)
+}
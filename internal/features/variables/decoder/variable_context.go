@@ -10,17 +10,31 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/terraform-ls/internal/features/variables/ast"
 	"github.com/hashicorp/terraform-ls/internal/features/variables/state"
+	tfmod "github.com/hashicorp/terraform-schema/module"
 	tfschema "github.com/hashicorp/terraform-schema/schema"
 )
 
-func variablePathContext(mod *state.VariableRecord, moduleReader ModuleReader, useAnySchema bool) (*decoder.PathContext, error) {
-	variables, _ := moduleReader.ModuleInputs(mod.Path())
+// variablePathContext returns a PathContext for variable files in the
+// given record. If modPath is set, it only covers the files which belong
+// to that module (see [FileModulePath]) and the variables it declares.
+func variablePathContext(mod *state.VariableRecord, moduleReader ModuleReader, varFileModules VarFileModules, useAnySchema bool, modPath string) (*decoder.PathContext, error) {
+	declPath, variables := modPath, make(map[string]tfmod.Variable)
+	if modPath == "" {
+		declPath, variables = moduleVariables(mod, moduleReader, varFileModules)
+	} else if inputs, err := moduleReader.ModuleInputs(modPath); err == nil {
+		variables = inputs
+	}
+	isFileIncluded := func(filename string) bool {
+		return modPath == "" ||
+			FileModulePath(mod.Path(), ast.VarsFilename(filename), varFileModules) == modPath
+	}
+
 	bodySchema := &schema.BodySchema{}
 	if useAnySchema {
-		bodySchema = tfschema.AnySchemaForVariableCollection(mod.Path())
+		bodySchema = tfschema.AnySchemaForVariableCollection(declPath)
 	} else {
 		var err error
-		bodySchema, err = tfschema.SchemaForVariables(variables, mod.Path())
+		bodySchema, err = tfschema.SchemaForVariables(variables, declPath)
 		if err != nil {
 			return nil, err
 		}
@@ -41,14 +55,49 @@ func variablePathContext(mod *state.VariableRecord, moduleReader ModuleReader, u
 	}
 
 	for _, origin := range mod.VarsRefOrigins {
-		if ast.IsVarsFilename(origin.OriginRange().Filename) {
+		filename := origin.OriginRange().Filename
+		if ast.IsVarsFilename(filename) && isFileIncluded(filename) {
 			pathCtx.ReferenceOrigins = append(pathCtx.ReferenceOrigins, origin)
 		}
 	}
 
 	for name, f := range mod.ParsedVarsFiles {
-		pathCtx.Files[name.String()] = f
+		if isFileIncluded(name.String()) {
+			pathCtx.Files[name.String()] = f
+		}
 	}
 
 	return pathCtx, nil
 }
+
+// moduleVariables returns variables of all modules which apply to
+// the variable files in the given record, along with the path of the
+// module which declares them. When files in the same directory are
+// mapped to different modules, their variables are combined.
+//
+// This is only used where the file in question isn't known
+// (e.g. completion), otherwise the variables of the module
+// the file belongs to are used.
+func moduleVariables(mod *state.VariableRecord, moduleReader ModuleReader, varFileModules VarFileModules) (string, map[string]tfmod.Variable) {
+	var declPath string
+	variables := make(map[string]tfmod.Variable)
+	for _, modPath := range ModulePaths(mod, varFileModules) {
+		inputs, err := moduleReader.ModuleInputs(modPath)
+		if err != nil {
+			continue
+		}
+		if declPath == "" {
+			declPath = modPath
+		}
+		for name, variable := range inputs {
+			if _, ok := variables[name]; !ok {
+				variables[name] = variable
+			}
+		}
+	}
+	if declPath == "" {
+		declPath = mod.Path()
+	}
+
+	return declPath, variables
+}
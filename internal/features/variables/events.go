@@ -7,10 +7,12 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
 	"github.com/hashicorp/terraform-ls/internal/document"
 	"github.com/hashicorp/terraform-ls/internal/features/variables/ast"
+	fdecoder "github.com/hashicorp/terraform-ls/internal/features/variables/decoder"
 	"github.com/hashicorp/terraform-ls/internal/features/variables/jobs"
 	"github.com/hashicorp/terraform-ls/internal/job"
 	"github.com/hashicorp/terraform-ls/internal/protocol"
@@ -60,12 +62,19 @@ func (f *VariablesFeature) didOpen(ctx context.Context, dir document.DirHandle,
 }
 
 func (f *VariablesFeature) didChange(ctx context.Context, dir document.DirHandle) (job.IDs, error) {
+	// Variable declarations in the directory may have changed
+	ids, err := f.revalidateMappedVariables(ctx, dir)
+	if err != nil {
+		return ids, err
+	}
+
 	hasVariableRecord := f.store.Exists(dir.Path())
 	if !hasVariableRecord {
-		return job.IDs{}, nil
+		return ids, nil
 	}
 
-	return f.decodeVariable(ctx, dir, true)
+	varIds, err := f.decodeVariable(ctx, dir, true)
+	return append(ids, varIds...), err
 }
 
 func (f *VariablesFeature) didChangeWatched(ctx context.Context, rawPath string, changeType protocol.FileChangeType, isDir bool) (job.IDs, error) {
@@ -122,6 +131,14 @@ func (f *VariablesFeature) didChangeWatched(ctx context.Context, rawPath string,
 
 	if changeType == protocol.Changed {
 		docHandle := document.HandleFromPath(rawPath)
+
+		// Variable declarations in the directory may have changed
+		mappedIds, err := f.revalidateMappedVariables(ctx, docHandle.Dir)
+		if err != nil {
+			f.logger.Printf("error when revalidating variable files mapped to %q: %s", docHandle.Dir, err)
+		}
+		ids = append(ids, mappedIds...)
+
 		// Check if the there are open documents for the path and the
 		// path is a module path. If so, we need to reparse the variable files
 		hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(docHandle.Dir)
@@ -170,6 +187,54 @@ func (f *VariablesFeature) didChangeWatched(ctx context.Context, rawPath string,
 	return ids, nil
 }
 
+// revalidateMappedVariables schedules validation of open variable files
+// in other directories which are mapped to the module in the given
+// directory, since they are affected by changes of its variables.
+func (f *VariablesFeature) revalidateMappedVariables(ctx context.Context, modDir document.DirHandle) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+
+	varFileModules := f.getVarFileModules()
+	if len(varFileModules) == 0 {
+		return ids, nil
+	}
+
+	records, err := f.store.List()
+	if err != nil {
+		return ids, err
+	}
+
+	// Jobs of the module (e.g. loading its metadata) are scheduled
+	// already, since the modules feature handles events first
+	modJobIds, err := f.stateStore.JobStore.ListIncompleteJobsForDir(modDir)
+	if err != nil {
+		return ids, err
+	}
+
+	for _, record := range records {
+		varsPath := record.Path()
+		if varsPath == modDir.Path() || !slices.Contains(fdecoder.ModulePaths(record, varFileModules), modDir.Path()) {
+			continue
+		}
+
+		dir := document.DirHandleFromPath(varsPath)
+		hasOpenDocs, err := f.stateStore.DocumentStore.HasOpenDocuments(dir)
+		if err != nil {
+			return ids, err
+		}
+		if !hasOpenDocs {
+			continue
+		}
+
+		varIds, err := f.decodeParsedVariable(ctx, dir, modJobIds, true)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, varIds...)
+	}
+
+	return ids, nil
+}
+
 func (f *VariablesFeature) removeIndexedVariable(rawPath string) {
 	modHandle := document.DirHandleFromPath(rawPath)
 
@@ -203,13 +268,23 @@ func (f *VariablesFeature) decodeVariable(ctx context.Context, dir document.DirH
 	}
 	ids = append(ids, parseVarsId)
 
+	varIds, err := f.decodeParsedVariable(ctx, dir, job.IDs{parseVarsId}, ignoreState)
+	return append(ids, varIds...), err
+}
+
+// decodeParsedVariable schedules decoding of references and validation
+// of variable files once parsed, i.e. once the given jobs are done
+func (f *VariablesFeature) decodeParsedVariable(ctx context.Context, dir document.DirHandle, dependsOn job.IDs, ignoreState bool) (job.IDs, error) {
+	ids := make(job.IDs, 0)
+	path := dir.Path()
+
 	varsRefsId, err := f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
 		Dir: dir,
 		Func: func(ctx context.Context) error {
-			return jobs.DecodeVarsReferences(ctx, f.store, f.moduleFeature, path)
+			return jobs.DecodeVarsReferences(ctx, f.store, f.moduleFeature, f.getVarFileModules(), path)
 		},
 		Type:        op.OpTypeDecodeVarsReferences.String(),
-		DependsOn:   job.IDs{parseVarsId},
+		DependsOn:   dependsOn,
 		IgnoreState: ignoreState,
 	})
 	if err != nil {
@@ -225,10 +300,10 @@ func (f *VariablesFeature) decodeVariable(ctx context.Context, dir document.DirH
 		_, err = f.stateStore.JobStore.EnqueueJob(ctx, job.Job{
 			Dir: dir,
 			Func: func(ctx context.Context) error {
-				return jobs.SchemaVariablesValidation(ctx, f.store, f.moduleFeature, path)
+				return jobs.SchemaVariablesValidation(ctx, f.store, f.moduleFeature, f.getVarFileModules(), path)
 			},
 			Type:        op.OpTypeSchemaVarsValidation.String(),
-			DependsOn:   job.IDs{parseVarsId},
+			DependsOn:   dependsOn,
 			IgnoreState: ignoreState,
 		})
 		if err != nil {
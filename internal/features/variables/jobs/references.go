@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
 	idecoder "github.com/hashicorp/terraform-ls/internal/decoder"
 	"github.com/hashicorp/terraform-ls/internal/document"
 	fdecoder "github.com/hashicorp/terraform-ls/internal/features/variables/decoder"
@@ -24,7 +25,7 @@ import (
 //
 // This is useful in hovering over those variable names,
 // go-to-definition and go-to-references.
-func DecodeVarsReferences(ctx context.Context, varStore *state.VariableStore, moduleFeature fdecoder.ModuleReader, modPath string) error {
+func DecodeVarsReferences(ctx context.Context, varStore *state.VariableStore, moduleFeature fdecoder.ModuleReader, varFileModules fdecoder.VarFileModules, modPath string) error {
 	mod, err := varStore.VariableRecordByPath(modPath)
 	if err != nil {
 		return err
@@ -42,22 +43,34 @@ func DecodeVarsReferences(ctx context.Context, varStore *state.VariableStore, mo
 		return err
 	}
 
-	d := decoder.NewDecoder(&fdecoder.PathReader{
-		StateReader:  varStore,
-		ModuleReader: moduleFeature,
-		UseAnySchema: true,
-	})
-	d.SetContext(idecoder.DecoderContext(ctx))
+	// Origins of each file refer to the module it belongs to
+	origins := make(reference.Origins, 0)
+	var rErr error
+	for _, fileModPath := range fdecoder.ModulePaths(mod, varFileModules) {
+		d := decoder.NewDecoder(&fdecoder.PathReader{
+			StateReader:    varStore,
+			ModuleReader:   moduleFeature,
+			VarFileModules: varFileModules,
+			UseAnySchema:   true,
+			ModulePath:     fileModPath,
+		})
+		d.SetContext(idecoder.DecoderContext(ctx))
 
-	varsDecoder, err := d.Path(lang.Path{
-		Path:       modPath,
-		LanguageID: ilsp.Tfvars.String(),
-	})
-	if err != nil {
-		return err
+		varsDecoder, err := d.Path(lang.Path{
+			Path:       modPath,
+			LanguageID: ilsp.Tfvars.String(),
+		})
+		if err != nil {
+			return err
+		}
+
+		modOrigins, err := varsDecoder.CollectReferenceOrigins()
+		if err != nil {
+			rErr = err
+		}
+		origins = append(origins, modOrigins...)
 	}
 
-	origins, rErr := varsDecoder.CollectReferenceOrigins()
 	sErr := varStore.UpdateVarsReferenceOrigins(modPath, origins, rErr)
 	if sErr != nil {
 		return sErr
@@ -10,7 +10,6 @@ import (
 
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
-	"github.com/hashicorp/hcl/v2"
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
 	idecoder "github.com/hashicorp/terraform-ls/internal/decoder"
 	"github.com/hashicorp/terraform-ls/internal/document"
@@ -29,7 +28,9 @@ import (
 //
 // It relies on previously parsed AST (via [ParseVariables])
 // and schema, as provided via [LoadModuleMetadata]).
-func SchemaVariablesValidation(ctx context.Context, varStore *state.VariableStore, moduleFeature fdecoder.ModuleReader, modPath string) error {
+// Variable files mapped to other root modules via varFileModules
+// are validated against variables of those modules.
+func SchemaVariablesValidation(ctx context.Context, varStore *state.VariableStore, moduleFeature fdecoder.ModuleReader, varFileModules fdecoder.VarFileModules, modPath string) error {
 	mod, err := varStore.VariableRecordByPath(modPath)
 	if err != nil {
 		return err
@@ -45,47 +46,49 @@ func SchemaVariablesValidation(ctx context.Context, varStore *state.VariableStor
 		return err
 	}
 
-	// We only wait a short period for the module to become ready
+	// We only wait a short period for the module(s) to become ready
 	// If we have to cancel the validation, we will just run it after the next change
-	timer := time.NewTimer(2 * time.Second)
-	defer timer.Stop()
-	wCh, moduleReady, err := moduleFeature.MetadataReady(document.DirHandleFromPath(modPath))
-	if err != nil {
-		return err
-	}
-	if !moduleReady {
-		select {
-		// Wait for module to be ready
-		case <-wCh:
-		// or for the remaining time to pass
-		case <-timer.C:
-		// or context cancellation
-		case <-ctx.Done():
-			return ctx.Err()
+	waitCtx, cancelWait := context.WithTimeout(ctx, 2*time.Second)
+	defer cancelWait()
+	var readyErr error
+	foundModule := false
+	for _, path := range fdecoder.ModulePaths(mod, varFileModules) {
+		wCh, moduleReady, err := moduleFeature.MetadataReady(document.DirHandleFromPath(path))
+		if err != nil {
+			// mapped files may live outside of any module
+			if readyErr == nil {
+				readyErr = err
+			}
+			continue
+		}
+		foundModule = true
+		if !moduleReady {
+			select {
+			// Wait for module to be ready
+			case <-wCh:
+			// or for the remaining time to pass (or context cancellation)
+			case <-waitCtx.Done():
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+			}
 		}
 	}
-
-	d := decoder.NewDecoder(&fdecoder.PathReader{
-		StateReader:  varStore,
-		ModuleReader: moduleFeature,
-	})
-	d.SetContext(idecoder.DecoderContext(ctx))
-
-	moduleDecoder, err := d.Path(lang.Path{
-		Path:       modPath,
-		LanguageID: ilsp.Tfvars.String(),
-	})
-	if err != nil {
-		return err
+	if !foundModule {
+		return readyErr
 	}
 
-	var rErr error
 	rpcContext := lsctx.DocumentContext(ctx)
 	if rpcContext.Method == "textDocument/didChange" && rpcContext.LanguageID == ilsp.Tfvars.String() {
 		filename := path.Base(rpcContext.URI)
 		// We only revalidate a single file that changed
-		var fileDiags hcl.Diagnostics
-		fileDiags, rErr = moduleDecoder.ValidateFile(ctx, filename)
+		// against the module it belongs to
+		fileModPath := fdecoder.FileModulePath(modPath, ast.VarsFilename(filename), varFileModules)
+		moduleDecoder, err := varsModuleDecoder(ctx, varStore, moduleFeature, varFileModules, modPath, fileModPath)
+		if err != nil {
+			return err
+		}
+		fileDiags, rErr := moduleDecoder.ValidateFile(ctx, filename)
 
 		varsDiags, ok := mod.VarsDiagnostics[globalAst.SchemaValidationSource]
 		if !ok {
@@ -97,16 +100,46 @@ func SchemaVariablesValidation(ctx context.Context, varStore *state.VariableStor
 		if sErr != nil {
 			return sErr
 		}
-	} else {
-		// We validate the whole module, e.g. on open
-		var diags lang.DiagnosticsMap
-		diags, rErr = moduleDecoder.Validate(ctx)
+		return rErr
+	}
 
-		sErr := varStore.UpdateVarsDiagnostics(modPath, globalAst.SchemaValidationSource, ast.VarsDiagsFromMap(diags))
-		if sErr != nil {
-			return sErr
+	// We validate the whole module, e.g. on open,
+	// with files validated against the module they belong to
+	var rErr error
+	diags := make(lang.DiagnosticsMap)
+	for _, fileModPath := range fdecoder.ModulePaths(mod, varFileModules) {
+		moduleDecoder, err := varsModuleDecoder(ctx, varStore, moduleFeature, varFileModules, modPath, fileModPath)
+		if err != nil {
+			return err
 		}
+		modDiags, err := moduleDecoder.Validate(ctx)
+		if err != nil {
+			rErr = err
+		}
+		diags = diags.Extend(modDiags)
+	}
+
+	sErr := varStore.UpdateVarsDiagnostics(modPath, globalAst.SchemaValidationSource, ast.VarsDiagsFromMap(diags))
+	if sErr != nil {
+		return sErr
 	}
 
 	return rErr
 }
+
+// varsModuleDecoder returns a decoder of variable files in the given
+// directory which belong to the module at fileModPath
+func varsModuleDecoder(ctx context.Context, varStore *state.VariableStore, moduleFeature fdecoder.ModuleReader, varFileModules fdecoder.VarFileModules, dirPath, fileModPath string) (*decoder.PathDecoder, error) {
+	d := decoder.NewDecoder(&fdecoder.PathReader{
+		StateReader:    varStore,
+		ModuleReader:   moduleFeature,
+		VarFileModules: varFileModules,
+		ModulePath:     fileModPath,
+	})
+	d.SetContext(idecoder.DecoderContext(ctx))
+
+	return d.Path(lang.Path{
+		Path:       dirPath,
+		LanguageID: ilsp.Tfvars.String(),
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
 	"github.com/hashicorp/terraform-ls/internal/document"
+	fdecoder "github.com/hashicorp/terraform-ls/internal/features/variables/decoder"
 	"github.com/hashicorp/terraform-ls/internal/features/variables/state"
 	"github.com/hashicorp/terraform-ls/internal/filesystem"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
@@ -63,7 +64,7 @@ func TestSchemaVarsValidation_FullModule(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = SchemaVariablesValidation(ctx, vs, ModuleReaderMock{}, modPath)
+	err = SchemaVariablesValidation(ctx, vs, ModuleReaderMock{}, nil, modPath)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -116,7 +117,7 @@ func TestSchemaVarsValidation_SingleFile(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = SchemaVariablesValidation(ctx, vs, ModuleReaderMock{}, modPath)
+	err = SchemaVariablesValidation(ctx, vs, ModuleReaderMock{}, nil, modPath)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -161,7 +162,7 @@ func TestSchemaVarsValidation_outsideOfModule(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = SchemaVariablesValidation(ctx, vs, ModuleReaderMock{}, modPath)
+	err = SchemaVariablesValidation(ctx, vs, ModuleReaderMock{}, nil, modPath)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -177,3 +178,160 @@ func TestSchemaVarsValidation_outsideOfModule(t *testing.T) {
 		t.Fatalf("expected %d diagnostics, %d given", expectedCount, diagsCount)
 	}
 }
+
+type mappedModuleReaderMock struct {
+	modPath string
+}
+
+func (r mappedModuleReaderMock) ModuleInputs(modPath string) (map[string]tfmod.Variable, error) {
+	if modPath != r.modPath {
+		return nil, &globalState.RecordNotFoundError{Source: modPath}
+	}
+	return map[string]tfmod.Variable{
+		"foo": {},
+	}, nil
+}
+
+func (r mappedModuleReaderMock) MetadataReady(dir document.DirHandle) (<-chan struct{}, bool, error) {
+	if dir.Path() != r.modPath {
+		return nil, false, &globalState.RecordNotFoundError{Source: dir.Path()}
+	}
+	return nil, true, nil
+}
+
+func TestSchemaVarsValidation_mappedVarFile(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs, err := state.NewVariableStore(gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootPath := filepath.Join(testData, "mapped-tfvars", "root")
+	varsPath := filepath.Join(testData, "mapped-tfvars", "envs")
+
+	err = vs.Add(varsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := filesystem.NewFilesystem(gs.DocumentStore)
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseVariables(ctx, fs, vs, varsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	varFileModules := fdecoder.VarFileModules{
+		{
+			Pattern:    filepath.Join(varsPath, "*.tfvars"),
+			ModulePath: rootPath,
+		},
+	}
+	err = SchemaVariablesValidation(ctx, vs, mappedModuleReaderMock{rootPath}, varFileModules, varsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := vs.VariableRecordByPath(varsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := mod.VarsDiagnostics[ast.SchemaValidationSource]["prod.tfvars"]
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, %d given: %#v", len(diags), diags)
+	}
+	expectedSummary := "Unexpected attribute"
+	if diags[0].Summary != expectedSummary {
+		t.Fatalf("expected summary %q, given: %q", expectedSummary, diags[0].Summary)
+	}
+}
+
+type moduleInputsMock map[string]map[string]tfmod.Variable
+
+func (m moduleInputsMock) ModuleInputs(modPath string) (map[string]tfmod.Variable, error) {
+	inputs, ok := m[modPath]
+	if !ok {
+		return nil, &globalState.RecordNotFoundError{Source: modPath}
+	}
+	return inputs, nil
+}
+
+func (m moduleInputsMock) MetadataReady(dir document.DirHandle) (<-chan struct{}, bool, error) {
+	if _, ok := m[dir.Path()]; !ok {
+		return nil, false, &globalState.RecordNotFoundError{Source: dir.Path()}
+	}
+	return nil, true, nil
+}
+
+func TestSchemaVarsValidation_varFilesMappedToDifferentModules(t *testing.T) {
+	ctx := context.Background()
+	gs, err := globalState.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs, err := state.NewVariableStore(gs.ChangeStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prodPath := filepath.Join(testData, "mapped-tfvars-multiple", "prod")
+	devPath := filepath.Join(testData, "mapped-tfvars-multiple", "dev")
+	varsPath := filepath.Join(testData, "mapped-tfvars-multiple", "envs")
+
+	err = vs.Add(varsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := filesystem.NewFilesystem(gs.DocumentStore)
+	ctx = lsctx.WithDocumentContext(ctx, lsctx.Document{})
+	err = ParseVariables(ctx, fs, vs, varsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	varFileModules := fdecoder.VarFileModules{
+		{
+			Pattern:    filepath.Join(varsPath, "prod.tfvars"),
+			ModulePath: prodPath,
+		},
+		{
+			Pattern:    filepath.Join(varsPath, "dev.tfvars"),
+			ModulePath: devPath,
+		},
+	}
+	moduleReader := moduleInputsMock{
+		prodPath: {"foo": {}},
+		devPath:  {"bar": {}},
+	}
+	err = SchemaVariablesValidation(ctx, vs, moduleReader, varFileModules, varsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := vs.VariableRecordByPath(varsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// bar is only declared by the module dev.tfvars is mapped to
+	prodDiags := mod.VarsDiagnostics[ast.SchemaValidationSource]["prod.tfvars"]
+	if len(prodDiags) != 1 {
+		t.Fatalf("expected 1 diagnostic for prod.tfvars, %d given: %#v", len(prodDiags), prodDiags)
+	}
+	devDiags := mod.VarsDiagnostics[ast.SchemaValidationSource]["dev.tfvars"]
+	if len(devDiags) != 0 {
+		t.Fatalf("expected no diagnostics for dev.tfvars, %d given: %#v", len(devDiags), devDiags)
+	}
+}
@@ -79,6 +79,7 @@ func newVariableRecord(modPath string) *VariableRecord {
 			globalAst.SchemaValidationSource:    op.OpStateUnknown,
 			globalAst.ReferenceValidationSource: op.OpStateUnknown,
 			globalAst.TerraformValidateSource:   op.OpStateUnknown,
+			globalAst.TerraformPlanSource:       op.OpStateUnknown,
 		},
 	}
 }
@@ -94,6 +94,7 @@ func TestVariableStore_VariableRecordByPath(t *testing.T) {
 			globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 			globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 			globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+			globalAst.TerraformPlanSource:       operation.OpStateUnknown,
 		},
 	}
 	if diff := cmp.Diff(expectedVariable, record, cmpOpts); diff != "" {
@@ -138,6 +139,7 @@ func TestVariableStore_List(t *testing.T) {
 				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 				globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+				globalAst.TerraformPlanSource:       operation.OpStateUnknown,
 			},
 		},
 		{
@@ -147,6 +149,7 @@ func TestVariableStore_List(t *testing.T) {
 				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 				globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+				globalAst.TerraformPlanSource:       operation.OpStateUnknown,
 			},
 		},
 		{
@@ -156,6 +159,7 @@ func TestVariableStore_List(t *testing.T) {
 				globalAst.SchemaValidationSource:    operation.OpStateUnknown,
 				globalAst.ReferenceValidationSource: operation.OpStateUnknown,
 				globalAst.TerraformValidateSource:   operation.OpStateUnknown,
+				globalAst.TerraformPlanSource:       operation.OpStateUnknown,
 			},
 		},
 	}
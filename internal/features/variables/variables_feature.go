@@ -7,10 +7,12 @@ import (
 	"context"
 	"io"
 	"log"
+	"sync"
 
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/terraform-ls/internal/eventbus"
+	"github.com/hashicorp/terraform-ls/internal/features/variables/ast"
 	fdecoder "github.com/hashicorp/terraform-ls/internal/features/variables/decoder"
 	"github.com/hashicorp/terraform-ls/internal/features/variables/jobs"
 	"github.com/hashicorp/terraform-ls/internal/features/variables/state"
@@ -30,6 +32,9 @@ type VariablesFeature struct {
 	moduleFeature fdecoder.ModuleReader
 	stateStore    *globalState.StateStore
 	fs            jobs.ReadOnlyFS
+
+	varFileModulesMu sync.RWMutex
+	varFileModules   fdecoder.VarFileModules
 }
 
 func NewVariablesFeature(eventbus *eventbus.EventBus, stateStore *globalState.StateStore, fs jobs.ReadOnlyFS, moduleFeature fdecoder.ModuleReader) (*VariablesFeature, error) {
@@ -55,6 +60,21 @@ func (f *VariablesFeature) SetLogger(logger *log.Logger) {
 	f.store.SetLogger(logger)
 }
 
+// SetVarFileModules sets the mapping of variable files which are not
+// loaded automatically (e.g. passed via -var-file) to root modules,
+// such that they are validated and completed against those modules.
+func (f *VariablesFeature) SetVarFileModules(varFileModules fdecoder.VarFileModules) {
+	f.varFileModulesMu.Lock()
+	defer f.varFileModulesMu.Unlock()
+	f.varFileModules = varFileModules
+}
+
+func (f *VariablesFeature) getVarFileModules() fdecoder.VarFileModules {
+	f.varFileModulesMu.RLock()
+	defer f.varFileModulesMu.RUnlock()
+	return f.varFileModules
+}
+
 // Start starts the features separate goroutine.
 // It listens to various events from the EventBus and performs corresponding actions.
 func (f *VariablesFeature) Start(ctx context.Context) {
@@ -105,8 +125,9 @@ func (f *VariablesFeature) Stop() {
 
 func (f *VariablesFeature) PathContext(path lang.Path) (*decoder.PathContext, error) {
 	pathReader := &fdecoder.PathReader{
-		StateReader:  f.store,
-		ModuleReader: f.moduleFeature,
+		StateReader:    f.store,
+		ModuleReader:   f.moduleFeature,
+		VarFileModules: f.getVarFileModules(),
 	}
 
 	return pathReader.PathContext(path)
@@ -114,8 +135,9 @@ func (f *VariablesFeature) PathContext(path lang.Path) (*decoder.PathContext, er
 
 func (f *VariablesFeature) Paths(ctx context.Context) []lang.Path {
 	pathReader := &fdecoder.PathReader{
-		StateReader:  f.store,
-		ModuleReader: f.moduleFeature,
+		StateReader:    f.store,
+		ModuleReader:   f.moduleFeature,
+		VarFileModules: f.getVarFileModules(),
 	}
 
 	return pathReader.Paths(ctx)
@@ -129,8 +151,11 @@ func (f *VariablesFeature) Diagnostics(path string) diagnostics.Diagnostics {
 		return diags
 	}
 
+	varFileModules := f.getVarFileModules()
 	for source, dm := range mod.VarsDiagnostics {
-		diags.Append(source, dm.AutoloadedOnly().AsMap())
+		diags.Append(source, dm.Filter(func(name ast.VarsFilename) bool {
+			return name.IsAutoloaded() || varFileModules.IsMapped(path, name)
+		}).AsMap())
 	}
 
 	return diags
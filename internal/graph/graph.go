@@ -0,0 +1,243 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package graph provides a directed dependency graph of configuration
+// objects (such as components or resources), which can be exported
+// as JSON-friendly nodes and edges or in the DOT format, and checked
+// for cycles.
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/rules"
+)
+
+// Node represents a single addressable configuration object
+type Node struct {
+	// Address uniquely identifies the node, e.g. component.foo
+	Address string
+	// Kind describes the type of the node, e.g. component
+	Kind string
+	// Range is the range of the whole block or attribute
+	Range hcl.Range
+}
+
+// Edge represents a dependency of one node on another,
+// i.e. From refers to To.
+type Edge struct {
+	From string
+	To   string
+	// Range is the range of the reference (or other expression)
+	// which caused the dependency
+	Range hcl.Range
+}
+
+type Graph struct {
+	nodes map[string]Node
+	edges []Edge
+}
+
+func NewGraph() *Graph {
+	return &Graph{
+		nodes: make(map[string]Node, 0),
+		edges: make([]Edge, 0),
+	}
+}
+
+func (g *Graph) AddNode(node Node) {
+	g.nodes[node.Address] = node
+}
+
+func (g *Graph) HasNode(address string) bool {
+	_, ok := g.nodes[address]
+	return ok
+}
+
+// AddEdge adds a dependency between two nodes.
+// Edges referring to unknown nodes are ignored when reading the graph,
+// so these can be added in any order relative to the nodes.
+func (g *Graph) AddEdge(edge Edge) {
+	g.edges = append(g.edges, edge)
+}
+
+// Nodes returns all nodes sorted by address
+func (g *Graph) Nodes() []Node {
+	nodes := make([]Node, 0, len(g.nodes))
+	for _, node := range g.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Address < nodes[j].Address
+	})
+	return nodes
+}
+
+// Edges returns all edges between known nodes,
+// sorted by their source, target and range.
+func (g *Graph) Edges() []Edge {
+	edges := make([]Edge, 0, len(g.edges))
+	for _, edge := range g.edges {
+		if g.HasNode(edge.From) && g.HasNode(edge.To) {
+			edges = append(edges, edge)
+		}
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		if edges[i].To != edges[j].To {
+			return edges[i].To < edges[j].To
+		}
+		if edges[i].Range.Filename != edges[j].Range.Filename {
+			return edges[i].Range.Filename < edges[j].Range.Filename
+		}
+		return edges[i].Range.Start.Byte < edges[j].Range.Start.Byte
+	})
+	return edges
+}
+
+// Cycles returns all cycles in the graph, each represented as a sorted
+// list of addresses of the nodes involved (a strongly connected
+// component). A single node is only reported if it refers to itself.
+func (g *Graph) Cycles() [][]string {
+	adjacency := make(map[string][]string, len(g.nodes))
+	selfRefs := make(map[string]bool, 0)
+	for _, edge := range g.Edges() {
+		adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+		if edge.From == edge.To {
+			selfRefs[edge.From] = true
+		}
+	}
+
+	// Tarjan's strongly connected components algorithm
+	index := 0
+	indices := make(map[string]int, len(g.nodes))
+	lowLinks := make(map[string]int, len(g.nodes))
+	onStack := make(map[string]bool, len(g.nodes))
+	stack := make([]string, 0)
+	cycles := make([][]string, 0)
+
+	var connect func(addr string)
+	connect = func(addr string) {
+		indices[addr] = index
+		lowLinks[addr] = index
+		index++
+		stack = append(stack, addr)
+		onStack[addr] = true
+
+		for _, to := range adjacency[addr] {
+			if _, visited := indices[to]; !visited {
+				connect(to)
+				lowLinks[addr] = min(lowLinks[addr], lowLinks[to])
+			} else if onStack[to] {
+				lowLinks[addr] = min(lowLinks[addr], indices[to])
+			}
+		}
+
+		if lowLinks[addr] != indices[addr] {
+			return
+		}
+
+		component := make([]string, 0)
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == addr {
+				break
+			}
+		}
+
+		if len(component) > 1 || selfRefs[addr] {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, node := range g.Nodes() {
+		if _, visited := indices[node.Address]; !visited {
+			connect(node.Address)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+
+	return cycles
+}
+
+// CycleDiagnostics returns a diagnostic for every edge which is part
+// of a cycle, keyed by the name of the file containing the edge.
+func (g *Graph) CycleDiagnostics() map[string]hcl.Diagnostics {
+	diags := make(map[string]hcl.Diagnostics, 0)
+
+	for _, cycle := range g.Cycles() {
+		members := make(map[string]bool, len(cycle))
+		for _, addr := range cycle {
+			members[addr] = true
+		}
+
+		for _, edge := range g.Edges() {
+			if !members[edge.From] || !members[edge.To] {
+				continue
+			}
+
+			filename := edge.Range.Filename
+			diags[filename] = append(diags[filename], &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Dependency cycle",
+				Detail: fmt.Sprintf("%s refers to %s, which forms a cycle between: %s",
+					edge.From, edge.To, strings.Join(cycle, ", ")),
+				Subject: edge.Range.Ptr(),
+				Extra:   rules.DependencyCycle,
+			})
+		}
+	}
+
+	return diags
+}
+
+// DOT returns the graph in the Graphviz DOT format
+func (g *Graph) DOT(name string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "digraph %q {\n", name)
+	b.WriteString("  compound = \"true\"\n")
+	b.WriteString("  newrank = \"true\"\n")
+
+	for _, node := range g.Nodes() {
+		fmt.Fprintf(&b, "  %q [label = %q, shape = %q]\n", node.Address, node.Address, shapeForKind(node.Kind))
+	}
+
+	seen := make(map[[2]string]bool, 0)
+	for _, edge := range g.Edges() {
+		key := [2]string{edge.From, edge.To}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		fmt.Fprintf(&b, "  %q -> %q\n", edge.From, edge.To)
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func shapeForKind(kind string) string {
+	switch kind {
+	case "variable", "output", "local":
+		return "note"
+	case "module", "component":
+		return "component"
+	case "deployment":
+		return "folder"
+	}
+	return "box"
+}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestGraph_Cycles(t *testing.T) {
+	testCases := []struct {
+		name           string
+		nodes          []string
+		edges          [][2]string
+		expectedCycles [][]string
+	}{
+		{
+			"no edges",
+			[]string{"component.a", "component.b"},
+			[][2]string{},
+			[][]string{},
+		},
+		{
+			"acyclic",
+			[]string{"component.a", "component.b", "component.c"},
+			[][2]string{
+				{"component.a", "component.b"},
+				{"component.b", "component.c"},
+				{"component.a", "component.c"},
+			},
+			[][]string{},
+		},
+		{
+			"self reference",
+			[]string{"component.a"},
+			[][2]string{
+				{"component.a", "component.a"},
+			},
+			[][]string{
+				{"component.a"},
+			},
+		},
+		{
+			"multiple cycles",
+			[]string{"component.a", "component.b", "component.c", "component.d", "component.e"},
+			[][2]string{
+				{"component.a", "component.b"},
+				{"component.b", "component.c"},
+				{"component.c", "component.a"},
+				{"component.d", "component.e"},
+				{"component.e", "component.d"},
+			},
+			[][]string{
+				{"component.a", "component.b", "component.c"},
+				{"component.d", "component.e"},
+			},
+		},
+		{
+			"edge to unknown node",
+			[]string{"component.a"},
+			[][2]string{
+				{"component.a", "component.unknown"},
+				{"component.unknown", "component.a"},
+			},
+			[][]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGraph()
+			for _, addr := range tc.nodes {
+				g.AddNode(Node{Address: addr, Kind: "component"})
+			}
+			for _, edge := range tc.edges {
+				g.AddEdge(Edge{From: edge[0], To: edge[1]})
+			}
+
+			cycles := g.Cycles()
+			if diff := cmp.Diff(tc.expectedCycles, cycles); diff != "" {
+				t.Fatalf("unexpected cycles: %s", diff)
+			}
+		})
+	}
+}
+
+func TestGraph_CycleDiagnostics(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(Node{Address: "component.a", Kind: "component"})
+	g.AddNode(Node{Address: "component.b", Kind: "component"})
+	g.AddNode(Node{Address: "component.c", Kind: "component"})
+	g.AddEdge(Edge{
+		From: "component.a",
+		To:   "component.b",
+		Range: hcl.Range{
+			Filename: "a.tfstack.hcl",
+			Start:    hcl.Pos{Line: 3, Column: 10, Byte: 30},
+			End:      hcl.Pos{Line: 3, Column: 21, Byte: 41},
+		},
+	})
+	g.AddEdge(Edge{
+		From: "component.b",
+		To:   "component.a",
+		Range: hcl.Range{
+			Filename: "b.tfstack.hcl",
+			Start:    hcl.Pos{Line: 5, Column: 10, Byte: 50},
+			End:      hcl.Pos{Line: 5, Column: 21, Byte: 61},
+		},
+	})
+	g.AddEdge(Edge{
+		From: "component.c",
+		To:   "component.a",
+		Range: hcl.Range{
+			Filename: "b.tfstack.hcl",
+			Start:    hcl.Pos{Line: 9, Column: 10, Byte: 90},
+			End:      hcl.Pos{Line: 9, Column: 21, Byte: 101},
+		},
+	})
+
+	diags := g.CycleDiagnostics()
+	if len(diags["a.tfstack.hcl"]) != 1 {
+		t.Fatalf("expected 1 diagnostic for a.tfstack.hcl, %d given", len(diags["a.tfstack.hcl"]))
+	}
+	if len(diags["b.tfstack.hcl"]) != 1 {
+		t.Fatalf("expected 1 diagnostic for b.tfstack.hcl, %d given", len(diags["b.tfstack.hcl"]))
+	}
+	if diags["b.tfstack.hcl"][0].Subject.Start.Line != 5 {
+		t.Fatalf("expected diagnostic on line 5, given: %#v", diags["b.tfstack.hcl"][0].Subject)
+	}
+}
+
+func TestGraph_DOT(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(Node{Address: "component.b", Kind: "component"})
+	g.AddNode(Node{Address: "component.a", Kind: "component"})
+	g.AddNode(Node{Address: "deployment.prod", Kind: "deployment"})
+	g.AddEdge(Edge{From: "component.b", To: "component.a"})
+	// duplicate edges with different ranges are rendered only once
+	g.AddEdge(Edge{From: "component.b", To: "component.a"})
+
+	expectedDOT := `digraph "stack" {
+  compound = "true"
+  newrank = "true"
+  "component.a" [label = "component.a", shape = "component"]
+  "component.b" [label = "component.b", shape = "component"]
+  "deployment.prod" [label = "deployment.prod", shape = "folder"]
+  "component.b" -> "component.a"
+}
+`
+	if diff := cmp.Diff(expectedDOT, g.DOT("stack")); diff != "" {
+		t.Fatalf("unexpected DOT output: %s", diff)
+	}
+}
@@ -5,13 +5,18 @@ package diagnostics
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"log"
 	"path/filepath"
+	"sort"
 	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	"github.com/hashicorp/terraform-ls/internal/uri"
 )
@@ -57,16 +62,11 @@ func (n *Notifier) PublishHCLDiags(ctx context.Context, dirPath string, diags Di
 	default:
 	}
 
-	for filename, ds := range diags {
-		fileDiags := make([]lsp.Diagnostic, 0)
-		for source, diags := range ds {
-			fileDiags = append(fileDiags, ilsp.HCLDiagsToLSP(diags, source.String())...)
-		}
-
+	for filename := range diags {
 		n.diags <- diagContext{
 			ctx:   ctx,
 			uri:   lsp.DocumentURI(uri.FromPath(filepath.Join(dirPath, filename))),
-			diags: fileDiags,
+			diags: diags.FileDiagnostics(filename),
 		}
 	}
 }
@@ -106,6 +106,56 @@ func (d Diagnostics) Append(src ast.DiagnosticSource, diagsMap map[string]hcl.Di
 	return d
 }
 
+// ApplyRules returns the diagnostics of files within the given
+// directory with configured rule severities and suppressions applied
+func (d Diagnostics) ApplyRules(dirPath string, filter *rules.Filter) Diagnostics {
+	filtered := make(Diagnostics, len(d))
+	for filename, fileDiags := range d {
+		filtered[filename] = make(map[ast.DiagnosticSource]hcl.Diagnostics, len(fileDiags))
+		if filename == "" {
+			continue
+		}
+		fileFilter := filter.ForFile(filepath.Join(dirPath, filename))
+		for src, diags := range fileDiags {
+			filtered[filename][src] = fileFilter.Apply(diags)
+		}
+	}
+
+	return filtered
+}
+
+// FileDiagnostics converts HCL diagnostics of the given file
+// to LSP diagnostics, ordered by their source.
+func (d Diagnostics) FileDiagnostics(filename string) []lsp.Diagnostic {
+	fileDiags := make([]lsp.Diagnostic, 0)
+
+	sources := make([]ast.DiagnosticSource, 0, len(d[filename]))
+	for source := range d[filename] {
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i] < sources[j]
+	})
+
+	for _, source := range sources {
+		fileDiags = append(fileDiags, ilsp.HCLDiagsToLSP(d[filename][source], source.String())...)
+	}
+
+	return fileDiags
+}
+
+// ResultID returns an identifier of the given diagnostics, which only
+// changes when the diagnostics change. It is used to avoid sending
+// unchanged diagnostics to clients which pull them.
+func ResultID(diags []lsp.Diagnostic) string {
+	b, err := json.Marshal(diags)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(b)
+	return hex.EncodeToString(hash[:16])
+}
+
 func (d Diagnostics) Extend(diags Diagnostics) Diagnostics {
 	for uri, uriDiags := range diags {
 		if _, ok := d[uri]; !ok {
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package diagnostics
+
+import (
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/rules"
+	"github.com/hashicorp/terraform-ls/internal/tflint"
+)
+
+// HCLDiagsFromTFLint turns issues and errors reported by tflint for
+// a module into diagnostics, attributing issues to tflint rules.
+//
+// Issues outside of the module directory (e.g. in called modules)
+// and errors without a range are left out.
+func HCLDiagsFromTFLint(output *tflint.Output) map[string]hcl.Diagnostics {
+	diagsMap := make(map[string]hcl.Diagnostics)
+
+	for _, issue := range output.Issues {
+		file := filepath.Clean(issue.Range.Filename)
+		if filepath.Base(file) != file {
+			continue
+		}
+
+		diag := &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  issue.Message,
+			Detail:   issue.Rule.Link,
+			Subject:  tflintRange(issue.Range),
+			Extra:    rules.ID(issue.Rule.Name),
+		}
+		switch issue.Rule.Severity {
+		case "error":
+			diag.Severity = hcl.DiagError
+		case "info", "notice":
+			diag = rules.WithSeverity(diag, rules.SeverityHint)
+		}
+
+		diagsMap[file] = append(diagsMap[file], diag)
+	}
+
+	for _, tflintErr := range output.Errors {
+		if tflintErr.Range == nil {
+			continue
+		}
+		file := filepath.Clean(tflintErr.Range.Filename)
+		if filepath.Base(file) != file {
+			continue
+		}
+
+		diagsMap[file] = append(diagsMap[file], &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  tflintErr.Message,
+			Subject:  tflintRange(*tflintErr.Range),
+		})
+	}
+
+	return diagsMap
+}
+
+func tflintRange(rng tflint.Range) *hcl.Range {
+	return &hcl.Range{
+		Filename: filepath.Clean(rng.Filename),
+		Start: hcl.Pos{
+			Line:   rng.Start.Line,
+			Column: rng.Start.Column,
+		},
+		End: hcl.Pos{
+			Line:   rng.End.Line,
+			Column: rng.End.Column,
+		},
+	}
+}
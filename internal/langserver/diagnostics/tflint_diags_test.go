@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/rules"
+	"github.com/hashicorp/terraform-ls/internal/tflint"
+)
+
+func TestHCLDiagsFromTFLint(t *testing.T) {
+	output, err := tflint.ParseOutput([]byte(`{
+  "issues": [
+    {
+      "rule": {
+        "name": "terraform_unused_declarations",
+        "severity": "warning",
+        "link": "https://github.com/terraform-linters/tflint-ruleset-terraform/blob/v0.5.0/docs/rules/terraform_unused_declarations.md"
+      },
+      "message": "variable \"region\" is declared but not used",
+      "range": {
+        "filename": "variables.tf",
+        "start": { "line": 1, "column": 1 },
+        "end": { "line": 1, "column": 18 }
+      },
+      "callers": []
+    },
+    {
+      "rule": {
+        "name": "terraform_naming_convention",
+        "severity": "notice",
+        "link": ""
+      },
+      "message": "resource name must match snake_case",
+      "range": {
+        "filename": "main.tf",
+        "start": { "line": 3, "column": 1 },
+        "end": { "line": 3, "column": 33 }
+      },
+      "callers": []
+    },
+    {
+      "rule": {
+        "name": "aws_instance_invalid_type",
+        "severity": "error",
+        "link": ""
+      },
+      "message": "\"t2.superlarge\" is an invalid value as instance_type",
+      "range": {
+        "filename": "modules/app/main.tf",
+        "start": { "line": 2, "column": 19 },
+        "end": { "line": 2, "column": 34 }
+      },
+      "callers": []
+    }
+  ],
+  "errors": [
+    {
+      "message": "Failed to initialize plugins",
+      "severity": "error"
+    }
+  ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := HCLDiagsFromTFLint(output)
+
+	// Notices are reported as hints
+	if len(diags["main.tf"]) != 1 {
+		t.Fatalf("expected 1 diagnostic for main.tf, %d given", len(diags["main.tf"]))
+	}
+	notice := diags["main.tf"][0]
+	if id, _ := rules.RuleID(notice); id != "terraform_naming_convention" {
+		t.Fatalf("unexpected rule ID: %q", id)
+	}
+	if severity, _ := rules.ConfiguredSeverity(notice); severity != rules.SeverityHint {
+		t.Fatalf("expected notice to be reported as hint, got %q", severity)
+	}
+	delete(diags, "main.tf")
+
+	expectedDiags := map[string]hcl.Diagnostics{
+		"variables.tf": {
+			{
+				Severity: hcl.DiagWarning,
+				Summary:  `variable "region" is declared but not used`,
+				Detail:   "https://github.com/terraform-linters/tflint-ruleset-terraform/blob/v0.5.0/docs/rules/terraform_unused_declarations.md",
+				Subject: &hcl.Range{
+					Filename: "variables.tf",
+					Start:    hcl.Pos{Line: 1, Column: 1},
+					End:      hcl.Pos{Line: 1, Column: 18},
+				},
+				Extra: rules.ID("terraform_unused_declarations"),
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diags); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
@@ -6,10 +6,13 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"os"
 
+	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/terraform-ls/internal/langserver/errors"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/refactor"
 	"github.com/hashicorp/terraform-ls/internal/terraform/module"
 )
 
@@ -26,18 +29,19 @@ func (svc *service) textDocumentCodeAction(ctx context.Context, params lsp.CodeA
 	var ca []lsp.CodeAction
 
 	// For action definitions, refer to https://code.visualstudio.com/api/references/vscode-api#CodeActionKind
-	// We only support format type code actions at the moment, and do not want to format without the client asking for
-	// them, so exit early here if nothing is requested.
-	if len(params.Context.Only) == 0 {
-		svc.logger.Printf("No code action requested, exiting")
-		return ca, nil
+	// We do not want to format without the client asking for it, so only
+	// refactorings and quick fixes are offered if nothing specific is requested.
+	only := params.Context.Only
+	if len(only) == 0 {
+		svc.logger.Printf("No code action requested, offering refactorings and quick fixes")
+		only = []lsp.CodeActionKind{lsp.Refactor, lsp.QuickFix}
 	}
 
-	for _, o := range params.Context.Only {
+	for _, o := range only {
 		svc.logger.Printf("Code actions requested: %q", o)
 	}
 
-	wantedCodeActions := ilsp.SupportedCodeActions.Only(params.Context.Only)
+	wantedCodeActions := ilsp.SupportedCodeActions.Only(only)
 	if len(wantedCodeActions) == 0 {
 		return nil, fmt.Errorf("could not find a supported code action to execute for %s, wanted %v",
 			params.TextDocument.URI, params.Context.Only)
@@ -52,7 +56,11 @@ func (svc *service) textDocumentCodeAction(ctx context.Context, params lsp.CodeA
 		return ca, err
 	}
 
-	for action := range wantedCodeActions {
+	// Refactorings of the module share the files and reference
+	// origins, which are only resolved once needed
+	var refactorCtx *refactorContext
+
+	for _, action := range wantedCodeActions.AsSlice() {
 		switch action {
 		case ilsp.SourceFormatAllTerraform:
 			tfExec, err := module.TerraformExecutorForModule(ctx, dh.Dir.Path())
@@ -74,6 +82,52 @@ func (svc *service) textDocumentCodeAction(ctx context.Context, params lsp.CodeA
 					},
 				},
 			})
+		case ilsp.RefactorRewriteMovedTerraform:
+			if doc.LanguageID != ilsp.Terraform.String() {
+				continue
+			}
+			// The saved file reflects names known to Terraform state
+			// more closely than any intermediate version of the document
+			saved, err := os.ReadFile(dh.FullPath())
+			if err != nil {
+				continue
+			}
+			pos, err := ilsp.HCLPositionFromLspPosition(params.Range.Start, doc)
+			if err != nil {
+				return ca, err
+			}
+
+			rename, ok := refactor.MovedBlock(saved, doc.Text, doc.Filename, pos)
+			if !ok {
+				continue
+			}
+
+			ca = append(ca, lsp.CodeAction{
+				Title: fmt.Sprintf("Add moved block for rename of %s", rename.From),
+				Kind:  action,
+				Edit: lsp.WorkspaceEdit{
+					Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+						lsp.DocumentURI(dh.FullURI()): ilsp.TextEdits([]lang.TextEdit{rename.Edit}, false),
+					},
+				},
+			})
+		case ilsp.RefactorExtractLocalTerraform, ilsp.RefactorExtractVariableTerraform, ilsp.RefactorInlineLocalTerraform,
+			ilsp.RefactorRewriteForEachTerraform, ilsp.RefactorRewriteCountTerraform, ilsp.RefactorExtractModuleTerraform:
+			if doc.LanguageID != ilsp.Terraform.String() {
+				continue
+			}
+			if refactorCtx == nil {
+				refactorCtx, err = svc.newRefactorContext(ctx, doc, params.Range)
+				if err != nil {
+					return ca, err
+				}
+			}
+			codeAction, ok := svc.moduleRefactoring(ctx, action, doc, params.Range, refactorCtx)
+			if ok {
+				ca = append(ca, codeAction)
+			}
+		case ilsp.QuickFixSuppressTerraform:
+			ca = append(ca, suppressionCodeActions(dh, doc, params.Context.Diagnostics)...)
 		}
 	}
 
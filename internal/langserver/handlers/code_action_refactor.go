@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/document"
+	"github.com/hashicorp/terraform-ls/internal/langserver/cmd"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/refactor"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+// refactorContext holds what refactorings of a module rely on,
+// which is resolved once per code action request
+type refactorContext struct {
+	modPath string
+	pathCtx *decoder.PathContext
+	rng     hcl.Range
+}
+
+// newRefactorContext returns the context for refactorings
+// of the module of the document at the given range
+func (svc *service) newRefactorContext(ctx context.Context, doc *document.Document, lspRng lsp.Range) (*refactorContext, error) {
+	// Refactorings rely on the latest parsed files and reference origins
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(doc.Dir)
+	if err != nil {
+		return nil, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	modPath := doc.Dir.Path()
+	pathCtx, err := svc.features.Modules.PathContext(lang.Path{
+		Path:       modPath,
+		LanguageID: ilsp.Terraform.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := ilsp.HCLPositionFromLspPosition(lspRng.Start, doc)
+	if err != nil {
+		return nil, err
+	}
+	end, err := ilsp.HCLPositionFromLspPosition(lspRng.End, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &refactorContext{
+		modPath: modPath,
+		pathCtx: pathCtx,
+		rng:     hcl.Range{Filename: doc.Filename, Start: start, End: end},
+	}, nil
+}
+
+// moduleRefactoring returns the code action for the given refactoring
+// of the module, which may span multiple files, if it is applicable
+// to the given range of the document.
+func (svc *service) moduleRefactoring(ctx context.Context, kind lsp.CodeActionKind, doc *document.Document, lspRng lsp.Range, rCtx *refactorContext) (lsp.CodeAction, bool) {
+	var codeAction lsp.CodeAction
+	modPath, pathCtx, rng := rCtx.modPath, rCtx.pathCtx, rCtx.rng
+	start := rng.Start
+
+	if kind == ilsp.RefactorExtractModuleTerraform {
+		// The new module directory is created by the server
+		// via command, so that it can be indexed right away
+		mod, ok := refactor.ExtractModule(pathCtx.Files, pathCtx.ReferenceOrigins, doc.Filename, rng)
+		if !ok {
+			return codeAction, false
+		}
+		if _, err := os.Stat(filepath.Join(modPath, mod.Dir)); err == nil {
+			return codeAction, false
+		}
+
+		cmdName := cmd.Name("module.extract")
+		if commandPrefix, _ := lsctx.CommandPrefix(ctx); commandPrefix != "" {
+			cmdName = commandPrefix + "." + cmdName
+		}
+		codeAction.Title = fmt.Sprintf("Extract to module module.%s", mod.Name)
+		codeAction.Kind = kind
+		codeAction.Command = &lsp.Command{
+			Title:   codeAction.Title,
+			Command: cmdName,
+			Arguments: []json.RawMessage{
+				rawArgument(fmt.Sprintf("uri=%s", uri.FromPath(filepath.Join(modPath, doc.Filename)))),
+				rawArgument(fmt.Sprintf("startline=%d", lspRng.Start.Line)),
+				rawArgument(fmt.Sprintf("startcharacter=%d", lspRng.Start.Character)),
+				rawArgument(fmt.Sprintf("endline=%d", lspRng.End.Line)),
+				rawArgument(fmt.Sprintf("endcharacter=%d", lspRng.End.Character)),
+			},
+		}
+		return codeAction, true
+	}
+
+	var edits refactor.Edits
+	var name string
+	var ok bool
+	switch kind {
+	case ilsp.RefactorExtractLocalTerraform:
+		edits, name, ok = refactor.ExtractLocal(pathCtx.Files, doc.Filename, rng)
+		codeAction.Title = fmt.Sprintf("Extract to local value local.%s", name)
+	case ilsp.RefactorExtractVariableTerraform:
+		edits, name, ok = refactor.ExtractVariable(pathCtx.Files, doc.Filename, rng)
+		codeAction.Title = fmt.Sprintf("Extract to variable var.%s", name)
+	case ilsp.RefactorInlineLocalTerraform:
+		edits, name, ok = refactor.InlineLocal(pathCtx.Files, pathCtx.ReferenceOrigins, doc.Filename, start)
+		codeAction.Title = fmt.Sprintf("Inline local value local.%s", name)
+	case ilsp.RefactorRewriteForEachTerraform:
+		edits, ok = refactor.CountToForEach(pathCtx.Files, pathCtx.ReferenceOrigins, doc.Filename, start)
+		codeAction.Title = "Convert count to for_each"
+	case ilsp.RefactorRewriteCountTerraform:
+		edits, ok = refactor.ForEachToCount(pathCtx.Files, pathCtx.ReferenceOrigins, doc.Filename, start)
+		codeAction.Title = "Convert for_each to count"
+	}
+	if !ok {
+		return codeAction, false
+	}
+
+	codeAction.Kind = kind
+	codeAction.Edit = ilsp.WorkspaceEdit(modPath, edits)
+	return codeAction, true
+}
+
+func rawArgument(arg string) json.RawMessage {
+	raw, _ := json.Marshal(arg)
+	return raw
+}
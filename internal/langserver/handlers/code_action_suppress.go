@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-ls/internal/document"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/rules"
+)
+
+// suppressionCodeActions offers to suppress the rules behind the given
+// diagnostics by inserting a comment above the innermost attribute
+// or block enclosing the line they are reported on
+func suppressionCodeActions(dh document.Handle, doc *document.Document, diags []lsp.Diagnostic) []lsp.CodeAction {
+	// comments are not supported in JSON
+	if strings.HasSuffix(doc.Filename, ".json") {
+		return nil
+	}
+
+	lines := bytes.Split(doc.Text, []byte("\n"))
+	type suppression struct {
+		line uint32
+		id   rules.ID
+	}
+	seen := make(map[suppression]bool)
+
+	ca := make([]lsp.CodeAction, 0)
+	for _, diag := range diags {
+		code, ok := diag.Code.(string)
+		if !ok || code == "" {
+			continue
+		}
+		if int(diag.Range.Start.Line) >= len(lines) {
+			continue
+		}
+		// HCL lines are 1-based, whereas LSP lines are 0-based
+		line := rules.SuppressionLine(doc.Filename, doc.Text, int(diag.Range.Start.Line)+1) - 1
+		s := suppression{line: uint32(line), id: rules.ID(code)}
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+
+		lineText := lines[s.line]
+		indent := lineText[:len(lineText)-len(bytes.TrimLeft(lineText, " \t"))]
+
+		ca = append(ca, lsp.CodeAction{
+			Title:       fmt.Sprintf("Suppress %s here", s.id),
+			Kind:        ilsp.QuickFixSuppressTerraform,
+			Diagnostics: []lsp.Diagnostic{diag},
+			Edit: lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					lsp.DocumentURI(dh.FullURI()): {
+						{
+							Range: lsp.Range{
+								Start: lsp.Position{Line: s.line, Character: 0},
+								End:   lsp.Position{Line: s.line, Character: 0},
+							},
+							NewText: string(indent) + rules.SuppressionComment(s.id) + "\n",
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return ca
+}
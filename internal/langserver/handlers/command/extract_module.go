@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/document"
+	"github.com/hashicorp/terraform-ls/internal/langserver/cmd"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/refactor"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+// ExtractModuleHandler moves the resources within the given range
+// of the document into a new child module directory, replaces them
+// with a module call by asking the client to apply the edit
+// and enqueues the new directory for indexing.
+func (h *CmdHandler) ExtractModuleHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
+	fileUri, ok := args.GetString("uri")
+	if !ok || fileUri == "" {
+		return nil, fmt.Errorf("%w: expected file uri argument to be set", jrpc2.InvalidParams.Err())
+	}
+
+	if !uri.IsURIValid(fileUri) {
+		return nil, fmt.Errorf("URI %q is not valid", fileUri)
+	}
+
+	var lspRng lsp.Range
+	for name, value := range map[string]*uint32{
+		"startline":      &lspRng.Start.Line,
+		"startcharacter": &lspRng.Start.Character,
+		"endline":        &lspRng.End.Line,
+		"endcharacter":   &lspRng.End.Character,
+	} {
+		n, ok := args.GetNumber(name)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("%w: expected %s argument to be set", jrpc2.InvalidParams.Err(), name)
+		}
+		*value = uint32(n)
+	}
+
+	dh := document.HandleFromURI(fileUri)
+	doc, err := h.StateStore.DocumentStore.GetDocument(dh)
+	if err != nil {
+		return nil, err
+	}
+
+	jobIds, err := h.StateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return nil, err
+	}
+	h.StateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	modPath := dh.Dir.Path()
+	pathCtx, err := h.ModulesFeature.PathContext(lang.Path{
+		Path:       modPath,
+		LanguageID: ilsp.Terraform.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := ilsp.HCLPositionFromLspPosition(lspRng.Start, doc)
+	if err != nil {
+		return nil, err
+	}
+	end, err := ilsp.HCLPositionFromLspPosition(lspRng.End, doc)
+	if err != nil {
+		return nil, err
+	}
+	rng := hcl.Range{Filename: dh.Filename, Start: start, End: end}
+
+	mod, ok := refactor.ExtractModule(pathCtx.Files, pathCtx.ReferenceOrigins, dh.Filename, rng)
+	if !ok {
+		return nil, fmt.Errorf("%w: no resources found within the given range", jrpc2.InvalidParams.Err())
+	}
+
+	modDir := filepath.Join(modPath, mod.Dir)
+	if _, err := os.Stat(modDir); err == nil {
+		return nil, fmt.Errorf("directory %q already exists", modDir)
+	}
+	createdDir := firstMissingDir(modDir)
+	err = writeModuleFiles(modDir, mod.Files)
+	if err != nil {
+		// don't leave a partially written module behind
+		return nil, removeCreatedDir(createdDir, err)
+	}
+
+	resp, err := jrpc2.ServerFromContext(ctx).Callback(ctx, "workspace/applyEdit", lsp.ApplyWorkspaceEditParams{
+		Label: fmt.Sprintf("Extract to module module.%s", mod.Name),
+		Edit:  ilsp.WorkspaceEdit(modPath, mod.Edits),
+	})
+	if err == nil {
+		var result lsp.ApplyWorkspaceEditResult
+		err = resp.UnmarshalResult(&result)
+		if err == nil && !result.Applied {
+			err = fmt.Errorf("client did not apply the edit: %s", result.FailureReason)
+		}
+	}
+	if err != nil {
+		// the module would not be called
+		return nil, removeCreatedDir(createdDir, err)
+	}
+
+	err = h.StateStore.WalkerPaths.EnqueueDir(ctx, document.DirHandleFromPath(modDir))
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func writeModuleFiles(modDir string, files map[string]string) error {
+	err := os.MkdirAll(modDir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	filenames := make([]string, 0, len(files))
+	for filename := range files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		err := os.WriteFile(filepath.Join(modDir, filename), []byte(files[filename]), 0o644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// firstMissingDir returns the outermost directory of the given path
+// which does not exist yet, i.e. the directory to remove again
+// to undo creating the path
+func firstMissingDir(dir string) string {
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		if _, err := os.Stat(parent); err == nil {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// removeCreatedDir removes the given directory after the given error
+// occurred, returning the error along with any error removing it
+func removeCreatedDir(dir string, err error) error {
+	rmErr := os.RemoveAll(dir)
+	if rmErr != nil {
+		return multierror.Append(err, fmt.Errorf("failed to remove %q: %w", dir, rmErr))
+	}
+	return err
+}
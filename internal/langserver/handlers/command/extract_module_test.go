@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_writeModuleFiles_cleanup(t *testing.T) {
+	modPath := t.TempDir()
+	modDir := filepath.Join(modPath, "modules", "web")
+
+	createdDir := firstMissingDir(modDir)
+	if createdDir != filepath.Join(modPath, "modules") {
+		t.Fatalf("unexpected directory to clean up: %q", createdDir)
+	}
+
+	// the file in a missing subdirectory cannot be written
+	err := writeModuleFiles(modDir, map[string]string{
+		"main.tf":          "",
+		"missing/other.tf": "",
+	})
+	if err == nil {
+		t.Fatal("expected error writing files")
+	}
+
+	err = removeCreatedDir(createdDir, err)
+	if err == nil {
+		t.Fatal("expected original error to be returned")
+	}
+	if _, err := os.Stat(createdDir); !os.IsNotExist(err) {
+		t.Fatalf("expected %q to be removed, got: %v", createdDir, err)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-ls/internal/graph"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+type graphNode struct {
+	Address string    `json:"address"`
+	Kind    string    `json:"kind"`
+	URI     string    `json:"uri"`
+	Range   lsp.Range `json:"range"`
+}
+
+type graphEdge struct {
+	From  string    `json:"from"`
+	To    string    `json:"to"`
+	URI   string    `json:"uri"`
+	Range lsp.Range `json:"range"`
+}
+
+// graphNodesAndEdges converts graph nodes and edges with ranges
+// relative to dirPath into their JSON representation
+func graphNodesAndEdges(g *graph.Graph, dirPath string) ([]graphNode, []graphEdge) {
+	nodes := make([]graphNode, 0)
+	for _, node := range g.Nodes() {
+		nodes = append(nodes, graphNode{
+			Address: node.Address,
+			Kind:    node.Kind,
+			URI:     uri.FromPath(filepath.Join(dirPath, node.Range.Filename)),
+			Range:   ilsp.HCLRangeToLSP(node.Range),
+		})
+	}
+
+	edges := make([]graphEdge, 0)
+	for _, edge := range g.Edges() {
+		edges = append(edges, graphEdge{
+			From:  edge.From,
+			To:    edge.To,
+			URI:   uri.FromPath(filepath.Join(dirPath, edge.Range.Filename)),
+			Range: ilsp.HCLRangeToLSP(edge.Range),
+		})
+	}
+
+	return nodes, edges
+}
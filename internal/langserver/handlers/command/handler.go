@@ -8,6 +8,7 @@ import (
 
 	fmodules "github.com/hashicorp/terraform-ls/internal/features/modules"
 	frootmodules "github.com/hashicorp/terraform-ls/internal/features/rootmodules"
+	fstacks "github.com/hashicorp/terraform-ls/internal/features/stacks"
 	"github.com/hashicorp/terraform-ls/internal/state"
 )
 
@@ -18,4 +19,5 @@ type CmdHandler struct {
 	// the features here?
 	ModulesFeature     *fmodules.ModulesFeature
 	RootModulesFeature *frootmodules.RootModulesFeature
+	StacksFeature      *fstacks.StacksFeature
 }
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/hashicorp/terraform-ls/internal/langserver/cmd"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+const moduleGraphVersion = 0
+
+type moduleGraphResponse struct {
+	FormatVersion int         `json:"v"`
+	Nodes         []graphNode `json:"nodes"`
+	Edges         []graphEdge `json:"edges"`
+	Cycles        [][]string  `json:"cycles"`
+	DOT           string      `json:"dot"`
+}
+
+func (h *CmdHandler) ModuleGraphHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
+	response := moduleGraphResponse{
+		FormatVersion: moduleGraphVersion,
+		Nodes:         make([]graphNode, 0),
+		Edges:         make([]graphEdge, 0),
+		Cycles:        make([][]string, 0),
+	}
+
+	modUri, ok := args.GetString("uri")
+	if !ok || modUri == "" {
+		return response, fmt.Errorf("%w: expected module uri argument to be set", jrpc2.InvalidParams.Err())
+	}
+
+	if !uri.IsURIValid(modUri) {
+		return response, fmt.Errorf("URI %q is not valid", modUri)
+	}
+
+	modPath, err := uri.PathFromURI(modUri)
+	if err != nil {
+		return response, err
+	}
+
+	g, err := h.ModulesFeature.DependencyGraph(modPath)
+	if err != nil {
+		return response, err
+	}
+
+	response.Nodes, response.Edges = graphNodesAndEdges(g, modPath)
+	response.Cycles = g.Cycles()
+	response.DOT = g.DOT(filepath.Base(modPath))
+
+	return response, nil
+}
@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/hashicorp/terraform-ls/internal/document"
+	"github.com/hashicorp/terraform-ls/internal/job"
+	"github.com/hashicorp/terraform-ls/internal/langserver/cmd"
+	"github.com/hashicorp/terraform-ls/internal/langserver/progress"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+func (h *CmdHandler) TerraformPlanHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
+	dirUri, ok := args.GetString("uri")
+	if !ok || dirUri == "" {
+		return nil, fmt.Errorf("%w: expected module uri argument to be set", jrpc2.InvalidParams.Err())
+	}
+
+	if !uri.IsURIValid(dirUri) {
+		return nil, fmt.Errorf("URI %q is not valid", dirUri)
+	}
+
+	dirHandle := document.DirHandleFromURI(dirUri)
+
+	varFiles := make([]string, 0)
+	if rawVarFiles, ok := args.GetString("varfiles"); ok && rawVarFiles != "" {
+		for _, varFile := range strings.Split(rawVarFiles, ",") {
+			varFile = strings.TrimSpace(varFile)
+			if varFile != "" {
+				varFiles = append(varFiles, varFile)
+			}
+		}
+	}
+
+	progress.Begin(ctx, "Planning")
+	defer func() {
+		progress.End(ctx, "Finished")
+	}()
+
+	progress.Report(ctx, "Running terraform plan ...")
+
+	// The job runs within the scheduler's context, so we capture
+	// the request context to be able to report progress from within
+	reqCtx := ctx
+	id, err := h.StateStore.JobStore.EnqueueJob(ctx, job.Job{
+		Dir: dirHandle,
+		Func: func(ctx context.Context) error {
+			return h.ModulesFeature.TerraformPlan(ctx, dirHandle.Path(), varFiles, func(msg string) {
+				progress.Report(reqCtx, msg)
+			})
+		},
+		Type:        op.OpTypeTerraformPlan.String(),
+		IgnoreState: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.StateStore.JobStore.WaitForJobs(ctx, id)
+}
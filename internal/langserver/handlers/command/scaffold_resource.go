@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/document"
+	"github.com/hashicorp/terraform-ls/internal/langserver/cmd"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/scaffold"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+)
+
+// defaultScaffoldName is the name of the scaffolded resource
+// unless a name is provided
+const defaultScaffoldName = "this"
+
+// ScaffoldResourceHandler appends a skeleton of a resource block
+// of the given type, generated from the provider schema, to the given file
+// by asking the client to apply the edit.
+func (h *CmdHandler) ScaffoldResourceHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
+	fileUri, ok := args.GetString("uri")
+	if !ok || fileUri == "" {
+		return nil, fmt.Errorf("%w: expected file uri argument to be set", jrpc2.InvalidParams.Err())
+	}
+
+	if !uri.IsURIValid(fileUri) {
+		return nil, fmt.Errorf("URI %q is not valid", fileUri)
+	}
+
+	rType, ok := args.GetString("type")
+	if !ok || rType == "" {
+		return nil, fmt.Errorf("%w: expected resource type argument to be set", jrpc2.InvalidParams.Err())
+	}
+
+	name, ok := args.GetString("name")
+	if !ok || name == "" {
+		name = defaultScaffoldName
+	}
+	if !hclsyntax.ValidIdentifier(name) {
+		return nil, fmt.Errorf("%w: %q is not a valid resource name", jrpc2.InvalidParams.Err(), name)
+	}
+
+	includeOptional, _ := args.GetBool("optional")
+
+	dh := document.HandleFromURI(fileUri)
+	modPath := dh.Dir.Path()
+
+	pAddr, pCons := h.resourceProvider(modPath, rType)
+	ps, err := h.StateStore.ProviderSchemas.ProviderSchema(modPath, pAddr, pCons)
+	if err != nil {
+		return nil, fmt.Errorf("no schema found for provider %s: %w", pAddr.ForDisplay(), err)
+	}
+	body, ok := ps.Resources[rType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown resource type %q of provider %s",
+			jrpc2.InvalidParams.Err(), rType, pAddr.ForDisplay())
+	}
+
+	text, exists, err := h.documentText(dh)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		cc, err := ilsp.ClientCapabilities(ctx)
+		if err != nil || !ilsp.SupportsCreateFile(cc) {
+			return nil, fmt.Errorf("%w: file %q does not exist", jrpc2.InvalidParams.Err(), dh.FullPath())
+		}
+	}
+
+	newText := scaffold.ResourceBlock(rType, name, body, includeOptional)
+	switch {
+	case len(text) == 0:
+	case strings.HasSuffix(string(text), "\n"):
+		newText = "\n" + newText
+	default:
+		newText = "\n\n" + newText
+	}
+
+	var edit interface{}
+	if exists {
+		f, _ := hclsyntax.ParseConfig(text, dh.Filename, hcl.InitialPos)
+		end := f.Body.(*hclsyntax.Body).Range().End
+		edit = lsp.WorkspaceEdit{
+			Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+				lsp.DocumentURI(dh.FullURI()): {
+					{
+						Range:   ilsp.HCLRangeToLSP(hcl.Range{Filename: dh.Filename, Start: end, End: end}),
+						NewText: newText,
+					},
+				},
+			},
+		}
+	} else {
+		edit = ilsp.NewCreateFileWorkspaceEdit(lsp.DocumentURI(dh.FullURI()), newText)
+	}
+
+	resp, err := jrpc2.ServerFromContext(ctx).Callback(ctx, "workspace/applyEdit", ilsp.ApplyWorkspaceEditParams{
+		Label: fmt.Sprintf("Scaffold %s.%s", rType, name),
+		Edit:  edit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result lsp.ApplyWorkspaceEditResult
+	err = resp.UnmarshalResult(&result)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Applied {
+		return nil, fmt.Errorf("client did not apply the edit: %s", result.FailureReason)
+	}
+
+	return nil, nil
+}
+
+// resourceProvider returns the address and version constraints
+// of the provider which the given resource type belongs to,
+// based on the conventional prefix of the type (e.g. aws in aws_instance)
+func (h *CmdHandler) resourceProvider(modPath, rType string) (tfaddr.Provider, version.Constraints) {
+	localName, _, _ := strings.Cut(rType, "_")
+
+	// the module may not be known yet, in which case
+	// the provider is assumed to be in the hashicorp namespace
+	reqs, _ := h.ModulesFeature.ProviderRequirements(modPath)
+	return matchingProvider(reqs, localName)
+}
+
+// matchingProvider returns the required provider of the given type.
+// If more providers of that type are required (e.g. a fork),
+// the one in the hashicorp namespace is preferred, followed by
+// the first one in alphabetical order, so that the choice is stable.
+func matchingProvider(reqs tfmod.ProviderRequirements, pType string) (tfaddr.Provider, version.Constraints) {
+	candidates := make([]tfaddr.Provider, 0)
+	for pAddr := range reqs {
+		if pAddr.Type == pType {
+			candidates = append(candidates, pAddr)
+		}
+	}
+	if len(candidates) == 0 {
+		return tfaddr.NewProvider(tfaddr.DefaultProviderRegistryHost, "hashicorp", pType), version.Constraints{}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		iHashicorp := candidates[i].Namespace == "hashicorp"
+		jHashicorp := candidates[j].Namespace == "hashicorp"
+		if iHashicorp != jHashicorp {
+			return iHashicorp
+		}
+		return candidates[i].String() < candidates[j].String()
+	})
+
+	return candidates[0], reqs[candidates[0]]
+}
+
+// documentText returns the text of the open document,
+// or the content of the file on disk, if it exists
+func (h *CmdHandler) documentText(dh document.Handle) ([]byte, bool, error) {
+	doc, err := h.StateStore.DocumentStore.GetDocument(dh)
+	if err == nil {
+		return doc.Text, true, nil
+	}
+
+	text, err := os.ReadFile(dh.FullPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	return text, err == nil, err
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+)
+
+func Test_matchingProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqs     tfmod.ProviderRequirements
+		expected tfaddr.Provider
+	}{
+		{
+			name:     "no requirements",
+			reqs:     nil,
+			expected: tfaddr.MustParseProviderSource("hashicorp/aws"),
+		},
+		{
+			name: "hashicorp namespace preferred",
+			reqs: tfmod.ProviderRequirements{
+				tfaddr.MustParseProviderSource("acme/aws"):      version.Constraints{},
+				tfaddr.MustParseProviderSource("hashicorp/aws"): version.MustConstraints(version.NewConstraint("~> 5.0")),
+				tfaddr.MustParseProviderSource("zeta/aws"):      version.Constraints{},
+			},
+			expected: tfaddr.MustParseProviderSource("hashicorp/aws"),
+		},
+		{
+			name: "sorted without hashicorp namespace",
+			reqs: tfmod.ProviderRequirements{
+				tfaddr.MustParseProviderSource("zeta/aws"):      version.Constraints{},
+				tfaddr.MustParseProviderSource("acme/aws"):      version.Constraints{},
+				tfaddr.MustParseProviderSource("hashicorp/gcp"): version.Constraints{},
+			},
+			expected: tfaddr.MustParseProviderSource("acme/aws"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// map iteration is random, so the choice is checked repeatedly
+			for i := 0; i < 10; i++ {
+				pAddr, cons := matchingProvider(tt.reqs, "aws")
+				if !pAddr.Equals(tt.expected) {
+					t.Fatalf("expected provider %s, given: %s", tt.expected, pAddr)
+				}
+				if cons.String() != tt.reqs[tt.expected].String() {
+					t.Fatalf("expected constraints %q, given: %q", tt.reqs[tt.expected], cons)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/hashicorp/terraform-ls/internal/langserver/cmd"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+const stackGraphVersion = 0
+
+type stackGraphResponse struct {
+	FormatVersion int               `json:"v"`
+	Nodes         []graphNode       `json:"nodes"`
+	Edges         []graphEdge       `json:"edges"`
+	Deployments   []stackDeployment `json:"deployments"`
+	Cycles        [][]string        `json:"cycles"`
+	DOT           string            `json:"dot"`
+}
+
+type stackDeployment struct {
+	Name   string   `json:"name"`
+	Inputs []string `json:"inputs"`
+}
+
+func (h *CmdHandler) StackGraphHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
+	response := stackGraphResponse{
+		FormatVersion: stackGraphVersion,
+		Nodes:         make([]graphNode, 0),
+		Edges:         make([]graphEdge, 0),
+		Deployments:   make([]stackDeployment, 0),
+		Cycles:        make([][]string, 0),
+	}
+
+	stackUri, ok := args.GetString("uri")
+	if !ok || stackUri == "" {
+		return response, fmt.Errorf("%w: expected stack uri argument to be set", jrpc2.InvalidParams.Err())
+	}
+
+	if !uri.IsURIValid(stackUri) {
+		return response, fmt.Errorf("URI %q is not valid", stackUri)
+	}
+
+	stackPath, err := uri.PathFromURI(stackUri)
+	if err != nil {
+		return response, err
+	}
+
+	g, err := h.StacksFeature.DependencyGraph(stackPath)
+	if err != nil {
+		return response, err
+	}
+
+	response.Nodes, response.Edges = graphNodesAndEdges(g, stackPath)
+	response.Cycles = g.Cycles()
+	response.DOT = g.DOT(filepath.Base(stackPath))
+
+	deployments, err := h.StacksFeature.Deployments(stackPath)
+	if err != nil {
+		return response, err
+	}
+	for name, deployment := range deployments {
+		inputs := make([]string, 0, len(deployment.Inputs))
+		for input := range deployment.Inputs {
+			inputs = append(inputs, input)
+		}
+		sort.Strings(inputs)
+
+		response.Deployments = append(response.Deployments, stackDeployment{
+			Name:   name,
+			Inputs: inputs,
+		})
+	}
+	sort.SliceStable(response.Deployments, func(i, j int) bool {
+		return response.Deployments[i].Name < response.Deployments[j].Name
+	})
+
+	return response, nil
+}
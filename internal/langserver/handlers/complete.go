@@ -50,6 +50,9 @@ func (svc *service) TextDocumentComplete(ctx context.Context, params lsp.Complet
 
 	svc.logger.Printf("Looking for candidates at %q -> %#v", doc.Filename, pos)
 	candidates, err := d.CompletionAtPos(ctx, doc.Filename, pos)
+	if err == nil && doc.LanguageID == ilsp.Terraform.String() {
+		candidates = svc.appendModuleResourceAddresses(doc, pos, candidates)
+	}
 	svc.logger.Printf("received candidates: %#v", candidates)
 	return ilsp.ToCompletionList(candidates, cc.TextDocument), err
 }
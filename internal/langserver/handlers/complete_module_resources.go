@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/document"
+)
+
+// addressAttrPrefix matches the beginning of an address
+// typed as the value of an address attribute (i.e. to or from)
+var addressAttrPrefix = regexp.MustCompile(`^\s*(to|from)\s*=\s*([a-zA-Z0-9_.\-]*)$`)
+
+// appendModuleResourceAddresses appends addresses of resources declared
+// in called modules to the given candidates when completing the target
+// address of an import block or the addresses of a moved block.
+//
+// Resources within modules are not reference targets of the calling
+// module and so cannot be completed by the decoder itself.
+func (svc *service) appendModuleResourceAddresses(doc *document.Document, pos hcl.Pos, candidates lang.Candidates) lang.Candidates {
+	f, _ := hclsyntax.ParseConfig(doc.Text, doc.Filename, hcl.InitialPos)
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return candidates
+	}
+
+	var blockType string
+	for _, block := range body.Blocks {
+		if block.Body.Range().ContainsPos(pos) {
+			blockType = block.Type
+			break
+		}
+	}
+	if blockType != "import" && blockType != "moved" {
+		return candidates
+	}
+
+	line, ok := linePrefix(doc.Text, pos)
+	if !ok {
+		return candidates
+	}
+	match := addressAttrPrefix.FindStringSubmatch(line)
+	if match == nil || (blockType == "import" && match[1] != "to") {
+		return candidates
+	}
+	prefix := match[2]
+
+	existing := make(map[string]bool, len(candidates.List))
+	for _, c := range candidates.List {
+		existing[c.Label] = true
+	}
+
+	editRng := hcl.Range{
+		Filename: doc.Filename,
+		Start: hcl.Pos{
+			Line:   pos.Line,
+			Column: pos.Column - len(prefix),
+			Byte:   pos.Byte - len(prefix),
+		},
+		End: pos,
+	}
+	for _, addr := range svc.features.Modules.ModuleCallResourceAddresses(doc.Dir.Path()) {
+		if !strings.HasPrefix(addr, prefix) || existing[addr] {
+			continue
+		}
+		candidates.List = append(candidates.List, lang.Candidate{
+			Label:  addr,
+			Detail: "resource in module",
+			Kind:   lang.ReferenceCandidateKind,
+			TextEdit: lang.TextEdit{
+				Range:   editRng,
+				NewText: addr,
+				Snippet: addr,
+			},
+		})
+	}
+
+	return candidates
+}
+
+// linePrefix returns the text of the line at the given position
+// up to the position
+func linePrefix(text []byte, pos hcl.Pos) (string, bool) {
+	if pos.Byte > len(text) {
+		return "", false
+	}
+	prefix := string(text[:pos.Byte])
+	if idx := strings.LastIndexByte(prefix, '\n'); idx >= 0 {
+		prefix = prefix[idx+1:]
+	}
+	return prefix, true
+}
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/document"
+	"github.com/hashicorp/terraform-ls/internal/eventbus"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/settings"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+// configDirs returns directories which may contain config files,
+// i.e. the root directory followed by any other workspace folders
+func configDirs(params lsp.InitializeParams) []string {
+	dirs := make([]string, 0)
+	seen := make(map[string]bool)
+
+	rawURIs := make([]string, 0, len(params.WorkspaceFolders)+1)
+	if params.RootURI != "" {
+		rawURIs = append(rawURIs, string(params.RootURI))
+	}
+	for _, folder := range params.WorkspaceFolders {
+		rawURIs = append(rawURIs, folder.URI)
+	}
+
+	for _, rawURI := range rawURIs {
+		if !uri.IsURIValid(rawURI) {
+			continue
+		}
+		dir := document.DirHandleFromURI(rawURI).Path()
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+
+	return dirs
+}
+
+// effectiveOptions returns the client options with settings
+// from config files applied. Invalid config files are reported
+// to the user and ignored.
+func (svc *service) effectiveOptions(ctx context.Context) *settings.Options {
+	files := make([]*settings.ConfigFile, 0)
+	for _, dir := range svc.configDirs {
+		cfg, err := settings.LoadConfigFile(dir)
+		if err != nil {
+			jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+				Type:    lsp.Warning,
+				Message: fmt.Sprintf("Ignoring invalid config file in %s: %s", dir, err),
+			})
+			continue
+		}
+		if cfg != nil {
+			svc.logger.Printf("loaded config file in %s", dir)
+			files = append(files, cfg)
+		}
+	}
+
+	options := svc.clientOptions.WithConfigFiles(files)
+	err := options.Validate()
+	if err != nil {
+		jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+			Type:    lsp.Warning,
+			Message: fmt.Sprintf("Ignoring invalid config files: %s", err),
+		})
+		return svc.clientOptions.Options
+	}
+
+	return options
+}
+
+// reloadConfigFiles applies settings from config files again
+// after any of them changed, without restarting the server
+func (svc *service) reloadConfigFiles(ctx context.Context) error {
+	if svc.clientOptions == nil || len(svc.configDirs) == 0 {
+		return nil
+	}
+
+	options := svc.effectiveOptions(ctx)
+	svc.applyWorkspaceOptions(ctx, svc.configDirs[0], options)
+	svc.ruleFilter.SetSeverities(ruleSeverities(options.Validation))
+
+	err := lsctx.SetValidationOptions(ctx, options.Validation)
+	if err != nil {
+		return err
+	}
+
+	// Options affecting validation (e.g. policy files, rules
+	// or variable files) may have changed
+	err = svc.revalidateOpenDocuments(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Walk again, so that directories which are no longer ignored get indexed
+	for _, dir := range svc.configDirs {
+		err := svc.stateStore.WalkerPaths.EnqueueDir(ctx, document.DirHandleFromPath(dir))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revalidateOpenDocuments schedules decoding and validation of
+// directories with open documents again, once per language
+func (svc *service) revalidateOpenDocuments(ctx context.Context) error {
+	docs, err := svc.stateStore.DocumentStore.ListDocuments()
+	if err != nil {
+		return err
+	}
+
+	type dirLanguage struct {
+		dir        document.DirHandle
+		languageID string
+	}
+	seen := make(map[dirLanguage]bool)
+	for _, doc := range docs {
+		key := dirLanguage{doc.Dir, doc.LanguageID}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		svc.eventBus.DidChange(eventbus.DidChangeEvent{
+			Context:    ctx,
+			Dir:        doc.Dir,
+			LanguageID: doc.LanguageID,
+		})
+	}
+
+	return nil
+}
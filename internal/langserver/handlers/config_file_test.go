@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+func TestConfigDirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	rootDir := filepath.Join(tmpDir, "root")
+	otherDir := filepath.Join(tmpDir, "other")
+
+	params := lsp.InitializeParams{}
+	params.RootURI = lsp.DocumentURI(uri.FromPath(rootDir))
+	params.WorkspaceFolders = []lsp.WorkspaceFolder{
+		{URI: uri.FromPath(rootDir), Name: "root"},
+		{URI: uri.FromPath(otherDir), Name: "other"},
+		{URI: "invalid", Name: "invalid"},
+	}
+	dirs := configDirs(params)
+
+	expectedDirs := []string{rootDir, otherDir}
+	if diff := cmp.Diff(expectedDirs, dirs); diff != "" {
+		t.Fatalf("unexpected config dirs: %s", diff)
+	}
+}
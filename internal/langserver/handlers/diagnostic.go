@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/terraform-ls/internal/document"
+	"github.com/hashicorp/terraform-ls/internal/langserver/diagnostics"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+// TextDocumentDiagnostic returns either a full or an unchanged
+// report of diagnostics for the given document
+func (svc *service) TextDocumentDiagnostic(ctx context.Context, params lsp.DocumentDiagnosticParams) (interface{}, error) {
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+
+	jobIds, err := svc.stateStore.JobStore.ListIncompleteJobsForDir(dh.Dir)
+	if err != nil {
+		return nil, err
+	}
+	svc.stateStore.JobStore.WaitForJobs(ctx, jobIds...)
+
+	diags := collectDiagnostics(svc.features, svc.ruleFilter, dh.Dir.Path())
+	items := diags.FileDiagnostics(dh.Filename)
+	resultId := diagnostics.ResultID(items)
+
+	if params.PreviousResultID == resultId {
+		return lsp.RelatedUnchangedDocumentDiagnosticReport{
+			UnchangedDocumentDiagnosticReport: lsp.UnchangedDocumentDiagnosticReport{
+				Kind:     string(lsp.DiagnosticUnchanged),
+				ResultID: resultId,
+			},
+		}, nil
+	}
+
+	return lsp.RelatedFullDocumentDiagnosticReport{
+		FullDocumentDiagnosticReport: lsp.FullDocumentDiagnosticReport{
+			Kind:     string(lsp.DiagnosticFull),
+			ResultID: resultId,
+			Items:    items,
+		},
+	}, nil
+}
+
+func (svc *service) WorkspaceDiagnostic(ctx context.Context, params lsp.WorkspaceDiagnosticParams) (ilsp.WorkspaceDiagnosticReport, error) {
+	report := ilsp.WorkspaceDiagnosticReport{
+		Items: make([]interface{}, 0),
+	}
+
+	previousResultIds := make(map[lsp.DocumentURI]string, len(params.PreviousResultIds))
+	for _, previous := range params.PreviousResultIds {
+		previousResultIds[previous.URI] = previous.Value
+	}
+
+	for _, path := range svc.diagnosticPaths(ctx) {
+		diags := collectDiagnostics(svc.features, svc.ruleFilter, path)
+
+		filenames := make([]string, 0, len(diags))
+		for filename := range diags {
+			if filename == "" {
+				continue
+			}
+			filenames = append(filenames, filename)
+		}
+		sort.Strings(filenames)
+
+		for _, filename := range filenames {
+			docUri := lsp.DocumentURI(uri.FromPath(filepath.Join(path, filename)))
+			items := diags.FileDiagnostics(filename)
+			resultId := diagnostics.ResultID(items)
+
+			// version is only known for open documents
+			var version *int32
+			doc, err := svc.stateStore.DocumentStore.GetDocument(document.Handle{
+				Dir:      document.DirHandleFromPath(path),
+				Filename: filename,
+			})
+			if err == nil {
+				v := int32(doc.Version)
+				version = &v
+			}
+
+			if previousResultIds[docUri] == resultId {
+				report.Items = append(report.Items, ilsp.WorkspaceUnchangedDocumentDiagnosticReport{
+					URI:     docUri,
+					Version: version,
+					UnchangedDocumentDiagnosticReport: lsp.UnchangedDocumentDiagnosticReport{
+						Kind:     string(lsp.DiagnosticUnchanged),
+						ResultID: resultId,
+					},
+				})
+				continue
+			}
+
+			report.Items = append(report.Items, ilsp.WorkspaceFullDocumentDiagnosticReport{
+				URI:     docUri,
+				Version: version,
+				FullDocumentDiagnosticReport: lsp.FullDocumentDiagnosticReport{
+					Kind:     string(lsp.DiagnosticFull),
+					ResultID: resultId,
+					Items:    items,
+				},
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// diagnosticPaths returns sorted paths of all directories
+// known to any feature which can report diagnostics
+func (svc *service) diagnosticPaths(ctx context.Context) []string {
+	uniquePaths := make(map[string]struct{}, 0)
+	for _, path := range svc.features.Modules.Paths(ctx) {
+		uniquePaths[path.Path] = struct{}{}
+	}
+	for _, path := range svc.features.Variables.Paths(ctx) {
+		uniquePaths[path.Path] = struct{}{}
+	}
+	for _, path := range svc.features.Stacks.Paths(ctx) {
+		uniquePaths[path.Path] = struct{}{}
+	}
+	for _, path := range svc.features.Tests.Paths(ctx) {
+		uniquePaths[path.Path] = struct{}{}
+	}
+
+	paths := make([]string, 0, len(uniquePaths))
+	for path := range uniquePaths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-ls/internal/langserver"
+	"github.com/hashicorp/terraform-ls/internal/langserver/session"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/walker"
+)
+
+func TestDocumentDiagnostic_withoutInitialization(t *testing.T) {
+	ls := langserver.NewLangServerMock(t, NewMockSession(nil))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.CallAndExpectError(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, TempDir(t).URI)}, session.SessionNotInitialized.Err())
+}
+
+func TestDocumentDiagnostic_resultId(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"textDocument": {
+				"diagnostic": {}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "variable \"test\" {\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	rsp := ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, tmpDir.URI),
+	})
+	var fullReport lsp.FullDocumentDiagnosticReport
+	err = json.Unmarshal(rsp.Result, &fullReport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fullReport.Kind != string(lsp.DiagnosticFull) {
+		t.Fatalf("expected full report, given: %q", fullReport.Kind)
+	}
+	if len(fullReport.Items) != 1 {
+		t.Fatalf("expected 1 diagnostic, given: %#v", fullReport.Items)
+	}
+	if fullReport.ResultID == "" {
+		t.Fatal("expected result ID to be set")
+	}
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"previousResultId": %q
+		}`, tmpDir.URI, fullReport.ResultID),
+	}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 4,
+		"result": {
+			"kind": "unchanged",
+			"resultId": %q
+		}
+	}`, fullReport.ResultID))
+
+	rsp = ls.Call(t, &langserver.CallRequest{
+		Method: "workspace/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"previousResultIds": [
+				{
+					"uri": "%s/main.tf",
+					"value": %q
+				}
+			]
+		}`, tmpDir.URI, fullReport.ResultID),
+	})
+	var workspaceReport struct {
+		Items []lsp.WorkspaceUnchangedDocumentDiagnosticReport `json:"items"`
+	}
+	err = json.Unmarshal(rsp.Result, &workspaceReport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workspaceReport.Items) != 1 {
+		t.Fatalf("expected 1 report, given: %#v", workspaceReport.Items)
+	}
+	if workspaceReport.Items[0].Kind != string(lsp.DiagnosticUnchanged) {
+		t.Fatalf("expected unchanged report, given: %q", workspaceReport.Items[0].Kind)
+	}
+}
+
+func TestWorkspaceDiagnostic_closedDocumentVersion(t *testing.T) {
+	tmpDir := TempDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "closed.tf"), []byte("variable \"closed\" {\n"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"textDocument": {
+				"diagnostic": {}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 2,
+			"languageId": "terraform",
+			"text": "variable \"test\" {\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	rsp := ls.Call(t, &langserver.CallRequest{
+		Method:    "workspace/diagnostic",
+		ReqParams: `{"previousResultIds": []}`,
+	})
+	var report struct {
+		Items []struct {
+			URI     string `json:"uri"`
+			Version *int32 `json:"version"`
+		} `json:"items"`
+	}
+	err = json.Unmarshal(rsp.Result, &report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions := make(map[string]*int32, 0)
+	for _, item := range report.Items {
+		versions[item.URI] = item.Version
+	}
+	openVersion, ok := versions[tmpDir.URI+"/main.tf"]
+	if !ok || openVersion == nil || *openVersion != 2 {
+		t.Fatalf("expected version 2 for open document, given: %#v", report.Items)
+	}
+	closedVersion, ok := versions[tmpDir.URI+"/closed.tf"]
+	if !ok || closedVersion != nil {
+		t.Fatalf("expected null version for closed document, given: %#v", report.Items)
+	}
+}
@@ -7,12 +7,15 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/creachadair/jrpc2"
 	"github.com/hashicorp/terraform-ls/internal/document"
 	"github.com/hashicorp/terraform-ls/internal/eventbus"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/settings"
 	"github.com/hashicorp/terraform-ls/internal/terraform/datadir"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfversion"
 	"github.com/hashicorp/terraform-ls/internal/uri"
 )
 
@@ -96,6 +99,48 @@ func (svc *service) DidChangeWatchedFiles(ctx context.Context, params lsp.DidCha
 		}
 		isDir := false
 
+		// If a file pinning the Terraform version changes
+		if tfversion.IsVersionFile(rawPath) {
+			dir := document.DirHandleFromPath(filepath.Dir(rawPath))
+			// Cached versions are invalidated before any feature
+			// reacts to the event, so that none decodes with stale ones
+			svc.versionResolver.Invalidate(dir.Path())
+
+			svc.eventBus.VersionFileChange(eventbus.VersionFileChangeEvent{
+				Context:    ctx, // We pass the context for data here
+				Dir:        dir,
+				ChangeType: change.Type,
+			})
+
+			continue
+		}
+
+		// If a policy file declaring custom rules changes
+		isPolicyFile, err := svc.features.Modules.PolicyFileChange(ctx, rawPath)
+		if err != nil {
+			svc.logger.Printf("failed to recheck policies (%q changed): %s", rawPath, err)
+		}
+		if isPolicyFile {
+			continue
+		}
+
+		// If a config file with project-specific settings changes
+		if settings.IsConfigFile(rawPath) {
+			err := svc.reloadConfigFiles(ctx)
+			if err != nil {
+				svc.logger.Printf("failed to reload config files (%q changed): %s", rawPath, err)
+			}
+
+			continue
+		}
+
+		if change.Type == lsp.Created || change.Type == lsp.Deleted {
+			// Whether a directory targets OpenTofu depends on files in it,
+			// so the cached result is invalidated before features react
+			svc.openTofuDetector.Invalidate(filepath.Dir(rawPath))
+			svc.openTofuDetector.Invalidate(rawPath)
+		}
+
 		if change.Type == lsp.Deleted {
 			// Fall through and just fire the event
 		}
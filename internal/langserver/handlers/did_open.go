@@ -10,7 +10,9 @@ import (
 	"github.com/creachadair/jrpc2"
 	"github.com/hashicorp/terraform-ls/internal/document"
 	"github.com/hashicorp/terraform-ls/internal/eventbus"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/terraform/opentofu"
 	"github.com/hashicorp/terraform-ls/internal/uri"
 )
 
@@ -30,16 +32,22 @@ func (svc *service) TextDocumentDidOpen(ctx context.Context, params lsp.DidOpenT
 	}
 
 	dh := document.HandleFromURI(docURI)
-	err := svc.stateStore.DocumentStore.OpenDocument(dh, params.TextDocument.LanguageID,
+	languageID := ilsp.NormalizeLanguageID(params.TextDocument.LanguageID)
+	err := svc.stateStore.DocumentStore.OpenDocument(dh, languageID,
 		int(params.TextDocument.Version), []byte(params.TextDocument.Text))
 	if err != nil {
 		return err
 	}
 
+	if opentofu.IsModuleFilename(dh.Filename) || opentofu.IsTestFilename(dh.Filename) {
+		// The document may not exist on disk yet
+		svc.openTofuDetector.Invalidate(dh.Dir.Path())
+	}
+
 	svc.eventBus.DidOpen(eventbus.DidOpenEvent{
 		Context:    ctx, // We pass the context for data here
 		Dir:        dh.Dir,
-		LanguageID: params.TextDocument.LanguageID,
+		LanguageID: languageID,
 	})
 
 	if svc.singleFileMode {
@@ -14,6 +14,14 @@ import (
 )
 
 func (svc *service) TextDocumentDidSave(ctx context.Context, params lsp.DidSaveTextDocumentParams) error {
+	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
+
+	_, err := svc.features.Modules.TFLint(ctx, dh.Dir)
+	if err != nil {
+		// Linting is independent of validation on save below
+		svc.logger.Printf("failed to schedule tflint for %q: %s", dh.Dir.Path(), err)
+	}
+
 	expFeatures, err := lsctx.ExperimentalFeatures(ctx)
 	if err != nil {
 		return err
@@ -22,8 +30,6 @@ func (svc *service) TextDocumentDidSave(ctx context.Context, params lsp.DidSaveT
 		return nil
 	}
 
-	dh := ilsp.HandleFromDocumentURI(params.TextDocument.URI)
-
 	cmdHandler := &command.CmdHandler{
 		StateStore: svc.stateStore,
 	}
@@ -22,15 +22,21 @@ func cmdHandlers(svc *service) cmd.Handlers {
 	if svc.features != nil {
 		cmdHandler.ModulesFeature = svc.features.Modules
 		cmdHandler.RootModulesFeature = svc.features.RootModules
+		cmdHandler.StacksFeature = svc.features.Stacks
 	}
 	return cmd.Handlers{
 		cmd.Name("rootmodules"):        removedHandler("use module.callers instead"),
 		cmd.Name("module.callers"):     cmdHandler.ModuleCallersHandler,
 		cmd.Name("terraform.init"):     cmdHandler.TerraformInitHandler,
 		cmd.Name("terraform.validate"): cmdHandler.TerraformValidateHandler,
+		cmd.Name("terraform.plan"):     cmdHandler.TerraformPlanHandler,
+		cmd.Name("resource.scaffold"):  cmdHandler.ScaffoldResourceHandler,
+		cmd.Name("module.extract"):     cmdHandler.ExtractModuleHandler,
 		cmd.Name("module.calls"):       cmdHandler.ModuleCallsHandler,
 		cmd.Name("module.providers"):   cmdHandler.ModuleProvidersHandler,
+		cmd.Name("module.graph"):       cmdHandler.ModuleGraphHandler,
 		cmd.Name("module.terraform"):   cmdHandler.TerraformVersionRequestHandler,
+		cmd.Name("stack.graph"):        cmdHandler.StackGraphHandler,
 	}
 }
 
@@ -118,7 +118,7 @@ func TestLangServer_workspaceExecuteCommand_moduleProviders_basic(t *testing.T)
 		},
 	}
 
-	err = features.Modules.Store.UpdateMetadata(modDir, metadata, nil)
+	err = features.Modules.Store.UpdateMetadata(modDir, 0, metadata, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -57,7 +57,7 @@ func TestLangServer_workspaceExecuteCommand_terraformVersion_basic(t *testing.T)
 		CoreRequirements: testConstraint(t, "~> 0.15"),
 	}
 
-	err = features.Modules.Store.UpdateMetadata(modDir, metadata, nil)
+	err = features.Modules.Store.UpdateMetadata(modDir, 0, metadata, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
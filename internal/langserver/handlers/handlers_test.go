@@ -51,7 +51,7 @@ func initializeResponse(t *testing.T, commandPrefix string) string {
 				"referencesProvider": true,
 				"documentSymbolProvider": true,
 				"codeActionProvider": {
-					"codeActionKinds": ["source.formatAll.terraform"]
+					"codeActionKinds": ["quickfix.suppress.terraform", "refactor.extract.local.terraform", "refactor.extract.module.terraform", "refactor.extract.variable.terraform", "refactor.inline.local.terraform", "refactor.rewrite.count.terraform", "refactor.rewrite.forEach.terraform", "refactor.rewrite.moved.terraform", "source.formatAll.terraform"]
 				},
 				"codeLensProvider": {},
 				"documentLinkProvider": {},
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/langserver/diagnostics"
 	"github.com/hashicorp/terraform-ls/internal/langserver/notifier"
 	"github.com/hashicorp/terraform-ls/internal/langserver/session"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 	"github.com/hashicorp/terraform-ls/internal/state"
 	"github.com/hashicorp/terraform-ls/internal/telemetry"
 )
@@ -51,7 +52,7 @@ func sendModuleTelemetry(features *Features, telemetrySender telemetry.Sender) n
 	}
 }
 
-func updateDiagnostics(features *Features, dNotifier *diagnostics.Notifier) notifier.Hook {
+func updateDiagnostics(features *Features, dNotifier *diagnostics.Notifier, ruleFilter *rules.Filter) notifier.Hook {
 	return func(ctx context.Context, changes state.Changes) error {
 		if changes.Diagnostics {
 			path, err := notifier.RecordPathFromContext(ctx)
@@ -61,11 +62,7 @@ func updateDiagnostics(features *Features, dNotifier *diagnostics.Notifier) noti
 
 			diags := diagnostics.NewDiagnostics()
 			diags.EmptyRootDiagnostic()
-
-			diags.Extend(features.Modules.Diagnostics(path))
-			diags.Extend(features.Variables.Diagnostics(path))
-			diags.Extend(features.Stacks.Diagnostics(path))
-			diags.Extend(features.Tests.Diagnostics(path))
+			diags.Extend(collectDiagnostics(features, ruleFilter, path))
 
 			dNotifier.PublishHCLDiags(ctx, path, diags)
 		}
@@ -73,6 +70,33 @@ func updateDiagnostics(features *Features, dNotifier *diagnostics.Notifier) noti
 	}
 }
 
+// refreshDiagnostics asks clients which pull diagnostics
+// to pull them again, instead of publishing them.
+func refreshDiagnostics(clientRequester session.ClientCaller) notifier.Hook {
+	return func(ctx context.Context, changes state.Changes) error {
+		if changes.Diagnostics {
+			_, err := clientRequester.Callback(ctx, "workspace/diagnostic/refresh", nil)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// collectDiagnostics merges diagnostics of the given path from all features
+// and applies configured rule severities and suppressions
+func collectDiagnostics(features *Features, ruleFilter *rules.Filter, path string) diagnostics.Diagnostics {
+	diags := diagnostics.NewDiagnostics()
+
+	diags.Extend(features.Modules.Diagnostics(path))
+	diags.Extend(features.Variables.Diagnostics(path))
+	diags.Extend(features.Stacks.Diagnostics(path))
+	diags.Extend(features.Tests.Diagnostics(path))
+
+	return diags.ApplyRules(path, ruleFilter)
+}
+
 func callRefreshClientCommand(clientRequester session.ClientCaller, commandId string) notifier.Hook {
 	return func(ctx context.Context, changes state.Changes) error {
 		// TODO: avoid triggering if module calls/providers did not change
@@ -100,7 +124,7 @@ func callRefreshClientCommand(clientRequester session.ClientCaller, commandId st
 func refreshCodeLens(clientRequester session.ClientCaller) notifier.Hook {
 	return func(ctx context.Context, changes state.Changes) error {
 		// TODO: avoid triggering for new targets outside of open module
-		if changes.ReferenceOrigins || changes.ReferenceTargets {
+		if changes.ReferenceOrigins || changes.ReferenceTargets || changes.PlannedActions {
 			_, err := clientRequester.Callback(ctx, "workspace/codeLens/refresh", nil)
 			if err != nil {
 				return err
@@ -41,6 +41,15 @@ func (svc *service) TextDocumentHover(ctx context.Context, params lsp.TextDocume
 	svc.logger.Printf("Looking for hover data at %q -> %#v", doc.Filename, pos)
 	hoverData, err := d.HoverAtPos(ctx, doc.Filename, pos)
 	svc.logger.Printf("received hover data: %#v", hoverData)
+
+	if doc.LanguageID == ilsp.Terraform.String() {
+		// State values may be available even if there is nothing else to show,
+		// e.g. for resources of providers with unknown schema
+		stateHoverData := svc.appendStateValue(doc, pos, hoverData)
+		if stateHoverData != nil {
+			return ilsp.HoverData(stateHoverData, cc.TextDocument), nil
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,132 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/document"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfstate"
+)
+
+// appendStateValue appends the current value of the resource (or its
+// attribute) referenced at the given position, as found in the local
+// state snapshot of the module, to the given hover data.
+func (svc *service) appendStateValue(doc *document.Document, pos hcl.Pos, hoverData *lang.HoverData) *lang.HoverData {
+	modPath := doc.Dir.Path()
+	pathCtx, err := svc.features.Modules.PathContext(lang.Path{
+		Path:       modPath,
+		LanguageID: ilsp.Terraform.String(),
+	})
+	if err != nil {
+		return hoverData
+	}
+
+	var addr lang.Address
+	var rng hcl.Range
+	origins, ok := pathCtx.ReferenceOrigins.AtPos(doc.Filename, pos)
+	if ok {
+		for _, origin := range origins {
+			if localOrigin, ok := origin.(reference.LocalOrigin); ok {
+				addr = localOrigin.Addr
+				rng = localOrigin.Range
+				break
+			}
+		}
+	}
+	if addr == nil {
+		// The position may also be within the header of the block itself
+		for _, target := range pathCtx.ReferenceTargets {
+			if target.DefRangePtr != nil && target.DefRangePtr.Filename == doc.Filename &&
+				target.DefRangePtr.ContainsPos(pos) {
+				addr = target.Addr
+				rng = *target.DefRangePtr
+				break
+			}
+		}
+	}
+	if addr == nil {
+		return hoverData
+	}
+
+	value, ok := svc.stateValue(modPath, addr)
+	if !ok {
+		return hoverData
+	}
+	formattedValue := tfstate.FormatValue(value)
+
+	if hoverData == nil {
+		hoverData = &lang.HoverData{
+			Content: lang.MarkupContent{Kind: lang.MarkdownKind},
+			Range:   rng,
+		}
+	}
+	var section, separator string
+	if hoverData.Content.Kind == lang.MarkdownKind {
+		section = fmt.Sprintf("**Current state value**\n```terraform\n%s\n```", formattedValue)
+		separator = "\n\n---\n\n"
+	} else {
+		section = fmt.Sprintf("Current state value:\n%s", formattedValue)
+		separator = "\n\n"
+	}
+	if hoverData.Content.Value != "" {
+		section = separator + section
+	}
+	hoverData.Content.Value += section
+
+	return hoverData
+}
+
+// stateValue returns the value of the given address from the local state
+// snapshot of the module, following any output of a called module
+// (e.g. module.vpc.vpc_id), since these are not persisted in state.
+// Values of outputs declared as sensitive are redacted.
+func (svc *service) stateValue(modPath string, addr lang.Address) (interface{}, bool) {
+	value, ok := svc.features.RootModules.StateValue(modPath, addr)
+	if ok {
+		return value, true
+	}
+
+	if len(addr) < 3 || addr[0].String() != "module" {
+		return nil, false
+	}
+	callStep, ok := addr[1].(lang.AttrStep)
+	if !ok {
+		return nil, false
+	}
+	outputStep, ok := addr[2].(lang.AttrStep)
+	if !ok {
+		return nil, false
+	}
+
+	outputRef, ok := svc.features.Modules.ModuleOutputValueReference(modPath, callStep.Name, outputStep.Name)
+	if !ok || len(outputRef.Addr) == 0 {
+		return nil, false
+	}
+
+	resolvedAddr := lang.Address{
+		lang.RootStep{Name: "module"},
+		lang.AttrStep{Name: callStep.Name},
+	}
+	for i, step := range outputRef.Addr {
+		if rootStep, ok := step.(lang.RootStep); ok && i == 0 {
+			step = lang.AttrStep{Name: rootStep.Name}
+		}
+		resolvedAddr = append(resolvedAddr, step)
+	}
+	resolvedAddr = append(resolvedAddr, addr[3:]...)
+
+	value, ok = svc.features.RootModules.StateValue(modPath, resolvedAddr)
+	if !ok {
+		return nil, false
+	}
+	if outputRef.Sensitive {
+		return tfstate.Sensitive{}, true
+	}
+	return value, true
+}
@@ -6,6 +6,8 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/go-version"
@@ -145,6 +147,113 @@ func TestHover_withValidData(t *testing.T) {
 		}`)
 }
 
+func TestHover_withStateValue(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	stateSnapshot := `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "serial": 1,
+  "lineage": "00000000-0000-0000-0000-000000000000",
+  "outputs": {},
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "web",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {"index_key": 0, "schema_version": 1, "attributes": {"id": "i-0"}},
+        {"index_key": 1, "schema_version": 1, "attributes": {"id": "i-1"}}
+      ]
+    }
+  ]
+}`
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "terraform.tfstate"), []byte(stateSnapshot), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"textDocument": {
+				"hover": {
+					"contentFormat": ["markdown"]
+				}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "resource \"aws_instance\" \"web\" {\n  count = 2\n}\n\noutput \"web_id\" {\n  value = aws_instance.web[1].id\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/hover",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"character": 14,
+				"line": 5
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"contents": {
+					"kind": "markdown",
+					"value": "**Current state value**\n`+"```"+`terraform\n\"i-1\"\n`+"```"+`"
+				},
+				"range": {
+					"start": {
+						"line": 5,
+						"character": 10
+					},
+					"end": {
+						"line": 5,
+						"character": 32
+					}
+				}
+			}
+		}`)
+}
+
 func TestVarsHover_withValidData(t *testing.T) {
 	tmpDir := TempDir(t)
 	InitPluginCache(t, tmpDir.Path())
@@ -263,3 +372,163 @@ func TestVarsHover_withValidData(t *testing.T) {
 			}
 		}`)
 }
+
+func TestHover_withSensitiveModuleOutputStateValue(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	// the attribute behind the output is not marked as sensitive in state
+	stateSnapshot := `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "serial": 1,
+  "lineage": "00000000-0000-0000-0000-000000000000",
+  "outputs": {},
+  "resources": [
+    {
+      "module": "module.db",
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "this",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {"schema_version": 1, "attributes": {"id": "db-1", "password": "hunter2"}}
+      ]
+    }
+  ]
+}`
+	err := os.WriteFile(filepath.Join(tmpDir.Path(), "terraform.tfstate"), []byte(stateSnapshot), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbPath := filepath.Join(tmpDir.Path(), "db")
+	err = os.Mkdir(dbPath, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbCfg := `resource "aws_db_instance" "this" {}
+
+output "password" {
+  value     = aws_db_instance.this.password
+  sensitive = true
+}
+
+output "id" {
+  value = aws_db_instance.this.id
+}
+`
+	err = os.WriteFile(filepath.Join(dbPath, "main.tf"), []byte(dbCfg), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+				dbPath:        validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"textDocument": {
+				"hover": {
+					"contentFormat": ["markdown"]
+				}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "module \"db\" {\n  source = \"./db\"\n}\n\noutput \"password\" {\n  value = module.db.password\n}\n\noutput \"id\" {\n  value = module.db.id\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/hover",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"character": 20,
+				"line": 5
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"contents": {
+					"kind": "markdown",
+					"value": "`+"`module.db.password`"+`\n_dynamic_\n\n---\n\n**Current state value**\n`+"```"+`terraform\n(sensitive value)\n`+"```"+`"
+				},
+				"range": {
+					"start": {
+						"line": 5,
+						"character": 10
+					},
+					"end": {
+						"line": 5,
+						"character": 28
+					}
+				}
+			}
+		}`)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/hover",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"character": 20,
+				"line": 9
+			}
+		}`, tmpDir.URI)}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"contents": {
+					"kind": "markdown",
+					"value": "`+"`module.db.id`"+`\n_dynamic_\n\n---\n\n**Current state value**\n`+"```"+`terraform\n\"db-1\"\n`+"```"+`"
+				},
+				"range": {
+					"start": {
+						"line": 9,
+						"character": 10
+					},
+					"end": {
+						"line": 9,
+						"character": 22
+					}
+				}
+			}
+		}`)
+}
@@ -12,8 +12,11 @@ import (
 	"github.com/creachadair/jrpc2"
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
 	"github.com/hashicorp/terraform-ls/internal/document"
+	"github.com/hashicorp/terraform-ls/internal/features/modules/lint"
+	fvdecoder "github.com/hashicorp/terraform-ls/internal/features/variables/decoder"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 	"github.com/hashicorp/terraform-ls/internal/settings"
 	"github.com/hashicorp/terraform-ls/internal/uri"
 	"github.com/mitchellh/go-homedir"
@@ -35,6 +38,16 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 	properties := getTelemetryProperties(out)
 	properties["lsVersion"] = serverCaps.ServerInfo.Version
 
+	// Settings from config files checked in at workspace folder roots
+	// apply, unless the client passes the same settings
+	svc.clientOptions = out
+	svc.configDirs = configDirs(params)
+	out = &settings.DecodedOptions{
+		Options:    svc.effectiveOptions(ctx),
+		UnusedKeys: out.UnusedKeys,
+		Keys:       out.Keys,
+	}
+
 	clientCaps := params.Capabilities
 	expClientCaps := lsp.ExperimentalClientCapabilities(clientCaps.Experimental)
 
@@ -80,6 +93,7 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 	if err != nil {
 		return serverCaps, err
 	}
+	svc.ruleFilter.SetSeverities(ruleSeverities(out.Options.Validation))
 
 	stCaps := clientCaps.TextDocument.SemanticTokens
 	caps := ilsp.SemanticTokensClientCapabilities{
@@ -95,6 +109,14 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 
 	serverCaps.Capabilities.SemanticTokensProvider = semanticTokensOpts
 
+	if clientCaps.TextDocument.Diagnostic != nil {
+		serverCaps.Capabilities.DiagnosticProvider = lsp.DiagnosticOptions{
+			Identifier:            "terraform-ls",
+			InterFileDependencies: true,
+			WorkspaceDiagnostics:  true,
+		}
+	}
+
 	// set commandPrefix for session
 	lsctx.SetCommandPrefix(ctx, out.Options.CommandPrefix)
 	// apply prefix to executeCommand handler names
@@ -200,6 +222,7 @@ func getTelemetryProperties(out *settings.DecodedOptions) map[string]interface{}
 		"options.commandPrefix":                           false,
 		"options.indexing.ignoreDirectoryNames":           false,
 		"options.indexing.ignorePaths":                    false,
+		"options.varFiles":                                false,
 		"options.experimentalFeatures.validateOnSave":     false,
 		"options.terraform.path":                          false,
 		"options.terraform.timeout":                       "",
@@ -214,6 +237,7 @@ func getTelemetryProperties(out *settings.DecodedOptions) map[string]interface{}
 	properties["options.commandPrefix"] = len(out.Options.CommandPrefix) > 0
 	properties["options.indexing.ignoreDirectoryNames"] = len(out.Options.Indexing.IgnoreDirectoryNames) > 0
 	properties["options.indexing.ignorePaths"] = len(out.Options.Indexing.IgnorePaths) > 0
+	properties["options.varFiles"] = len(out.Options.VarFiles) > 0
 	properties["options.experimentalFeatures.prefillRequiredFields"] = out.Options.ExperimentalFeatures.PrefillRequiredFields
 	properties["options.experimentalFeatures.validateOnSave"] = out.Options.ExperimentalFeatures.ValidateOnSave
 	properties["options.ignoreSingleFileWarning"] = out.Options.IgnoreSingleFileWarning
@@ -300,20 +324,6 @@ func (svc *service) setupWalker(ctx context.Context, params lsp.InitializeParams
 		})
 	}
 
-	var ignoredPaths []string
-	for _, rawPath := range options.Indexing.IgnorePaths {
-		modPath, err := resolvePath(root.Path(), rawPath)
-		if err != nil {
-			jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
-				Type: lsp.Warning,
-				Message: fmt.Sprintf("Unable to ignore path (unsupported or invalid URI): %s: %s",
-					rawPath, err),
-			})
-			continue
-		}
-		ignoredPaths = append(ignoredPaths, modPath)
-	}
-
 	err = svc.stateStore.WalkerPaths.EnqueueDir(ctx, root)
 	if err != nil {
 		return err
@@ -344,12 +354,83 @@ func (svc *service) setupWalker(ctx context.Context, params lsp.InitializeParams
 		}
 	}
 
+	svc.applyWorkspaceOptions(ctx, root.Path(), options)
+
+	return nil
+}
+
+// applyWorkspaceOptions applies options which may also come
+// from config files, and which can therefore change at runtime
+func (svc *service) applyWorkspaceOptions(ctx context.Context, rootDir string, options *settings.Options) {
+	var ignoredPaths []string
+	for _, rawPath := range options.Indexing.IgnorePaths {
+		modPath, err := resolvePath(rootDir, rawPath)
+		if err != nil {
+			jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+				Type: lsp.Warning,
+				Message: fmt.Sprintf("Unable to ignore path (unsupported or invalid URI): %s: %s",
+					rawPath, err),
+			})
+			continue
+		}
+		ignoredPaths = append(ignoredPaths, modPath)
+	}
+
+	varFileModules := make(fvdecoder.VarFileModules, 0, len(options.VarFiles))
+	for _, varFile := range options.VarFiles {
+		pattern, err := resolvePath(rootDir, varFile.Path)
+		if err != nil {
+			jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+				Type: lsp.Warning,
+				Message: fmt.Sprintf("Unable to map variable file (unsupported or invalid path): %s: %s",
+					varFile.Path, err),
+			})
+			continue
+		}
+		modPath, err := resolvePath(rootDir, varFile.Module)
+		if err != nil {
+			jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+				Type: lsp.Warning,
+				Message: fmt.Sprintf("Unable to map variable file to module (unsupported or invalid path): %s: %s",
+					varFile.Module, err),
+			})
+			continue
+		}
+		varFileModules = append(varFileModules, fvdecoder.VarFileModule{
+			Pattern:    pattern,
+			ModulePath: modPath,
+		})
+	}
+	svc.features.Variables.SetVarFileModules(varFileModules)
+	svc.features.Modules.SetPolicyFiles(options.Validation.PolicyFiles)
+	svc.schemaFS.SetDirs(options.SchemaDirs)
+
 	svc.closedDirWalker.SetIgnoredDirectoryNames(options.Indexing.IgnoreDirectoryNames)
 	svc.closedDirWalker.SetIgnoredPaths(ignoredPaths)
 	svc.openDirWalker.SetIgnoredDirectoryNames(options.Indexing.IgnoreDirectoryNames)
 	svc.openDirWalker.SetIgnoredPaths(ignoredPaths)
+}
 
-	return nil
+// ruleSeverities returns severities configured per rule,
+// which were already validated along with other options
+func ruleSeverities(options settings.ValidationOptions) map[rules.ID]rules.Severity {
+	severities := make(map[rules.ID]rules.Severity, len(options.Rules))
+	for id, rawSeverity := range options.Rules {
+		severity, err := rules.ParseSeverity(rawSeverity)
+		if err != nil {
+			continue
+		}
+		severities[rules.ID(id)] = severity
+	}
+	return severities
+}
+
+func rulePlugins(options settings.ValidationOptions) []*lint.Plugin {
+	plugins := make([]*lint.Plugin, 0, len(options.RulePlugins))
+	for _, plugin := range options.RulePlugins {
+		plugins = append(plugins, lint.NewPlugin(plugin.Command, plugin.Args...))
+	}
+	return plugins
 }
 
 func resolvePath(rootDir, rawPath string) (string, error) {
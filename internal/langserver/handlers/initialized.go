@@ -5,12 +5,15 @@ package handlers
 
 import (
 	"context"
+	"path/filepath"
 
 	"github.com/creachadair/jrpc2"
 	"github.com/hashicorp/go-uuid"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/settings"
 	"github.com/hashicorp/terraform-ls/internal/terraform/datadir"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfversion"
 )
 
 func (svc *service) Initialized(ctx context.Context, params lsp.InitializedParams) error {
@@ -35,6 +38,14 @@ func (svc *service) setupWatchedFiles(ctx context.Context, caps lsp.DidChangeWat
 	}
 
 	watchPatterns := datadir.PathGlobPatternsForWatching()
+	watchPatterns = append(watchPatterns, tfversion.PathGlobPatternsForWatching()...)
+	watchPatterns = append(watchPatterns, settings.ConfigFilePatternsForWatching()...)
+	for _, path := range svc.features.Modules.PolicyFilePaths() {
+		watchPatterns = append(watchPatterns, datadir.WatchPattern{
+			Pattern:   filepath.ToSlash(path),
+			EventType: datadir.AnyEventType,
+		})
+	}
 	watchers := make([]lsp.FileSystemWatcher, len(watchPatterns))
 	for i, wp := range watchPatterns {
 		watchers[i] = lsp.FileSystemWatcher{
@@ -13,8 +13,10 @@ import (
 
 	"github.com/creachadair/jrpc2"
 	rpch "github.com/creachadair/jrpc2/handler"
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/terraform-ls/internal/codelens"
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
 	idecoder "github.com/hashicorp/terraform-ls/internal/decoder"
 	"github.com/hashicorp/terraform-ls/internal/document"
@@ -32,12 +34,17 @@ import (
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
 	"github.com/hashicorp/terraform-ls/internal/registry"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 	"github.com/hashicorp/terraform-ls/internal/scheduler"
+	"github.com/hashicorp/terraform-ls/internal/schemas"
 	"github.com/hashicorp/terraform-ls/internal/settings"
 	"github.com/hashicorp/terraform-ls/internal/state"
 	"github.com/hashicorp/terraform-ls/internal/telemetry"
 	"github.com/hashicorp/terraform-ls/internal/terraform/discovery"
 	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/hashicorp/terraform-ls/internal/terraform/opentofu"
+	"github.com/hashicorp/terraform-ls/internal/terraform/tfversion"
+	"github.com/hashicorp/terraform-ls/internal/tflint"
 	"github.com/hashicorp/terraform-ls/internal/walker"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -71,6 +78,7 @@ type service struct {
 
 	fs             *filesystem.Filesystem
 	tfDiscoFunc    discovery.DiscoveryFunc
+	tofuDiscoFunc  discovery.DiscoveryFunc
 	tfExecFactory  exec.ExecutorFactory
 	tfExecOpts     *exec.ExecutorOpts
 	telemetry      telemetry.Sender
@@ -88,6 +96,23 @@ type service struct {
 	additionalHandlers map[string]rpch.Func
 
 	singleFileMode bool
+
+	// clientOptions are options as passed by the client
+	clientOptions *settings.DecodedOptions
+	// configDirs are directories searched for config files,
+	// i.e. the root directory followed by workspace folders
+	configDirs []string
+
+	ruleFilter *rules.Filter
+	// schemaFS provides provider schemas from configured
+	// schema directories, followed by the embedded schemas
+	schemaFS *schemas.LocalFS
+	// versionResolver caches Terraform versions pinned via version files
+	// for all features, and is invalidated when these files change
+	versionResolver *tfversion.Resolver
+	// openTofuDetector caches which directories contain OpenTofu-specific
+	// files, and is invalidated when such files are created or removed
+	openTofuDetector *opentofu.Detector
 }
 
 var discardLogs = log.New(io.Discard, "", 0)
@@ -102,6 +127,7 @@ func NewSession(srvCtx context.Context) session.Session {
 		sessCtx:        sessCtx,
 		stopSession:    stopSession,
 		tfDiscoFunc:    d.LookPath,
+		tofuDiscoFunc:  d.LookOpenTofuPath,
 		tfExecFactory:  exec.NewExecutor,
 		telemetry:      &telemetry.NoopSender{},
 		registryClient: registry.NewClient(),
@@ -286,6 +312,14 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 
 			return handle(ctx, req, svc.TextDocumentCodeLens)
 		},
+		"textDocument/diagnostic": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.TextDocumentDiagnostic)
+		},
 		"textDocument/formatting": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -380,6 +414,14 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 
 			return handle(ctx, req, svc.WorkspaceSymbol)
 		},
+		"workspace/diagnostic": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			return handle(ctx, req, svc.WorkspaceDiagnostic)
+		},
 		"shutdown": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.Shutdown(req)
 			if err != nil {
@@ -418,6 +460,32 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 	return convertMap(m), nil
 }
 
+// tflintExecutor returns an executor running tflint
+// as configured, or as found in PATH
+func (svc *service) tflintExecutor(opts settings.TFLint) (*tflint.Executor, error) {
+	path := opts.Path
+	if path == "" {
+		var err error
+		path, err = tflint.LookPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	executor := tflint.NewExecutor(path)
+	executor.SetLogger(svc.logger)
+
+	if len(opts.Timeout) > 0 {
+		d, err := time.ParseDuration(opts.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse tflint.timeout LSP config option: %s", err)
+		}
+		executor.SetTimeout(d)
+	}
+
+	return executor, nil
+}
+
 func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *settings.Options) error {
 	// Raise warnings for deprecated options
 	if cfgOpts.XLegacyTerraformExecPath != "" {
@@ -444,16 +512,35 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 
 	// The following is set via CLI flags, hence available in the server context
 	execOpts := &exec.ExecutorOpts{}
+	isOpenTofu := cfgOpts.Terraform.Distribution == settings.DistributionOpenTofu
 	if len(cfgOpts.Terraform.Path) > 0 {
 		execOpts.ExecPath = cfgOpts.Terraform.Path
+	} else if isOpenTofu && svc.tofuDiscoFunc != nil {
+		path, err := svc.tofuDiscoFunc()
+		if err == nil {
+			execOpts.ExecPath = path
+		}
 	} else {
 		path, err := svc.tfDiscoFunc()
 		if err == nil {
 			execOpts.ExecPath = path
 		}
 	}
+	if isOpenTofu {
+		execOpts.OpenTofuExecPath = execOpts.ExecPath
+	} else if svc.tofuDiscoFunc != nil {
+		// Modules with *.tofu files are served by OpenTofu if installed
+		path, err := svc.tofuDiscoFunc()
+		if err == nil {
+			execOpts.OpenTofuExecPath = path
+		}
+	}
 	svc.srvCtx = lsctx.WithTerraformExecPath(svc.srvCtx, execOpts.ExecPath)
 
+	if len(cfgOpts.Terraform.ToolchainDir) > 0 {
+		execOpts.ToolchainDir = cfgOpts.Terraform.ToolchainDir
+	}
+
 	if len(cfgOpts.Terraform.LogFilePath) > 0 {
 		execOpts.ExecLogPath = cfgOpts.Terraform.LogFilePath
 	}
@@ -498,6 +585,10 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 	}
 	svc.fs.SetLogger(svc.logger)
 
+	if svc.ruleFilter == nil {
+		svc.ruleFilter = rules.NewFilter(svc.fs)
+	}
+
 	if svc.eventBus == nil {
 		svc.eventBus = eventbus.NewEventBus()
 	}
@@ -513,6 +604,16 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 	svc.closedDirWalker.Collector = svc.walkerCollector
 	svc.openDirWalker.SetLogger(svc.logger)
 
+	if svc.schemaFS == nil {
+		svc.schemaFS = schemas.NewLocalFS()
+	}
+	if svc.versionResolver == nil {
+		svc.versionResolver = tfversion.NewResolver(svc.fs)
+	}
+	if svc.openTofuDetector == nil {
+		svc.openTofuDetector = opentofu.NewDetector(svc.fs)
+	}
+
 	if svc.features == nil {
 		rootModulesFeature, err := frootmodules.NewRootModulesFeature(svc.eventBus, svc.stateStore, svc.fs,
 			svc.tfExecFactory)
@@ -520,6 +621,10 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 			return err
 		}
 		rootModulesFeature.SetLogger(svc.logger)
+		rootModulesFeature.SetDistribution(cfgOpts.Terraform.Distribution)
+		rootModulesFeature.SetOpenTofuDetector(svc.openTofuDetector)
+		rootModulesFeature.SetVersionResolver(svc.versionResolver)
+		rootModulesFeature.SetStateSnapshotFile(cfgOpts.Terraform.StateSnapshotFile)
 		rootModulesFeature.Start(svc.sessCtx)
 
 		modulesFeature, err := fmodules.NewModulesFeature(svc.eventBus, svc.stateStore, svc.fs,
@@ -528,6 +633,7 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 			return err
 		}
 		modulesFeature.SetLogger(svc.logger)
+		modulesFeature.SetSchemaFS(svc.schemaFS)
 		modulesFeature.Start(svc.sessCtx)
 
 		variablesFeature, err := fvariables.NewVariablesFeature(svc.eventBus, svc.stateStore, svc.fs,
@@ -543,6 +649,8 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 			return err
 		}
 		stacksFeature.SetLogger(svc.logger)
+		stacksFeature.SetSchemaFS(svc.schemaFS)
+		stacksFeature.SetVersionResolver(svc.versionResolver)
 		stacksFeature.Start(svc.sessCtx)
 
 		testsFeature, err := ftests.NewTestsFeature(svc.eventBus, svc.stateStore, svc.fs, modulesFeature, rootModulesFeature)
@@ -561,6 +669,13 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 		}
 	}
 
+	if len(execOpts.ToolchainDir) > 0 {
+		execOpts.ModuleVersion = svc.moduleTerraformVersion
+	}
+	if len(execOpts.OpenTofuExecPath) > 0 {
+		execOpts.IsOpenTofuModule = svc.features.RootModules.IsOpenTofu
+	}
+
 	svc.decoder = decoder.NewDecoder(&idecoder.GlobalPathReader{
 		PathReaderMap: idecoder.PathReaderMap{
 			"terraform":        svc.features.Modules,
@@ -572,17 +687,43 @@ func (svc *service) configureSessionDependencies(ctx context.Context, cfgOpts *s
 		},
 	})
 	decoderContext := idecoder.DecoderContext(ctx)
+	svc.features.Modules.SetModuleCatalogFile(cfgOpts.ModuleSearch.CatalogFile)
+	svc.features.Modules.SetOfflineModuleSearch(cfgOpts.ModuleSearch.Offline)
+	svc.features.Modules.SetRulePlugins(rulePlugins(cfgOpts.Validation))
+	if cfgOpts.TFLint.Enable {
+		linter, err := svc.tflintExecutor(cfgOpts.TFLint)
+		if err != nil {
+			jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+				Type:    lsp.Warning,
+				Message: fmt.Sprintf("Unable to lint modules with tflint: %s", err),
+			})
+		} else {
+			svc.features.Modules.SetTFLint(linter)
+		}
+	}
 	svc.features.Modules.AppendCompletionHooks(svc.srvCtx, decoderContext)
+	decoderContext.CodeLenses = append(decoderContext.CodeLenses, codelens.PlannedActions(svc.plannedActions))
 	svc.decoder.SetContext(decoderContext)
 
 	moduleHooks := []notifier.Hook{
-		updateDiagnostics(svc.features, svc.diagsNotifier),
 		sendModuleTelemetry(svc.features, svc.telemetry),
 	}
 
 	cc, err := ilsp.ClientCapabilities(ctx)
+	if err == nil && cc.TextDocument.Diagnostic != nil {
+		// Clients supporting pull diagnostics request them when needed,
+		// so we just let them know when diagnostics may have changed
+		if cc.Workspace.Diagnostics != nil && cc.Workspace.Diagnostics.RefreshSupport {
+			moduleHooks = append(moduleHooks, refreshDiagnostics(svc.server))
+		}
+	} else {
+		moduleHooks = append(moduleHooks, updateDiagnostics(svc.features, svc.diagsNotifier, svc.ruleFilter))
+	}
+
 	if err == nil {
-		if _, ok := lsp.ExperimentalClientCapabilities(cc.Experimental).ShowReferencesCommandId(); ok {
+		_, showReferences := lsp.ExperimentalClientCapabilities(cc.Experimental).ShowReferencesCommandId()
+		codeLensRefresh := cc.Workspace.CodeLens != nil && cc.Workspace.CodeLens.RefreshSupport
+		if showReferences || codeLensRefresh {
 			moduleHooks = append(moduleHooks, refreshCodeLens(svc.server))
 		}
 
@@ -752,3 +893,26 @@ func (svc *service) decoderForDocument(_ context.Context, doc *document.Document
 		LanguageID: doc.LanguageID,
 	})
 }
+
+// moduleTerraformVersion returns the version requirements of a module,
+// used to pick the right binary from the Terraform toolchain directory
+func (svc *service) moduleTerraformVersion(modPath string) (*version.Version, version.Constraints) {
+	pinned := svc.features.RootModules.PinnedTerraformVersion(modPath)
+
+	constraints, err := svc.features.Modules.CoreRequirements(modPath)
+	if err != nil {
+		return pinned, nil
+	}
+
+	return pinned, constraints
+}
+
+// plannedActions returns the resource actions
+// from the last `terraform plan` run for the module
+func (svc *service) plannedActions(modPath string) (map[string][]string, error) {
+	actions, err := svc.features.Modules.PlannedActions(modPath)
+	if err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
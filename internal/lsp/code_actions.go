@@ -5,6 +5,7 @@ package lsp
 
 import (
 	"sort"
+	"strings"
 
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
 )
@@ -12,6 +13,38 @@ import (
 const (
 	// SourceFormatAllTerraform is a Terraform specific format code action.
 	SourceFormatAllTerraform = "source.formatAll.terraform"
+
+	// RefactorRewriteMovedTerraform is a Terraform specific code action
+	// which records renames of resources and module calls in moved blocks.
+	RefactorRewriteMovedTerraform = "refactor.rewrite.moved.terraform"
+
+	// RefactorRewriteForEachTerraform is a Terraform specific code action
+	// which converts the count meta-argument of a block to for_each.
+	RefactorRewriteForEachTerraform = "refactor.rewrite.forEach.terraform"
+
+	// RefactorRewriteCountTerraform is a Terraform specific code action
+	// which converts the for_each meta-argument of a block to count.
+	RefactorRewriteCountTerraform = "refactor.rewrite.count.terraform"
+
+	// RefactorExtractLocalTerraform is a Terraform specific code action
+	// which extracts an expression into a local value.
+	RefactorExtractLocalTerraform = "refactor.extract.local.terraform"
+
+	// RefactorExtractVariableTerraform is a Terraform specific code action
+	// which extracts a literal expression into a variable.
+	RefactorExtractVariableTerraform = "refactor.extract.variable.terraform"
+
+	// RefactorExtractModuleTerraform is a Terraform specific code action
+	// which moves resources into a new local child module.
+	RefactorExtractModuleTerraform = "refactor.extract.module.terraform"
+
+	// RefactorInlineLocalTerraform is a Terraform specific code action
+	// which replaces references to a local value with its expression.
+	RefactorInlineLocalTerraform = "refactor.inline.local.terraform"
+
+	// QuickFixSuppressTerraform is a Terraform specific code action
+	// which inserts a comment suppressing the rule behind a diagnostic.
+	QuickFixSuppressTerraform = "quickfix.suppress.terraform"
 )
 
 type CodeActions map[lsp.CodeActionKind]bool
@@ -33,8 +66,22 @@ var (
 	// We do not register this for terraform to allow fine grained selection of actions.
 	// A user should be able to set `source.formatAll` to true, and source.formatAll.terraform to false to allow all
 	// files to be formatted, but not terraform files (or vice versa).
+	//
+	// `refactor.*` and `quickfix.*`: Refactor and quick fix code actions are shown
+	// in the lightbulb menu and are therefore also offered when no specific kind is requested.
+	// Their kinds are hierarchical, e.g. requesting `refactor.rewrite` includes
+	// `refactor.rewrite.moved.terraform`, while source actions (i.e. formatting)
+	// are only run if requested exactly, so that e.g. `source` doesn't format.
 	SupportedCodeActions = CodeActions{
-		SourceFormatAllTerraform: true,
+		SourceFormatAllTerraform:         true,
+		RefactorRewriteMovedTerraform:    true,
+		RefactorRewriteForEachTerraform:  true,
+		RefactorRewriteCountTerraform:    true,
+		RefactorExtractLocalTerraform:    true,
+		RefactorExtractVariableTerraform: true,
+		RefactorExtractModuleTerraform:   true,
+		RefactorInlineLocalTerraform:     true,
+		QuickFixSuppressTerraform:        true,
 	}
 )
 
@@ -53,9 +100,19 @@ func (c CodeActions) AsSlice() []lsp.CodeActionKind {
 func (ca CodeActions) Only(only []lsp.CodeActionKind) CodeActions {
 	wanted := make(CodeActions, 0)
 
+	// Kinds are hierarchical, e.g. requesting refactor.rewrite
+	// includes refactor.rewrite.moved.terraform, except for
+	// source actions which must be requested explicitly
 	for _, kind := range only {
-		if v, ok := ca[kind]; ok {
-			wanted[kind] = v
+		for k, v := range ca {
+			if k == kind {
+				wanted[k] = v
+				continue
+			}
+			isSource := strings.HasPrefix(string(k), string(lsp.Source)+".")
+			if !isSource && kind != "" && strings.HasPrefix(string(k), string(kind)+".") {
+				wanted[k] = v
+			}
 		}
 	}
 
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lsp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func TestCodeActions_Only(t *testing.T) {
+	testCases := []struct {
+		only     []lsp.CodeActionKind
+		expected []lsp.CodeActionKind
+	}{
+		{
+			[]lsp.CodeActionKind{SourceFormatAllTerraform},
+			[]lsp.CodeActionKind{SourceFormatAllTerraform},
+		},
+		{
+			// formatting must be requested explicitly
+			[]lsp.CodeActionKind{lsp.Source},
+			[]lsp.CodeActionKind{},
+		},
+		{
+			[]lsp.CodeActionKind{"source.formatAll"},
+			[]lsp.CodeActionKind{},
+		},
+		{
+			[]lsp.CodeActionKind{"refactor.rewrite"},
+			[]lsp.CodeActionKind{
+				RefactorRewriteCountTerraform,
+				RefactorRewriteForEachTerraform,
+				RefactorRewriteMovedTerraform,
+			},
+		},
+		{
+			[]lsp.CodeActionKind{lsp.QuickFix, RefactorInlineLocalTerraform},
+			[]lsp.CodeActionKind{
+				QuickFixSuppressTerraform,
+				RefactorInlineLocalTerraform,
+			},
+		},
+		{
+			[]lsp.CodeActionKind{"refactor.rewrite.moved"},
+			[]lsp.CodeActionKind{RefactorRewriteMovedTerraform},
+		},
+		{
+			// prefixes must end at a dot
+			[]lsp.CodeActionKind{"refactor.rewrite.mov"},
+			[]lsp.CodeActionKind{},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%v", i, tc.only), func(t *testing.T) {
+			wanted := SupportedCodeActions.Only(tc.only).AsSlice()
+			if diff := cmp.Diff(tc.expected, wanted); diff != "" {
+				t.Fatalf("unexpected code actions: %s", diff)
+			}
+		})
+	}
+}
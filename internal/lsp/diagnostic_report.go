@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lsp
+
+import (
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+// WorkspaceDiagnosticReport is a result of workspace/diagnostic,
+// where each item is either [WorkspaceFullDocumentDiagnosticReport]
+// or [WorkspaceUnchangedDocumentDiagnosticReport].
+type WorkspaceDiagnosticReport struct {
+	Items []interface{} `json:"items"`
+}
+
+// WorkspaceFullDocumentDiagnosticReport is like
+// [lsp.WorkspaceFullDocumentDiagnosticReport], except that
+// the version is null for documents which are not open.
+type WorkspaceFullDocumentDiagnosticReport struct {
+	URI     lsp.DocumentURI `json:"uri"`
+	Version *int32          `json:"version"`
+	lsp.FullDocumentDiagnosticReport
+}
+
+// WorkspaceUnchangedDocumentDiagnosticReport is like
+// [lsp.WorkspaceUnchangedDocumentDiagnosticReport], except that
+// the version is null for documents which are not open.
+type WorkspaceUnchangedDocumentDiagnosticReport struct {
+	URI     lsp.DocumentURI `json:"uri"`
+	Version *int32          `json:"version"`
+	lsp.UnchangedDocumentDiagnosticReport
+}
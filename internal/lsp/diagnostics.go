@@ -6,6 +6,7 @@ package lsp
 import (
 	"github.com/hashicorp/hcl/v2"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 )
 
 func HCLSeverityToLSP(severity hcl.DiagnosticSeverity) lsp.DiagnosticSeverity {
@@ -21,6 +22,18 @@ func HCLSeverityToLSP(severity hcl.DiagnosticSeverity) lsp.DiagnosticSeverity {
 	return sev
 }
 
+// RuleSeverityToLSP converts severity configured for a rule. Rules
+// which are turned off never produce diagnostics to convert.
+func RuleSeverityToLSP(severity rules.Severity) lsp.DiagnosticSeverity {
+	switch severity {
+	case rules.SeverityError:
+		return lsp.SeverityError
+	case rules.SeverityHint:
+		return lsp.SeverityHint
+	}
+	return lsp.SeverityWarning
+}
+
 func HCLDiagsToLSP(hclDiags hcl.Diagnostics, source string) []lsp.Diagnostic {
 	diags := []lsp.Diagnostic{}
 
@@ -33,12 +46,19 @@ func HCLDiagsToLSP(hclDiags hcl.Diagnostics, source string) []lsp.Diagnostic {
 		if hclDiag.Subject != nil {
 			rnge = HCLRangeToLSP(*hclDiag.Subject)
 		}
-		diags = append(diags, lsp.Diagnostic{
+		diag := lsp.Diagnostic{
 			Range:    rnge,
 			Severity: HCLSeverityToLSP(hclDiag.Severity),
 			Source:   source,
 			Message:  msg,
-		})
+		}
+		if id, ok := rules.RuleID(hclDiag); ok {
+			diag.Code = id.String()
+		}
+		if severity, ok := rules.ConfiguredSeverity(hclDiag); ok {
+			diag.Severity = RuleSeverityToLSP(severity)
+		}
+		diags = append(diags, diag)
 
 	}
 	return diags
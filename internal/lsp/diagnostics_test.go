@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/rules"
 )
 
 func TestHCLDiagsToLSP_NeverReturnsNil(t *testing.T) {
@@ -29,3 +30,24 @@ func TestHCLDiagsToLSP_NeverReturnsNil(t *testing.T) {
 		t.Fatal("diags should not be nil")
 	}
 }
+
+func TestHCLDiagsToLSP_ruleCode(t *testing.T) {
+	diags := HCLDiagsToLSP(hcl.Diagnostics{
+		{
+			Severity: hcl.DiagWarning,
+			Summary:  "No declaration found",
+			Extra:    rules.UnreferencedOrigin,
+		},
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid expression",
+		},
+	}, "source")
+
+	if diags[0].Code != "unreferenced-origin" {
+		t.Fatalf("expected rule ID as code, given: %#v", diags[0].Code)
+	}
+	if diags[1].Code != nil {
+		t.Fatalf("expected no code, given: %#v", diags[1].Code)
+	}
+}
@@ -19,3 +19,19 @@ const (
 func (l LanguageID) String() string {
 	return string(l)
 }
+
+// languageIDAliases maps language IDs which some clients use
+// for OpenTofu files to the equivalent Terraform language IDs
+var languageIDAliases = map[string]LanguageID{
+	"opentofu":      Terraform,
+	"opentofu-vars": Tfvars,
+}
+
+// NormalizeLanguageID returns the language ID which the server
+// understands for the given (client-provided) language ID
+func NormalizeLanguageID(languageID string) string {
+	if id, ok := languageIDAliases[languageID]; ok {
+		return id.String()
+	}
+	return languageID
+}
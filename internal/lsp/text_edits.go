@@ -4,9 +4,12 @@
 package lsp
 
 import (
+	"path/filepath"
+
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/terraform-ls/internal/document"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/uri"
 )
 
 func TextEditsFromDocumentChanges(changes document.Changes) []lsp.TextEdit {
@@ -32,6 +35,19 @@ func TextEdits(tes []lang.TextEdit, snippetSupport bool) []lsp.TextEdit {
 	return edits
 }
 
+// WorkspaceEdit turns edits of files (keyed by filename) within
+// the module at the given path into a single workspace edit
+func WorkspaceEdit(modPath string, edits map[string][]lang.TextEdit) lsp.WorkspaceEdit {
+	changes := make(map[lsp.DocumentURI][]lsp.TextEdit, len(edits))
+	for filename, fileEdits := range edits {
+		fileUri := uri.FromPath(filepath.Join(modPath, filename))
+		changes[lsp.DocumentURI(fileUri)] = TextEdits(fileEdits, false)
+	}
+	return lsp.WorkspaceEdit{
+		Changes: changes,
+	}
+}
+
 func textEdit(te lang.TextEdit, snippetSupport bool) *lsp.TextEdit {
 	if snippetSupport {
 		return &lsp.TextEdit{
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lsp
+
+import (
+	"slices"
+
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+// ApplyWorkspaceEditParams is like [lsp.ApplyWorkspaceEditParams],
+// except that it accepts edits which [lsp.WorkspaceEdit] cannot express,
+// such as [CreateFileWorkspaceEdit].
+type ApplyWorkspaceEditParams struct {
+	Label string      `json:"label,omitempty"`
+	Edit  interface{} `json:"edit"`
+}
+
+// CreateFileWorkspaceEdit creates a file and inserts text into it.
+// The version of the created document is null, since it is not open yet.
+type CreateFileWorkspaceEdit struct {
+	DocumentChanges []interface{} `json:"documentChanges"`
+}
+
+type newTextDocumentEdit struct {
+	TextDocument newTextDocumentIdentifier `json:"textDocument"`
+	Edits        []lsp.TextEdit            `json:"edits"`
+}
+
+type newTextDocumentIdentifier struct {
+	URI     lsp.DocumentURI `json:"uri"`
+	Version *int32          `json:"version"`
+}
+
+// NewCreateFileWorkspaceEdit returns an edit creating
+// the file of the given URI with the given text
+func NewCreateFileWorkspaceEdit(uri lsp.DocumentURI, text string) CreateFileWorkspaceEdit {
+	return CreateFileWorkspaceEdit{
+		DocumentChanges: []interface{}{
+			lsp.CreateFile{
+				Kind: string(lsp.Create),
+				URI:  uri,
+			},
+			newTextDocumentEdit{
+				TextDocument: newTextDocumentIdentifier{URI: uri},
+				Edits: []lsp.TextEdit{
+					{NewText: text},
+				},
+			},
+		},
+	}
+}
+
+// SupportsCreateFile reports whether the client can apply
+// workspace edits which create files
+func SupportsCreateFile(caps lsp.ClientCapabilities) bool {
+	wec := caps.Workspace.WorkspaceEdit
+	if wec == nil || !wec.DocumentChanges {
+		return false
+	}
+	return slices.Contains(wec.ResourceOperations, lsp.Create)
+}
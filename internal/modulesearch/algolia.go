@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package modulesearch
+
+import (
+	"context"
+
+	"github.com/algolia/algoliasearch-client-go/v3/algolia/opt"
+	"github.com/algolia/algoliasearch-client-go/v3/algolia/search"
+)
+
+const algoliaModuleIndex = "tf-registry:prod:modules"
+
+// Algolia searches the hosted index of modules
+// published in the public Terraform Registry
+type Algolia struct {
+	client *search.Client
+}
+
+func NewAlgolia(client *search.Client) *Algolia {
+	return &Algolia{
+		client: client,
+	}
+}
+
+func (a *Algolia) Search(ctx context.Context, term string) ([]Module, error) {
+	modules := make([]Module, 0)
+
+	index := a.client.InitIndex(algoliaModuleIndex)
+	params := []interface{}{
+		ctx, // transport.Request will magically extract the context from here
+		opt.AttributesToRetrieve("full-name", "description"),
+		opt.HitsPerPage(10),
+	}
+
+	res, err := index.Search(term, params...)
+	if err != nil {
+		return modules, err
+	}
+
+	err = res.UnmarshalHits(&modules)
+	if err != nil {
+		return modules, err
+
+	}
+
+	return modules, nil
+}
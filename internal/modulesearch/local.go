@@ -0,0 +1,180 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package modulesearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+)
+
+const maxLocalResults = 10
+
+// ModuleSources lists source addresses of registry modules
+// known locally, e.g. cached from the registry or installed
+type ModuleSources interface {
+	RegistryModuleSources() ([]tfaddr.Module, error)
+}
+
+// Local searches modules known locally, i.e. modules listed by any
+// of the given sources and modules listed in the catalog file (if any),
+// so that it works without the hosted search service.
+//
+// The catalog file is a JSON file in the following format:
+//
+//	{
+//	  "modules": [
+//	    {
+//	      "source": "app.terraform.io/example-corp/vpc/aws",
+//	      "description": "Private VPC module"
+//	    }
+//	  ]
+//	}
+type Local struct {
+	catalogFile string
+	sources     []ModuleSources
+
+	catalogMu      sync.Mutex
+	catalog        []Module
+	catalogModTime time.Time
+}
+
+type catalogFile struct {
+	Modules []catalogEntry `json:"modules"`
+}
+
+type catalogEntry struct {
+	Source      string `json:"source"`
+	Description string `json:"description"`
+}
+
+func NewLocal(catalogFile string, sources ...ModuleSources) *Local {
+	return &Local{
+		catalogFile: catalogFile,
+		sources:     sources,
+	}
+}
+
+func (l *Local) Search(ctx context.Context, term string) ([]Module, error) {
+	modules := make(map[string]Module)
+
+	var firstErr error
+	catalog, err := l.loadCatalog()
+	if err != nil {
+		firstErr = err
+	}
+	for _, mod := range catalog {
+		modules[mod.FullName] = mod
+	}
+
+	for _, source := range l.sources {
+		addrs, err := source.RegistryModuleSources()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, addr := range addrs {
+			name := addr.ForDisplay()
+			if _, ok := modules[name]; !ok {
+				modules[name] = Module{FullName: name}
+			}
+		}
+	}
+
+	return matchModules(modules, term), firstErr
+}
+
+// matchModules returns modules whose name or description contains
+// the given term (case-insensitive), with modules whose name
+// starts with the term first
+func matchModules(modules map[string]Module, term string) []Module {
+	term = strings.ToLower(term)
+
+	type match struct {
+		Module
+		isPrefix bool
+	}
+	matches := make([]match, 0)
+	for _, mod := range modules {
+		name := strings.ToLower(mod.FullName)
+		if strings.HasPrefix(name, term) {
+			matches = append(matches, match{mod, true})
+			continue
+		}
+		if strings.Contains(name, term) || strings.Contains(strings.ToLower(mod.Description), term) {
+			matches = append(matches, match{mod, false})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].isPrefix != matches[j].isPrefix {
+			return matches[i].isPrefix
+		}
+		return matches[i].FullName < matches[j].FullName
+	})
+
+	result := make([]Module, 0, len(matches))
+	for i, m := range matches {
+		if i == maxLocalResults {
+			break
+		}
+		result = append(result, m.Module)
+	}
+	return result
+}
+
+// loadCatalog reads the catalog file, unless
+// it has not changed since it was last read
+func (l *Local) loadCatalog() ([]Module, error) {
+	if l.catalogFile == "" {
+		return nil, nil
+	}
+
+	l.catalogMu.Lock()
+	defer l.catalogMu.Unlock()
+
+	fi, err := os.Stat(l.catalogFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read module catalog: %w", err)
+	}
+	if fi.ModTime().Equal(l.catalogModTime) {
+		return l.catalog, nil
+	}
+
+	b, err := os.ReadFile(l.catalogFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read module catalog: %w", err)
+	}
+	var cf catalogFile
+	err = json.Unmarshal(b, &cf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse module catalog %q: %w", l.catalogFile, err)
+	}
+
+	catalog := make([]Module, 0, len(cf.Modules))
+	for _, entry := range cf.Modules {
+		addr, err := tfaddr.ParseModuleSource(entry.Source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module source %q in catalog %q: %w",
+				entry.Source, l.catalogFile, err)
+		}
+		catalog = append(catalog, Module{
+			FullName:    addr.ForDisplay(),
+			Description: entry.Description,
+		})
+	}
+
+	l.catalog = catalog
+	l.catalogModTime = fi.ModTime()
+
+	return catalog, nil
+}
@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package modulesearch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+)
+
+type staticSources []string
+
+func (s staticSources) RegistryModuleSources() ([]tfaddr.Module, error) {
+	addrs := make([]tfaddr.Module, 0, len(s))
+	for _, source := range s {
+		addr, err := tfaddr.ParseModuleSource(source)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+func TestLocal_Search(t *testing.T) {
+	catalogFile := filepath.Join(t.TempDir(), "catalog.json")
+	err := os.WriteFile(catalogFile, []byte(`{
+  "modules": [
+    {
+      "source": "app.terraform.io/example-corp/vpc/aws",
+      "description": "Corporate network layout"
+    }
+  ]
+}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLocal(catalogFile,
+		staticSources{"terraform-aws-modules/vpc/aws", "terraform-aws-modules/eks/aws"},
+		staticSources{"hashicorp/consul/aws", "terraform-aws-modules/vpc/aws"},
+	)
+
+	testCases := []struct {
+		term            string
+		expectedModules []Module
+	}{
+		{
+			"terraform-aws",
+			[]Module{
+				{FullName: "terraform-aws-modules/eks/aws"},
+				{FullName: "terraform-aws-modules/vpc/aws"},
+			},
+		},
+		{
+			"VPC",
+			[]Module{
+				{FullName: "app.terraform.io/example-corp/vpc/aws", Description: "Corporate network layout"},
+				{FullName: "terraform-aws-modules/vpc/aws"},
+			},
+		},
+		{
+			"network",
+			[]Module{
+				{FullName: "app.terraform.io/example-corp/vpc/aws", Description: "Corporate network layout"},
+			},
+		},
+		{
+			"azure",
+			[]Module{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.term, func(t *testing.T) {
+			modules, err := l.Search(context.Background(), tc.term)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedModules, modules); diff != "" {
+				t.Fatalf("unexpected modules: %s", diff)
+			}
+		})
+	}
+}
+
+func TestLocal_Search_invalidCatalog(t *testing.T) {
+	catalogFile := filepath.Join(t.TempDir(), "catalog.json")
+	err := os.WriteFile(catalogFile, []byte(`{"modules": [{"source": "./local"}]}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLocal(catalogFile, staticSources{"hashicorp/consul/aws"})
+	modules, err := l.Search(context.Background(), "consul")
+	if err == nil {
+		t.Fatal("expected error for invalid catalog")
+	}
+	expectedModules := []Module{{FullName: "hashicorp/consul/aws"}}
+	if diff := cmp.Diff(expectedModules, modules); diff != "" {
+		t.Fatalf("unexpected modules: %s", diff)
+	}
+}
+
+type failingBackend struct{}
+
+func (failingBackend) Search(ctx context.Context, term string) ([]Module, error) {
+	return nil, errors.New("offline")
+}
+
+func TestCombined_Search(t *testing.T) {
+	b := Combined(
+		NewLocal("", staticSources{"hashicorp/consul/aws"}),
+		failingBackend{},
+		NewLocal("", staticSources{"hashicorp/consul/aws", "hashicorp/vault/aws"}),
+	)
+
+	modules, err := b.Search(context.Background(), "hashicorp")
+	if err == nil || err.Error() != "offline" {
+		t.Fatalf("expected error of failing backend, given: %v", err)
+	}
+	expectedModules := []Module{
+		{FullName: "hashicorp/consul/aws"},
+		{FullName: "hashicorp/vault/aws"},
+	}
+	if diff := cmp.Diff(expectedModules, modules); diff != "" {
+		t.Fatalf("unexpected modules: %s", diff)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package modulesearch provides backends for searching registry
+// modules by a (partial) source address or description,
+// as used for completion of module sources.
+package modulesearch
+
+import (
+	"context"
+)
+
+// Module represents a registry module found by a search
+type Module struct {
+	FullName    string `json:"full-name"`
+	Description string `json:"description"`
+}
+
+// Backend searches for registry modules matching the given term
+type Backend interface {
+	Search(ctx context.Context, term string) ([]Module, error)
+}
+
+type combined []Backend
+
+// Combined returns a backend which searches all the given backends
+// in the given order and returns the results without duplicates.
+//
+// If any backend returns an error, results of the other backends
+// are returned along with the (first) error.
+func Combined(backends ...Backend) Backend {
+	return combined(backends)
+}
+
+func (c combined) Search(ctx context.Context, term string) ([]Module, error) {
+	modules := make([]Module, 0)
+	seen := make(map[string]bool)
+
+	var firstErr error
+	for _, backend := range c {
+		found, err := backend.Search(ctx, term)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		for _, mod := range found {
+			if seen[mod.FullName] {
+				continue
+			}
+			seen[mod.FullName] = true
+			modules = append(modules, mod)
+		}
+	}
+
+	return modules, firstErr
+}
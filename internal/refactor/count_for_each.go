@@ -0,0 +1,436 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactor
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// repeatedBlock represents a resource, data source or module call
+// which may be repeated via count or for_each
+type repeatedBlock struct {
+	block *hclsyntax.Block
+	addr  lang.Address
+}
+
+func (rb repeatedBlock) String() string {
+	return rb.addr.String()
+}
+
+// CountToForEach converts the count meta-argument of the block
+// at the given position to for_each, keyed by the former index
+// (as string), such that:
+//   - count.index within the block refers to each.value
+//   - references to instances elsewhere in the module (found via
+//     the given reference origins) use the string keys
+//   - splat references to all instances become a list of the instances
+//     in the order of the former index, e.g.
+//     [for i in range(2) : aws_instance.web[tostring(i)]][*].id
+//   - moved blocks are added for all instances, if the count is known
+//
+// It returns the edits for the files of the module.
+func CountToForEach(files map[string]*hcl.File, origins reference.Origins, filename string, pos hcl.Pos) (Edits, bool) {
+	rb, ok := repeatedBlockAt(files, filename, pos)
+	if !ok {
+		return nil, false
+	}
+	countAttr, ok := rb.block.Body.Attributes["count"]
+	if !ok {
+		return nil, false
+	}
+	if _, ok := rb.block.Body.Attributes["for_each"]; ok {
+		return nil, false
+	}
+
+	f := files[filename]
+	countText := source(f, countAttr.Expr.Range())
+	edits := make(Edits, 0)
+	edits.add(filename, countAttr.SrcRange,
+		fmt.Sprintf("for_each = { for i in range(%s) : i => i }", countText))
+
+	for _, rng := range metaReferences(rb.block.Body, "count", "index") {
+		edits.add(filename, rng, "each.value")
+	}
+
+	for _, ref := range instanceReferences(files, origins, rb.addr) {
+		switch {
+		case ref.indexRng != nil:
+			idx, ok := intKey(ref.key)
+			if !ok {
+				continue
+			}
+			edits.add(ref.indexRng.Filename, *ref.indexRng, fmt.Sprintf("[%q]", strconv.FormatInt(idx, 10)))
+		case ref.isSplat:
+			// Splat expressions only apply to lists, not maps, and values()
+			// would order the instances lexically by key (i.e. "10" before "2")
+			edits.add(ref.rng.Filename, ref.rng,
+				fmt.Sprintf("[for i in range(%s) : %s[tostring(i)]]", countText, rb))
+		}
+	}
+
+	val, diags := countAttr.Expr.Value(nil)
+	if !diags.HasErrors() && val.IsKnown() && !val.IsNull() && val.Type() == cty.Number {
+		count, accuracy := val.AsBigFloat().Int64()
+		if accuracy == big.Exact && count > 0 {
+			moves := make([][2]string, 0, count)
+			for i := int64(0); i < count; i++ {
+				moves = append(moves, [2]string{
+					fmt.Sprintf("%s[%d]", rb, i),
+					fmt.Sprintf("%s[%q]", rb, strconv.FormatInt(i, 10)),
+				})
+			}
+			addMovedBlocks(edits, filename, rb, moves)
+		}
+	}
+
+	return edits, true
+}
+
+// ForEachToCount converts the for_each meta-argument of the block
+// at the given position to count, such that:
+//   - each.key and each.value within the block refer to the element
+//     of the collection at count.index
+//   - references to instances elsewhere in the module (found via
+//     the given reference origins) use the index, if the keys are known
+//   - moved blocks are added for all instances, if the keys are known
+//
+// Sets created via toset(list) are indexed in the order of the list,
+// while maps are indexed in the (lexical) order of their keys.
+// Other sets cannot be indexed, so only expressions known
+// to be maps (or objects) are converted otherwise.
+//
+// It returns the edits for the files of the module.
+func ForEachToCount(files map[string]*hcl.File, origins reference.Origins, filename string, pos hcl.Pos) (Edits, bool) {
+	rb, ok := repeatedBlockAt(files, filename, pos)
+	if !ok {
+		return nil, false
+	}
+	forEachAttr, ok := rb.block.Body.Attributes["for_each"]
+	if !ok {
+		return nil, false
+	}
+	if _, ok := rb.block.Body.Attributes["count"]; ok {
+		return nil, false
+	}
+
+	f := files[filename]
+	edits := make(Edits, 0)
+
+	var keys []string
+	var countText, keyText, valueText string
+	if call, ok := forEachAttr.Expr.(*hclsyntax.FunctionCallExpr); ok && call.Name == "toset" && len(call.Args) == 1 {
+		listText := source(f, call.Args[0].Range())
+		countText = fmt.Sprintf("length(%s)", listText)
+		keyText = fmt.Sprintf("%s[count.index]", listText)
+		valueText = keyText
+		keys = listKeys(call.Args[0])
+	} else {
+		if !isMapValued(files, forEachAttr.Expr) {
+			return nil, false
+		}
+		exprText := source(f, forEachAttr.Expr.Range())
+		countText = fmt.Sprintf("length(%s)", exprText)
+		keyText = fmt.Sprintf("keys(%s)[count.index]", exprText)
+		valueText = fmt.Sprintf("values(%s)[count.index]", exprText)
+		keys = mapKeys(forEachAttr.Expr)
+	}
+
+	edits.add(filename, forEachAttr.SrcRange, "count = "+countText)
+	for _, rng := range metaReferences(rb.block.Body, "each", "key") {
+		edits.add(filename, rng, keyText)
+	}
+	for _, rng := range metaReferences(rb.block.Body, "each", "value") {
+		edits.add(filename, rng, valueText)
+	}
+
+	indexes := make(map[string]int, len(keys))
+	for i, key := range keys {
+		if _, ok := indexes[key]; !ok {
+			indexes[key] = i
+		}
+	}
+	for _, ref := range instanceReferences(files, origins, rb.addr) {
+		if ref.indexRng == nil || ref.key.Type() != cty.String {
+			continue
+		}
+		idx, ok := indexes[ref.key.AsString()]
+		if !ok {
+			continue
+		}
+		edits.add(ref.indexRng.Filename, *ref.indexRng, fmt.Sprintf("[%d]", idx))
+	}
+
+	if len(keys) > 0 {
+		moves := make([][2]string, 0, len(keys))
+		for i, key := range keys {
+			if indexes[key] != i {
+				// duplicate elements of the set
+				continue
+			}
+			moves = append(moves, [2]string{
+				fmt.Sprintf("%s[%q]", rb, key),
+				fmt.Sprintf("%s[%d]", rb, i),
+			})
+		}
+		addMovedBlocks(edits, filename, rb, moves)
+	}
+
+	return edits, true
+}
+
+// isMapValued returns whether the given expression is known to evaluate
+// to a map or object (rather than a set), i.e. whether it is a map
+// constructor, a map-typed variable, or a local value of such expression
+func isMapValued(files map[string]*hcl.File, expr hclsyntax.Expression) bool {
+	return isMapValuedExpr(files, expr, make(map[string]bool))
+}
+
+func isMapValuedExpr(files map[string]*hcl.File, expr hclsyntax.Expression, seenLocals map[string]bool) bool {
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		return true
+	case *hclsyntax.ForExpr:
+		return e.KeyExpr != nil
+	case *hclsyntax.FunctionCallExpr:
+		return e.Name == "tomap" || e.Name == "merge"
+	case *hclsyntax.ScopeTraversalExpr:
+		if len(e.Traversal) != 2 {
+			return false
+		}
+		attr, ok := e.Traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			return false
+		}
+		switch e.Traversal.RootName() {
+		case "var":
+			decl := variableBlock(files, attr.Name)
+			if decl == nil {
+				return false
+			}
+			typeAttr, ok := decl.Body.Attributes["type"]
+			if !ok {
+				return false
+			}
+			call, ok := typeAttr.Expr.(*hclsyntax.FunctionCallExpr)
+			return ok && (call.Name == "map" || call.Name == "object")
+		case "local":
+			if seenLocals[attr.Name] {
+				// cyclic local values
+				return false
+			}
+			seenLocals[attr.Name] = true
+			localExpr, ok := localExpression(files, attr.Name)
+			return ok && isMapValuedExpr(files, localExpr, seenLocals)
+		}
+	}
+	return false
+}
+
+// localExpression returns the expression of the local value
+// of the given name within the module
+func localExpression(files map[string]*hcl.File, name string) (hclsyntax.Expression, bool) {
+	for _, fName := range sortedFilenames(files) {
+		body, ok := files[fName].Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "locals" {
+				continue
+			}
+			if attr, ok := block.Body.Attributes[name]; ok {
+				return attr.Expr, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// repeatedBlockAt returns the resource, data source or module block
+// at the given position
+func repeatedBlockAt(files map[string]*hcl.File, filename string, pos hcl.Pos) (repeatedBlock, bool) {
+	f, ok := files[filename]
+	if !ok {
+		return repeatedBlock{}, false
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return repeatedBlock{}, false
+	}
+
+	for _, block := range body.Blocks {
+		if block.Range().ContainsPos(pos) {
+			return addressableBlock(block)
+		}
+	}
+	return repeatedBlock{}, false
+}
+
+// addressableBlock returns the given block along with its address
+// if it is a resource, data source or module block
+func addressableBlock(block *hclsyntax.Block) (repeatedBlock, bool) {
+	switch {
+	case block.Type == "resource" && len(block.Labels) == 2:
+		return repeatedBlock{block, lang.Address{
+			lang.RootStep{Name: block.Labels[0]},
+			lang.AttrStep{Name: block.Labels[1]},
+		}}, true
+	case block.Type == "data" && len(block.Labels) == 2:
+		return repeatedBlock{block, lang.Address{
+			lang.RootStep{Name: "data"},
+			lang.AttrStep{Name: block.Labels[0]},
+			lang.AttrStep{Name: block.Labels[1]},
+		}}, true
+	case block.Type == "module" && len(block.Labels) == 1:
+		return repeatedBlock{block, lang.Address{
+			lang.RootStep{Name: "module"},
+			lang.AttrStep{Name: block.Labels[0]},
+		}}, true
+	}
+	return repeatedBlock{}, false
+}
+
+// metaReferences returns ranges of all references to the given
+// attribute of a meta object (such as count.index) within the body
+func metaReferences(body *hclsyntax.Body, root, attrName string) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+		if !ok || len(expr.Traversal) < 2 || expr.Traversal.RootName() != root {
+			return nil
+		}
+		attr, ok := expr.Traversal[1].(hcl.TraverseAttr)
+		if !ok || attr.Name != attrName {
+			return nil
+		}
+		ranges = append(ranges, hcl.RangeBetween(expr.Traversal[0].SourceRange(), attr.SrcRange))
+		return nil
+	})
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start.Byte < ranges[j].Start.Byte
+	})
+	return ranges
+}
+
+// instanceReference represents a reference to the repeated block
+type instanceReference struct {
+	rng hcl.Range
+
+	// indexRng is the range of the instance key (including brackets)
+	// if the reference refers to a particular instance
+	indexRng *hcl.Range
+	key      cty.Value
+
+	// isSplat indicates whether the reference is followed by a splat
+	isSplat bool
+}
+
+// instanceReferences returns all references to the given address
+// among the reference origins
+func instanceReferences(files map[string]*hcl.File, origins reference.Origins, addr lang.Address) []instanceReference {
+	refs := make([]instanceReference, 0)
+	for _, origin := range origins {
+		localOrigin, ok := origin.(reference.LocalOrigin)
+		if !ok || len(localOrigin.Addr) < len(addr) || !localOrigin.Addr.FirstSteps(uint(len(addr))).Equals(addr) {
+			continue
+		}
+		f, ok := files[localOrigin.Range.Filename]
+		if !ok {
+			continue
+		}
+
+		ref := instanceReference{rng: localOrigin.Range}
+		if len(localOrigin.Addr) == len(addr) {
+			following := f.Bytes[localOrigin.Range.End.Byte:]
+			ref.isSplat = strings.HasPrefix(string(following), "[*]") || strings.HasPrefix(string(following), ".*")
+			refs = append(refs, ref)
+			continue
+		}
+		if _, ok := localOrigin.Addr[len(addr)].(lang.IndexStep); !ok {
+			continue
+		}
+
+		traversal, diags := hclsyntax.ParseTraversalAbs([]byte(source(f, localOrigin.Range)),
+			localOrigin.Range.Filename, localOrigin.Range.Start)
+		if diags.HasErrors() || len(traversal) <= len(addr) {
+			continue
+		}
+		index, ok := traversal[len(addr)].(hcl.TraverseIndex)
+		if !ok {
+			continue
+		}
+		indexRng := index.SrcRange
+		ref.indexRng = &indexRng
+		ref.key = index.Key
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func intKey(key cty.Value) (int64, bool) {
+	if key.IsNull() || !key.IsKnown() || key.Type() != cty.Number {
+		return 0, false
+	}
+	idx, accuracy := key.AsBigFloat().Int64()
+	return idx, accuracy == big.Exact
+}
+
+// listKeys returns elements of the given literal list of strings
+func listKeys(expr hclsyntax.Expression) []string {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || !val.CanIterateElements() {
+		return nil
+	}
+	keys := make([]string, 0, val.LengthInt())
+	for it := val.ElementIterator(); it.Next(); {
+		_, elem := it.Element()
+		if elem.IsNull() || elem.Type() != cty.String {
+			return nil
+		}
+		keys = append(keys, elem.AsString())
+	}
+	return keys
+}
+
+// mapKeys returns the sorted keys of the given literal map
+func mapKeys(expr hclsyntax.Expression) []string {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() ||
+		!(val.Type().IsObjectType() || val.Type().IsMapType()) {
+		return nil
+	}
+	keys := make([]string, 0, val.LengthInt())
+	for it := val.ElementIterator(); it.Next(); {
+		key, _ := it.Element()
+		keys = append(keys, key.AsString())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// addMovedBlocks adds moved blocks for the given (from, to) pairs
+// of instance addresses after the repeated block
+func addMovedBlocks(edits Edits, filename string, rb repeatedBlock, moves [][2]string) {
+	if rb.block.Type == "data" || len(moves) == 0 {
+		// data sources have no state to preserve
+		return
+	}
+
+	var sb strings.Builder
+	for _, move := range moves {
+		fmt.Fprintf(&sb, "\n\nmoved {\n  from = %s\n  to   = %s\n}", move[0], move[1])
+	}
+	end := rb.block.Range().End
+	edits.add(filename, hcl.Range{Filename: filename, Start: end, End: end}, sb.String())
+}
@@ -0,0 +1,217 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCountToForEach(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  count = 2
+  tags  = { Name = "web-${count.index}" }
+}
+`,
+		"outputs.tf": `output "first" {
+  value = aws_instance.web[0].id
+}
+
+output "all" {
+  value = aws_instance.web[*].id
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	origins := reference.Origins{
+		reference.LocalOrigin{
+			Addr: lang.Address{
+				lang.RootStep{Name: "aws_instance"},
+				lang.AttrStep{Name: "web"},
+				lang.IndexStep{Key: cty.NumberIntVal(0)},
+				lang.AttrStep{Name: "id"},
+			},
+			Range: hcl.Range{
+				Filename: "outputs.tf",
+				Start:    hcl.Pos{Line: 2, Column: 11, Byte: 27},
+				End:      hcl.Pos{Line: 2, Column: 33, Byte: 49},
+			},
+		},
+		reference.LocalOrigin{
+			Addr: lang.Address{
+				lang.RootStep{Name: "aws_instance"},
+				lang.AttrStep{Name: "web"},
+			},
+			Range: hcl.Range{
+				Filename: "outputs.tf",
+				Start:    hcl.Pos{Line: 6, Column: 11, Byte: 78},
+				End:      hcl.Pos{Line: 6, Column: 27, Byte: 94},
+			},
+		},
+	}
+
+	edits, ok := CountToForEach(files, origins, "main.tf", hcl.Pos{Line: 1, Column: 3, Byte: 2})
+	if !ok {
+		t.Fatal("expected conversion")
+	}
+
+	expectedSrcs := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  for_each = { for i in range(2) : i => i }
+  tags  = { Name = "web-${each.value}" }
+}
+
+moved {
+  from = aws_instance.web[0]
+  to   = aws_instance.web["0"]
+}
+
+moved {
+  from = aws_instance.web[1]
+  to   = aws_instance.web["1"]
+}
+`,
+		"outputs.tf": `output "first" {
+  value = aws_instance.web["0"].id
+}
+
+output "all" {
+  value = [for i in range(2) : aws_instance.web[tostring(i)]][*].id
+}
+`,
+	}
+	if diff := cmp.Diff(expectedSrcs, applyEdits(srcs, edits)); diff != "" {
+		t.Fatalf("unexpected result: %s", diff)
+	}
+}
+
+func TestForEachToCount(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `module "app" {
+  source   = "./app"
+  for_each = toset(["b", "a"])
+  name     = each.key
+}
+
+output "b" {
+  value = module.app["b"].id
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	origins := reference.Origins{
+		reference.LocalOrigin{
+			Addr: lang.Address{
+				lang.RootStep{Name: "module"},
+				lang.AttrStep{Name: "app"},
+				lang.IndexStep{Key: cty.StringVal("b")},
+				lang.AttrStep{Name: "id"},
+			},
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 8, Column: 11, Byte: 115},
+				End:      hcl.Pos{Line: 8, Column: 29, Byte: 133},
+			},
+		},
+	}
+
+	edits, ok := ForEachToCount(files, origins, "main.tf", hcl.Pos{Line: 4, Column: 3, Byte: 69})
+	if !ok {
+		t.Fatal("expected conversion")
+	}
+
+	expectedSrcs := map[string]string{
+		"main.tf": `module "app" {
+  source   = "./app"
+  count = length(["b", "a"])
+  name     = ["b", "a"][count.index]
+}
+
+moved {
+  from = module.app["b"]
+  to   = module.app[0]
+}
+
+moved {
+  from = module.app["a"]
+  to   = module.app[1]
+}
+
+output "b" {
+  value = module.app[0].id
+}
+`,
+	}
+	if diff := cmp.Diff(expectedSrcs, applyEdits(srcs, edits)); diff != "" {
+		t.Fatalf("unexpected result: %s", diff)
+	}
+}
+
+func TestCountToForEach_noCount(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	_, ok := CountToForEach(files, reference.Origins{}, "main.tf", hcl.Pos{Line: 2, Column: 3, Byte: 34})
+	if ok {
+		t.Fatal("expected no conversion of block without count")
+	}
+}
+
+func TestForEachToCount_set(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `variable "names" {
+  type = set(string)
+}
+
+variable "tags" {
+  type = map(string)
+}
+
+locals {
+  tags = var.tags
+}
+
+resource "aws_iam_user" "set" {
+  for_each = var.names
+  name     = each.key
+}
+
+resource "aws_iam_user" "map" {
+  for_each = local.tags
+  name     = each.key
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	setPos := strings.Index(srcs["main.tf"], `resource "aws_iam_user" "set"`)
+	_, ok := ForEachToCount(files, reference.Origins{}, "main.tf", posAt(srcs["main.tf"], setPos))
+	if ok {
+		t.Fatal("expected no conversion of for_each over a set")
+	}
+
+	mapPos := strings.Index(srcs["main.tf"], `resource "aws_iam_user" "map"`)
+	edits, ok := ForEachToCount(files, reference.Origins{}, "main.tf", posAt(srcs["main.tf"], mapPos))
+	if !ok {
+		t.Fatal("expected conversion of for_each over a map")
+	}
+	result := applyEdits(srcs, edits)["main.tf"]
+	if !strings.Contains(result, "name     = keys(local.tags)[count.index]") {
+		t.Fatalf("unexpected result: %s", result)
+	}
+}
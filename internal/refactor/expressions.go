@@ -0,0 +1,287 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// Edits represents text edits keyed by name of the file
+// within the module which they apply to
+type Edits map[string][]lang.TextEdit
+
+func (e Edits) add(filename string, rng hcl.Range, text string) {
+	e[filename] = append(e[filename], lang.TextEdit{
+		Range:   rng,
+		NewText: text,
+		Snippet: text,
+	})
+}
+
+// nonReferenceableBlocks are top-level blocks in which
+// local values and variables cannot be referenced
+var nonReferenceableBlocks = map[string]bool{
+	"terraform": true,
+	"variable":  true,
+	"moved":     true,
+	"removed":   true,
+}
+
+// referencesAllowed returns false for attributes which only accept
+// static values or addresses, where a reference to a local value
+// or variable would make the configuration invalid
+func referencesAllowed(topBlock, parent *hclsyntax.Block, attrName string) bool {
+	if parent == topBlock {
+		switch attrName {
+		case "depends_on", "provider", "providers":
+			return false
+		}
+	}
+	switch parent.Type {
+	case "lifecycle":
+		// only conditions nested within lifecycle accept references
+		return false
+	case "module":
+		return attrName != "source" && attrName != "version"
+	case "import":
+		return attrName != "to"
+	}
+	return true
+}
+
+// selectedExpression represents the expression selected within
+// an attribute along with its context
+type selectedExpression struct {
+	expr hclsyntax.Expression
+
+	// attrName is the name of the attribute if the whole
+	// value of the attribute is selected
+	attrName string
+
+	// topBlock is the top-level block containing the expression
+	topBlock *hclsyntax.Block
+
+	// scopedNames are names only available within the scope of the
+	// expression, such as count, each and iterators of for expressions
+	scopedNames map[string]bool
+}
+
+// expressionAt returns the smallest expression fully containing the given
+// range, or the value of the attribute at the position if the range is empty
+func expressionAt(body *hclsyntax.Body, rng hcl.Range) (*selectedExpression, bool) {
+	for _, block := range body.Blocks {
+		if !block.Range().ContainsPos(rng.Start) {
+			continue
+		}
+		scopedNames := map[string]bool{
+			"count": true,
+			"each":  true,
+			"self":  true,
+		}
+		attr, parent, dynamicNames, ok := attributeAt(block, rng)
+		if !ok || !referencesAllowed(block, parent, attr.Name) {
+			return nil, false
+		}
+		for name := range dynamicNames {
+			scopedNames[name] = true
+		}
+
+		selected := &selectedExpression{
+			expr:        attr.Expr,
+			topBlock:    block,
+			scopedNames: scopedNames,
+		}
+		if rng.Start == rng.End || attr.Expr.Range() == rng {
+			selected.attrName = attr.Name
+			return selected, true
+		}
+
+		excluded := excludedRanges(attr.Expr)
+		hclsyntax.VisitAll(attr.Expr, func(node hclsyntax.Node) hcl.Diagnostics {
+			expr, ok := node.(hclsyntax.Expression)
+			if !ok || !containsRange(expr.Range(), rng) || isExcluded(expr.Range(), excluded) {
+				return nil
+			}
+			if forExpr, ok := expr.(*hclsyntax.ForExpr); ok {
+				// iterators are only in scope of the expressions
+				// within the for expression
+				if forExpr.Range() != rng {
+					if forExpr.KeyVar != "" {
+						selected.scopedNames[forExpr.KeyVar] = true
+					}
+					selected.scopedNames[forExpr.ValVar] = true
+				}
+			}
+			if rangeLen(expr.Range()) < rangeLen(selected.expr.Range()) || expr.Range() == rng {
+				selected.expr = expr
+			}
+			return nil
+		})
+		if selected.expr == attr.Expr {
+			selected.attrName = attr.Name
+		}
+		return selected, true
+	}
+	return nil, false
+}
+
+// attributeAt returns the attribute within the given block containing
+// the given range, the (possibly nested) block declaring the attribute
+// and names of iterators of any dynamic blocks around it
+func attributeAt(parent *hclsyntax.Block, rng hcl.Range) (*hclsyntax.Attribute, *hclsyntax.Block, map[string]bool, bool) {
+	for _, attr := range parent.Body.Attributes {
+		if containsRange(attr.Expr.Range(), rng) {
+			return attr, parent, map[string]bool{}, true
+		}
+	}
+	for _, block := range parent.Body.Blocks {
+		if !block.Body.Range().ContainsPos(rng.Start) {
+			continue
+		}
+		attr, attrParent, names, ok := attributeAt(block, rng)
+		if !ok {
+			return nil, nil, nil, false
+		}
+		if block.Type == "dynamic" && len(block.Labels) == 1 {
+			names[block.Labels[0]] = true
+			if iterator, ok := block.Body.Attributes["iterator"]; ok {
+				if traversal, diags := hcl.AbsTraversalForExpr(iterator.Expr); !diags.HasErrors() {
+					names[traversal.RootName()] = true
+				}
+			}
+		}
+		return attr, attrParent, names, true
+	}
+	return nil, nil, nil, false
+}
+
+// excludedRanges returns ranges of expressions which cannot be
+// replaced by a reference, such as keys of objects or
+// literal parts of string templates
+func excludedRanges(expr hclsyntax.Expression) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+	hclsyntax.VisitAll(expr, func(node hclsyntax.Node) hcl.Diagnostics {
+		switch e := node.(type) {
+		case *hclsyntax.ObjectConsKeyExpr:
+			ranges = append(ranges, e.Range())
+		case *hclsyntax.TemplateExpr:
+			for _, part := range e.Parts {
+				if _, ok := part.(*hclsyntax.LiteralValueExpr); ok {
+					ranges = append(ranges, part.Range())
+				}
+			}
+		}
+		return nil
+	})
+	return ranges
+}
+
+func isExcluded(rng hcl.Range, excluded []hcl.Range) bool {
+	for _, ex := range excluded {
+		if containsRange(ex, rng) {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesScopedNames returns true if the expression refers to any
+// of the given names, which would be out of scope elsewhere
+func referencesScopedNames(expr hclsyntax.Expression, names map[string]bool) bool {
+	for _, traversal := range expr.Variables() {
+		if names[traversal.RootName()] {
+			return true
+		}
+	}
+	return false
+}
+
+func containsRange(outer, inner hcl.Range) bool {
+	return outer.Filename == inner.Filename &&
+		outer.Start.Byte <= inner.Start.Byte && inner.End.Byte <= outer.End.Byte
+}
+
+func rangeLen(rng hcl.Range) int {
+	return rng.End.Byte - rng.Start.Byte
+}
+
+func source(f *hcl.File, rng hcl.Range) string {
+	if rng.Start.Byte < 0 || rng.End.Byte > len(f.Bytes) || rng.Start.Byte > rng.End.Byte {
+		return ""
+	}
+	return string(f.Bytes[rng.Start.Byte:rng.End.Byte])
+}
+
+// lineStart returns the position at the start of the line of the given position
+func lineStart(pos hcl.Pos) hcl.Pos {
+	return hcl.Pos{
+		Line:   pos.Line,
+		Column: 1,
+		Byte:   pos.Byte - (pos.Column - 1),
+	}
+}
+
+// declaredNames returns names of all blocks of the given type (for
+// blocks with a single label) or attributes within blocks of the
+// given type (for blocks without labels, i.e. locals) in the given files
+func declaredNames(files map[string]*hcl.File, blockType string) map[string]bool {
+	names := make(map[string]bool, 0)
+	for _, f := range files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != blockType {
+				continue
+			}
+			if len(block.Labels) == 1 {
+				names[block.Labels[0]] = true
+				continue
+			}
+			for name := range block.Body.Attributes {
+				names[name] = true
+			}
+		}
+	}
+	return names
+}
+
+// uniqueName returns the given name, or the name
+// with the lowest numeric suffix not declared yet
+func uniqueName(name string, declared map[string]bool) string {
+	if !hclsyntax.ValidIdentifier(name) {
+		name = "extracted"
+	}
+	if !declared[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", name, i)
+		if !declared[candidate] {
+			return candidate
+		}
+	}
+}
+
+// sortedFilenames returns names of the files in a stable order
+func sortedFilenames(files map[string]*hcl.File) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// indentLines indents all lines of the given text except the first one
+func indentLines(text, indent string) string {
+	return strings.ReplaceAll(text, "\n", "\n"+indent)
+}
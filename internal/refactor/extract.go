@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactor
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// variablesFilename is the conventional file for variable declarations
+const variablesFilename = "variables.tf"
+
+// ExtractLocal moves the expression selected by the given range into
+// a new local value and replaces the expression with a reference to it.
+// It returns the edits for the files of the module along with
+// the name of the local value.
+func ExtractLocal(files map[string]*hcl.File, filename string, rng hcl.Range) (Edits, string, bool) {
+	f, ok := files[filename]
+	if !ok {
+		return nil, "", false
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, "", false
+	}
+
+	sel, ok := expressionAt(body, rng)
+	if !ok || nonReferenceableBlocks[sel.topBlock.Type] || referencesScopedNames(sel.expr, sel.scopedNames) {
+		return nil, "", false
+	}
+	if traversal, ok := sel.expr.(*hclsyntax.ScopeTraversalExpr); ok && traversal.Traversal.RootName() == "local" {
+		// the expression already is a local value
+		return nil, "", false
+	}
+
+	exprText := source(f, sel.expr.Range())
+	name := sel.attrName
+	if name == "" {
+		name = "extracted"
+	}
+	name = uniqueName(name, declaredNames(files, "locals"))
+
+	edits := make(Edits, 0)
+	edits.add(filename, sel.expr.Range(), "local."+name)
+
+	localsBlock := sel.topBlock
+	if localsBlock.Type != "locals" {
+		localsBlock = nil
+		for _, block := range body.Blocks {
+			if block.Type == "locals" && block.CloseBraceRange.Start.Line > block.OpenBraceRange.Start.Line {
+				localsBlock = block
+				break
+			}
+		}
+	}
+	if localsBlock != nil {
+		pos := lineStart(localsBlock.CloseBraceRange.Start)
+		edits.add(filename, hcl.Range{Filename: filename, Start: pos, End: pos},
+			fmt.Sprintf("  %s = %s\n", name, exprText))
+	} else {
+		pos := lineStart(sel.topBlock.Range().Start)
+		edits.add(filename, hcl.Range{Filename: filename, Start: pos, End: pos},
+			fmt.Sprintf("locals {\n  %s = %s\n}\n\n", name, exprText))
+	}
+
+	return edits, name, true
+}
+
+// ExtractVariable moves the literal expression selected by the given range
+// into the default value of a new variable, with the type inferred
+// from the value, and replaces the expression with a reference to it.
+// It returns the edits for the files of the module along with
+// the name of the variable.
+//
+// The variable is declared in variables.tf if the module has one,
+// or above the block containing the expression otherwise.
+func ExtractVariable(files map[string]*hcl.File, filename string, rng hcl.Range) (Edits, string, bool) {
+	f, ok := files[filename]
+	if !ok {
+		return nil, "", false
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, "", false
+	}
+
+	sel, ok := expressionAt(body, rng)
+	if !ok || nonReferenceableBlocks[sel.topBlock.Type] || len(sel.expr.Variables()) > 0 {
+		return nil, "", false
+	}
+	val, diags := sel.expr.Value(nil)
+	if diags.HasErrors() || val.IsNull() || !val.IsWhollyKnown() {
+		return nil, "", false
+	}
+
+	name := sel.attrName
+	if name == "" {
+		name = "extracted"
+	}
+	name = uniqueName(name, declaredNames(files, "variable"))
+
+	exprText := source(f, sel.expr.Range())
+	block := fmt.Sprintf("variable %q {\n  type    = %s\n  default = %s\n}\n",
+		name, typeexpr.TypeString(inferredType(val.Type())), exprText)
+
+	edits := make(Edits, 0)
+	edits.add(filename, sel.expr.Range(), "var."+name)
+
+	if varsFile, ok := files[variablesFilename]; ok && filename != variablesFilename {
+		if varsBody, ok := varsFile.Body.(*hclsyntax.Body); ok {
+			end := varsBody.Range().End
+			switch {
+			case len(varsFile.Bytes) == 0:
+			case varsFile.Bytes[len(varsFile.Bytes)-1] == '\n':
+				block = "\n" + block
+			default:
+				block = "\n\n" + block
+			}
+			edits.add(variablesFilename, hcl.Range{Filename: variablesFilename, Start: end, End: end}, block)
+			return edits, name, true
+		}
+	}
+
+	pos := lineStart(sel.topBlock.Range().Start)
+	edits.add(filename, hcl.Range{Filename: filename, Start: pos, End: pos}, block+"\n")
+
+	return edits, name, true
+}
+
+// inferredType returns the type to declare for a variable with
+// the given value, preferring collection types over structural
+// types for values with elements of a single type
+func inferredType(ty cty.Type) cty.Type {
+	switch {
+	case ty.IsTupleType():
+		elemTypes := ty.TupleElementTypes()
+		if len(elemTypes) == 0 {
+			return cty.List(cty.DynamicPseudoType)
+		}
+		inferred := make([]cty.Type, len(elemTypes))
+		for i, elemType := range elemTypes {
+			inferred[i] = inferredType(elemType)
+		}
+		if elemType, ok := singleType(inferred); ok {
+			return cty.List(elemType)
+		}
+		return cty.Tuple(inferred)
+	case ty.IsObjectType():
+		attrTypes := ty.AttributeTypes()
+		if len(attrTypes) == 0 {
+			return cty.Map(cty.DynamicPseudoType)
+		}
+		inferred := make(map[string]cty.Type, len(attrTypes))
+		types := make([]cty.Type, 0, len(attrTypes))
+		for name, attrType := range attrTypes {
+			inferred[name] = inferredType(attrType)
+			types = append(types, inferred[name])
+		}
+		if elemType, ok := singleType(types); ok {
+			return cty.Map(elemType)
+		}
+		return cty.Object(inferred)
+	}
+	return ty
+}
+
+func singleType(types []cty.Type) (cty.Type, bool) {
+	for _, ty := range types[1:] {
+		if !ty.Equals(types[0]) {
+			return cty.NilType, false
+		}
+	}
+	return types[0], true
+}
@@ -0,0 +1,554 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactor
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// modulesDir is the conventional directory for local child modules
+const modulesDir = "modules"
+
+// ExtractedModule represents a new child module extracted
+// from resources of the module
+type ExtractedModule struct {
+	// Name is the name of the module call
+	Name string
+
+	// Dir is the path of the new module directory,
+	// relative to the directory of the module
+	Dir string
+
+	// Files holds the content of the files of the new module
+	Files map[string]string
+
+	// Edits are the edits for the files of the module,
+	// replacing the resources with the module call
+	Edits Edits
+}
+
+// moduleValue represents a value passed into or out
+// of the extracted module
+type moduleValue struct {
+	name string
+	expr string
+
+	// decl is the declaring block of a variable passed
+	// into the module, if any
+	decl *hclsyntax.Block
+}
+
+// ExtractModule moves the resource and data blocks overlapping the given
+// (non-empty) selection into a new child module, such that:
+//   - all values referenced by the blocks from elsewhere in the module
+//     (found via the given reference origins) become variables
+//   - all attributes of the blocks referenced from elsewhere become outputs
+//   - a module block passing the variables replaces the blocks
+//   - aliased provider configurations used by the blocks are passed
+//     to the module and declared as configuration aliases
+//   - moved blocks are added for the resources to preserve state
+//
+// Blocks depending on blocks outside of the selection via depends_on
+// (or lifecycle arguments) cannot be extracted, as such arguments
+// do not accept variables.
+func ExtractModule(files map[string]*hcl.File, origins reference.Origins, filename string, rng hcl.Range) (*ExtractedModule, bool) {
+	if rng.Start.Byte == rng.End.Byte {
+		// blocks must be selected explicitly
+		return nil, false
+	}
+	f, ok := files[filename]
+	if !ok {
+		return nil, false
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, false
+	}
+
+	selected := make([]repeatedBlock, 0)
+	for _, block := range body.Blocks {
+		if block.Type != "resource" && block.Type != "data" {
+			continue
+		}
+		blockRng := block.Range()
+		if blockRng.Overlaps(rng) {
+			if rb, ok := addressableBlock(block); ok {
+				selected = append(selected, rb)
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return nil, false
+	}
+
+	name := uniqueName(selected[0].block.Labels[len(selected[0].block.Labels)-1], declaredNames(files, "module"))
+	mod := &ExtractedModule{
+		Name:  name,
+		Dir:   path.Join(modulesDir, name),
+		Files: make(map[string]string),
+		Edits: make(Edits, 0),
+	}
+
+	isSelected := func(addr lang.Address) bool {
+		for _, rb := range selected {
+			if len(addr) >= len(rb.addr) && addr.FirstSteps(uint(len(rb.addr))).Equals(rb.addr) {
+				return true
+			}
+		}
+		return false
+	}
+	selectedBlockAt := func(originRng hcl.Range) (int, bool) {
+		if originRng.Filename != filename {
+			return 0, false
+		}
+		for i, rb := range selected {
+			if rb.block.Range().ContainsPos(originRng.Start) {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	// references from within the selected blocks become variables
+	inputs := make([]*moduleValue, 0)
+	inputsByExpr := make(map[string]*moduleValue)
+	inputNames := make(map[string]bool)
+	blockEdits := make([][]lang.TextEdit, len(selected))
+
+	// references to the selected blocks from elsewhere become outputs
+	outputs := make([]*moduleValue, 0)
+	outputsByExpr := make(map[string]*moduleValue)
+	outputNames := make(map[string]bool)
+
+	for _, origin := range sortedOrigins(origins) {
+		if idx, ok := selectedBlockAt(origin.Range); ok {
+			if isSelected(origin.Addr) {
+				continue
+			}
+			block := selected[idx].block
+			attr, parent, _, ok := attributeAt(block, origin.Range)
+			if ok && parent == block && attr.Name == "provider" {
+				// provider configurations are passed via providers
+				continue
+			}
+			if ok && !referencesAllowed(block, parent, attr.Name) {
+				return nil, false
+			}
+			steps := inputSteps(origin.Addr)
+			prefixRng, ok := traversalPrefixRange(files, origin, steps)
+			if !ok {
+				continue
+			}
+			expr := source(files[origin.Range.Filename], prefixRng)
+			input, ok := inputsByExpr[expr]
+			if !ok {
+				varName := inputName(origin.Addr[:steps])
+				input = &moduleValue{
+					name: uniqueName(varName, inputNames),
+					expr: expr,
+				}
+				if origin.Addr[0].String() == "var" {
+					input.decl = variableBlock(files, varName)
+				}
+				inputNames[input.name] = true
+				inputsByExpr[expr] = input
+				inputs = append(inputs, input)
+			}
+			blockEdits[idx] = append(blockEdits[idx], lang.TextEdit{
+				Range:   prefixRng,
+				NewText: "var." + input.name,
+			})
+			continue
+		}
+
+		for _, rb := range selected {
+			if len(origin.Addr) < len(rb.addr) || !origin.Addr.FirstSteps(uint(len(rb.addr))).Equals(rb.addr) {
+				continue
+			}
+			steps := len(rb.addr)
+			outName := rb.block.Labels[len(rb.block.Labels)-1]
+			if len(origin.Addr) > steps {
+				if attr, ok := origin.Addr[steps].(lang.AttrStep); ok {
+					steps++
+					outName += "_" + attr.Name
+				}
+			}
+			prefixRng, ok := traversalPrefixRange(files, origin, steps)
+			if !ok {
+				break
+			}
+			expr := source(files[origin.Range.Filename], prefixRng)
+			output, ok := outputsByExpr[expr]
+			if !ok {
+				output = &moduleValue{
+					name: uniqueName(outName, outputNames),
+					expr: expr,
+				}
+				outputNames[output.name] = true
+				outputsByExpr[expr] = output
+				outputs = append(outputs, output)
+			}
+			mod.Edits.add(origin.Range.Filename, prefixRng, fmt.Sprintf("module.%s.%s", name, output.name))
+			break
+		}
+	}
+
+	// main.tf of the new module
+	blocks := make([]string, 0, len(selected))
+	for i, rb := range selected {
+		blocks = append(blocks, applyRelativeEdits(f, rb.block.Range(), blockEdits[i]))
+	}
+	mod.Files["main.tf"] = strings.Join(blocks, "\n\n") + "\n"
+
+	if len(inputs) > 0 {
+		decls := make([]string, 0, len(inputs))
+		for _, input := range inputs {
+			decls = append(decls, variableDeclaration(files, input))
+		}
+		mod.Files["variables.tf"] = strings.Join(decls, "\n")
+	}
+	if len(outputs) > 0 {
+		decls := make([]string, 0, len(outputs))
+		for _, output := range outputs {
+			decls = append(decls, fmt.Sprintf("output %q {\n  value = %s\n}\n", output.name, output.expr))
+		}
+		mod.Files["outputs.tf"] = strings.Join(decls, "\n")
+	}
+	aliases := providerAliases(selected)
+	if reqs, ok := requiredProviders(files, selected, aliases); ok {
+		mod.Files["versions.tf"] = reqs
+	}
+
+	// module call and moved blocks replacing the selected blocks
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "module %q {\n  source = \"./%s\"\n", name, mod.Dir)
+	if len(inputs) > 0 {
+		width := 0
+		for _, input := range inputs {
+			width = max(width, len(input.name))
+		}
+		sb.WriteString("\n")
+		for _, input := range inputs {
+			fmt.Fprintf(&sb, "  %-*s = %s\n", width, input.name, input.expr)
+		}
+	}
+	if len(aliases) > 0 {
+		sb.WriteString("\n  providers = {\n")
+		for _, alias := range sortedAliases(aliases) {
+			fmt.Fprintf(&sb, "    %s = %s\n", alias, alias)
+		}
+		sb.WriteString("  }\n")
+	}
+	sb.WriteString("}")
+	for _, rb := range selected {
+		if rb.block.Type != "resource" {
+			// data sources have no state to preserve
+			continue
+		}
+		fmt.Fprintf(&sb, "\n\nmoved {\n  from = %s\n  to   = module.%s.%s\n}", rb, name, rb)
+	}
+	mod.Edits.add(filename, selected[0].block.Range(), sb.String())
+
+	for _, rb := range selected[1:] {
+		removeRng := rb.block.Range()
+		removeRng.Start = lineStart(removeRng.Start)
+		removeRng.End = nextLineStart(f.Bytes, removeRng.End)
+		// remove the blank line separating the block from the next one
+		removeRng.End = nextLineStart(f.Bytes, removeRng.End)
+		mod.Edits.add(filename, removeRng, "")
+	}
+
+	return mod, true
+}
+
+// sortedOrigins returns the local reference origins
+// in the order of their position
+func sortedOrigins(origins reference.Origins) []reference.LocalOrigin {
+	sorted := make([]reference.LocalOrigin, 0, len(origins))
+	for _, origin := range origins {
+		if localOrigin, ok := origin.(reference.LocalOrigin); ok && len(localOrigin.Addr) > 0 {
+			sorted = append(sorted, localOrigin)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Range.Filename != sorted[j].Range.Filename {
+			return sorted[i].Range.Filename < sorted[j].Range.Filename
+		}
+		return sorted[i].Range.Start.Byte < sorted[j].Range.Start.Byte
+	})
+	return sorted
+}
+
+// inputSteps returns the number of steps of the given address which
+// make up the value to pass into the module (e.g. var.name
+// or module.vpc.id), or 0 if it can only be referenced in its scope
+func inputSteps(addr lang.Address) int {
+	steps := 2
+	switch addr[0].String() {
+	case "count", "each", "self", "path", "terraform":
+		return 0
+	case "data", "module":
+		steps = 3
+	}
+	if len(addr) < steps {
+		return 0
+	}
+	for _, step := range addr[1:steps] {
+		if _, ok := step.(lang.AttrStep); !ok {
+			return 0
+		}
+	}
+	return steps
+}
+
+// inputName returns the name of the variable for the given address
+func inputName(addr lang.Address) string {
+	names := make([]string, 0, len(addr))
+	for _, step := range addr {
+		switch s := step.(type) {
+		case lang.RootStep:
+			names = append(names, s.Name)
+		case lang.AttrStep:
+			names = append(names, s.Name)
+		}
+	}
+	switch names[0] {
+	case "var", "local", "module":
+		return names[len(names)-1]
+	case "data":
+		return strings.Join(names[1:], "_")
+	}
+	return strings.Join(names, "_")
+}
+
+// traversalPrefixRange returns the range of the given number of
+// leading steps of the traversal at the reference origin
+func traversalPrefixRange(files map[string]*hcl.File, origin reference.LocalOrigin, steps int) (hcl.Range, bool) {
+	if steps == 0 {
+		return hcl.Range{}, false
+	}
+	f, ok := files[origin.Range.Filename]
+	if !ok {
+		return hcl.Range{}, false
+	}
+	traversal, diags := hclsyntax.ParseTraversalAbs([]byte(source(f, origin.Range)),
+		origin.Range.Filename, origin.Range.Start)
+	if diags.HasErrors() || len(traversal) < steps {
+		return hcl.Range{}, false
+	}
+	return hcl.RangeBetween(traversal[0].SourceRange(), traversal[steps-1].SourceRange()), true
+}
+
+// applyRelativeEdits returns the source of the given range
+// of the file with the edits within it applied
+func applyRelativeEdits(f *hcl.File, rng hcl.Range, edits []lang.TextEdit) string {
+	sorted := append(edits[:0:0], edits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Range.Start.Byte > sorted[j].Range.Start.Byte
+	})
+	src := source(f, rng)
+	for _, edit := range sorted {
+		start := edit.Range.Start.Byte - rng.Start.Byte
+		end := edit.Range.End.Byte - rng.Start.Byte
+		src = src[:start] + edit.NewText + src[end:]
+	}
+	return src
+}
+
+// variableBlock returns the declaration of the variable
+// of the given name within the module
+func variableBlock(files map[string]*hcl.File, name string) *hclsyntax.Block {
+	for _, fName := range sortedFilenames(files) {
+		body, ok := files[fName].Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type == "variable" && len(block.Labels) == 1 && block.Labels[0] == name {
+				return block
+			}
+		}
+	}
+	return nil
+}
+
+// variableDeclaration returns the variable block for the given
+// input of the module, retaining the type and description
+// of the variable it is passed from
+func variableDeclaration(files map[string]*hcl.File, input *moduleValue) string {
+	attrs := make([]string, 0)
+	if input.decl != nil {
+		f := files[input.decl.Range().Filename]
+		for _, name := range []string{"type", "description", "sensitive", "nullable"} {
+			if attr, ok := input.decl.Body.Attributes[name]; ok {
+				attrs = append(attrs, source(f, attr.SrcRange))
+			}
+		}
+	}
+	if len(attrs) == 0 {
+		return fmt.Sprintf("variable %q {}\n", input.name)
+	}
+	return fmt.Sprintf("variable %q {\n  %s\n}\n", input.name, strings.Join(attrs, "\n  "))
+}
+
+// providerAliases returns the aliases of provider configurations
+// the given blocks refer to (e.g. aws.west), by local provider name
+func providerAliases(blocks []repeatedBlock) map[string][]string {
+	aliases := make(map[string][]string)
+	for _, rb := range blocks {
+		attr, ok := rb.block.Body.Attributes["provider"]
+		if !ok {
+			continue
+		}
+		traversal, ok := attr.Expr.(*hclsyntax.ScopeTraversalExpr)
+		if !ok || len(traversal.Traversal) != 2 {
+			continue
+		}
+		step, ok := traversal.Traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			continue
+		}
+		localName := traversal.Traversal.RootName()
+		alias := localName + "." + step.Name
+		if !slices.Contains(aliases[localName], alias) {
+			aliases[localName] = append(aliases[localName], alias)
+		}
+	}
+	for _, names := range aliases {
+		sort.Strings(names)
+	}
+	return aliases
+}
+
+// sortedAliases returns all aliases of provider configurations, sorted
+func sortedAliases(aliases map[string][]string) []string {
+	sorted := make([]string, 0)
+	for _, names := range aliases {
+		sorted = append(sorted, names...)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// requiredProviders returns a terraform block with the provider requirements
+// of the module which apply to the given blocks, declaring the given aliases
+// of provider configurations passed into the module
+func requiredProviders(files map[string]*hcl.File, blocks []repeatedBlock, aliases map[string][]string) (string, bool) {
+	localNames := make(map[string]bool)
+	for _, rb := range blocks {
+		if attr, ok := rb.block.Body.Attributes["provider"]; ok {
+			if traversal, ok := attr.Expr.(*hclsyntax.ScopeTraversalExpr); ok {
+				localNames[traversal.Traversal.RootName()] = true
+				continue
+			}
+		}
+		localName, _, _ := strings.Cut(rb.block.Labels[0], "_")
+		localNames[localName] = true
+	}
+
+	reqs := make([]string, 0)
+	required := make(map[string]bool)
+	for _, fName := range sortedFilenames(files) {
+		body, ok := files[fName].Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "terraform" {
+				continue
+			}
+			for _, nested := range block.Body.Blocks {
+				if nested.Type != "required_providers" {
+					continue
+				}
+				names := make([]string, 0, len(nested.Body.Attributes))
+				for name := range nested.Body.Attributes {
+					if localNames[name] {
+						names = append(names, name)
+					}
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					attr := nested.Body.Attributes[name]
+					required[name] = true
+					if len(aliases[name]) > 0 {
+						reqs = append(reqs, aliasedRequirement(files[fName], attr, aliases[name]))
+						continue
+					}
+					// the entries keep their indentation at the same depth
+					reqs = append(reqs, source(files[fName], attr.SrcRange))
+				}
+			}
+		}
+	}
+
+	// aliases must be declared even if the provider isn't required explicitly
+	for _, localName := range sortedKeys(aliases) {
+		if !required[localName] {
+			reqs = append(reqs, requirementEntry(localName, [][2]string{
+				{"configuration_aliases", "[" + strings.Join(aliases[localName], ", ") + "]"},
+			}))
+		}
+	}
+
+	if len(reqs) == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("terraform {\n  required_providers {\n    %s\n  }\n}\n", strings.Join(reqs, "\n    ")), true
+}
+
+// aliasedRequirement returns the given provider requirement
+// with the given aliases declared as configuration_aliases
+func aliasedRequirement(f *hcl.File, attr *hclsyntax.Attribute, aliases []string) string {
+	items := make([][2]string, 0)
+	switch expr := attr.Expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		for _, item := range expr.Items {
+			key := source(f, item.KeyExpr.Range())
+			if strings.Trim(key, `"`) == "configuration_aliases" {
+				continue
+			}
+			items = append(items, [2]string{key, source(f, item.ValueExpr.Range())})
+		}
+	default:
+		// legacy requirement of a version constraint only
+		items = append(items, [2]string{"version", source(f, attr.Expr.Range())})
+	}
+	items = append(items, [2]string{"configuration_aliases", "[" + strings.Join(aliases, ", ") + "]"})
+	return requirementEntry(attr.Name, items)
+}
+
+// requirementEntry returns an entry of required_providers
+// with the given (aligned) arguments
+func requirementEntry(localName string, items [][2]string) string {
+	width := 0
+	for _, item := range items {
+		width = max(width, len(item[0]))
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s = {\n", localName)
+	for _, item := range items {
+		fmt.Fprintf(&sb, "      %-*s = %s\n", width, item[0], item[1])
+	}
+	sb.WriteString("    }")
+	return sb.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,319 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// traversalOrigins returns reference origins of all
+// traversals within the given files
+func traversalOrigins(t *testing.T, files map[string]*hcl.File) reference.Origins {
+	origins := make(reference.Origins, 0)
+	for _, fName := range sortedFilenames(files) {
+		hclsyntax.VisitAll(files[fName].Body.(*hclsyntax.Body), func(node hclsyntax.Node) hcl.Diagnostics {
+			expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+			if !ok {
+				return nil
+			}
+			addr, err := lang.TraversalToAddress(expr.Traversal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			origins = append(origins, reference.LocalOrigin{
+				Addr:  addr,
+				Range: expr.Range(),
+			})
+			return nil
+		})
+	}
+	return origins
+}
+
+func TestExtractModule(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+    random = {
+      source = "hashicorp/random"
+    }
+  }
+}
+
+variable "env" {
+  type        = string
+  description = "Name of the environment"
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "aws_subnet" "web" {
+  vpc_id     = aws_vpc.main.id
+  cidr_block = cidrsubnet(aws_vpc.main.cidr_block, 8, 1)
+}
+
+resource "aws_instance" "web" {
+  subnet_id = aws_subnet.web.id
+  tags      = { Env = var.env }
+}
+
+output "instance_id" {
+  value = aws_instance.web.id
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+	origins := traversalOrigins(t, files)
+
+	// selection from the subnet to the instance
+	mod, ok := ExtractModule(files, origins, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 21, Column: 1, Byte: 291},
+		End:      hcl.Pos{Line: 28, Column: 3, Byte: 480},
+	})
+	if !ok {
+		t.Fatal("expected extraction")
+	}
+	if mod.Name != "web" || mod.Dir != "modules/web" {
+		t.Fatalf("unexpected module: %q in %q", mod.Name, mod.Dir)
+	}
+
+	expectedFiles := map[string]string{
+		"main.tf": `resource "aws_subnet" "web" {
+  vpc_id     = var.aws_vpc_main.id
+  cidr_block = cidrsubnet(var.aws_vpc_main.cidr_block, 8, 1)
+}
+
+resource "aws_instance" "web" {
+  subnet_id = aws_subnet.web.id
+  tags      = { Env = var.env }
+}
+`,
+		"variables.tf": `variable "aws_vpc_main" {}
+
+variable "env" {
+  type        = string
+  description = "Name of the environment"
+}
+`,
+		"outputs.tf": `output "web_id" {
+  value = aws_instance.web.id
+}
+`,
+		"versions.tf": `terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+`,
+	}
+	if diff := cmp.Diff(expectedFiles, mod.Files); diff != "" {
+		t.Fatalf("unexpected files: %s", diff)
+	}
+
+	expectedSrcs := map[string]string{
+		"main.tf": `terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+    random = {
+      source = "hashicorp/random"
+    }
+  }
+}
+
+variable "env" {
+  type        = string
+  description = "Name of the environment"
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+
+module "web" {
+  source = "./modules/web"
+
+  aws_vpc_main = aws_vpc.main
+  env          = var.env
+}
+
+moved {
+  from = aws_subnet.web
+  to   = module.web.aws_subnet.web
+}
+
+moved {
+  from = aws_instance.web
+  to   = module.web.aws_instance.web
+}
+
+output "instance_id" {
+  value = module.web.web_id
+}
+`,
+	}
+	if diff := cmp.Diff(expectedSrcs, applyEdits(srcs, mod.Edits)); diff != "" {
+		t.Fatalf("unexpected result: %s", diff)
+	}
+}
+
+func TestExtractModule_providerAlias(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "aws" {
+  alias  = "west"
+  region = "us-west-2"
+}
+
+resource "aws_s3_bucket" "logs" {
+  provider = aws.west
+  bucket   = "logs"
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+	origins := traversalOrigins(t, files)
+
+	start := strings.Index(srcs["main.tf"], "resource")
+	mod, ok := ExtractModule(files, origins, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    posAt(srcs["main.tf"], start),
+		End:      posAt(srcs["main.tf"], start+8),
+	})
+	if !ok {
+		t.Fatal("expected extraction")
+	}
+
+	expectedFiles := map[string]string{
+		"main.tf": `resource "aws_s3_bucket" "logs" {
+  provider = aws.west
+  bucket   = "logs"
+}
+`,
+		"versions.tf": `terraform {
+  required_providers {
+    aws = {
+      source                = "hashicorp/aws"
+      version               = "~> 5.0"
+      configuration_aliases = [aws.west]
+    }
+  }
+}
+`,
+	}
+	if diff := cmp.Diff(expectedFiles, mod.Files); diff != "" {
+		t.Fatalf("unexpected files: %s", diff)
+	}
+
+	expectedSrcs := map[string]string{
+		"main.tf": `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "aws" {
+  alias  = "west"
+  region = "us-west-2"
+}
+
+module "logs" {
+  source = "./modules/logs"
+
+  providers = {
+    aws.west = aws.west
+  }
+}
+
+moved {
+  from = aws_s3_bucket.logs
+  to   = module.logs.aws_s3_bucket.logs
+}
+`,
+	}
+	if diff := cmp.Diff(expectedSrcs, applyEdits(srcs, mod.Edits)); diff != "" {
+		t.Fatalf("unexpected result: %s", diff)
+	}
+}
+
+func TestExtractModule_dependsOnOutside(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `resource "aws_iam_role" "app" {
+  name = "app"
+}
+
+resource "aws_instance" "app" {
+  ami = "ami-123"
+
+  depends_on = [aws_iam_role.app]
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+	origins := traversalOrigins(t, files)
+
+	start := strings.Index(srcs["main.tf"], `resource "aws_instance"`)
+	_, ok := ExtractModule(files, origins, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    posAt(srcs["main.tf"], start),
+		End:      posAt(srcs["main.tf"], start+8),
+	})
+	if ok {
+		t.Fatal("expected no extraction of block depending on a block outside of the selection")
+	}
+
+	// depending on a block within the selection is fine
+	_, ok = ExtractModule(files, origins, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    posAt(srcs["main.tf"], 0),
+		End:      posAt(srcs["main.tf"], start+8),
+	})
+	if !ok {
+		t.Fatal("expected extraction of both blocks")
+	}
+}
+
+func TestExtractModule_noResource(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `variable "env" {}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	_, ok := ExtractModule(files, reference.Origins{}, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 1, Column: 3, Byte: 2},
+		End:      hcl.Pos{Line: 1, Column: 3, Byte: 2},
+	})
+	if ok {
+		t.Fatal("expected no extraction without resources")
+	}
+}
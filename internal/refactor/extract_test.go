@@ -0,0 +1,310 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactor
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func parseFiles(t *testing.T, srcs map[string]string) map[string]*hcl.File {
+	files := make(map[string]*hcl.File, len(srcs))
+	for name, src := range srcs {
+		f, diags := hclsyntax.ParseConfig([]byte(src), name, hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		files[name] = f
+	}
+	return files
+}
+
+// applyEdits applies the edits to the given sources
+func applyEdits(srcs map[string]string, edits Edits) map[string]string {
+	result := make(map[string]string, len(srcs))
+	for name, src := range srcs {
+		result[name] = src
+	}
+	for name, fileEdits := range edits {
+		sorted := append(fileEdits[:0:0], fileEdits...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Range.Start.Byte > sorted[j].Range.Start.Byte
+		})
+		src := result[name]
+		for _, edit := range sorted {
+			src = src[:edit.Range.Start.Byte] + edit.NewText + src[edit.Range.End.Byte:]
+		}
+		result[name] = src
+	}
+	return result
+}
+
+func TestExtractLocal(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  ami  = "ami-123"
+  tags = { Name = "${var.env}-web" }
+}
+`,
+		"other.tf": `resource "aws_instance" "db" {
+  ami = "ami-123"
+}
+
+variable "ami" {
+  default = "ami-123"
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	// cursor within "ami-123" in main.tf
+	edits, name, ok := ExtractLocal(files, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 12, Byte: 43},
+		End:      hcl.Pos{Line: 2, Column: 12, Byte: 43},
+	})
+	if !ok {
+		t.Fatal("expected extraction")
+	}
+	if name != "ami" {
+		t.Fatalf("unexpected name: %q", name)
+	}
+
+	expectedSrcs := map[string]string{
+		"main.tf": `locals {
+  ami = "ami-123"
+}
+
+resource "aws_instance" "web" {
+  ami  = local.ami
+  tags = { Name = "${var.env}-web" }
+}
+`,
+		// identical expressions elsewhere are left alone
+		"other.tf": `resource "aws_instance" "db" {
+  ami = "ami-123"
+}
+
+variable "ami" {
+  default = "ami-123"
+}
+`,
+	}
+	if diff := cmp.Diff(expectedSrcs, applyEdits(srcs, edits)); diff != "" {
+		t.Fatalf("unexpected result: %s", diff)
+	}
+}
+
+func TestExtractLocal_identicalExpressions(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  monitoring = true
+  ebs_optimized = true
+}
+`,
+		"other.tf": `resource "aws_instance" "db" {
+  monitoring = true
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	// cursor within the first true
+	edits, _, ok := ExtractLocal(files, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 16, Byte: 47},
+		End:      hcl.Pos{Line: 2, Column: 16, Byte: 47},
+	})
+	if !ok {
+		t.Fatal("expected extraction")
+	}
+
+	expectedSrcs := map[string]string{
+		"main.tf": `locals {
+  monitoring = true
+}
+
+resource "aws_instance" "web" {
+  monitoring = local.monitoring
+  ebs_optimized = true
+}
+`,
+		"other.tf": `resource "aws_instance" "db" {
+  monitoring = true
+}
+`,
+	}
+	if diff := cmp.Diff(expectedSrcs, applyEdits(srcs, edits)); diff != "" {
+		t.Fatalf("unexpected result: %s", diff)
+	}
+}
+
+func TestExtract_nonReferenceablePositions(t *testing.T) {
+	src := `module "vpc" {
+  source     = "terraform-aws-modules/vpc/aws"
+  version    = "5.0.0"
+  depends_on = [aws_instance.web]
+  providers = {
+    aws = aws.west
+  }
+}
+
+resource "aws_instance" "web" {
+  provider = aws.west
+
+  lifecycle {
+    prevent_destroy = true
+    ignore_changes  = [tags]
+
+    precondition {
+      condition     = true
+      error_message = "invalid"
+    }
+  }
+}
+
+import {
+  to = aws_instance.web
+  id = "i-123"
+}
+`
+	files := parseFiles(t, map[string]string{"main.tf": src})
+
+	testCases := []struct {
+		name          string
+		value         string
+		expectedFound bool
+	}{
+		{"module source", `"terraform-aws-modules/vpc/aws"`, false},
+		{"module version", `"5.0.0"`, false},
+		{"depends_on", `[aws_instance.web]`, false},
+		{"providers", `aws.west
+  }`, false},
+		{"provider", `aws.west
+
+  lifecycle`, false},
+		{"prevent_destroy", `true
+    ignore_changes`, false},
+		{"ignore_changes", `[tags]`, false},
+		{"import to", `aws_instance.web
+  id`, false},
+		{"precondition", `true
+      error_message`, true},
+		{"import id", `"i-123"`, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idx := strings.Index(src, tc.value)
+			if idx < 0 {
+				t.Fatalf("value %q not found", tc.value)
+			}
+			pos := posAt(src, idx+1)
+			rng := hcl.Range{Filename: "main.tf", Start: pos, End: pos}
+
+			_, _, ok := ExtractLocal(files, "main.tf", rng)
+			if ok != tc.expectedFound {
+				t.Fatalf("expected local extraction: %t, given: %t", tc.expectedFound, ok)
+			}
+			if strings.HasPrefix(tc.value, `"`) || tc.value == "true" {
+				_, _, ok = ExtractVariable(files, "main.tf", rng)
+				if ok != tc.expectedFound {
+					t.Fatalf("expected variable extraction: %t, given: %t", tc.expectedFound, ok)
+				}
+			}
+		})
+	}
+}
+
+// posAt returns the position of the given byte offset in src
+func posAt(src string, offset int) hcl.Pos {
+	line := strings.Count(src[:offset], "\n") + 1
+	column := offset - strings.LastIndex(src[:offset], "\n")
+	return hcl.Pos{Line: line, Column: column, Byte: offset}
+}
+
+func TestExtractLocal_scopedNames(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  count = 2
+  ami   = "ami-${count.index}"
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	_, _, ok := ExtractLocal(files, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 3, Column: 12, Byte: 55},
+		End:      hcl.Pos{Line: 3, Column: 12, Byte: 55},
+	})
+	if ok {
+		t.Fatal("expected no extraction of expression referring to count")
+	}
+}
+
+func TestExtractVariable(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  tags = { Name = "web", Env = "dev" }
+}
+`,
+		"variables.tf": `variable "region" {}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	edits, name, ok := ExtractVariable(files, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 10, Byte: 41},
+		End:      hcl.Pos{Line: 2, Column: 39, Byte: 70},
+	})
+	if !ok {
+		t.Fatal("expected extraction")
+	}
+	if name != "tags" {
+		t.Fatalf("unexpected name: %q", name)
+	}
+
+	expectedSrcs := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  tags = var.tags
+}
+`,
+		"variables.tf": `variable "region" {}
+
+variable "tags" {
+  type    = map(string)
+  default = { Name = "web", Env = "dev" }
+}
+`,
+	}
+	if diff := cmp.Diff(expectedSrcs, applyEdits(srcs, edits)); diff != "" {
+		t.Fatalf("unexpected result: %s", diff)
+	}
+}
+
+func TestExtractVariable_nonLiteral(t *testing.T) {
+	srcs := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  ami = var.ami
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	_, _, ok := ExtractVariable(files, "main.tf", hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 10, Byte: 41},
+		End:      hcl.Pos{Line: 2, Column: 10, Byte: 41},
+	})
+	if ok {
+		t.Fatal("expected no extraction of non-literal expression")
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactor
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// InlineLocal replaces all references to the local value at the given
+// position, either referenced or declared there, with its expression
+// and removes the declaration. References are looked up among
+// the given reference origins of the module.
+//
+// It returns the edits for the files of the module along with
+// the name of the local value.
+func InlineLocal(files map[string]*hcl.File, origins reference.Origins, filename string, pos hcl.Pos) (Edits, string, bool) {
+	name, ok := localNameAt(files, origins, filename, pos)
+	if !ok {
+		return nil, "", false
+	}
+
+	var defFilename string
+	var defBlock *hclsyntax.Block
+	var defAttr *hclsyntax.Attribute
+	for _, fName := range sortedFilenames(files) {
+		body, ok := files[fName].Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "locals" {
+				continue
+			}
+			if attr, ok := block.Body.Attributes[name]; ok {
+				defFilename, defBlock, defAttr = fName, block, attr
+				break
+			}
+		}
+		if defAttr != nil {
+			break
+		}
+	}
+	if defAttr == nil {
+		return nil, "", false
+	}
+
+	defFile := files[defFilename]
+	exprText := source(defFile, defAttr.Expr.Range())
+	switch defAttr.Expr.(type) {
+	case *hclsyntax.BinaryOpExpr, *hclsyntax.UnaryOpExpr, *hclsyntax.ConditionalExpr:
+		exprText = "(" + exprText + ")"
+	}
+
+	edits := make(Edits, 0)
+	refLen := len("local." + name)
+	for _, origin := range origins {
+		localOrigin, ok := origin.(reference.LocalOrigin)
+		if !ok || !isLocalAddress(localOrigin.Addr, name) {
+			continue
+		}
+		// The origin may continue with further steps, such
+		// as local.tags.Name, which are to be preserved
+		rng := localOrigin.Range
+		rng.End = hcl.Pos{
+			Line:   rng.Start.Line,
+			Column: rng.Start.Column + refLen,
+			Byte:   rng.Start.Byte + refLen,
+		}
+		edits.add(rng.Filename, rng, exprText)
+	}
+
+	removeRng := defAttr.SrcRange
+	if len(defBlock.Body.Attributes) == 1 && len(defBlock.Body.Blocks) == 0 {
+		removeRng = defBlock.Range()
+	}
+	removeRng.Start = lineStart(removeRng.Start)
+	removeRng.End = nextLineStart(defFile.Bytes, removeRng.End)
+	edits.add(defFilename, removeRng, "")
+
+	return edits, name, true
+}
+
+// localNameAt returns the name of the local value referenced
+// or declared at the given position
+func localNameAt(files map[string]*hcl.File, origins reference.Origins, filename string, pos hcl.Pos) (string, bool) {
+	for _, origin := range origins {
+		localOrigin, ok := origin.(reference.LocalOrigin)
+		if !ok || localOrigin.Range.Filename != filename || !localOrigin.Range.ContainsPos(pos) {
+			continue
+		}
+		if len(localOrigin.Addr) >= 2 && localOrigin.Addr[0].String() == "local" {
+			if step, ok := localOrigin.Addr[1].(lang.AttrStep); ok {
+				return step.Name, true
+			}
+		}
+	}
+
+	f, ok := files[filename]
+	if !ok {
+		return "", false
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return "", false
+	}
+	for _, block := range body.Blocks {
+		if block.Type != "locals" || !block.Range().ContainsPos(pos) {
+			continue
+		}
+		for name, attr := range block.Body.Attributes {
+			if attr.NameRange.ContainsPos(pos) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+func isLocalAddress(addr lang.Address, name string) bool {
+	if len(addr) < 2 || addr[0].String() != "local" {
+		return false
+	}
+	step, ok := addr[1].(lang.AttrStep)
+	return ok && step.Name == name
+}
+
+// nextLineStart returns the position at the start of the line
+// following the given position, or the given position
+// if it is not followed by a newline
+func nextLineStart(src []byte, pos hcl.Pos) hcl.Pos {
+	if pos.Byte < len(src) && src[pos.Byte] == '\n' {
+		return hcl.Pos{
+			Line:   pos.Line + 1,
+			Column: 1,
+			Byte:   pos.Byte + 1,
+		}
+	}
+	return pos
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactor
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestInlineLocal(t *testing.T) {
+	srcs := map[string]string{
+		"locals.tf": `locals {
+  prefix = "${var.env}-"
+  size   = var.large ? 3 : 1
+}
+`,
+		"main.tf": `resource "aws_instance" "web" {
+  count = local.size
+  tags  = { Name = "${local.prefix}web" }
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	origins := reference.Origins{
+		reference.LocalOrigin{
+			Addr: lang.Address{lang.RootStep{Name: "local"}, lang.AttrStep{Name: "size"}},
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 2, Column: 11, Byte: 42},
+				End:      hcl.Pos{Line: 2, Column: 21, Byte: 52},
+			},
+		},
+		reference.LocalOrigin{
+			Addr: lang.Address{lang.RootStep{Name: "local"}, lang.AttrStep{Name: "prefix"}},
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 3, Column: 23, Byte: 74},
+				End:      hcl.Pos{Line: 3, Column: 35, Byte: 86},
+			},
+		},
+	}
+
+	// cursor on the reference to local.size
+	edits, name, ok := InlineLocal(files, origins, "main.tf", hcl.Pos{Line: 2, Column: 15, Byte: 46})
+	if !ok {
+		t.Fatal("expected inlining")
+	}
+	if name != "size" {
+		t.Fatalf("unexpected name: %q", name)
+	}
+
+	expectedSrcs := map[string]string{
+		"locals.tf": `locals {
+  prefix = "${var.env}-"
+}
+`,
+		"main.tf": `resource "aws_instance" "web" {
+  count = (var.large ? 3 : 1)
+  tags  = { Name = "${local.prefix}web" }
+}
+`,
+	}
+	if diff := cmp.Diff(expectedSrcs, applyEdits(srcs, edits)); diff != "" {
+		t.Fatalf("unexpected result: %s", diff)
+	}
+}
+
+func TestInlineLocal_declaration(t *testing.T) {
+	srcs := map[string]string{
+		"locals.tf": `locals {
+  prefix = "${var.env}-"
+}
+
+locals {
+  suffix = "-web"
+}
+`,
+		"main.tf": `resource "aws_instance" "web" {
+  tags = { Name = "${local.prefix}web" }
+}
+`,
+	}
+	files := parseFiles(t, srcs)
+
+	origins := reference.Origins{
+		reference.LocalOrigin{
+			Addr: lang.Address{lang.RootStep{Name: "local"}, lang.AttrStep{Name: "prefix"}},
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 2, Column: 22, Byte: 53},
+				End:      hcl.Pos{Line: 2, Column: 34, Byte: 65},
+			},
+		},
+	}
+
+	// cursor on the name of the declaration
+	edits, name, ok := InlineLocal(files, origins, "locals.tf", hcl.Pos{Line: 2, Column: 4, Byte: 12})
+	if !ok {
+		t.Fatal("expected inlining")
+	}
+	if name != "prefix" {
+		t.Fatalf("unexpected name: %q", name)
+	}
+
+	expectedSrcs := map[string]string{
+		"locals.tf": `
+locals {
+  suffix = "-web"
+}
+`,
+		"main.tf": `resource "aws_instance" "web" {
+  tags = { Name = "${"${var.env}-"}web" }
+}
+`,
+	}
+	if diff := cmp.Diff(expectedSrcs, applyEdits(srcs, edits)); diff != "" {
+		t.Fatalf("unexpected result: %s", diff)
+	}
+}
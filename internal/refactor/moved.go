@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package refactor implements refactorings of Terraform configuration
+// which are offered as code actions, such as recording renames
+// of resources in moved blocks.
+package refactor
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	globalAst "github.com/hashicorp/terraform-ls/internal/terraform/ast"
+)
+
+// Rename represents a resource or module call renamed
+// since the last saved version of a file
+type Rename struct {
+	From string
+	To   string
+
+	// Edit inserts a moved block recording the rename
+	// right after the renamed block
+	Edit lang.TextEdit
+}
+
+// MovedBlock compares the resource or module block at the given position
+// in the current text of a file with its last saved text and returns
+// the rename if the block was renamed since.
+//
+// The previous name is found among blocks which no longer exist in the
+// current text, of the same type (and resource type), either as the only
+// such block or by the order of blocks in the file.
+func MovedBlock(saved, current []byte, filename string, pos hcl.Pos) (*Rename, bool) {
+	currentBody, ok := parseBody(current, filename)
+	if !ok {
+		return nil, false
+	}
+	savedBody, ok := parseBody(saved, filename)
+	if !ok {
+		return nil, false
+	}
+
+	var renamed *hclsyntax.Block
+	for _, block := range currentBody.Blocks {
+		if block.Range().ContainsPos(pos) {
+			renamed = block
+			break
+		}
+	}
+	if renamed == nil {
+		return nil, false
+	}
+	to, ok := globalAst.BlockAddress(renamed)
+	if !ok {
+		return nil, false
+	}
+
+	// ordinal is the position of the renamed block
+	// among blocks of the same kind
+	ordinal := -1
+	seenRenamed := false
+	currentAddrs := make(map[string]bool, 0)
+	movedFrom := make(map[string]bool, 0)
+	for _, block := range currentBody.Blocks {
+		if block.Type == "moved" {
+			if addr, ok := globalAst.AttributeAddress(block.Body.Attributes["from"]); ok {
+				movedFrom[addr] = true
+			}
+			continue
+		}
+		addr, ok := globalAst.BlockAddress(block)
+		if !ok {
+			continue
+		}
+		currentAddrs[addr] = true
+		if !seenRenamed && sameKind(block, renamed) {
+			ordinal++
+		}
+		if block == renamed {
+			seenRenamed = true
+		}
+	}
+
+	savedAddrs := make(map[string]bool, 0)
+	candidates := make([]string, 0)
+	var ordinalAddr string
+	savedOrdinal := -1
+	for _, block := range savedBody.Blocks {
+		addr, ok := globalAst.BlockAddress(block)
+		if !ok {
+			continue
+		}
+		savedAddrs[addr] = true
+		if !sameKind(block, renamed) {
+			continue
+		}
+		savedOrdinal++
+		if currentAddrs[addr] {
+			continue
+		}
+		candidates = append(candidates, addr)
+		if savedOrdinal == ordinal {
+			ordinalAddr = addr
+		}
+	}
+	if savedAddrs[to] {
+		// the block was not renamed
+		return nil, false
+	}
+
+	var from string
+	switch {
+	case len(candidates) == 1:
+		from = candidates[0]
+	case ordinalAddr != "":
+		from = ordinalAddr
+	default:
+		return nil, false
+	}
+	if movedFrom[from] {
+		return nil, false
+	}
+
+	end := renamed.Range().End
+	text := fmt.Sprintf("\n\nmoved {\n  from = %s\n  to   = %s\n}", from, to)
+	return &Rename{
+		From: from,
+		To:   to,
+		Edit: lang.TextEdit{
+			Range: hcl.Range{
+				Filename: filename,
+				Start:    end,
+				End:      end,
+			},
+			NewText: text,
+			Snippet: text,
+		},
+	}, true
+}
+
+func parseBody(src []byte, filename string) (*hclsyntax.Body, bool) {
+	f, _ := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if f == nil {
+		return nil, false
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	return body, ok
+}
+
+func sameKind(a, b *hclsyntax.Block) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	if a.Type == "resource" {
+		return len(a.Labels) > 0 && len(b.Labels) > 0 && a.Labels[0] == b.Labels[0]
+	}
+	return true
+}
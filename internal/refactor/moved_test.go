@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactor
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestMovedBlock(t *testing.T) {
+	testCases := []struct {
+		name          string
+		saved         string
+		current       string
+		pos           hcl.Pos
+		expectedFound bool
+		expectedFrom  string
+		expectedText  string
+	}{
+		{
+			"renamed resource",
+			`resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+			`resource "aws_instance" "app" {
+  ami = "ami-123"
+}
+`,
+			hcl.Pos{Line: 1, Column: 27, Byte: 26},
+			true,
+			"aws_instance.web",
+			"\n\nmoved {\n  from = aws_instance.web\n  to   = aws_instance.app\n}",
+		},
+		{
+			"renamed module call",
+			`module "vpc" {
+  source = "./vpc"
+}
+`,
+			`module "network" {
+  source = "./vpc"
+}
+`,
+			hcl.Pos{Line: 2, Column: 3, Byte: 21},
+			true,
+			"module.vpc",
+			"\n\nmoved {\n  from = module.vpc\n  to   = module.network\n}",
+		},
+		{
+			"renamed by order among multiple resources",
+			`resource "aws_instance" "a" {}
+resource "aws_instance" "b" {}
+`,
+			`resource "aws_instance" "x" {}
+resource "aws_instance" "y" {}
+`,
+			hcl.Pos{Line: 2, Column: 2, Byte: 32},
+			true,
+			"aws_instance.b",
+			"\n\nmoved {\n  from = aws_instance.b\n  to   = aws_instance.y\n}",
+		},
+		{
+			"not renamed",
+			`resource "aws_instance" "web" {}
+`,
+			`resource "aws_instance" "web" {}
+`,
+			hcl.Pos{Line: 1, Column: 2, Byte: 1},
+			false,
+			"",
+			"",
+		},
+		{
+			"different resource type",
+			`resource "aws_instance" "web" {}
+`,
+			`resource "aws_eip" "web" {}
+`,
+			hcl.Pos{Line: 1, Column: 2, Byte: 1},
+			false,
+			"",
+			"",
+		},
+		{
+			"already moved",
+			`resource "aws_instance" "web" {}
+`,
+			`resource "aws_instance" "app" {}
+
+moved {
+  from = aws_instance.web
+  to   = aws_instance.app
+}
+`,
+			hcl.Pos{Line: 1, Column: 2, Byte: 1},
+			false,
+			"",
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rename, ok := MovedBlock([]byte(tc.saved), []byte(tc.current), "main.tf", tc.pos)
+			if ok != tc.expectedFound {
+				t.Fatalf("expected found: %t, given: %t", tc.expectedFound, ok)
+			}
+			if !ok {
+				return
+			}
+			if rename.From != tc.expectedFrom {
+				t.Fatalf("expected from: %q, given: %q", tc.expectedFrom, rename.From)
+			}
+			if diff := cmp.Diff(tc.expectedText, rename.Edit.NewText); diff != "" {
+				t.Fatalf("unexpected text: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rules
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+type ReadOnlyFS interface {
+	ReadFile(name string) ([]byte, error)
+}
+
+// Filter applies configured rule severities and
+// suppression comments to diagnostics of files
+type Filter struct {
+	fs ReadOnlyFS
+
+	severitiesMu sync.RWMutex
+	severities   map[ID]Severity
+}
+
+func NewFilter(fs ReadOnlyFS) *Filter {
+	return &Filter{
+		fs:         fs,
+		severities: make(map[ID]Severity),
+	}
+}
+
+// SetSeverities replaces the severities configured per rule
+func (f *Filter) SetSeverities(severities map[ID]Severity) {
+	f.severitiesMu.Lock()
+	defer f.severitiesMu.Unlock()
+
+	f.severities = severities
+}
+
+func (f *Filter) severity(id ID) (Severity, bool) {
+	f.severitiesMu.RLock()
+	defer f.severitiesMu.RUnlock()
+
+	sev, ok := f.severities[id]
+	return sev, ok
+}
+
+// FileFilter applies rules to diagnostics of a single file
+type FileFilter struct {
+	filter   *Filter
+	filePath string
+
+	suppressions       Suppressions
+	suppressionsLoaded bool
+}
+
+// ForFile returns a filter for diagnostics of the file at the given path.
+// The file is only read once diagnostics produced by any rule are applied.
+func (f *Filter) ForFile(filePath string) *FileFilter {
+	return &FileFilter{
+		filter:   f,
+		filePath: filePath,
+	}
+}
+
+// Apply returns the given diagnostics without those which are suppressed
+// or turned off, with severities configured for their rules applied
+func (ff *FileFilter) Apply(diags hcl.Diagnostics) hcl.Diagnostics {
+	filtered := make(hcl.Diagnostics, 0, len(diags))
+	for _, diag := range diags {
+		id, ok := RuleID(diag)
+		if !ok {
+			filtered = append(filtered, diag)
+			continue
+		}
+
+		if ff.loadSuppressions().IsSuppressed(diag) {
+			continue
+		}
+
+		severity, ok := ff.filter.severity(id)
+		if !ok {
+			filtered = append(filtered, diag)
+			continue
+		}
+		if severity == SeverityOff {
+			continue
+		}
+		filtered = append(filtered, WithSeverity(diag, severity))
+	}
+	return filtered
+}
+
+func (ff *FileFilter) loadSuppressions() Suppressions {
+	if ff.suppressionsLoaded {
+		return ff.suppressions
+	}
+	ff.suppressionsLoaded = true
+
+	// comments are not supported in JSON
+	if strings.HasSuffix(ff.filePath, ".json") {
+		return ff.suppressions
+	}
+	src, err := ff.filter.fs.ReadFile(ff.filePath)
+	if err != nil {
+		return ff.suppressions
+	}
+	ff.suppressions = ParseSuppressions(filepath.Base(ff.filePath), src)
+
+	return ff.suppressions
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+)
+
+type osFS struct{}
+
+func (osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func TestFileFilter_Apply(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "main.tf")
+	err := os.WriteFile(filePath, []byte(`output "a" {
+  value = var.a # terraform-ls:ignore unreferenced-origin
+}
+output "b" {
+  value = var.b
+  foo   = "bar"
+  old   = "baz"
+}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diagAtLine := func(id ID, line int) *hcl.Diagnostic {
+		diag := &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  string(id),
+			Subject: &hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: line, Column: 3},
+				End:      hcl.Pos{Line: line, Column: 8},
+			},
+		}
+		if id != "" {
+			diag.Extra = id
+		}
+		return diag
+	}
+
+	f := NewFilter(osFS{})
+	f.SetSeverities(map[ID]Severity{
+		UnexpectedAttribute: SeverityOff,
+		DeprecatedAttribute: SeverityHint,
+	})
+
+	diags := f.ForFile(filePath).Apply(hcl.Diagnostics{
+		diagAtLine(UnreferencedOrigin, 2),
+		diagAtLine(UnreferencedOrigin, 5),
+		diagAtLine(UnexpectedAttribute, 6),
+		diagAtLine(DeprecatedAttribute, 7),
+		diagAtLine("", 7),
+	})
+
+	type summary struct {
+		Summary  string
+		Severity Severity
+	}
+	summaries := make([]summary, 0, len(diags))
+	for _, diag := range diags {
+		severity, _ := ConfiguredSeverity(diag)
+		summaries = append(summaries, summary{diag.Summary, severity})
+	}
+	expectedSummaries := []summary{
+		{string(UnreferencedOrigin), ""},
+		{string(DeprecatedAttribute), SeverityHint},
+		{"", ""},
+	}
+	if diff := cmp.Diff(expectedSummaries, summaries); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+
+	id, ok := RuleID(diags[1])
+	if !ok || id != DeprecatedAttribute {
+		t.Fatalf("expected rule ID to be kept, given: %q", id)
+	}
+}
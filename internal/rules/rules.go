@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package rules identifies validation rules behind diagnostics,
+// so that their severity can be configured and they can be
+// suppressed via inline comments.
+package rules
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// ID identifies a validation rule. It is attached to diagnostics
+// via [hcl.Diagnostic.Extra] and sent to clients as the diagnostic code.
+type ID string
+
+const (
+	BlockLabelsLength        ID = "block-labels-length"
+	DeprecatedAttribute      ID = "deprecated-attribute"
+	DeprecatedBlock          ID = "deprecated-block"
+	MaxBlocks                ID = "max-blocks"
+	MinBlocks                ID = "min-blocks"
+	MissingRequiredAttribute ID = "missing-required-attribute"
+	UnexpectedAttribute      ID = "unexpected-attribute"
+	UnexpectedBlock          ID = "unexpected-block"
+
+	UnreferencedOrigin  ID = "unreferenced-origin"
+	DependencyCycle     ID = "dependency-cycle"
+	ImportMovedBlocks   ID = "import-moved-blocks"
+	BackendConfig       ID = "backend-configuration"
+	ModuleSourceAddress ID = "module-source-address"
+	ModuleInstallation  ID = "module-installation"
+	ComponentInputs     ID = "component-inputs"
+	BlockName           ID = "block-name"
+)
+
+func (id ID) String() string {
+	return string(id)
+}
+
+// Severity is the severity configured for a rule
+type Severity string
+
+const (
+	SeverityOff     Severity = "off"
+	SeverityHint    Severity = "hint"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(s); sev {
+	case SeverityOff, SeverityHint, SeverityWarning, SeverityError:
+		return sev, nil
+	}
+	return "", fmt.Errorf("expected severity to be one of %q, %q, %q or %q, got %q",
+		SeverityOff, SeverityHint, SeverityWarning, SeverityError, s)
+}
+
+// RuleID returns the ID of the rule which produced the given diagnostic
+func RuleID(diag *hcl.Diagnostic) (ID, bool) {
+	return hcl.DiagnosticExtra[ID](diag)
+}
+
+// Tag attaches the given rule ID to all diagnostics
+// which don't carry any extra information yet
+func Tag(id ID, diags hcl.Diagnostics) hcl.Diagnostics {
+	for _, diag := range diags {
+		if diag.Extra == nil {
+			diag.Extra = id
+		}
+	}
+	return diags
+}
+
+// severityExtra overrides the severity of a diagnostic,
+// wrapping any other extra information
+type severityExtra struct {
+	severity Severity
+	wrapped  interface{}
+}
+
+func (e severityExtra) UnwrapDiagnosticExtra() interface{} {
+	return e.wrapped
+}
+
+// ConfiguredSeverity returns the severity configured
+// for the rule which produced the given diagnostic, if any
+func ConfiguredSeverity(diag *hcl.Diagnostic) (Severity, bool) {
+	e, ok := hcl.DiagnosticExtra[severityExtra](diag)
+	return e.severity, ok
+}
+
+// WithSeverity returns a copy of the given diagnostic with the
+// given severity, leaving the original (e.g. stored in state) untouched
+func WithSeverity(diag *hcl.Diagnostic, severity Severity) *hcl.Diagnostic {
+	d := *diag
+	d.Extra = severityExtra{
+		severity: severity,
+		wrapped:  diag.Extra,
+	}
+	switch severity {
+	case SeverityError:
+		d.Severity = hcl.DiagError
+	default:
+		// HCL has no notion of hints, which is why
+		// the severity is kept as extra information
+		d.Severity = hcl.DiagWarning
+	}
+	return &d
+}
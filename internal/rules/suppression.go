@@ -0,0 +1,218 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rules
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// IgnoreDirective starts comments which suppress diagnostics
+// of the listed rules (or all rules, if none are listed), e.g.
+//
+//	# terraform-ls:ignore unreferenced-origin
+const IgnoreDirective = "terraform-ls:ignore"
+
+// SuppressionComment returns a comment suppressing the given rule
+func SuppressionComment(id ID) string {
+	return "# " + IgnoreDirective + " " + id.String()
+}
+
+type suppression struct {
+	startLine int
+	endLine   int
+	// ids is nil if all rules are suppressed
+	ids map[ID]bool
+}
+
+// Suppressions represents suppression comments within a file
+type Suppressions []suppression
+
+// ParseSuppressions finds suppression comments in the given file
+// (in native syntax). A comment following code on the same line
+// applies to that line. A comment on its own line applies
+// to the next line, or the whole block or attribute starting there.
+func ParseSuppressions(filename string, src []byte) Suppressions {
+	suppressions := make(Suppressions, 0)
+
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	var body *hclsyntax.Body
+	if f, _ := hclsyntax.ParseConfig(src, filename, hcl.InitialPos); f != nil {
+		body, _ = f.Body.(*hclsyntax.Body)
+	}
+
+	lastCodeLine := 0
+	for i, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenNewline, hclsyntax.TokenEOF:
+			continue
+		case hclsyntax.TokenComment:
+		default:
+			lastCodeLine = token.Range.End.Line
+			continue
+		}
+
+		ids, ok := parseIgnoreDirective(string(token.Bytes))
+		if !ok {
+			continue
+		}
+
+		line := token.Range.Start.Line
+		if lastCodeLine == line {
+			suppressions = append(suppressions, suppression{
+				startLine: line,
+				endLine:   line,
+				ids:       ids,
+			})
+			continue
+		}
+
+		nextLine, ok := nextCodeLine(tokens[i+1:])
+		if !ok {
+			continue
+		}
+		endLine := nextLine
+		if body != nil {
+			if rng, ok := nodeRangeStartingAt(body, nextLine); ok {
+				endLine = rng.End.Line
+			}
+		}
+		suppressions = append(suppressions, suppression{
+			startLine: nextLine,
+			endLine:   endLine,
+			ids:       ids,
+		})
+	}
+
+	return suppressions
+}
+
+// IsSuppressed reports whether the given diagnostic
+// is produced by a rule which is suppressed where it is reported
+func (s Suppressions) IsSuppressed(diag *hcl.Diagnostic) bool {
+	id, ok := RuleID(diag)
+	if !ok || diag.Subject == nil {
+		return false
+	}
+
+	line := diag.Subject.Start.Line
+	for _, sup := range s {
+		if line < sup.startLine || line > sup.endLine {
+			continue
+		}
+		if sup.ids == nil || sup.ids[id] {
+			return true
+		}
+	}
+	return false
+}
+
+func parseIgnoreDirective(comment string) (map[ID]bool, bool) {
+	comment = strings.TrimSpace(comment)
+	switch {
+	case strings.HasPrefix(comment, "#"):
+		comment = strings.TrimPrefix(comment, "#")
+	case strings.HasPrefix(comment, "//"):
+		comment = strings.TrimPrefix(comment, "//")
+	case strings.HasPrefix(comment, "/*"):
+		comment = strings.TrimSuffix(strings.TrimPrefix(comment, "/*"), "*/")
+	}
+	comment = strings.TrimSpace(comment)
+
+	rest, ok := strings.CutPrefix(comment, IgnoreDirective)
+	if !ok || (rest != "" && !unicode.IsSpace(rune(rest[0]))) {
+		return nil, false
+	}
+
+	fields := strings.FieldsFunc(rest, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if len(fields) == 0 {
+		return nil, true
+	}
+
+	ids := make(map[ID]bool, len(fields))
+	for _, field := range fields {
+		ids[ID(field)] = true
+	}
+	return ids, true
+}
+
+func nextCodeLine(tokens hclsyntax.Tokens) (int, bool) {
+	for _, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenNewline, hclsyntax.TokenComment:
+			continue
+		case hclsyntax.TokenEOF:
+			return 0, false
+		}
+		return token.Range.Start.Line, true
+	}
+	return 0, false
+}
+
+// nodeRangeStartingAt returns the range of the outermost
+// attribute or block which starts at the given line
+func nodeRangeStartingAt(body *hclsyntax.Body, line int) (hcl.Range, bool) {
+	for _, attr := range body.Attributes {
+		if attr.SrcRange.Start.Line == line {
+			return attr.SrcRange, true
+		}
+	}
+	for _, block := range body.Blocks {
+		rng := block.Range()
+		if rng.Start.Line == line {
+			return rng, true
+		}
+		if line > rng.Start.Line && line <= rng.End.Line {
+			return nodeRangeStartingAt(block.Body, line)
+		}
+	}
+	return hcl.Range{}, false
+}
+
+// SuppressionLine returns the line above which a suppression comment
+// for a diagnostic reported on the given line should be inserted,
+// i.e. the first line of the innermost attribute or block enclosing it.
+// This avoids inserting comments into multi-line expressions, such as heredocs.
+func SuppressionLine(filename string, src []byte, line int) int {
+	f, _ := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if f == nil {
+		return line
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return line
+	}
+	if rng, ok := enclosingNodeRange(body, line); ok {
+		return rng.Start.Line
+	}
+	return line
+}
+
+// enclosingNodeRange returns the range of the innermost
+// attribute or block which contains the given line
+func enclosingNodeRange(body *hclsyntax.Body, line int) (hcl.Range, bool) {
+	for _, attr := range body.Attributes {
+		if line >= attr.SrcRange.Start.Line && line <= attr.SrcRange.End.Line {
+			return attr.SrcRange, true
+		}
+	}
+	for _, block := range body.Blocks {
+		rng := block.Range()
+		if line < rng.Start.Line || line > rng.End.Line {
+			continue
+		}
+		if line > rng.Start.Line {
+			if inner, ok := enclosingNodeRange(block.Body, line); ok {
+				return inner, true
+			}
+		}
+		return rng, true
+	}
+	return hcl.Range{}, false
+}
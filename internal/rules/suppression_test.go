@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestSuppressions_IsSuppressed(t *testing.T) {
+	src := []byte(`# terraform-ls:ignore unreferenced-origin
+resource "aws_instance" "app" {
+  ami = var.ami
+  foo = var.foo
+
+  # terraform-ls:ignore
+  tags = {
+    Name = local.name
+  }
+}
+
+output "ip" {
+  value = aws_instance.web.public_ip # terraform-ls:ignore unreferenced-origin, unexpected-attribute
+  bar   = var.bar
+}
+
+// terraform-ls:ignore deprecated-attribute
+output "dns" {
+  value = var.dns
+}
+`)
+	s := ParseSuppressions("main.tf", src)
+
+	testCases := []struct {
+		id                 ID
+		line               int
+		expectedSuppressed bool
+	}{
+		{UnreferencedOrigin, 3, true},
+		{UnreferencedOrigin, 4, true},
+		{UnexpectedAttribute, 4, false},
+		{UnexpectedAttribute, 8, true},
+		{UnreferencedOrigin, 13, true},
+		{UnexpectedAttribute, 13, true},
+		{UnreferencedOrigin, 14, false},
+		{UnreferencedOrigin, 19, false},
+		{DeprecatedAttribute, 19, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s:%d", tc.id, tc.line), func(t *testing.T) {
+			diag := &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Test",
+				Subject: &hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: tc.line, Column: 3},
+					End:      hcl.Pos{Line: tc.line, Column: 10},
+				},
+				Extra: tc.id,
+			}
+			suppressed := s.IsSuppressed(diag)
+			if suppressed != tc.expectedSuppressed {
+				t.Fatalf("expected suppressed: %t, given: %t", tc.expectedSuppressed, suppressed)
+			}
+		})
+	}
+}
+
+func TestSuppressions_IsSuppressed_noRule(t *testing.T) {
+	s := ParseSuppressions("main.tf", []byte(`# terraform-ls:ignore
+foo = bar
+`))
+	diag := &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid expression",
+		Subject: &hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 2, Column: 1},
+			End:      hcl.Pos{Line: 2, Column: 4},
+		},
+	}
+	if s.IsSuppressed(diag) {
+		t.Fatal("expected diagnostic without rule not to be suppressed")
+	}
+}
+
+func TestSuppressionLine(t *testing.T) {
+	src := []byte(`resource "aws_instance" "app" {
+  ami = var.ami
+  user_data = <<EOT
+echo ${var.foo}
+EOT
+  tags = {
+    Name = local.name
+  }
+
+  lifecycle {
+    ignore_changes = [tags]
+  }
+}
+`)
+
+	testCases := []struct {
+		line         int
+		expectedLine int
+	}{
+		{1, 1},
+		{2, 2},
+		{4, 3},
+		{7, 6},
+		{9, 1},
+		{10, 10},
+		{11, 11},
+		{20, 20},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d", tc.line), func(t *testing.T) {
+			line := SuppressionLine("main.tf", src, tc.line)
+			if line != tc.expectedLine {
+				t.Fatalf("expected line %d, given: %d", tc.expectedLine, line)
+			}
+		})
+	}
+
+	// the suppression comment applies to the diagnostic
+	lines := strings.Split(string(src), "\n")
+	lines = append(lines[:2], append([]string{SuppressionComment(UnreferencedOrigin)}, lines[2:]...)...)
+	s := ParseSuppressions("main.tf", []byte(strings.Join(lines, "\n")))
+	diag := &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Test",
+		Subject: &hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 5, Column: 8},
+			End:      hcl.Pos{Line: 5, Column: 15},
+		},
+		Extra: UnreferencedOrigin,
+	}
+	if !s.IsSuppressed(diag) {
+		t.Fatal("expected diagnostic within heredoc to be suppressed")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rules
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type ruleValidator struct {
+	id        ID
+	validator validator.Validator
+}
+
+// Validator wraps the given validator, such that all diagnostics
+// it produces are attributed to the rule with the given ID
+func Validator(id ID, v validator.Validator) validator.Validator {
+	return ruleValidator{
+		id:        id,
+		validator: v,
+	}
+}
+
+func (v ruleValidator) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	ctx, diags := v.validator.Visit(ctx, node, nodeSchema)
+	return ctx, Tag(v.id, diags)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package scaffold generates skeletons of configuration blocks
+// from their schema.
+package scaffold
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ResourceBlock returns a resource block of the given type and name
+// containing all required attributes and nested blocks (as many
+// as the minimum number of items), with placeholder values.
+//
+// If includeOptional is true, optional attributes are included too,
+// but commented out.
+func ResourceBlock(rType, name string, body *schema.BodySchema, includeOptional bool) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "resource %q %q {\n", rType, name)
+	writeBody(&sb, body, includeOptional, 1)
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func writeBody(sb *strings.Builder, body *schema.BodySchema, includeOptional bool, indent int) {
+	if body == nil {
+		return
+	}
+
+	required := make([]string, 0)
+	optional := make([]string, 0)
+	for name, attr := range body.Attributes {
+		switch {
+		case attr.IsRequired:
+			required = append(required, name)
+		case attr.IsOptional && !attr.IsDeprecated:
+			optional = append(optional, name)
+		}
+	}
+	sort.Strings(required)
+	sort.Strings(optional)
+
+	blocks := make([]string, 0)
+	for name, block := range body.Blocks {
+		if block.MinItems > 0 {
+			blocks = append(blocks, name)
+		}
+	}
+	sort.Strings(blocks)
+
+	needsSeparator := false
+	if len(required) > 0 {
+		writeAttributes(sb, body, required, "", indent)
+		needsSeparator = true
+	}
+	if includeOptional && len(optional) > 0 {
+		if needsSeparator {
+			sb.WriteString("\n")
+		}
+		writeAttributes(sb, body, optional, "# ", indent)
+		needsSeparator = true
+	}
+	for _, name := range blocks {
+		block := body.Blocks[name]
+		for i := uint64(0); i < block.MinItems; i++ {
+			if needsSeparator {
+				sb.WriteString("\n")
+			}
+			writeIndent(sb, indent)
+			sb.WriteString(name)
+			sb.WriteString(" {\n")
+			writeBody(sb, block.Body, includeOptional, indent+1)
+			writeIndent(sb, indent)
+			sb.WriteString("}\n")
+			needsSeparator = true
+		}
+	}
+}
+
+// writeAttributes writes the given attributes with aligned
+// equals signs, as terraform fmt would
+func writeAttributes(sb *strings.Builder, body *schema.BodySchema, names []string, prefix string, indent int) {
+	maxLen := 0
+	for _, name := range names {
+		if len(name) > maxLen {
+			maxLen = len(name)
+		}
+	}
+
+	for _, name := range names {
+		writeIndent(sb, indent)
+		sb.WriteString(prefix)
+		sb.WriteString(name)
+		sb.WriteString(strings.Repeat(" ", maxLen-len(name)))
+		sb.WriteString(" = ")
+		sb.WriteString(placeholder(body.Attributes[name].Constraint))
+		sb.WriteString("\n")
+	}
+}
+
+// placeholder returns an empty value matching the given constraint
+func placeholder(cons schema.Constraint) string {
+	switch c := cons.(type) {
+	case schema.AnyExpression:
+		return placeholderForType(c.OfType)
+	case schema.LiteralType:
+		return placeholderForType(c.Type)
+	case schema.OneOf:
+		if len(c) > 0 {
+			return placeholder(c[0])
+		}
+	case schema.List, schema.Set, schema.Tuple:
+		return "[]"
+	case schema.Map, schema.Object:
+		return "{}"
+	}
+	return "null"
+}
+
+func placeholderForType(typ cty.Type) string {
+	switch {
+	case typ == cty.String:
+		return `""`
+	case typ == cty.Number:
+		return "0"
+	case typ == cty.Bool:
+		return "false"
+	case typ.IsListType(), typ.IsSetType(), typ.IsTupleType():
+		return "[]"
+	case typ.IsMapType(), typ.IsObjectType():
+		return "{}"
+	}
+	return "null"
+}
+
+func writeIndent(sb *strings.Builder, indent int) {
+	sb.WriteString(strings.Repeat("  ", indent))
+}
@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package scaffold
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var bucketSchema = &schema.BodySchema{
+	Attributes: map[string]*schema.AttributeSchema{
+		"id": {
+			Constraint: schema.AnyExpression{OfType: cty.String},
+			IsComputed: true,
+		},
+		"bucket": {
+			Constraint: schema.AnyExpression{OfType: cty.String},
+			IsRequired: true,
+		},
+		"force_destroy": {
+			Constraint: schema.AnyExpression{OfType: cty.Bool},
+			IsOptional: true,
+		},
+		"tags": {
+			Constraint: schema.OneOf{
+				schema.AnyExpression{OfType: cty.Map(cty.String), SkipLiteralComplexTypes: true},
+				schema.Map{Elem: schema.AnyExpression{OfType: cty.String}},
+			},
+			IsOptional: true,
+		},
+		"acl": {
+			Constraint:   schema.AnyExpression{OfType: cty.String},
+			IsOptional:   true,
+			IsDeprecated: true,
+		},
+	},
+	Blocks: map[string]*schema.BlockSchema{
+		"rule": {
+			MinItems: 1,
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"days": {
+						Constraint: schema.AnyExpression{OfType: cty.Number},
+						IsRequired: true,
+					},
+				},
+			},
+		},
+		"website": {
+			Body: &schema.BodySchema{},
+		},
+	},
+}
+
+func TestResourceBlock(t *testing.T) {
+	testCases := []struct {
+		name            string
+		includeOptional bool
+		expectedBlock   string
+	}{
+		{
+			"required only",
+			false,
+			`resource "aws_s3_bucket" "this" {
+  bucket = ""
+
+  rule {
+    days = 0
+  }
+}
+`,
+		},
+		{
+			"with optional",
+			true,
+			`resource "aws_s3_bucket" "this" {
+  bucket = ""
+
+  # force_destroy = false
+  # tags          = {}
+
+  rule {
+    days = 0
+  }
+}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			block := ResourceBlock("aws_s3_bucket", "this", bucketSchema, tc.includeOptional)
+			if diff := cmp.Diff(tc.expectedBlock, block); diff != "" {
+				t.Fatalf("unexpected block: %s", diff)
+			}
+		})
+	}
+}
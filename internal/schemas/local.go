@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schemas
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+)
+
+// LocalFS provides provider schemas from local directories laid out
+// like the embedded schemas, i.e.
+// <hostname>/<namespace>/<type>/<version>/schema.json.gz,
+// falling back to the embedded schemas.
+//
+// Directories can be changed at any time, which affects
+// schemas looked up afterwards.
+type LocalFS struct {
+	mu   sync.RWMutex
+	dirs []fs.FS
+}
+
+func NewLocalFS() *LocalFS {
+	return &LocalFS{}
+}
+
+// SetDirs sets the directories to look up schemas in (in order)
+func (lfs *LocalFS) SetDirs(dirs []string) {
+	fss := make([]fs.FS, 0, len(dirs))
+	for _, dir := range dirs {
+		fss = append(fss, os.DirFS(dir))
+	}
+
+	lfs.mu.Lock()
+	defer lfs.mu.Unlock()
+	lfs.dirs = fss
+}
+
+func (lfs *LocalFS) Open(name string) (fs.File, error) {
+	for _, dirFS := range lfs.localDirs() {
+		f, err := dirFS.Open(localName(name))
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return FS.Open(name)
+}
+
+func (lfs *LocalFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	for _, dirFS := range lfs.localDirs() {
+		entries, err := fs.ReadDir(dirFS, localName(name))
+		if err == nil {
+			return entries, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return FS.ReadDir(name)
+}
+
+func (lfs *LocalFS) localDirs() []fs.FS {
+	lfs.mu.RLock()
+	defer lfs.mu.RUnlock()
+	return lfs.dirs
+}
+
+// localName returns the name of the given embedded file
+// relative to a local directory, which has no data directory
+func localName(name string) string {
+	if name == "data" {
+		return "."
+	}
+	return strings.TrimPrefix(name, "data/")
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package schemas
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+)
+
+func TestLocalFS_FindProviderSchemaFile(t *testing.T) {
+	dir := t.TempDir()
+	providerDir := filepath.Join(dir, "registry.terraform.io", "example-corp", "internal", "1.2.0")
+	err := os.MkdirAll(providerDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(providerDir, "schema.json.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzw := gzip.NewWriter(f)
+	_, err = gzw.Write([]byte(`{"format_version": "1.0"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzw.Close()
+	f.Close()
+
+	pAddr := tfaddr.MustParseProviderSource("example-corp/internal")
+
+	lfs := NewLocalFS()
+	_, err = FindProviderSchemaFile(lfs, pAddr)
+	if !errors.Is(err, SchemaNotAvailable{Addr: pAddr}) {
+		t.Fatalf("expected schema to be unavailable without directories, given: %v", err)
+	}
+
+	lfs.SetDirs([]string{dir})
+	ps, err := FindProviderSchemaFile(lfs, pAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps.Version.String() != "1.2.0" {
+		t.Fatalf("unexpected version: %s", ps.Version)
+	}
+	b, err := io.ReadAll(ps.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"format_version": "1.0"}` {
+		t.Fatalf("unexpected content: %s", b)
+	}
+}
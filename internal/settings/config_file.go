@@ -0,0 +1,230 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package settings
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/terraform-ls/internal/terraform/datadir"
+)
+
+const (
+	// ConfigFileName is the name of the (checked-in) file carrying
+	// project-specific settings at the root of a workspace folder
+	ConfigFileName = ".terraform-ls.hcl"
+	// ConfigFileJSONName is the name of the same file in JSON syntax
+	ConfigFileJSONName = ".terraform-ls.json"
+)
+
+// ConfigFile represents settings read from a config file, e.g.
+//
+//	indexing {
+//	  ignore_paths           = ["vendor"]
+//	  ignore_directory_names = [".cache"]
+//	}
+//
+//	validation {
+//	  enable_enhanced_validation = true
+//	  policy_files               = ["policies/tagging.hcl"]
+//	}
+//
+//	var_file "envs/*.tfvars" {
+//	  module = "."
+//	}
+//
+//	rule "unreferenced-origin" {
+//	  severity = "hint"
+//	}
+//
+//	schema_dirs = ["schemas"]
+//
+// Relative paths are resolved against the directory of the file.
+type ConfigFile struct {
+	// Dir is the directory the file was read from
+	Dir string
+
+	Indexing   *ConfigIndexing   `hcl:"indexing,block"`
+	Validation *ConfigValidation `hcl:"validation,block"`
+	VarFiles   []ConfigVarFile   `hcl:"var_file,block"`
+	Rules      []ConfigRule      `hcl:"rule,block"`
+	SchemaDirs []string          `hcl:"schema_dirs,optional"`
+}
+
+type ConfigIndexing struct {
+	IgnoreDirectoryNames []string `hcl:"ignore_directory_names,optional"`
+	IgnorePaths          []string `hcl:"ignore_paths,optional"`
+}
+
+type ConfigValidation struct {
+	EnableEnhancedValidation *bool    `hcl:"enable_enhanced_validation,optional"`
+	PolicyFiles              []string `hcl:"policy_files,optional"`
+}
+
+type ConfigVarFile struct {
+	Path   string `hcl:"path,label"`
+	Module string `hcl:"module"`
+}
+
+type ConfigRule struct {
+	ID       string `hcl:"id,label"`
+	Severity string `hcl:"severity"`
+}
+
+// IsConfigFile reports whether the given path is a config file
+func IsConfigFile(path string) bool {
+	name := filepath.Base(path)
+	return name == ConfigFileName || name == ConfigFileJSONName
+}
+
+// ConfigFilePatternsForWatching returns patterns of config files
+func ConfigFilePatternsForWatching() []datadir.WatchPattern {
+	return []datadir.WatchPattern{
+		{
+			Pattern:   "**/" + ConfigFileName,
+			EventType: datadir.AnyEventType,
+		},
+		{
+			Pattern:   "**/" + ConfigFileJSONName,
+			EventType: datadir.AnyEventType,
+		},
+	}
+}
+
+// LoadConfigFile reads the config file in the given directory,
+// preferring the native syntax over JSON if both exist.
+//
+// It returns nil (and no error) if there is no config file.
+func LoadConfigFile(dir string) (*ConfigFile, error) {
+	parser := hclparse.NewParser()
+
+	for _, name := range []string{ConfigFileName, ConfigFileJSONName} {
+		path := filepath.Join(dir, name)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+
+		var f *hcl.File
+		var diags hcl.Diagnostics
+		if name == ConfigFileJSONName {
+			f, diags = parser.ParseJSON(src, path)
+		} else {
+			f, diags = parser.ParseHCL(src, path)
+		}
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		cfg := &ConfigFile{Dir: dir}
+		diags = gohcl.DecodeBody(f.Body, nil, cfg)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return cfg, nil
+	}
+
+	return nil, nil
+}
+
+// WithConfigFiles returns the options with settings from the given
+// config files applied, unless the same settings were passed by the client
+// explicitly, as these take precedence. Lists from all files are combined.
+//
+// Empty or default values passed by the client are treated as unset,
+// as clients commonly pass all their settings, including defaults.
+func (do *DecodedOptions) WithConfigFiles(files []*ConfigFile) *Options {
+	opts := *do.Options
+	isPassed := make(map[string]bool, len(do.Keys))
+	for _, key := range do.Keys {
+		isPassed[key] = true
+	}
+	isSet := func(key string, isDefault bool) bool {
+		return isPassed[key] && !isDefault
+	}
+
+	var ignorePaths, ignoreDirNames, policyFiles, schemaDirs []string
+	var varFiles []VarFile
+	var enhancedValidation *bool
+	ruleSeverities := make(map[string]string)
+	for _, f := range files {
+		if f.Indexing != nil {
+			for _, path := range f.Indexing.IgnorePaths {
+				ignorePaths = append(ignorePaths, f.resolvePath(path))
+			}
+			ignoreDirNames = append(ignoreDirNames, f.Indexing.IgnoreDirectoryNames...)
+		}
+		if f.Validation != nil && f.Validation.EnableEnhancedValidation != nil && enhancedValidation == nil {
+			enhancedValidation = f.Validation.EnableEnhancedValidation
+		}
+		if f.Validation != nil {
+			for _, path := range f.Validation.PolicyFiles {
+				policyFiles = append(policyFiles, f.resolvePath(path))
+			}
+		}
+		for _, vf := range f.VarFiles {
+			varFiles = append(varFiles, VarFile{
+				Path:   f.resolvePath(vf.Path),
+				Module: f.resolvePath(vf.Module),
+			})
+		}
+		for _, dir := range f.SchemaDirs {
+			schemaDirs = append(schemaDirs, f.resolvePath(dir))
+		}
+		for _, rule := range f.Rules {
+			if _, ok := ruleSeverities[rule.ID]; !ok {
+				ruleSeverities[rule.ID] = rule.Severity
+			}
+		}
+	}
+
+	if !isSet("indexing.ignorePaths", len(opts.Indexing.IgnorePaths) == 0) && len(ignorePaths) > 0 {
+		opts.Indexing.IgnorePaths = ignorePaths
+	}
+	if !isSet("indexing.ignoreDirectoryNames", len(opts.Indexing.IgnoreDirectoryNames) == 0) && len(ignoreDirNames) > 0 {
+		opts.Indexing.IgnoreDirectoryNames = ignoreDirNames
+	}
+	// Enhanced validation is enabled by default
+	if !isSet("validation.enableEnhancedValidation", opts.Validation.EnableEnhancedValidation) && enhancedValidation != nil {
+		opts.Validation.EnableEnhancedValidation = *enhancedValidation
+	}
+	if !isSet("validation.policyFiles", len(opts.Validation.PolicyFiles) == 0) && len(policyFiles) > 0 {
+		opts.Validation.PolicyFiles = policyFiles
+	}
+	if !isSet("varFiles", len(opts.VarFiles) == 0) && len(varFiles) > 0 {
+		opts.VarFiles = varFiles
+	}
+	if !isSet("schemaDirs", len(opts.SchemaDirs) == 0) && len(schemaDirs) > 0 {
+		opts.SchemaDirs = schemaDirs
+	}
+	if len(ruleSeverities) > 0 {
+		// Rules are merged one by one, so that the client
+		// can override the severity of individual rules
+		merged := make(map[string]string, len(ruleSeverities)+len(opts.Validation.Rules))
+		for id, severity := range ruleSeverities {
+			merged[id] = severity
+		}
+		for id, severity := range opts.Validation.Rules {
+			merged[id] = severity
+		}
+		opts.Validation.Rules = merged
+	}
+
+	return &opts
+}
+
+func (f *ConfigFile) resolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(f.Dir, path)
+}
@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := LoadConfigFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg != nil {
+		t.Fatalf("expected no config for directory without config file, given: %#v", cfg)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, ConfigFileJSONName), []byte(`{
+  "indexing": {
+    "ignore_paths": ["vendor"]
+  }
+}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadConfigFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedCfg := &ConfigFile{
+		Dir: dir,
+		Indexing: &ConfigIndexing{
+			IgnorePaths: []string{"vendor"},
+		},
+	}
+	if diff := cmp.Diff(expectedCfg, cfg); diff != "" {
+		t.Fatalf("unexpected config: %s", diff)
+	}
+
+	// native syntax takes precedence
+	err = os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(`
+validation {
+  enable_enhanced_validation = false
+}
+
+var_file "envs/*.tfvars" {
+  module = "."
+}
+
+rule "unreferenced-origin" {
+  severity = "hint"
+}
+
+schema_dirs = ["schemas"]
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadConfigFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	disabled := false
+	expectedCfg = &ConfigFile{
+		Dir: dir,
+		Validation: &ConfigValidation{
+			EnableEnhancedValidation: &disabled,
+		},
+		VarFiles: []ConfigVarFile{
+			{Path: "envs/*.tfvars", Module: "."},
+		},
+		Rules: []ConfigRule{
+			{ID: "unreferenced-origin", Severity: "hint"},
+		},
+		SchemaDirs: []string{"schemas"},
+	}
+	if diff := cmp.Diff(expectedCfg, cfg); diff != "" {
+		t.Fatalf("unexpected config: %s", diff)
+	}
+}
+
+func TestLoadConfigFile_invalid(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(`
+indexing {
+  unknown = true
+}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = LoadConfigFile(dir)
+	if err == nil {
+		t.Fatal("expected error for unknown attribute")
+	}
+}
+
+func TestDecodedOptions_WithConfigFiles(t *testing.T) {
+	out, err := DecodeOptions(map[string]interface{}{
+		"indexing": map[string]interface{}{
+			"ignorePaths": []string{"/editor/path"},
+		},
+		"validation": map[string]interface{}{
+			"rules": map[string]interface{}{
+				"unreferenced-origin": "error",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disabled := false
+	opts := out.WithConfigFiles([]*ConfigFile{
+		{
+			Dir: "/workspace",
+			Indexing: &ConfigIndexing{
+				IgnorePaths:          []string{"vendor"},
+				IgnoreDirectoryNames: []string{".cache"},
+			},
+			Validation: &ConfigValidation{
+				EnableEnhancedValidation: &disabled,
+				PolicyFiles:              []string{"policies/tagging.hcl"},
+			},
+			VarFiles: []ConfigVarFile{
+				{Path: "envs/*.tfvars", Module: "."},
+			},
+			Rules: []ConfigRule{
+				{ID: "unreferenced-origin", Severity: "hint"},
+				{ID: "deprecated-attribute", Severity: "off"},
+			},
+			SchemaDirs: []string{"schemas"},
+		},
+		{
+			Dir: "/other",
+			VarFiles: []ConfigVarFile{
+				{Path: "prod.tfvars", Module: "/modules/root"},
+			},
+		},
+	})
+
+	expectedOpts := *out.Options
+	expectedOpts.Indexing = Indexing{
+		IgnorePaths:          []string{"/editor/path"},
+		IgnoreDirectoryNames: []string{".cache"},
+	}
+	expectedOpts.Validation.EnableEnhancedValidation = false
+	expectedOpts.Validation.PolicyFiles = []string{
+		filepath.Join("/workspace", "policies/tagging.hcl"),
+	}
+	expectedOpts.Validation.Rules = map[string]string{
+		"unreferenced-origin":  "error",
+		"deprecated-attribute": "off",
+	}
+	expectedOpts.VarFiles = []VarFile{
+		{Path: filepath.Join("/workspace", "envs/*.tfvars"), Module: "/workspace"},
+		{Path: filepath.Join("/other", "prod.tfvars"), Module: "/modules/root"},
+	}
+	expectedOpts.SchemaDirs = []string{
+		filepath.Join("/workspace", "schemas"),
+	}
+	if diff := cmp.Diff(&expectedOpts, opts); diff != "" {
+		t.Fatalf("unexpected options: %s", diff)
+	}
+
+	if !out.Options.Validation.EnableEnhancedValidation {
+		t.Fatal("expected decoded options to remain unchanged")
+	}
+}
+
+func TestDecodedOptions_WithConfigFiles_clientDefaults(t *testing.T) {
+	// Clients commonly pass all settings, including empty and default values
+	out, err := DecodeOptions(map[string]interface{}{
+		"indexing": map[string]interface{}{
+			"ignorePaths":          []string{},
+			"ignoreDirectoryNames": []string{},
+		},
+		"validation": map[string]interface{}{
+			"enableEnhancedValidation": true,
+			"policyFiles":              []string{},
+		},
+		"varFiles":   []interface{}{},
+		"schemaDirs": []string{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disabled := false
+	opts := out.WithConfigFiles([]*ConfigFile{
+		{
+			Dir: "/workspace",
+			Indexing: &ConfigIndexing{
+				IgnorePaths:          []string{"vendor"},
+				IgnoreDirectoryNames: []string{".cache"},
+			},
+			Validation: &ConfigValidation{
+				EnableEnhancedValidation: &disabled,
+				PolicyFiles:              []string{"policies/tagging.hcl"},
+			},
+			VarFiles: []ConfigVarFile{
+				{Path: "envs/*.tfvars", Module: "."},
+			},
+			SchemaDirs: []string{"schemas"},
+		},
+	})
+
+	expectedOpts := *out.Options
+	expectedOpts.Indexing = Indexing{
+		IgnorePaths:          []string{filepath.Join("/workspace", "vendor")},
+		IgnoreDirectoryNames: []string{".cache"},
+	}
+	expectedOpts.Validation.EnableEnhancedValidation = false
+	expectedOpts.Validation.PolicyFiles = []string{
+		filepath.Join("/workspace", "policies/tagging.hcl"),
+	}
+	expectedOpts.VarFiles = []VarFile{
+		{Path: filepath.Join("/workspace", "envs/*.tfvars"), Module: "/workspace"},
+	}
+	expectedOpts.SchemaDirs = []string{
+		filepath.Join("/workspace", "schemas"),
+	}
+	if diff := cmp.Diff(&expectedOpts, opts); diff != "" {
+		t.Fatalf("unexpected options: %s", diff)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/hashicorp/terraform-ls/internal/rules"
 	"github.com/hashicorp/terraform-ls/internal/terraform/datadir"
 	"github.com/mcuadros/go-defaults"
 	"github.com/mitchellh/mapstructure"
@@ -21,6 +22,20 @@ type ExperimentalFeatures struct {
 
 type ValidationOptions struct {
 	EnableEnhancedValidation bool `mapstructure:"enableEnhancedValidation" default:"true"`
+
+	// Rules maps rule IDs to severities (off, hint, warning or error)
+	Rules map[string]string `mapstructure:"rules"`
+
+	// RulePlugins are processes serving custom rules for modules
+	RulePlugins []RulePlugin `mapstructure:"rulePlugins"`
+
+	// PolicyFiles are paths to files declaring rules for modules
+	PolicyFiles []string `mapstructure:"policyFiles"`
+}
+
+type RulePlugin struct {
+	Command string   `mapstructure:"command"`
+	Args    []string `mapstructure:"args"`
 }
 
 type Indexing struct {
@@ -28,10 +43,34 @@ type Indexing struct {
 	IgnorePaths          []string `mapstructure:"ignorePaths"`
 }
 
+type ModuleSearch struct {
+	CatalogFile string `mapstructure:"catalogFile"`
+	Offline     bool   `mapstructure:"offline"`
+}
+
+type VarFile struct {
+	Path   string `mapstructure:"path"`
+	Module string `mapstructure:"module"`
+}
+
+const (
+	DistributionTerraform = "terraform"
+	DistributionOpenTofu  = "opentofu"
+)
+
 type Terraform struct {
-	Path        string `mapstructure:"path"`
-	Timeout     string `mapstructure:"timeout"`
-	LogFilePath string `mapstructure:"logFilePath"`
+	Path              string `mapstructure:"path"`
+	ToolchainDir      string `mapstructure:"toolchainDir"`
+	Distribution      string `mapstructure:"distribution"`
+	StateSnapshotFile string `mapstructure:"stateSnapshotFile"`
+	Timeout           string `mapstructure:"timeout"`
+	LogFilePath       string `mapstructure:"logFilePath"`
+}
+
+type TFLint struct {
+	Enable  bool   `mapstructure:"enable"`
+	Path    string `mapstructure:"path"`
+	Timeout string `mapstructure:"timeout"`
 }
 
 type Options struct {
@@ -47,6 +86,16 @@ type Options struct {
 
 	Terraform Terraform `mapstructure:"terraform"`
 
+	ModuleSearch ModuleSearch `mapstructure:"moduleSearch"`
+
+	TFLint TFLint `mapstructure:"tflint"`
+
+	VarFiles []VarFile `mapstructure:"varFiles"`
+
+	// SchemaDirs are directories of provider schemas
+	// to use instead of the embedded ones
+	SchemaDirs []string `mapstructure:"schemaDirs"`
+
 	XLegacyModulePaths              []string `mapstructure:"rootModulePaths"`
 	XLegacyExcludeModulePaths       []string `mapstructure:"excludeModulePaths"`
 	XLegacyIgnoreDirectoryNames     []string `mapstructure:"ignoreDirectoryNames"`
@@ -70,6 +119,75 @@ func (o *Options) Validate() error {
 		}
 	}
 
+	if o.Terraform.ToolchainDir != "" {
+		path := o.Terraform.ToolchainDir
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("Expected absolute path for Terraform toolchain directory, got %q", path)
+		}
+		stat, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("Unable to find Terraform toolchain directory: %s", err)
+		}
+		if !stat.IsDir() {
+			return fmt.Errorf("Expected a directory of Terraform binaries, got a file: %q", path)
+		}
+	}
+
+	if o.ModuleSearch.CatalogFile != "" {
+		path := o.ModuleSearch.CatalogFile
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("Expected absolute path for module catalog file, got %q", path)
+		}
+	}
+
+	for _, varFile := range o.VarFiles {
+		if varFile.Path == "" || varFile.Module == "" {
+			return fmt.Errorf("Expected both path and module for variable file, got %q and %q",
+				varFile.Path, varFile.Module)
+		}
+		if _, err := filepath.Match(varFile.Path, ""); err != nil {
+			return fmt.Errorf("Invalid variable file pattern %q: %s", varFile.Path, err)
+		}
+	}
+
+	for _, dir := range o.SchemaDirs {
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("Expected absolute path for schema directory, got %q", dir)
+		}
+	}
+
+	for id, severity := range o.Validation.Rules {
+		if _, err := rules.ParseSeverity(severity); err != nil {
+			return fmt.Errorf("Invalid severity for rule %q: %s", id, err)
+		}
+	}
+
+	for _, plugin := range o.Validation.RulePlugins {
+		if !filepath.IsAbs(plugin.Command) {
+			return fmt.Errorf("Expected absolute path for rule plugin command, got %q", plugin.Command)
+		}
+	}
+
+	for _, path := range o.Validation.PolicyFiles {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("Expected absolute path for policy file, got %q", path)
+		}
+	}
+
+	if o.TFLint.Path != "" {
+		path := o.TFLint.Path
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("Expected absolute path for tflint binary, got %q", path)
+		}
+	}
+
+	switch o.Terraform.Distribution {
+	case "", DistributionTerraform, DistributionOpenTofu:
+	default:
+		return fmt.Errorf("Expected distribution to be %q or %q, got %q",
+			DistributionTerraform, DistributionOpenTofu, o.Terraform.Distribution)
+	}
+
 	if len(o.Indexing.IgnoreDirectoryNames) > 0 {
 		for _, directory := range o.Indexing.IgnoreDirectoryNames {
 			if directory == datadir.DataDirName {
@@ -88,6 +206,9 @@ func (o *Options) Validate() error {
 type DecodedOptions struct {
 	Options    *Options
 	UnusedKeys []string
+
+	// Keys are (dot-separated) keys of options passed explicitly
+	Keys []string
 }
 
 func DecodeOptions(input interface{}) (*DecodedOptions, error) {
@@ -115,5 +236,6 @@ func DecodeOptions(input interface{}) (*DecodedOptions, error) {
 	return &DecodedOptions{
 		Options:    options,
 		UnusedKeys: md.Unused,
+		Keys:       md.Keys,
 	}, nil
 }
@@ -107,3 +107,173 @@ func TestValidate_relativePath(t *testing.T) {
 		t.Fatal("expected decoding of relative path to result in error")
 	}
 }
+
+func TestValidate_toolchainDirRelativePath(t *testing.T) {
+	out, err := DecodeOptions(map[string]interface{}{
+		"terraform": map[string]interface{}{
+			"toolchainDir": "relative/path",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := out.Options.Validate()
+	if result == nil {
+		t.Fatal("expected decoding of relative toolchain path to result in error")
+	}
+}
+
+func TestValidate_moduleCatalogFileRelativePath(t *testing.T) {
+	out, err := DecodeOptions(map[string]interface{}{
+		"moduleSearch": map[string]interface{}{
+			"catalogFile": "relative/catalog.json",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := out.Options.Validate()
+	if result == nil {
+		t.Fatal("expected decoding of relative catalog path to result in error")
+	}
+}
+
+func TestValidate_rulePluginRelativePath(t *testing.T) {
+	out, err := DecodeOptions(map[string]interface{}{
+		"validation": map[string]interface{}{
+			"rulePlugins": []interface{}{
+				map[string]interface{}{
+					"command": "bin/house-rules",
+					"args":    []interface{}{"--strict"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := out.Options.Validate()
+	if result == nil {
+		t.Fatal("expected decoding of relative rule plugin command to result in error")
+	}
+}
+
+func TestValidate_varFiles(t *testing.T) {
+	testCases := []struct {
+		name        string
+		varFile     map[string]interface{}
+		expectedErr bool
+	}{
+		{
+			"valid",
+			map[string]interface{}{"path": "envs/*.tfvars", "module": "."},
+			false,
+		},
+		{
+			"missing module",
+			map[string]interface{}{"path": "envs/prod.tfvars"},
+			true,
+		},
+		{
+			"invalid pattern",
+			map[string]interface{}{"path": "envs/[prod.tfvars", "module": "."},
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := DecodeOptions(map[string]interface{}{
+				"varFiles": []interface{}{tc.varFile},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = out.Options.Validate()
+			if tc.expectedErr && err == nil {
+				t.Fatal("expected validation error")
+			}
+			if !tc.expectedErr && err != nil {
+				t.Fatalf("unexpected validation error: %s", err)
+			}
+		})
+	}
+}
+
+func TestValidate_rules(t *testing.T) {
+	out, err := DecodeOptions(map[string]interface{}{
+		"validation": map[string]interface{}{
+			"rules": map[string]interface{}{
+				"unreferenced-origin":  "hint",
+				"deprecated-attribute": "off",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Options.Validate(); err != nil {
+		t.Fatalf("did not expect error: %s", err)
+	}
+
+	out, err = DecodeOptions(map[string]interface{}{
+		"validation": map[string]interface{}{
+			"rules": map[string]interface{}{
+				"unreferenced-origin": "info",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Options.Validate(); err == nil {
+		t.Fatal("expected error for invalid severity")
+	}
+}
+
+func TestValidate_distribution(t *testing.T) {
+	out, err := DecodeOptions(map[string]interface{}{
+		"terraform": map[string]interface{}{
+			"distribution": "opentofu",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Options.Validate(); err != nil {
+		t.Fatalf("did not expect error: %s", err)
+	}
+
+	out, err = DecodeOptions(map[string]interface{}{
+		"terraform": map[string]interface{}{
+			"distribution": "unknown",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Options.Validate(); err == nil {
+		t.Fatal("expected unknown distribution to result in error")
+	}
+}
+
+func TestValidate_tflintRelativePath(t *testing.T) {
+	out, err := DecodeOptions(map[string]interface{}{
+		"tflint": map[string]interface{}{
+			"enable": true,
+			"path":   "bin/tflint",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := out.Options.Validate()
+	if result == nil {
+		t.Fatal("expected decoding of relative tflint path to result in error")
+	}
+}
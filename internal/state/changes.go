@@ -41,6 +41,7 @@ type Changes struct {
 	Diagnostics          bool
 	ReferenceOrigins     bool
 	ReferenceTargets     bool
+	PlannedActions       bool
 }
 
 const maxTimespan = 1 * time.Second
@@ -72,6 +73,7 @@ func (s *ChangeStore) QueueChange(dir document.DirHandle, changes Changes) error
 			Diagnostics:          cb.Changes.Diagnostics || changes.Diagnostics,
 			ReferenceOrigins:     cb.Changes.ReferenceOrigins || changes.ReferenceOrigins,
 			ReferenceTargets:     cb.Changes.ReferenceTargets || changes.ReferenceTargets,
+			PlannedActions:       cb.Changes.PlannedActions || changes.PlannedActions,
 		}
 	} else {
 		// create new change batch
@@ -158,6 +158,23 @@ func (s *DocumentStore) CloseDocument(dh document.Handle) error {
 	return nil
 }
 
+// ListDocuments returns all open documents
+func (s *DocumentStore) ListDocuments() ([]*document.Document, error) {
+	txn := s.db.Txn(false)
+	it, err := txn.Get(s.tableName, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]*document.Document, 0)
+	for item := it.Next(); item != nil; item = it.Next() {
+		doc := item.(*document.Document)
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
 func (s *DocumentStore) ListDocumentsInDir(dirHandle document.DirHandle) ([]*document.Document, error) {
 	txn := s.db.Txn(false)
 	it, err := txn.Get(s.tableName, "dir", dirHandle)
@@ -139,6 +139,56 @@ func TestDocumentStore_GetDocument_notFound(t *testing.T) {
 	}
 }
 
+func TestDocumentStore_ListDocuments(t *testing.T) {
+	s, err := NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.DocumentStore.TimeProvider = testTimeProvider
+
+	testHandle1 := document.HandleFromURI("file:///dir/test1.tf")
+	err = s.DocumentStore.OpenDocument(testHandle1, "terraform", 0, []byte("foobar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testHandle2 := document.HandleFromURI("file:///other/test.tfvars")
+	err = s.DocumentStore.OpenDocument(testHandle2, "terraform-vars", 0, []byte("foobar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := s.DocumentStore.ListDocuments()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDocs := []*document.Document{
+		{
+			Dir:        document.DirHandleFromURI("file:///dir"),
+			Filename:   "test1.tf",
+			ModTime:    testTimeProvider(),
+			LanguageID: "terraform",
+			Version:    0,
+			Text:       []byte("foobar"),
+			Lines:      source.MakeSourceLines("test1.tf", []byte("foobar")),
+		},
+		{
+			Dir:        document.DirHandleFromURI("file:///other"),
+			Filename:   "test.tfvars",
+			ModTime:    testTimeProvider(),
+			LanguageID: "terraform-vars",
+			Version:    0,
+			Text:       []byte("foobar"),
+			Lines:      source.MakeSourceLines("test.tfvars", []byte("foobar")),
+		},
+	}
+	if diff := cmp.Diff(expectedDocs, docs); diff != "" {
+		t.Fatalf("unexpected docs: %s", diff)
+	}
+}
+
 func TestDocumentStore_ListDocumentsInDir(t *testing.T) {
 	s, err := NewStateStore()
 	if err != nil {
@@ -18,6 +18,7 @@ import (
 	"github.com/hashicorp/go-version"
 	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/hashicorp/terraform-ls/internal/schemas"
+	"github.com/hashicorp/terraform-ls/internal/terraform/opentofu"
 	tfaddr "github.com/hashicorp/terraform-registry-address"
 	tfschema "github.com/hashicorp/terraform-schema/schema"
 	"go.opentelemetry.io/otel"
@@ -345,6 +346,13 @@ func (s *ProviderSchemaStore) ProviderSchema(modPath string, addr tfaddr.Provide
 		return s.ProviderSchema(modPath, NewBuiltInProvider("terraform"), vc)
 	}
 
+	if len(schemas) == 0 && addr.Hostname == opentofu.DefaultProviderRegistryHost {
+		// Bundled schemas come from the Terraform Registry, and providers
+		// in the OpenTofu Registry are typically the same ones mirrored
+		addr.Hostname = tfaddr.DefaultProviderRegistryHost
+		return s.ProviderSchema(modPath, addr, vc)
+	}
+
 	if len(schemas) == 0 && addr.IsLegacy() {
 		if addr.Type == "terraform" {
 			return s.ProviderSchema(modPath, NewBuiltInProvider("terraform"), vc)
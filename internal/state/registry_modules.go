@@ -132,3 +132,27 @@ func (s *RegistryModuleStore) RegistryModuleMeta(addr tfaddr.Module, cons versio
 		Source: addr.String(),
 	}
 }
+
+// RegistryModuleSources returns source addresses of all modules
+// which were successfully fetched from the registry
+func (s *RegistryModuleStore) RegistryModuleSources() ([]tfaddr.Module, error) {
+	txn := s.db.Txn(false)
+
+	it, err := txn.Get(s.tableName, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	sources := make([]tfaddr.Module, 0)
+	for item := it.Next(); item != nil; item = it.Next() {
+		mod := item.(*RegistryModuleData)
+		if mod.Error || seen[mod.Source.String()] {
+			continue
+		}
+		seen[mod.Source.String()] = true
+		sources = append(sources, mod.Source)
+	}
+
+	return sources, nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ast
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// BlockAddress returns the address of a resource or module block,
+// such as aws_instance.web or module.vpc
+func BlockAddress(block *hclsyntax.Block) (string, bool) {
+	switch block.Type {
+	case "resource":
+		if len(block.Labels) == 2 {
+			return block.Labels[0] + "." + block.Labels[1], true
+		}
+	case "module":
+		if len(block.Labels) == 1 {
+			return "module." + block.Labels[0], true
+		}
+	}
+	return "", false
+}
+
+// AttributeAddress returns the address which the attribute
+// refers to, including any instance keys, such as in moved blocks
+func AttributeAddress(attr *hclsyntax.Attribute) (string, bool) {
+	if attr == nil {
+		return "", false
+	}
+	traversal, diags := hcl.AbsTraversalForExpr(attr.Expr)
+	if diags.HasErrors() {
+		return "", false
+	}
+	addr, err := lang.TraversalToAddress(traversal)
+	if err != nil {
+		return "", false
+	}
+	return addr.String(), true
+}
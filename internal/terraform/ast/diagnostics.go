@@ -15,9 +15,15 @@ const (
 	SchemaValidationSource
 	ReferenceValidationSource
 	TerraformValidateSource
+	TerraformPlanSource
+	TFLintSource
+	CustomRulesSource
 )
 
 func (d DiagnosticSource) String() string {
+	if d == TFLintSource {
+		return "tflint"
+	}
 	return "Terraform"
 }
 
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func azurermBackend(v *version.Version) *Backend {
+	bodySchema := &schema.BodySchema{
+		Description: lang.Markdown("Azure Storage blob (with locking via blob leases)"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"storage_account_name":        required(cty.String, "The name of the storage account"),
+			"container_name":              required(cty.String, "The container name"),
+			"key":                         required(cty.String, "The blob key"),
+			"resource_group_name":         optional(cty.String, "The resource group name of the storage account"),
+			"environment":                 optional(cty.String, "The Azure cloud environment, `public` by default"),
+			"endpoint":                    optional(cty.String, "A custom endpoint for Azure Resource Manager"),
+			"snapshot":                    optional(cty.Bool, "Whether to enable automatic blob snapshotting"),
+			"access_key":                  sensitive(cty.String, "The access key of the storage account"),
+			"sas_token":                   sensitive(cty.String, "A SAS token used to interact with the blob storage account"),
+			"client_id":                   optional(cty.String, "The client ID of the service principal"),
+			"client_secret":               sensitive(cty.String, "The client secret of the service principal"),
+			"client_certificate_path":     optional(cty.String, "The path to the PFX file used as the client certificate"),
+			"client_certificate_password": sensitive(cty.String, "The password associated with the client certificate"),
+			"subscription_id":             optional(cty.String, "The subscription ID"),
+			"tenant_id":                   optional(cty.String, "The tenant ID"),
+			"use_msi":                     optional(cty.Bool, "Whether to authenticate using Managed Service Identity"),
+			"msi_endpoint":                optional(cty.String, "The path to a custom Managed Service Identity endpoint"),
+		},
+	}
+	exclusive := [][]string{
+		{"access_key", "sas_token"},
+		{"client_secret", "client_certificate_path"},
+	}
+
+	if v.LessThan(v0_15_0) {
+		// https://github.com/hashicorp/terraform/pull/26721
+		bodySchema.Attributes["arm_client_id"] = deprecated(cty.String, "The client ID of the service principal", "client_id")
+		bodySchema.Attributes["arm_client_secret"] = deprecated(cty.String, "The client secret of the service principal", "client_secret")
+		bodySchema.Attributes["arm_subscription_id"] = deprecated(cty.String, "The subscription ID", "subscription_id")
+		bodySchema.Attributes["arm_tenant_id"] = deprecated(cty.String, "The tenant ID", "tenant_id")
+		exclusive = append(exclusive,
+			[]string{"arm_client_id", "client_id"},
+			[]string{"arm_client_secret", "client_secret"},
+			[]string{"arm_subscription_id", "subscription_id"},
+			[]string{"arm_tenant_id", "tenant_id"},
+		)
+	}
+
+	if v.GreaterThanOrEqual(v0_13_0) {
+		bodySchema.Attributes["use_azuread_auth"] = optional(cty.Bool, "Whether to use Azure Active Directory authentication to access the storage data plane")
+	}
+
+	if v.GreaterThanOrEqual(v1_2_0) {
+		bodySchema.Attributes["use_oidc"] = optional(cty.Bool, "Whether to authenticate using OpenID Connect")
+		bodySchema.Attributes["oidc_request_url"] = optional(cty.String, "The URL of the OIDC provider from which to request an ID token")
+		bodySchema.Attributes["oidc_request_token"] = sensitive(cty.String, "The bearer token used to request an ID token from the OIDC provider")
+		bodySchema.Attributes["oidc_token"] = sensitive(cty.String, "The ID token used for OIDC authentication")
+		bodySchema.Attributes["oidc_token_file_path"] = optional(cty.String, "The path to a file containing the ID token used for OIDC authentication")
+		exclusive = append(exclusive, []string{"oidc_token", "oidc_token_file_path"})
+	}
+
+	if v.GreaterThanOrEqual(v1_3_0) {
+		bodySchema.Attributes["metadata_host"] = optional(cty.String, "The hostname of the Azure Metadata Service, used to discover the environment")
+	}
+
+	if v.GreaterThanOrEqual(v1_10_0) {
+		bodySchema.Attributes["use_cli"] = optional(cty.Bool, "Whether to authenticate using the Azure CLI")
+		bodySchema.Attributes["lookup_blob_endpoint"] = optional(cty.Bool, "Whether to look up the storage account blob endpoint, required for e.g. DNS zone endpoints")
+	}
+
+	return &Backend{
+		Body:               bodySchema,
+		ExclusiveArguments: exclusive,
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var (
+	v0_13_0 = version.Must(version.NewVersion("0.13.0"))
+	v0_14_0 = version.Must(version.NewVersion("0.14.0"))
+	v0_15_0 = version.Must(version.NewVersion("0.15.0"))
+	v1_2_0  = version.Must(version.NewVersion("1.2.0"))
+	v1_3_0  = version.Must(version.NewVersion("1.3.0"))
+	v1_4_0  = version.Must(version.NewVersion("1.4.0"))
+	v1_6_0  = version.Must(version.NewVersion("1.6.0"))
+	v1_10_0 = version.Must(version.NewVersion("1.10.0"))
+)
+
+// Backend represents the configuration schema of a backend
+type Backend struct {
+	Body *schema.BodySchema
+
+	// ExclusiveArguments are groups of arguments of which at most one
+	// may be set. Arguments of nested blocks are dot-separated,
+	// e.g. workspaces.name
+	ExclusiveArguments [][]string
+}
+
+// ForVersion returns schemas of backends available
+// in the given version of Terraform, keyed by backend type
+func ForVersion(v *version.Version) map[string]*Backend {
+	if v == nil {
+		return map[string]*Backend{}
+	}
+	v = v.Core()
+
+	backends := map[string]*Backend{
+		"azurerm": azurermBackend(v),
+		"consul":  consulBackend(v),
+		"gcs":     gcsBackend(v),
+		"http":    httpBackend(v),
+		"local":   localBackend(v),
+		"pg":      pgBackend(v),
+		"remote":  remoteBackend(v),
+		"s3":      s3Backend(v),
+	}
+	if v.GreaterThanOrEqual(v0_13_0) {
+		backends["kubernetes"] = kubernetesBackend(v)
+	}
+
+	for backendType, backend := range backends {
+		docsUrl := fmt.Sprintf("https://developer.hashicorp.com/terraform/language/settings/backends/%s", backendType)
+		backend.Body.HoverURL = docsUrl
+		backend.Body.DocsLink = &schema.DocsLink{URL: docsUrl}
+	}
+
+	return backends
+}
+
+// PatchModuleSchema returns a copy of the given module schema with
+// the schemas of backends available in the given version of Terraform
+// replacing the respective dependent bodies of the backend block.
+//
+// Only the blocks which are patched are copied, so that
+// the (potentially large) rest of the schema can be shared.
+func PatchModuleSchema(bs *schema.BodySchema, v *version.Version) *schema.BodySchema {
+	if bs == nil {
+		return nil
+	}
+	tfBlock, ok := bs.Blocks["terraform"]
+	if !ok || tfBlock.Body == nil {
+		return bs
+	}
+	backendBlock, ok := tfBlock.Body.Blocks["backend"]
+	if !ok {
+		return bs
+	}
+
+	backend := *backendBlock
+	backend.DependentBody = make(map[schema.SchemaKey]*schema.BodySchema, len(backendBlock.DependentBody))
+	for key, depBody := range backendBlock.DependentBody {
+		backend.DependentBody[key] = depBody
+	}
+	for backendType, b := range ForVersion(v) {
+		backend.DependentBody[labelKey(backendType)] = b.Body
+	}
+
+	body := *tfBlock.Body
+	body.Blocks = make(map[string]*schema.BlockSchema, len(tfBlock.Body.Blocks))
+	for name, b := range tfBlock.Body.Blocks {
+		body.Blocks[name] = b
+	}
+	body.Blocks["backend"] = &backend
+
+	block := *tfBlock
+	block.Body = &body
+
+	patched := *bs
+	patched.Blocks = make(map[string]*schema.BlockSchema, len(bs.Blocks))
+	for name, b := range bs.Blocks {
+		patched.Blocks[name] = b
+	}
+	patched.Blocks["terraform"] = &block
+
+	return &patched
+}
+
+func labelKey(value string) schema.SchemaKey {
+	return schema.NewSchemaKey(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{{Index: 0, Value: value}},
+	})
+}
+
+func optional(ty cty.Type, description string) *schema.AttributeSchema {
+	return &schema.AttributeSchema{
+		Constraint:  schema.LiteralType{Type: ty},
+		IsOptional:  true,
+		Description: lang.Markdown(description),
+	}
+}
+
+func required(ty cty.Type, description string) *schema.AttributeSchema {
+	return &schema.AttributeSchema{
+		Constraint:  schema.LiteralType{Type: ty},
+		IsRequired:  true,
+		Description: lang.Markdown(description),
+	}
+}
+
+func sensitive(ty cty.Type, description string) *schema.AttributeSchema {
+	attr := optional(ty, description)
+	attr.IsSensitive = true
+	return attr
+}
+
+func deprecated(ty cty.Type, description, replacement string) *schema.AttributeSchema {
+	attr := optional(ty, fmt.Sprintf("**DEPRECATED**: use `%s` instead. %s", replacement, description))
+	attr.IsDeprecated = true
+	return attr
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+)
+
+func TestForVersion(t *testing.T) {
+	v0_12 := version.Must(version.NewVersion("0.12.31"))
+	if _, ok := ForVersion(v0_12)["kubernetes"]; ok {
+		t.Fatal("expected no kubernetes backend in 0.12")
+	}
+
+	v1_5 := version.Must(version.NewVersion("1.5.7"))
+	s3 := ForVersion(v1_5)["s3"]
+	if !s3.Body.Attributes["region"].IsRequired {
+		t.Fatal("expected region of s3 backend to be required in 1.5")
+	}
+	if _, ok := s3.Body.Blocks["assume_role"]; ok {
+		t.Fatal("expected no assume_role block of s3 backend in 1.5")
+	}
+
+	v1_6 := version.Must(version.NewVersion("1.6.0-beta1"))
+	s3 = ForVersion(v1_6)["s3"]
+	if s3.Body.Attributes["region"].IsRequired {
+		t.Fatal("expected region of s3 backend to be optional in 1.6")
+	}
+	if !s3.Body.Attributes["role_arn"].IsDeprecated {
+		t.Fatal("expected role_arn of s3 backend to be deprecated in 1.6")
+	}
+	if s3.Body.DocsLink == nil {
+		t.Fatal("expected docs link for s3 backend")
+	}
+}
+
+func TestPatchModuleSchema(t *testing.T) {
+	original := &schema.BlockSchema{}
+	bs := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"terraform": {
+				Body: &schema.BodySchema{
+					Blocks: map[string]*schema.BlockSchema{
+						"backend": {
+							DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+								labelKey("etcd"): {},
+							},
+						},
+					},
+				},
+			},
+			"resource": original,
+		},
+	}
+
+	patched := PatchModuleSchema(bs, version.Must(version.NewVersion("1.10.0")))
+
+	backend := patched.Blocks["terraform"].Body.Blocks["backend"]
+	if _, ok := backend.DependentBody[labelKey("etcd")]; !ok {
+		t.Fatal("expected other backends to be retained")
+	}
+	s3, ok := backend.DependentBody[labelKey("s3")]
+	if !ok {
+		t.Fatal("expected s3 backend to be patched")
+	}
+	if _, ok := s3.Attributes["use_lockfile"]; !ok {
+		t.Fatal("expected use_lockfile attribute of s3 backend in 1.10")
+	}
+	if patched.Blocks["resource"] != original {
+		t.Fatal("expected unpatched blocks to be shared")
+	}
+	if len(bs.Blocks["terraform"].Body.Blocks["backend"].DependentBody) != 1 {
+		t.Fatal("expected original schema to be left intact")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func consulBackend(v *version.Version) *Backend {
+	return &Backend{
+		Body: &schema.BodySchema{
+			Description: lang.Markdown("Consul KV store (with locking via sessions)"),
+			Attributes: map[string]*schema.AttributeSchema{
+				"path":         required(cty.String, "Path in the Consul KV store"),
+				"access_token": sensitive(cty.String, "Access token"),
+				"address":      optional(cty.String, "Address to the Consul agent, `127.0.0.1:8500` by default"),
+				"scheme":       optional(cty.String, "Scheme to communicate with the Consul agent, `http` or `https`"),
+				"datacenter":   optional(cty.String, "The datacenter to use, the datacenter of the agent by default"),
+				"http_auth":    sensitive(cty.String, "HTTP basic authentication credentials, `user:pass`"),
+				"gzip":         optional(cty.Bool, "Whether to compress the state data using gzip"),
+				"lock":         optional(cty.Bool, "Whether to lock state access, `true` by default"),
+				"ca_file":      optional(cty.String, "A path to a PEM-encoded certificate authority used to verify the remote agent's certificate"),
+				"cert_file":    optional(cty.String, "A path to a PEM-encoded certificate provided to the remote agent"),
+				"key_file":     optional(cty.String, "A path to a PEM-encoded private key, required if `cert_file` is set"),
+			},
+		},
+	}
+}
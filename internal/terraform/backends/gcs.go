@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func gcsBackend(v *version.Version) *Backend {
+	bodySchema := &schema.BodySchema{
+		Description: lang.Markdown("Google Cloud Storage (with locking)"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"bucket":         required(cty.String, "The name of the Google Cloud Storage bucket"),
+			"prefix":         optional(cty.String, "The directory where state files will be saved inside the bucket"),
+			"credentials":    sensitive(cty.String, "Google Cloud JSON Account Key (path or contents)"),
+			"access_token":   sensitive(cty.String, "An OAuth2 token used for GCP authentication"),
+			"encryption_key": sensitive(cty.String, "A 32 byte base64 encoded customer-supplied encryption key"),
+		},
+	}
+	exclusive := [][]string{
+		{"credentials", "access_token"},
+	}
+
+	if v.LessThan(v0_15_0) {
+		// https://github.com/hashicorp/terraform/pull/27474
+		bodySchema.Attributes["path"] = deprecated(cty.String, "The path where to place/look for state file inside the bucket", "prefix")
+		bodySchema.Attributes["project"] = optional(cty.String, "The project ID to which the bucket belongs (only used for bucket creation)")
+		bodySchema.Attributes["region"] = optional(cty.String, "The region where the bucket is created (only used for bucket creation)")
+		exclusive = append(exclusive, []string{"path", "prefix"})
+	}
+
+	if v.GreaterThanOrEqual(v0_14_0) {
+		bodySchema.Attributes["impersonate_service_account"] = optional(cty.String, "The service account to impersonate for all Google API calls")
+		bodySchema.Attributes["impersonate_service_account_delegates"] = optional(cty.List(cty.String), "The delegation chain for the impersonated service account")
+	}
+
+	if v.GreaterThanOrEqual(v1_3_0) {
+		bodySchema.Attributes["kms_encryption_key"] = optional(cty.String, "A Cloud KMS key (customer-managed encryption key) used to encrypt state")
+		exclusive = append(exclusive, []string{"encryption_key", "kms_encryption_key"})
+	}
+
+	if v.GreaterThanOrEqual(v1_4_0) {
+		bodySchema.Attributes["storage_custom_endpoint"] = optional(cty.String, "A URL containing the scheme, host and version of a custom Storage API endpoint")
+	}
+
+	return &Backend{
+		Body:               bodySchema,
+		ExclusiveArguments: exclusive,
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func httpBackend(v *version.Version) *Backend {
+	bodySchema := &schema.BodySchema{
+		Description: lang.Markdown("REST client, storing state via `GET`, `POST` and `DELETE`"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"address":                required(cty.String, "The address of the REST endpoint"),
+			"update_method":          optional(cty.String, "HTTP method to use when updating state, `POST` by default"),
+			"lock_address":           optional(cty.String, "The address of the lock REST endpoint"),
+			"unlock_address":         optional(cty.String, "The address of the unlock REST endpoint"),
+			"lock_method":            optional(cty.String, "The HTTP method to use when locking, `LOCK` by default"),
+			"unlock_method":          optional(cty.String, "The HTTP method to use when unlocking, `UNLOCK` by default"),
+			"username":               optional(cty.String, "The username for HTTP basic authentication"),
+			"password":               sensitive(cty.String, "The password for HTTP basic authentication"),
+			"skip_cert_verification": optional(cty.Bool, "Whether to skip TLS verification"),
+		},
+	}
+
+	if v.GreaterThanOrEqual(v0_13_0) {
+		bodySchema.Attributes["retry_max"] = optional(cty.Number, "The number of HTTP request retries, 2 by default")
+		bodySchema.Attributes["retry_wait_min"] = optional(cty.Number, "The minimum time in seconds to wait between HTTP request attempts")
+		bodySchema.Attributes["retry_wait_max"] = optional(cty.Number, "The maximum time in seconds to wait between HTTP request attempts")
+	}
+
+	if v.GreaterThanOrEqual(v1_6_0) {
+		bodySchema.Attributes["client_ca_certificate_pem"] = optional(cty.String, "A PEM-encoded CA certificate chain used by the client to verify server certificates")
+		bodySchema.Attributes["client_certificate_pem"] = optional(cty.String, "A PEM-encoded certificate used by the server to verify the client (mTLS)")
+		bodySchema.Attributes["client_private_key_pem"] = sensitive(cty.String, "A PEM-encoded private key, required if `client_certificate_pem` is set")
+	}
+
+	return &Backend{Body: bodySchema}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func kubernetesBackend(v *version.Version) *Backend {
+	bodySchema := &schema.BodySchema{
+		Description: lang.Markdown("Kubernetes secret (with locking via leases)"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"secret_suffix":            required(cty.String, "Suffix used when creating the secret, prefixed by `tfstate-{workspace}-`"),
+			"labels":                   optional(cty.Map(cty.String), "Map of additional labels to be applied to the secret"),
+			"namespace":                optional(cty.String, "Namespace to store the secret in"),
+			"in_cluster_config":        optional(cty.Bool, "Whether to use the service account Kubernetes provides to pods"),
+			"host":                     optional(cty.String, "The hostname (in form of URI) of the Kubernetes API"),
+			"username":                 optional(cty.String, "The username for HTTP basic authentication"),
+			"password":                 sensitive(cty.String, "The password for HTTP basic authentication"),
+			"insecure":                 optional(cty.Bool, "Whether the server should be accessed without verifying the TLS certificate"),
+			"client_certificate":       optional(cty.String, "PEM-encoded client certificate for TLS authentication"),
+			"client_key":               sensitive(cty.String, "PEM-encoded client certificate key for TLS authentication"),
+			"cluster_ca_certificate":   optional(cty.String, "PEM-encoded root certificates bundle for TLS authentication"),
+			"config_path":              optional(cty.String, "Path to the kube config file"),
+			"config_context":           optional(cty.String, "Context to choose from the config file"),
+			"config_context_auth_info": optional(cty.String, "Authentication info context of the kube config"),
+			"config_context_cluster":   optional(cty.String, "Cluster context of the kube config"),
+			"token":                    sensitive(cty.String, "Token of your service account"),
+			"load_config_file":         optional(cty.Bool, "Whether to load the local kube config file"),
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"exec": {
+				Description: lang.Markdown("Configuration of an exec-based credential plugin"),
+				MaxItems:    1,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"api_version": required(cty.String, "API version to use when decoding the ExecCredentials resource"),
+						"command":     required(cty.String, "Command to execute"),
+						"args":        optional(cty.List(cty.String), "List of arguments to pass when executing the plugin"),
+						"env":         optional(cty.Map(cty.String), "Map of environment variables to set when executing the plugin"),
+					},
+				},
+			},
+		},
+	}
+
+	exclusive := [][]string{}
+	if v.GreaterThanOrEqual(v0_15_0) {
+		// https://github.com/hashicorp/terraform/pull/27441
+		delete(bodySchema.Attributes, "load_config_file")
+		bodySchema.Attributes["config_paths"] = optional(cty.List(cty.String), "List of paths to kube config files")
+		exclusive = append(exclusive, []string{"config_path", "config_paths"})
+	}
+
+	return &Backend{
+		Body:               bodySchema,
+		ExclusiveArguments: exclusive,
+	}
+}
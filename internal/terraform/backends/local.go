@@ -0,0 +1,23 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func localBackend(v *version.Version) *Backend {
+	return &Backend{
+		Body: &schema.BodySchema{
+			Description: lang.Markdown("Local filesystem (with locking via system APIs)"),
+			Attributes: map[string]*schema.AttributeSchema{
+				"path":          optional(cty.String, "The path to the `tfstate` file, `terraform.tfstate` relative to the root module by default"),
+				"workspace_dir": optional(cty.String, "The path to non-default workspaces"),
+			},
+		},
+	}
+}
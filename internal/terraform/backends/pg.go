@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func pgBackend(v *version.Version) *Backend {
+	bodySchema := &schema.BodySchema{
+		Description: lang.Markdown("Postgres database (with locking via advisory locks)"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"conn_str":             required(cty.String, "Postgres connection string, a `postgres://` URL"),
+			"schema_name":          optional(cty.String, "Name of the automatically-managed Postgres schema, `terraform_remote_state` by default"),
+			"skip_schema_creation": optional(cty.Bool, "Whether to skip creating the Postgres schema, assuming it exists"),
+		},
+	}
+
+	if v.GreaterThanOrEqual(v0_14_0) {
+		bodySchema.Attributes["skip_table_creation"] = optional(cty.Bool, "Whether to skip creating the Postgres table, assuming it exists")
+		bodySchema.Attributes["skip_index_creation"] = optional(cty.Bool, "Whether to skip creating the Postgres index, assuming it exists")
+	}
+
+	if v.GreaterThanOrEqual(v1_4_0) {
+		// the connection string can be sourced from PG_CONN_STR
+		bodySchema.Attributes["conn_str"] = optional(cty.String,
+			"Postgres connection string, a `postgres://` URL. Can also be set via `PG_CONN_STR`")
+	}
+
+	return &Backend{Body: bodySchema}
+}
@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func remoteBackend(v *version.Version) *Backend {
+	return &Backend{
+		Body: &schema.BodySchema{
+			Description: lang.Markdown("HCP Terraform or Terraform Enterprise, storing state and running operations remotely"),
+			Attributes: map[string]*schema.AttributeSchema{
+				"hostname":     optional(cty.String, "The remote backend hostname to connect to, `app.terraform.io` by default"),
+				"organization": required(cty.String, "The name of the organization containing the targeted workspace(s)"),
+				"token":        sensitive(cty.String, "The token used to authenticate with the remote backend"),
+			},
+			Blocks: map[string]*schema.BlockSchema{
+				"workspaces": {
+					Description: lang.Markdown("Workspaces to use in the remote backend"),
+					MinItems:    1,
+					MaxItems:    1,
+					Body: &schema.BodySchema{
+						Attributes: map[string]*schema.AttributeSchema{
+							"name":   optional(cty.String, "A workspace name used to map the default workspace to a named remote workspace"),
+							"prefix": optional(cty.String, "A prefix used to map all workspaces to remote workspaces with the prefix"),
+						},
+					},
+				},
+			},
+		},
+		ExclusiveArguments: [][]string{
+			{"workspaces.name", "workspaces.prefix"},
+		},
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package backends
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func s3Backend(v *version.Version) *Backend {
+	bodySchema := &schema.BodySchema{
+		Description: lang.Markdown("Amazon S3 (with locking via DynamoDB)"),
+		Attributes: map[string]*schema.AttributeSchema{
+			"bucket":                      required(cty.String, "The name of the S3 bucket"),
+			"key":                         required(cty.String, "The path to the state file inside the bucket"),
+			"region":                      required(cty.String, "AWS Region of the S3 Bucket and DynamoDB Table (if used)"),
+			"dynamodb_table":              optional(cty.String, "The name of a DynamoDB table to use for state locking and consistency"),
+			"encrypt":                     optional(cty.Bool, "Whether to enable server side encryption of the state file"),
+			"acl":                         optional(cty.String, "Canned ACL to be applied to the state file"),
+			"kms_key_id":                  optional(cty.String, "The ARN of a KMS Key to use for encrypting the state"),
+			"sse_customer_key":            sensitive(cty.String, "The base64-encoded encryption key to use for server-side encryption with customer-provided keys (SSE-C)"),
+			"workspace_key_prefix":        optional(cty.String, "The prefix applied to the non-default workspace state path, `env:` by default"),
+			"profile":                     optional(cty.String, "AWS profile name"),
+			"access_key":                  sensitive(cty.String, "AWS access key"),
+			"secret_key":                  sensitive(cty.String, "AWS secret key"),
+			"token":                       sensitive(cty.String, "MFA token"),
+			"skip_credentials_validation": optional(cty.Bool, "Whether to skip the credentials validation via the STS API"),
+			"skip_region_validation":      optional(cty.Bool, "Whether to skip validation of the provided region name"),
+			"skip_metadata_api_check":     optional(cty.Bool, "Whether to skip the AWS Metadata API check"),
+			"max_retries":                 optional(cty.Number, "The maximum number of times an AWS API request is retried on retryable failure"),
+		},
+		Blocks: map[string]*schema.BlockSchema{},
+	}
+	exclusive := [][]string{
+		{"kms_key_id", "sse_customer_key"},
+	}
+
+	if v.LessThan(v1_6_0) {
+		bodySchema.Attributes["endpoint"] = optional(cty.String, "A custom endpoint for the S3 API")
+		bodySchema.Attributes["dynamodb_endpoint"] = optional(cty.String, "A custom endpoint for the DynamoDB API")
+		bodySchema.Attributes["iam_endpoint"] = optional(cty.String, "A custom endpoint for the IAM API")
+		bodySchema.Attributes["sts_endpoint"] = optional(cty.String, "A custom endpoint for the STS API")
+		bodySchema.Attributes["shared_credentials_file"] = optional(cty.String, "This is the path to the shared credentials file")
+		bodySchema.Attributes["force_path_style"] = optional(cty.Bool, "Whether to force S3 path style addressing")
+		bodySchema.Attributes["role_arn"] = optional(cty.String, "The role to be assumed")
+		bodySchema.Attributes["session_name"] = optional(cty.String, "The session name to use when assuming the role")
+		bodySchema.Attributes["external_id"] = optional(cty.String, "The external ID to use when assuming the role")
+		bodySchema.Attributes["assume_role_duration_seconds"] = optional(cty.Number, "Seconds to restrict the assume role session duration")
+		bodySchema.Attributes["assume_role_policy"] = optional(cty.String, "IAM Policy JSON describing further restricting permissions for the IAM Role being assumed")
+		bodySchema.Attributes["assume_role_policy_arns"] = optional(cty.Set(cty.String), "ARNs of IAM Policies describing further restricting permissions for the IAM Role being assumed")
+		bodySchema.Attributes["assume_role_tags"] = optional(cty.Map(cty.String), "Assume role session tags")
+		bodySchema.Attributes["assume_role_transitive_tag_keys"] = optional(cty.Set(cty.String), "Assume role session tag keys to pass to any subsequent sessions")
+		bodySchema.Attributes["lock_table"] = deprecated(cty.String, "The name of a DynamoDB table to use for state locking", "dynamodb_table")
+		exclusive = append(exclusive, []string{"lock_table", "dynamodb_table"})
+	}
+
+	if v.GreaterThanOrEqual(v1_6_0) {
+		// https://github.com/hashicorp/terraform/pull/33687
+		// Region can be sourced from AWS_REGION or AWS_DEFAULT_REGION
+		bodySchema.Attributes["region"] = optional(cty.String,
+			"AWS Region of the S3 Bucket and DynamoDB Table (if used). Can also be set via `AWS_REGION` or `AWS_DEFAULT_REGION`")
+
+		bodySchema.Attributes["endpoint"] = deprecated(cty.String, "A custom endpoint for the S3 API", "endpoints.s3")
+		bodySchema.Attributes["dynamodb_endpoint"] = deprecated(cty.String, "A custom endpoint for the DynamoDB API", "endpoints.dynamodb")
+		bodySchema.Attributes["iam_endpoint"] = deprecated(cty.String, "A custom endpoint for the IAM API", "endpoints.iam")
+		bodySchema.Attributes["sts_endpoint"] = deprecated(cty.String, "A custom endpoint for the STS API", "endpoints.sts")
+		bodySchema.Attributes["shared_credentials_file"] = deprecated(cty.String, "This is the path to the shared credentials file", "shared_credentials_files")
+		bodySchema.Attributes["force_path_style"] = deprecated(cty.Bool, "Whether to force S3 path style addressing", "use_path_style")
+		bodySchema.Attributes["role_arn"] = deprecated(cty.String, "The role to be assumed", "assume_role.role_arn")
+		bodySchema.Attributes["session_name"] = deprecated(cty.String, "The session name to use when assuming the role", "assume_role.session_name")
+		bodySchema.Attributes["external_id"] = deprecated(cty.String, "The external ID to use when assuming the role", "assume_role.external_id")
+		bodySchema.Attributes["assume_role_duration_seconds"] = deprecated(cty.Number, "Seconds to restrict the assume role session duration", "assume_role.duration")
+		bodySchema.Attributes["assume_role_policy"] = deprecated(cty.String, "IAM Policy JSON describing further restricting permissions for the IAM Role being assumed", "assume_role.policy")
+		bodySchema.Attributes["assume_role_policy_arns"] = deprecated(cty.Set(cty.String), "ARNs of IAM Policies describing further restricting permissions for the IAM Role being assumed", "assume_role.policy_arns")
+		bodySchema.Attributes["assume_role_tags"] = deprecated(cty.Map(cty.String), "Assume role session tags", "assume_role.tags")
+		bodySchema.Attributes["assume_role_transitive_tag_keys"] = deprecated(cty.Set(cty.String), "Assume role session tag keys to pass to any subsequent sessions", "assume_role.transitive_tag_keys")
+
+		bodySchema.Attributes["shared_config_files"] = optional(cty.List(cty.String), "List of paths to AWS shared config files")
+		bodySchema.Attributes["shared_credentials_files"] = optional(cty.List(cty.String), "List of paths to AWS shared credentials files")
+		bodySchema.Attributes["use_path_style"] = optional(cty.Bool, "Whether to enable path-style S3 URLs")
+		bodySchema.Attributes["skip_requesting_account_id"] = optional(cty.Bool, "Whether to skip requesting the account ID")
+		bodySchema.Attributes["skip_s3_checksum"] = optional(cty.Bool, "Whether to skip computing checksums, e.g. for S3-compatible APIs")
+		bodySchema.Attributes["allowed_account_ids"] = optional(cty.Set(cty.String), "List of allowed AWS account IDs")
+		bodySchema.Attributes["forbidden_account_ids"] = optional(cty.Set(cty.String), "List of forbidden AWS account IDs")
+		bodySchema.Attributes["custom_ca_bundle"] = optional(cty.String, "File containing custom root and intermediate certificates")
+		bodySchema.Attributes["ec2_metadata_service_endpoint"] = optional(cty.String, "Address of the EC2 metadata service (IMDS) endpoint")
+		bodySchema.Attributes["ec2_metadata_service_endpoint_mode"] = optional(cty.String, "Mode to use in communicating with the metadata service, `IPv4` or `IPv6`")
+		bodySchema.Attributes["http_proxy"] = optional(cty.String, "URL of a proxy to use for HTTP requests")
+		bodySchema.Attributes["insecure"] = optional(cty.Bool, "Whether to explicitly allow the backend to perform insecure SSL requests")
+		bodySchema.Attributes["use_dualstack_endpoint"] = optional(cty.Bool, "Whether to resolve an endpoint with DualStack capability")
+		bodySchema.Attributes["use_fips_endpoint"] = optional(cty.Bool, "Whether to resolve an endpoint with FIPS capability")
+		bodySchema.Attributes["retry_mode"] = optional(cty.String, "Specifies how retries are attempted, `standard` or `adaptive`")
+
+		bodySchema.Blocks["endpoints"] = &schema.BlockSchema{
+			Description: lang.Markdown("Custom endpoints for AWS API services"),
+			MaxItems:    1,
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"dynamodb": optional(cty.String, "A custom endpoint for the DynamoDB API"),
+					"iam":      optional(cty.String, "A custom endpoint for the IAM API"),
+					"s3":       optional(cty.String, "A custom endpoint for the S3 API"),
+					"sso":      optional(cty.String, "A custom endpoint for the IAM Identity Center (SSO) API"),
+					"sts":      optional(cty.String, "A custom endpoint for the STS API"),
+				},
+			},
+		}
+		bodySchema.Blocks["assume_role"] = &schema.BlockSchema{
+			Description: lang.Markdown("Configuration of the role to be assumed"),
+			MaxItems:    1,
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"role_arn":            required(cty.String, "The role to be assumed"),
+					"duration":            optional(cty.String, "The duration the credentials will be valid, e.g. `1h`"),
+					"external_id":         optional(cty.String, "The external ID to use when assuming the role"),
+					"policy":              optional(cty.String, "IAM Policy JSON describing further restricting permissions for the IAM Role being assumed"),
+					"policy_arns":         optional(cty.Set(cty.String), "ARNs of IAM Policies describing further restricting permissions for the IAM Role being assumed"),
+					"session_name":        optional(cty.String, "The session name to use when assuming the role"),
+					"source_identity":     optional(cty.String, "Source identity specified by the principal assuming the role"),
+					"tags":                optional(cty.Map(cty.String), "Assume role session tags"),
+					"transitive_tag_keys": optional(cty.Set(cty.String), "Assume role session tag keys to pass to any subsequent sessions"),
+				},
+			},
+		}
+
+		exclusive = append(exclusive,
+			[]string{"allowed_account_ids", "forbidden_account_ids"},
+			[]string{"shared_credentials_file", "shared_credentials_files"},
+			[]string{"force_path_style", "use_path_style"},
+			[]string{"role_arn", "assume_role"},
+			[]string{"endpoint", "endpoints.s3"},
+			[]string{"dynamodb_endpoint", "endpoints.dynamodb"},
+			[]string{"iam_endpoint", "endpoints.iam"},
+			[]string{"sts_endpoint", "endpoints.sts"},
+		)
+	}
+
+	if v.GreaterThanOrEqual(v1_10_0) {
+		bodySchema.Attributes["use_lockfile"] = optional(cty.Bool, "Whether to use a lock file in the S3 bucket for state locking (S3 native locking)")
+	}
+
+	return &Backend{
+		Body:               bodySchema,
+		ExclusiveArguments: exclusive,
+	}
+}
@@ -19,3 +19,12 @@ func (d *Discovery) LookPath() (string, error) {
 	}
 	return path, nil
 }
+
+// LookOpenTofuPath looks up the OpenTofu binary (tofu) in $PATH
+func (d *Discovery) LookOpenTofuPath() (string, error) {
+	path, err := exec.LookPath(openTofuExecutableName)
+	if err != nil {
+		return "", fmt.Errorf("unable to find %s: %s", openTofuExecutableName, err)
+	}
+	return path, nil
+}
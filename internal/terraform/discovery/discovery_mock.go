@@ -4,9 +4,14 @@
 package discovery
 
 type MockDiscovery struct {
-	Path string
+	Path         string
+	OpenTofuPath string
 }
 
 func (d *MockDiscovery) LookPath() (string, error) {
 	return d.Path, nil
 }
+
+func (d *MockDiscovery) LookOpenTofuPath() (string, error) {
+	return d.OpenTofuPath, nil
+}
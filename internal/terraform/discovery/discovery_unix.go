@@ -6,4 +6,7 @@
 
 package discovery
 
-const executableName = "terraform"
+const (
+	executableName         = "terraform"
+	openTofuExecutableName = "tofu"
+)
@@ -3,4 +3,7 @@
 
 package discovery
 
-const executableName = "terraform.exe"
+const (
+	executableName         = "terraform.exe"
+	openTofuExecutableName = "tofu.exe"
+)
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"log"
 	"os/exec"
 	"time"
@@ -183,6 +184,38 @@ func (e *Executor) Validate(ctx context.Context) ([]tfjson.Diagnostic, error) {
 	return validation.Diagnostics, nil
 }
 
+// Plan runs `terraform plan -json` with the given var files and streams
+// the machine-readable output (see [PlanMessage]) into w.
+// It reports whether there are any changes planned.
+func (e *Executor) Plan(ctx context.Context, w io.Writer, varFiles []string) (bool, error) {
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	err := e.setLogPath("Plan")
+	if err != nil {
+		return false, err
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "terraform-exec:Plan")
+	defer span.End()
+
+	// The plan is only used for feedback in the editor, so we avoid
+	// locking the state, which could get in the way of the user
+	opts := []tfexec.PlanOption{
+		tfexec.Lock(false),
+	}
+	for _, varFile := range varFiles {
+		opts = append(opts, tfexec.VarFile(varFile))
+	}
+
+	hasChanges, err := e.tf.PlanJSON(ctx, w, opts...)
+	e.setSpanStatus(span, err)
+	if err != nil {
+		return false, e.contextfulError(ctx, "Plan", err)
+	}
+
+	return hasChanges, nil
+}
+
 func (e *Executor) Version(ctx context.Context) (*version.Version, map[string]*version.Version, error) {
 	ctx, cancel := e.withTimeout(ctx)
 	defer cancel()
@@ -6,12 +6,27 @@ package exec
 import (
 	"context"
 	"time"
+
+	"github.com/hashicorp/go-version"
 )
 
 type ExecutorOpts struct {
 	ExecPath    string
 	ExecLogPath string
 	Timeout     time.Duration
+
+	// ToolchainDir is a directory of versioned Terraform binaries
+	// to pick from for each module, instead of ExecPath
+	ToolchainDir string
+	// ModuleVersion returns the Terraform version pinned for the module
+	// and the required_version constraints it declares, if known
+	ModuleVersion func(modPath string) (*version.Version, version.Constraints)
+
+	// OpenTofuExecPath is the path to the OpenTofu binary (tofu)
+	// used for modules which target OpenTofu
+	OpenTofuExecPath string
+	// IsOpenTofuModule reports whether the module targets OpenTofu
+	IsOpenTofuModule func(modPath string) bool
 }
 
 var ctxExecOpts = ctxKey("executor opts")
@@ -5,6 +5,8 @@ package mock
 import (
 	context "context"
 
+	io "io"
+
 	log "log"
 
 	mock "github.com/stretchr/testify/mock"
@@ -102,6 +104,27 @@ func (_m *Executor) Init(ctx context.Context, opts ...tfexec.InitOption) error {
 	return r0
 }
 
+// Plan provides a mock function with given fields: ctx, w, varFiles
+func (_m *Executor) Plan(ctx context.Context, w io.Writer, varFiles []string) (bool, error) {
+	ret := _m.Called(ctx, w, varFiles)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, io.Writer, []string) bool); ok {
+		r0 = rf(ctx, w, varFiles)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, io.Writer, []string) error); ok {
+		r1 = rf(ctx, w, varFiles)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ProviderSchemas provides a mock function with given fields: ctx
 func (_m *Executor) ProviderSchemas(ctx context.Context) (*tfjson.ProviderSchemas, error) {
 	ret := _m.Called(ctx)
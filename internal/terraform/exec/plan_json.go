@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package exec
+
+import (
+	"bytes"
+	"encoding/json"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// PlanMessage represents a single line of the machine-readable
+// output of `terraform plan -json`
+type PlanMessage struct {
+	Level      string             `json:"@level"`
+	Message    string             `json:"@message"`
+	Type       string             `json:"type"`
+	Diagnostic *tfjson.Diagnostic `json:"diagnostic,omitempty"`
+	Change     *PlannedChange     `json:"change,omitempty"`
+}
+
+// PlannedChange describes the action planned for a single resource instance
+type PlannedChange struct {
+	Resource PlannedResource `json:"resource"`
+	Action   string          `json:"action"`
+}
+
+type PlannedResource struct {
+	Addr         string `json:"addr"`
+	Module       string `json:"module"`
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+}
+
+// PlanMessageWriter is an io.Writer which decodes
+// the streamed JSON lines into messages as they arrive.
+// Lines which cannot be decoded are ignored.
+type PlanMessageWriter struct {
+	buf       []byte
+	onMessage func(PlanMessage)
+}
+
+func NewPlanMessageWriter(onMessage func(PlanMessage)) *PlanMessageWriter {
+	return &PlanMessageWriter{
+		onMessage: onMessage,
+	}
+}
+
+func (w *PlanMessageWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		w.decodeLine(w.buf[:idx])
+		w.buf = w.buf[idx+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush decodes any remaining line which was not terminated
+func (w *PlanMessageWriter) Flush() {
+	w.decodeLine(w.buf)
+	w.buf = nil
+}
+
+func (w *PlanMessageWriter) decodeLine(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+
+	var msg PlanMessage
+	err := json.Unmarshal(line, &msg)
+	if err != nil {
+		return
+	}
+	w.onMessage(msg)
+}
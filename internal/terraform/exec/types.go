@@ -5,6 +5,7 @@ package exec
 
 import (
 	"context"
+	"io"
 	"log"
 	"time"
 
@@ -31,5 +32,6 @@ type TerraformExecutor interface {
 	Format(ctx context.Context, input []byte) ([]byte, error)
 	Version(ctx context.Context) (*version.Version, map[string]*version.Version, error)
 	Validate(ctx context.Context) ([]tfjson.Diagnostic, error)
+	Plan(ctx context.Context, w io.Writer, varFiles []string) (bool, error)
 	ProviderSchemas(ctx context.Context) (*tfjson.ProviderSchemas, error)
 }
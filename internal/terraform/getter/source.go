@@ -0,0 +1,276 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package getter parses remote module source addresses in the forms
+// supported by go-getter, i.e. the forms Terraform accepts
+// for modules which are not local or installed from a registry.
+package getter
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Getter is the name of the getter used to download a module
+type Getter string
+
+const (
+	GitGetter   Getter = "git"
+	HgGetter    Getter = "hg"
+	S3Getter    Getter = "s3"
+	GCSGetter   Getter = "gcs"
+	HTTPGetter  Getter = "http"
+	HTTPSGetter Getter = "https"
+)
+
+var knownGetters = map[Getter]bool{
+	GitGetter:   true,
+	HgGetter:    true,
+	S3Getter:    true,
+	GCSGetter:   true,
+	HTTPGetter:  true,
+	HTTPSGetter: true,
+}
+
+var (
+	forcedGetterRe = regexp.MustCompile(`^([A-Za-z0-9]+)::(.*)$`)
+	scpLikeRe      = regexp.MustCompile(`^(?:[\w.-]+@)?[\w.-]+:[\w./~-]`)
+)
+
+// Source represents a parsed remote module source address
+type Source struct {
+	// Getter is the getter used to download the module,
+	// either forced via the <getter>:: prefix or detected
+	Getter Getter
+
+	// URL is the address of the package, without the subdirectory
+	// and the query arguments interpreted by the getter
+	URL string
+
+	// Ref is the value of the ref argument (i.e. the git branch,
+	// tag or commit), if any
+	Ref string
+
+	// Subdir is the subdirectory within the package
+	// (declared after //), if any
+	Subdir string
+
+	// Query contains other arguments of the address, such as depth
+	// or archive
+	Query url.Values
+}
+
+// SamePackage returns true if both sources refer to the same
+// package (and subdirectory), regardless of the ref
+func (s Source) SamePackage(other Source) bool {
+	return s.Getter == other.Getter &&
+		strings.TrimSuffix(s.URL, "/") == strings.TrimSuffix(other.URL, "/") &&
+		s.Subdir == other.Subdir
+}
+
+// Parse parses the given remote module source address,
+// such as git::https://example.com/vpc.git//modules/subnet?ref=v1.2.0,
+// github.com/hashicorp/example or s3::https://s3.amazonaws.com/bucket/vpc.zip
+//
+// An error is returned if the address is malformed or if it cannot
+// be recognized as any of the forms supported by go-getter.
+func Parse(raw string) (Source, error) {
+	var src Source
+	if strings.TrimSpace(raw) == "" {
+		return src, fmt.Errorf("source address must not be empty")
+	}
+
+	addr := raw
+	if m := forcedGetterRe.FindStringSubmatch(addr); m != nil {
+		src.Getter = Getter(m[1])
+		addr = m[2]
+		if !knownGetters[src.Getter] {
+			return src, fmt.Errorf("unsupported getter %q", src.Getter)
+		}
+		if addr == "" {
+			return src, fmt.Errorf("missing address after %q", string(src.Getter)+"::")
+		}
+	}
+
+	addr, rawQuery, _ := strings.Cut(addr, "?")
+	addr, subdir, err := splitSubdir(addr)
+	if err != nil {
+		return src, err
+	}
+	src.Subdir = subdir
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return src, fmt.Errorf("invalid query string %q: %s", rawQuery, err)
+	}
+	if refs, ok := query["ref"]; ok {
+		if len(refs) != 1 || refs[0] == "" {
+			return src, fmt.Errorf("ref argument must have exactly one non-empty value")
+		}
+		src.Ref = refs[0]
+		query.Del("ref")
+	}
+	src.Query = query
+
+	getter, detectedUrl, err := detect(addr, src.Getter)
+	if err != nil {
+		return src, err
+	}
+	src.Getter = getter
+	src.URL = detectedUrl
+
+	if src.Ref != "" && src.Getter != GitGetter && src.Getter != HgGetter {
+		return src, fmt.Errorf("ref argument is only supported by git and hg sources")
+	}
+
+	return src, nil
+}
+
+// splitSubdir splits the package address from the subdirectory
+// declared after // (which is not part of a URL scheme)
+func splitSubdir(addr string) (string, string, error) {
+	offset := 0
+	if idx := strings.Index(addr, "://"); idx > -1 {
+		offset = idx + len("://")
+	}
+
+	idx := strings.Index(addr[offset:], "//")
+	if idx == -1 {
+		return addr, "", nil
+	}
+	idx += offset
+
+	subdir := addr[idx+len("//"):]
+	if subdir == "" {
+		return "", "", fmt.Errorf("subdirectory after // must not be empty")
+	}
+	cleaned := path.Clean(subdir)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") || path.IsAbs(cleaned) {
+		return "", "", fmt.Errorf("subdirectory %q must not escape the package", subdir)
+	}
+
+	return addr[:idx], cleaned, nil
+}
+
+// detect recognizes the getter of the given address (unless forced)
+// and returns the address as a URL which the getter understands
+func detect(addr string, forced Getter) (Getter, string, error) {
+	switch {
+	case strings.HasPrefix(addr, "github.com/"):
+		return detectHostedGit(addr, forced, "GitHub")
+	case strings.HasPrefix(addr, "bitbucket.org/"):
+		return detectHostedGit(addr, forced, "Bitbucket")
+	}
+
+	if !strings.Contains(addr, "://") {
+		if scpLikeRe.MatchString(addr) && (forced == "" || forced == GitGetter) {
+			// e.g. git@github.com:hashicorp/example.git
+			host, repoPath, _ := strings.Cut(addr, ":")
+			return GitGetter, fmt.Sprintf("ssh://%s/%s", host, repoPath), nil
+		}
+
+		host, _, _ := strings.Cut(addr, "/")
+		switch {
+		case isS3Host(host) && (forced == "" || forced == S3Getter):
+			return detectS3("https://" + addr)
+		case host == "www.googleapis.com" && (forced == "" || forced == GCSGetter):
+			return detectGCS("https://" + addr)
+		}
+		return forced, "", fmt.Errorf("unable to detect the source type of %q; "+
+			"use a URL or a getter prefix such as git::", addr)
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return forced, "", fmt.Errorf("invalid URL %q: %s", addr, err)
+	}
+	if u.Host == "" && u.Scheme != "file" {
+		return forced, "", fmt.Errorf("URL %q must contain a host", addr)
+	}
+
+	switch forced {
+	case GitGetter, HgGetter:
+		switch u.Scheme {
+		case "http", "https", "ssh", "git", "file":
+			return forced, addr, nil
+		}
+		return forced, "", fmt.Errorf("scheme %q is not supported by the %s getter", u.Scheme, forced)
+	case S3Getter:
+		return detectS3(addr)
+	case GCSGetter:
+		return detectGCS(addr)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		// e.g. archives, or URLs redirecting via X-Terraform-Get
+		if forced == "" {
+			return Getter(u.Scheme), addr, nil
+		}
+		return forced, addr, nil
+	}
+	return forced, "", fmt.Errorf("scheme %q is not supported without a getter prefix "+
+		"such as git::", u.Scheme)
+}
+
+func detectHostedGit(addr string, forced Getter, service string) (Getter, string, error) {
+	if forced != "" && forced != GitGetter {
+		return forced, "", fmt.Errorf("%s sources are not supported by the %s getter", service, forced)
+	}
+
+	host, repoPath, _ := strings.Cut(addr, "/")
+	parts := strings.Split(strings.TrimSuffix(repoPath, ".git"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return forced, "", fmt.Errorf("%s source must be in the form %s/<owner>/<repository>", service, host)
+	}
+
+	return GitGetter, fmt.Sprintf("https://%s/%s/%s.git", host, parts[0], parts[1]), nil
+}
+
+func detectS3(addr string) (Getter, string, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return S3Getter, "", fmt.Errorf("invalid URL %q: %s", addr, err)
+	}
+	pathParts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if !isS3Host(u.Host) || strings.HasPrefix(u.Host, "s3") {
+		// path-style (or custom endpoint) URL, i.e. <host>/<bucket>/<key>
+		if len(pathParts) < 2 || pathParts[0] == "" || pathParts[1] == "" {
+			return S3Getter, "", fmt.Errorf("S3 source must contain both a bucket and an object key")
+		}
+		return S3Getter, addr, nil
+	}
+	// virtual-hosted-style URL, i.e. <bucket>.s3.amazonaws.com/<key>
+	// which is normalized to the path-style
+	if pathParts[0] == "" {
+		return S3Getter, "", fmt.Errorf("S3 source must contain an object key")
+	}
+	bucket, host, _ := strings.Cut(u.Host, ".")
+	u.Host = host
+	u.Path = "/" + bucket + u.Path
+	return S3Getter, u.String(), nil
+}
+
+func isS3Host(host string) bool {
+	return strings.HasSuffix(host, ".amazonaws.com") &&
+		(strings.HasPrefix(host, "s3") || strings.Contains(host, ".s3"))
+}
+
+func detectGCS(addr string) (Getter, string, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return GCSGetter, "", fmt.Errorf("invalid URL %q: %s", addr, err)
+	}
+	if u.Host != "www.googleapis.com" {
+		return GCSGetter, "", fmt.Errorf("GCS source must be hosted at www.googleapis.com")
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] != "storage" || parts[2] == "" || parts[3] == "" {
+		return GCSGetter, "", fmt.Errorf("GCS source must be in the form " +
+			"www.googleapis.com/storage/<version>/<bucket>/<path>")
+	}
+	return GCSGetter, addr, nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		raw            string
+		expectedSource Source
+	}{
+		{
+			"github.com/hashicorp/example",
+			Source{Getter: GitGetter, URL: "https://github.com/hashicorp/example.git"},
+		},
+		{
+			"github.com/hashicorp/example//modules/vpc?ref=v1.2.0",
+			Source{
+				Getter: GitGetter,
+				URL:    "https://github.com/hashicorp/example.git",
+				Ref:    "v1.2.0",
+				Subdir: "modules/vpc",
+			},
+		},
+		{
+			"git::https://example.com/vpc.git?ref=main&depth=1",
+			Source{
+				Getter: GitGetter,
+				URL:    "https://example.com/vpc.git",
+				Ref:    "main",
+				Query:  url.Values{"depth": []string{"1"}},
+			},
+		},
+		{
+			"git@github.com:hashicorp/example.git",
+			Source{Getter: GitGetter, URL: "ssh://git@github.com/hashicorp/example.git"},
+		},
+		{
+			"git::ssh://git@example.com/storage.git",
+			Source{Getter: GitGetter, URL: "ssh://git@example.com/storage.git"},
+		},
+		{
+			"bitbucket.org/hashicorp/example",
+			Source{Getter: GitGetter, URL: "https://bitbucket.org/hashicorp/example.git"},
+		},
+		{
+			"s3::https://s3-eu-west-1.amazonaws.com/examplecorp/vpc.zip",
+			Source{Getter: S3Getter, URL: "https://s3-eu-west-1.amazonaws.com/examplecorp/vpc.zip"},
+		},
+		{
+			"examplecorp.s3.amazonaws.com/vpc.zip",
+			Source{Getter: S3Getter, URL: "https://s3.amazonaws.com/examplecorp/vpc.zip"},
+		},
+		{
+			"gcs::https://www.googleapis.com/storage/v1/modules/foomodule.zip",
+			Source{Getter: GCSGetter, URL: "https://www.googleapis.com/storage/v1/modules/foomodule.zip"},
+		},
+		{
+			"https://example.com/vpc-module.zip?archive=zip",
+			Source{
+				Getter: HTTPSGetter,
+				URL:    "https://example.com/vpc-module.zip",
+				Query:  url.Values{"archive": []string{"zip"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.raw, func(t *testing.T) {
+			src, err := Parse(tc.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.expectedSource.Query == nil {
+				tc.expectedSource.Query = url.Values{}
+			}
+			if diff := cmp.Diff(tc.expectedSource, src); diff != "" {
+				t.Fatalf("unexpected source: %s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	testCases := []struct {
+		raw           string
+		expectedError string
+	}{
+		{"foo::https://example.com/vpc", `unsupported getter "foo"`},
+		{"git::", `missing address after "git::"`},
+		{"github.com/hashicorp", "GitHub source must be in the form github.com/<owner>/<repository>"},
+		{"git::https://example.com/vpc.git?ref=", "ref argument must have exactly one non-empty value"},
+		{"https://example.com/vpc.zip?ref=v1", "ref argument is only supported by git and hg sources"},
+		{"git::https://example.com/vpc.git//", "subdirectory after // must not be empty"},
+		{"git::https://example.com/vpc.git//../other", `subdirectory "../other" must not escape the package`},
+		{"ssh://git@example.com/vpc.git", `scheme "ssh" is not supported without a getter prefix such as git::`},
+		{"git::ftp://example.com/vpc.git", `scheme "ftp" is not supported by the git getter`},
+		{"s3::https://s3.amazonaws.com/examplecorp", "S3 source must contain both a bucket and an object key"},
+		{"gcs::https://example.com/modules/vpc.zip", "GCS source must be hosted at www.googleapis.com"},
+		{"example.com/vpc", `unable to detect the source type of "example.com/vpc"; use a URL or a getter prefix such as git::`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.raw, func(t *testing.T) {
+			_, err := Parse(tc.raw)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if err.Error() != tc.expectedError {
+				t.Fatalf("unexpected error: %q, expected: %q", err, tc.expectedError)
+			}
+		})
+	}
+}
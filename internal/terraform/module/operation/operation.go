@@ -48,4 +48,7 @@ const (
 	OpTypeDecodeTestReferenceOrigins
 	OpTypeDecodeWriteOnlyAttributes
 	OpTypeSchemaTestValidation
+	OpTypeTerraformPlan
+	OpTypeTFLint
+	OpTypeCustomRulesValidation
 )
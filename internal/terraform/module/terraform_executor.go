@@ -7,7 +7,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/hashicorp/terraform-ls/internal/terraform/toolchain"
 )
 
 func TerraformExecutorForModule(ctx context.Context, modPath string) (exec.TerraformExecutor, error) {
@@ -16,7 +18,7 @@ func TerraformExecutorForModule(ctx context.Context, modPath string) (exec.Terra
 		return nil, fmt.Errorf("no terraform executor provided")
 	}
 
-	execPath, err := TerraformExecPath(ctx)
+	execPath, err := terraformExecPathForModule(ctx, modPath)
 	if err != nil {
 		return nil, err
 	}
@@ -45,3 +47,40 @@ func TerraformExecPath(ctx context.Context) (string, error) {
 		return "", NoTerraformExecPathErr{}
 	}
 }
+
+// terraformExecPathForModule picks the OpenTofu binary for modules
+// targeting OpenTofu, or otherwise the newest binary from the toolchain
+// directory which satisfies the module's version requirements,
+// falling back to the default path if there is none.
+func terraformExecPathForModule(ctx context.Context, modPath string) (string, error) {
+	opts, ok := exec.ExecutorOptsFromContext(ctx)
+	if !ok {
+		return TerraformExecPath(ctx)
+	}
+
+	if opts.OpenTofuExecPath != "" && opts.IsOpenTofuModule != nil && opts.IsOpenTofuModule(modPath) {
+		return opts.OpenTofuExecPath, nil
+	}
+
+	if opts.ToolchainDir == "" {
+		return TerraformExecPath(ctx)
+	}
+
+	binaries, err := toolchain.FindBinaries(opts.ToolchainDir)
+	if err != nil {
+		return TerraformExecPath(ctx)
+	}
+
+	var pinned *version.Version
+	var constraints version.Constraints
+	if opts.ModuleVersion != nil {
+		pinned, constraints = opts.ModuleVersion(modPath)
+	}
+
+	binary, ok := toolchain.SelectBinary(binaries, pinned, constraints)
+	if !ok {
+		return TerraformExecPath(ctx)
+	}
+
+	return binary.Path, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package opentofu
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sync"
+)
+
+type FS interface {
+	ReadDir(name string) ([]fs.DirEntry, error)
+}
+
+// Detector detects directories targeting OpenTofu and caches
+// the result per directory, so that the directory isn't read
+// on every lookup.
+type Detector struct {
+	fs FS
+
+	mu       sync.RWMutex
+	detected map[string]bool
+}
+
+func NewDetector(fs FS) *Detector {
+	return &Detector{
+		fs:       fs,
+		detected: make(map[string]bool, 0),
+	}
+}
+
+// IsOpenTofuDir reports whether the given directory contains
+// any OpenTofu-specific files (see [ContainsOpenTofuFiles]).
+func (d *Detector) IsOpenTofuDir(dirPath string) bool {
+	dirPath = filepath.Clean(dirPath)
+
+	d.mu.RLock()
+	isOpenTofu, ok := d.detected[dirPath]
+	d.mu.RUnlock()
+	if ok {
+		return isOpenTofu
+	}
+
+	entries, err := d.fs.ReadDir(dirPath)
+	if err != nil {
+		// the directory may not exist yet, so we don't cache the result
+		return false
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	isOpenTofu = ContainsOpenTofuFiles(names)
+
+	d.mu.Lock()
+	d.detected[dirPath] = isOpenTofu
+	d.mu.Unlock()
+
+	return isOpenTofu
+}
+
+// Invalidate forgets the result for the given directory,
+// e.g. when an OpenTofu-specific file is created or removed there.
+func (d *Detector) Invalidate(dirPath string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.detected, filepath.Clean(dirPath))
+}
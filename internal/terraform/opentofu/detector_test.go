@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package opentofu
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+type countingFS struct {
+	fstest.MapFS
+	readDirCalls int
+}
+
+func (c *countingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	c.readDirCalls++
+	return c.MapFS.ReadDir(name)
+}
+
+func TestDetector(t *testing.T) {
+	fsys := &countingFS{
+		MapFS: fstest.MapFS{
+			"tf/main.tf":     &fstest.MapFile{},
+			"tofu/main.tofu": &fstest.MapFile{},
+		},
+	}
+	d := NewDetector(fsys)
+
+	if d.IsOpenTofuDir("tf") {
+		t.Fatal("expected tf not to target OpenTofu")
+	}
+	if !d.IsOpenTofuDir("tofu") {
+		t.Fatal("expected tofu to target OpenTofu")
+	}
+	d.IsOpenTofuDir("tf")
+	d.IsOpenTofuDir("tofu")
+	if fsys.readDirCalls != 2 {
+		t.Fatalf("expected results to be cached, given %d ReadDir calls", fsys.readDirCalls)
+	}
+
+	fsys.MapFS["tf/providers.tofu"] = &fstest.MapFile{}
+	if d.IsOpenTofuDir("tf") {
+		t.Fatal("expected cached result before invalidation")
+	}
+	d.Invalidate("tf")
+	if !d.IsOpenTofuDir("tf") {
+		t.Fatal("expected tf to target OpenTofu after invalidation")
+	}
+}
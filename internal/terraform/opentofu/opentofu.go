@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package opentofu provides what the language server needs to understand
+// configuration targeting OpenTofu rather than Terraform, i.e. OpenTofu-specific
+// files, provider addresses and language constructs.
+package opentofu
+
+import (
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+)
+
+// DefaultProviderRegistryHost is the hostname OpenTofu uses
+// for provider source addresses which don't specify one
+const DefaultProviderRegistryHost = svchost.Hostname("registry.opentofu.org")
+
+// IsModuleFilename reports whether the given name is an OpenTofu-specific
+// module file, which takes precedence over a *.tf file of the same name.
+func IsModuleFilename(name string) bool {
+	return strings.HasSuffix(name, ".tofu") ||
+		strings.HasSuffix(name, ".tofu.json")
+}
+
+// IsTestFilename reports whether the given name is an OpenTofu-specific
+// test file, which takes precedence over a *.tftest.hcl file of the same name.
+func IsTestFilename(name string) bool {
+	return strings.HasSuffix(name, ".tofutest.hcl") ||
+		strings.HasSuffix(name, ".tofutest.json")
+}
+
+// ContainsOpenTofuFiles reports whether any of the given file names
+// is specific to OpenTofu, which means the directory targets OpenTofu.
+func ContainsOpenTofuFiles(names []string) bool {
+	for _, name := range names {
+		if IsModuleFilename(name) || IsTestFilename(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// OverriddenFilename returns the name of the Terraform file which
+// the given OpenTofu-specific file takes precedence over, if any.
+func OverriddenFilename(name string) (string, bool) {
+	switch {
+	case strings.HasSuffix(name, ".tofu.json"):
+		return strings.TrimSuffix(name, ".tofu.json") + ".tf.json", true
+	case strings.HasSuffix(name, ".tofu"):
+		return strings.TrimSuffix(name, ".tofu") + ".tf", true
+	case strings.HasSuffix(name, ".tofutest.hcl"):
+		return strings.TrimSuffix(name, ".tofutest.hcl") + ".tftest.hcl", true
+	case strings.HasSuffix(name, ".tofutest.json"):
+		return strings.TrimSuffix(name, ".tofutest.json") + ".tftest.json", true
+	}
+	return "", false
+}
+
+// OverriddenFilenames returns names of Terraform files which are
+// shadowed by OpenTofu-specific files among the given names,
+// i.e. files which OpenTofu ignores.
+func OverriddenFilenames(names []string) map[string]bool {
+	overridden := make(map[string]bool, 0)
+	for _, name := range names {
+		if tfName, ok := OverriddenFilename(name); ok {
+			overridden[tfName] = true
+		}
+	}
+	return overridden
+}
@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package opentofu
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+)
+
+func TestOverriddenFilenames(t *testing.T) {
+	names := []string{
+		"main.tf",
+		"main.tofu",
+		"outputs.tf",
+		"variables.tofu.json",
+		"basic.tftest.hcl",
+		"basic.tofutest.hcl",
+	}
+
+	expected := map[string]bool{
+		"main.tf":           true,
+		"variables.tf.json": true,
+		"basic.tftest.hcl":  true,
+	}
+	if diff := cmp.Diff(expected, OverriddenFilenames(names)); diff != "" {
+		t.Fatalf("unexpected overridden files: %s", diff)
+	}
+}
+
+func TestContainsOpenTofuFiles(t *testing.T) {
+	if ContainsOpenTofuFiles([]string{"main.tf", "terraform.tfvars"}) {
+		t.Fatal("expected no OpenTofu files")
+	}
+	if !ContainsOpenTofuFiles([]string{"main.tf", "providers.tofu"}) {
+		t.Fatal("expected OpenTofu files")
+	}
+}
+
+func TestProviderAddr(t *testing.T) {
+	src := []byte(`terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+    google = {
+      source = "registry.terraform.io/hashicorp/google"
+    }
+  }
+}
+`)
+	f, diags := hclsyntax.ParseConfig(src, "main.tofu", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	explicitlyHosted := ExplicitlyHostedProviders(map[string]*hcl.File{"main.tofu": f})
+
+	aws := tfaddr.MustParseProviderSource("hashicorp/aws")
+	if addr := ProviderAddr(aws, explicitlyHosted); addr.Hostname != DefaultProviderRegistryHost {
+		t.Fatalf("expected implied host to be %s, given: %s", DefaultProviderRegistryHost, addr)
+	}
+
+	google := tfaddr.MustParseProviderSource("hashicorp/google")
+	if addr := ProviderAddr(google, explicitlyHosted); addr.Hostname != tfaddr.DefaultProviderRegistryHost {
+		t.Fatalf("expected explicit host to be kept, given: %s", addr)
+	}
+
+	custom := tfaddr.MustParseProviderSource("example.com/acme/foo")
+	if addr := ProviderAddr(custom, explicitlyHosted); !addr.Equals(custom) {
+		t.Fatalf("expected custom host to be kept, given: %s", addr)
+	}
+}
+
+func TestPatchModuleSchema(t *testing.T) {
+	original := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"terraform": {
+				Body: &schema.BodySchema{
+					Blocks: map[string]*schema.BlockSchema{
+						"backend": {},
+					},
+				},
+			},
+			"provider": {
+				Body: &schema.BodySchema{
+					Extensions: &schema.BodyExtensions{DynamicBlocks: true},
+				},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+					"aws": {
+						Extensions: &schema.BodyExtensions{DynamicBlocks: true},
+					},
+				},
+			},
+		},
+	}
+
+	patched := PatchModuleSchema(original)
+
+	if _, ok := patched.Blocks["terraform"].Body.Blocks["encryption"]; !ok {
+		t.Fatal("expected encryption block in patched schema")
+	}
+	if !patched.Blocks["provider"].Body.Extensions.ForEach {
+		t.Fatal("expected for_each in patched provider block")
+	}
+	depBody := patched.Blocks["provider"].DependentBody["aws"]
+	if !depBody.Extensions.ForEach || !depBody.Extensions.DynamicBlocks {
+		t.Fatalf("expected for_each and dynamic blocks in dependent body, given: %#v", depBody.Extensions)
+	}
+
+	// original schema must be left intact
+	if _, ok := original.Blocks["terraform"].Body.Blocks["encryption"]; ok {
+		t.Fatal("expected original schema to be unchanged")
+	}
+	if original.Blocks["provider"].Body.Extensions.ForEach ||
+		original.Blocks["provider"].DependentBody["aws"].Extensions.ForEach {
+		t.Fatal("expected original provider schema to be unchanged")
+	}
+}
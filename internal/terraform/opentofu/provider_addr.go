@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package opentofu
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ProviderAddr returns the address which OpenTofu resolves the given
+// provider address to, i.e. one on [DefaultProviderRegistryHost],
+// unless the address is known to declare its hostname explicitly.
+func ProviderAddr(addr tfaddr.Provider, explicitlyHosted map[tfaddr.Provider]bool) tfaddr.Provider {
+	if addr.Hostname != tfaddr.DefaultProviderRegistryHost || explicitlyHosted[addr] {
+		return addr
+	}
+	addr.Hostname = DefaultProviderRegistryHost
+	return addr
+}
+
+// ExplicitlyHostedProviders returns providers whose source address
+// in required_providers declares the hostname explicitly,
+// e.g. registry.terraform.io/hashicorp/aws rather than hashicorp/aws.
+func ExplicitlyHostedProviders(files map[string]*hcl.File) map[tfaddr.Provider]bool {
+	providers := make(map[tfaddr.Provider]bool, 0)
+
+	for _, f := range files {
+		content, _, _ := f.Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{{Type: "terraform"}},
+		})
+		for _, tfBlock := range content.Blocks {
+			tfContent, _, _ := tfBlock.Body.PartialContent(&hcl.BodySchema{
+				Blocks: []hcl.BlockHeaderSchema{{Type: "required_providers"}},
+			})
+			for _, rpBlock := range tfContent.Blocks {
+				attrs, _ := rpBlock.Body.JustAttributes()
+				for _, attr := range attrs {
+					source, ok := sourceFromRequirement(attr.Expr)
+					if !ok || strings.Count(source, "/") != 2 {
+						continue
+					}
+					addr, err := tfaddr.ParseProviderSource(source)
+					if err != nil {
+						continue
+					}
+					providers[addr] = true
+				}
+			}
+		}
+	}
+
+	return providers
+}
+
+func sourceFromRequirement(expr hcl.Expression) (string, bool) {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() {
+		return "", false
+	}
+	if !val.Type().IsObjectType() || !val.Type().HasAttribute("source") {
+		return "", false
+	}
+	source := val.GetAttr("source")
+	if source.IsNull() || source.Type() != cty.String {
+		return "", false
+	}
+	return source.AsString(), true
+}
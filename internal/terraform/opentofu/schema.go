@@ -0,0 +1,215 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package opentofu
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var (
+	keyProviderScope = lang.ScopeId("key_provider")
+	methodScope      = lang.ScopeId("encryption_method")
+)
+
+// PatchModuleSchema returns a copy of the given module schema
+// extended with OpenTofu-only constructs, i.e. the encryption block
+// within the terraform block and for_each in provider blocks.
+//
+// Only the blocks which are patched are copied, so that
+// the (potentially large) rest of the schema can be shared.
+func PatchModuleSchema(bs *schema.BodySchema) *schema.BodySchema {
+	if bs == nil {
+		return nil
+	}
+
+	patched := *bs
+	patched.Blocks = make(map[string]*schema.BlockSchema, len(bs.Blocks))
+	for name, block := range bs.Blocks {
+		patched.Blocks[name] = block
+	}
+
+	if tfBlock, ok := patched.Blocks["terraform"]; ok && tfBlock.Body != nil {
+		block := *tfBlock
+		body := *tfBlock.Body
+		body.Blocks = make(map[string]*schema.BlockSchema, len(tfBlock.Body.Blocks)+1)
+		for name, b := range tfBlock.Body.Blocks {
+			body.Blocks[name] = b
+		}
+		body.Blocks["encryption"] = encryptionBlockSchema()
+		block.Body = &body
+		patched.Blocks["terraform"] = &block
+	}
+
+	if providerBlock, ok := patched.Blocks["provider"]; ok && providerBlock.Body != nil {
+		block := *providerBlock
+		block.Body = withForEach(providerBlock.Body)
+
+		// Extensions of a dependent body take precedence
+		// over those of the static body when merged
+		if len(providerBlock.DependentBody) > 0 {
+			block.DependentBody = make(map[schema.SchemaKey]*schema.BodySchema, len(providerBlock.DependentBody))
+			for key, depBody := range providerBlock.DependentBody {
+				if depBody.Extensions != nil {
+					depBody = withForEach(depBody)
+				}
+				block.DependentBody[key] = depBody
+			}
+		}
+		patched.Blocks["provider"] = &block
+	}
+
+	return &patched
+}
+
+func withForEach(bs *schema.BodySchema) *schema.BodySchema {
+	body := *bs
+	if bs.Extensions != nil {
+		body.Extensions = bs.Extensions.Copy()
+	} else {
+		body.Extensions = &schema.BodyExtensions{}
+	}
+	body.Extensions.ForEach = true
+	return &body
+}
+
+func encryptionBlockSchema() *schema.BlockSchema {
+	return &schema.BlockSchema{
+		Description: lang.Markdown("State and plan encryption configuration (OpenTofu only)"),
+		MaxItems:    1,
+		Body: &schema.BodySchema{
+			Blocks: map[string]*schema.BlockSchema{
+				"key_provider": {
+					Description: lang.Markdown("Key provider which supplies keys to encryption methods"),
+					Address: &schema.BlockAddrSchema{
+						Steps: []schema.AddrStep{
+							schema.StaticStep{Name: "key_provider"},
+							schema.LabelStep{Index: 0},
+							schema.LabelStep{Index: 1},
+						},
+						FriendlyName: "key_provider",
+						ScopeId:      keyProviderScope,
+						AsReference:  true,
+					},
+					Labels: []*schema.LabelSchema{
+						{
+							Name:        "type",
+							Description: lang.Markdown("Key provider type, e.g. `pbkdf2`, `aws_kms`, `gcp_kms` or `openbao`"),
+							Completable: true,
+						},
+						{
+							Name:        "name",
+							Description: lang.PlainText("Key provider name"),
+						},
+					},
+					Body: &schema.BodySchema{
+						AnyAttribute: &schema.AttributeSchema{
+							Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+							IsOptional: true,
+						},
+					},
+				},
+				"method": {
+					Description: lang.Markdown("Encryption method which uses keys from a key provider"),
+					Address: &schema.BlockAddrSchema{
+						Steps: []schema.AddrStep{
+							schema.StaticStep{Name: "method"},
+							schema.LabelStep{Index: 0},
+							schema.LabelStep{Index: 1},
+						},
+						FriendlyName: "method",
+						ScopeId:      methodScope,
+						AsReference:  true,
+					},
+					Labels: []*schema.LabelSchema{
+						{
+							Name:        "type",
+							Description: lang.Markdown("Encryption method type, e.g. `aes_gcm` or `unencrypted`"),
+							Completable: true,
+						},
+						{
+							Name:        "name",
+							Description: lang.PlainText("Encryption method name"),
+						},
+					},
+					Body: &schema.BodySchema{
+						Attributes: map[string]*schema.AttributeSchema{
+							"keys": {
+								Constraint:  schema.Reference{OfScopeId: keyProviderScope},
+								IsOptional:  true,
+								Description: lang.Markdown("Key provider supplying the keys, e.g. `key_provider.pbkdf2.mykey`"),
+							},
+						},
+					},
+				},
+				"state": {
+					Description: lang.Markdown("Encryption of the state file"),
+					MaxItems:    1,
+					Body:        targetBodySchema(),
+				},
+				"plan": {
+					Description: lang.Markdown("Encryption of the plan file"),
+					MaxItems:    1,
+					Body:        targetBodySchema(),
+				},
+				"remote_state_data_sources": {
+					Description: lang.Markdown("Encryption of state read via `terraform_remote_state` data sources"),
+					MaxItems:    1,
+					Body: &schema.BodySchema{
+						Blocks: map[string]*schema.BlockSchema{
+							"default": {
+								Description: lang.Markdown("Encryption used for all remote state data sources by default"),
+								MaxItems:    1,
+								Body:        targetBodySchema(),
+							},
+							"remote_state_data_source": {
+								Description: lang.Markdown("Encryption used for a particular remote state data source"),
+								Labels: []*schema.LabelSchema{
+									{
+										Name:        "address",
+										Description: lang.Markdown("Address of the data source, e.g. `data.terraform_remote_state.foo`"),
+									},
+								},
+								Body: targetBodySchema(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func targetBodySchema() *schema.BodySchema {
+	return &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"method": {
+				Constraint:  schema.Reference{OfScopeId: methodScope},
+				IsRequired:  true,
+				Description: lang.Markdown("Encryption method to use, e.g. `method.aes_gcm.mymethod`"),
+			},
+			"enforced": {
+				Constraint:  schema.LiteralType{Type: cty.Bool},
+				IsOptional:  true,
+				Description: lang.Markdown("Whether to refuse writing unencrypted data"),
+			},
+		},
+		Blocks: map[string]*schema.BlockSchema{
+			"fallback": {
+				Description: lang.Markdown("Method used to read data encrypted previously, e.g. during key rotation"),
+				MaxItems:    1,
+				Body: &schema.BodySchema{
+					Attributes: map[string]*schema.AttributeSchema{
+						"method": {
+							Constraint:  schema.Reference{OfScopeId: methodScope},
+							IsRequired:  true,
+							Description: lang.Markdown("Encryption method to fall back to"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
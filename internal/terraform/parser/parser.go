@@ -4,6 +4,7 @@
 package parser
 
 import (
+	"crypto/sha256"
 	"io/fs"
 
 	"github.com/hashicorp/hcl/v2"
@@ -28,3 +29,12 @@ func ParseFile(src []byte, filename filename) (*hcl.File, hcl.Diagnostics) {
 	}
 	return hclsyntax.ParseConfig(src, filename.String(), hcl.InitialPos)
 }
+
+// ContentHash represents a hash of file content
+type ContentHash [sha256.Size]byte
+
+// HashContent returns a hash of the given file content, which can be
+// used to tell whether the content changed since it was last parsed.
+func HashContent(src []byte) ContentHash {
+	return sha256.Sum256(src)
+}
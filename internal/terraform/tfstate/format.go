@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+const sensitivePlaceholder = "(sensitive value)"
+
+// FormatValue renders the given value using HCL-like syntax,
+// similar to how Terraform shows values in `terraform console`.
+func FormatValue(value interface{}) string {
+	var sb strings.Builder
+	formatValue(&sb, value, 0)
+	return sb.String()
+}
+
+func formatValue(sb *strings.Builder, value interface{}, indent int) {
+	switch v := value.(type) {
+	case nil:
+		sb.WriteString("null")
+	case Sensitive:
+		sb.WriteString(sensitivePlaceholder)
+	case string:
+		sb.WriteString(strconv.Quote(v))
+	case bool:
+		fmt.Fprintf(sb, "%t", v)
+	case float64:
+		sb.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case json.Number:
+		sb.WriteString(v.String())
+	case []interface{}:
+		if len(v) == 0 {
+			sb.WriteString("[]")
+			return
+		}
+		sb.WriteString("[\n")
+		for _, elem := range v {
+			writeIndent(sb, indent+1)
+			formatValue(sb, elem, indent+1)
+			sb.WriteString(",\n")
+		}
+		writeIndent(sb, indent)
+		sb.WriteString("]")
+	case map[string]interface{}:
+		if len(v) == 0 {
+			sb.WriteString("{}")
+			return
+		}
+		keys := make([]string, 0, len(v))
+		maxLen := 0
+		for key := range v {
+			keys = append(keys, key)
+			if len(formatKey(key)) > maxLen {
+				maxLen = len(formatKey(key))
+			}
+		}
+		sort.Strings(keys)
+
+		sb.WriteString("{\n")
+		for _, key := range keys {
+			writeIndent(sb, indent+1)
+			k := formatKey(key)
+			sb.WriteString(k)
+			sb.WriteString(strings.Repeat(" ", maxLen-len(k)))
+			sb.WriteString(" = ")
+			formatValue(sb, v[key], indent+1)
+			sb.WriteString("\n")
+		}
+		writeIndent(sb, indent)
+		sb.WriteString("}")
+	default:
+		fmt.Fprintf(sb, "%v", v)
+	}
+}
+
+func formatKey(key string) string {
+	if hclsyntax.ValidIdentifier(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+func writeIndent(sb *strings.Builder, indent int) {
+	sb.WriteString(strings.Repeat("  ", indent))
+}
@@ -0,0 +1,251 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// Parse parses a state snapshot in any of the supported formats
+func Parse(src []byte) (*Snapshot, error) {
+	var probe struct {
+		Version       json.RawMessage `json:"version"`
+		FormatVersion string          `json:"format_version"`
+		PriorState    json.RawMessage `json:"prior_state"`
+		PlannedValues json.RawMessage `json:"planned_values"`
+	}
+	err := json.Unmarshal(src, &probe)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case probe.FormatVersion == "" && len(probe.Version) > 0:
+		return parseRawState(src)
+	case len(probe.PriorState) > 0 || len(probe.PlannedValues) > 0:
+		var plan tfjson.Plan
+		err := json.Unmarshal(src, &plan)
+		if err != nil {
+			return nil, err
+		}
+		// prior state reflects the current state, while planned
+		// values are the next best thing if there is no state yet
+		if plan.PriorState != nil && plan.PriorState.Values != nil {
+			return snapshotFromStateValues(plan.PriorState.Values), nil
+		}
+		return snapshotFromStateValues(plan.PlannedValues), nil
+	case probe.FormatVersion != "":
+		var state tfjson.State
+		err := json.Unmarshal(src, &state)
+		if err != nil {
+			return nil, err
+		}
+		return snapshotFromStateValues(state.Values), nil
+	}
+
+	return nil, fmt.Errorf("unknown state snapshot format")
+}
+
+func snapshotFromStateValues(values *tfjson.StateValues) *Snapshot {
+	snapshot := newSnapshot()
+	if values == nil || values.RootModule == nil {
+		return snapshot
+	}
+
+	addStateModule(snapshot, values.RootModule)
+	return snapshot
+}
+
+func addStateModule(snapshot *Snapshot, mod *tfjson.StateModule) {
+	modPrefix := ""
+	if mod.Address != "" {
+		modPrefix = mod.Address + "."
+	}
+
+	for _, r := range mod.Resources {
+		var sensitive interface{}
+		if len(r.SensitiveValues) > 0 {
+			// invalid marks are treated as no marks
+			_ = json.Unmarshal(r.SensitiveValues, &sensitive)
+		}
+
+		values := make(map[string]interface{}, len(r.AttributeValues))
+		for name, value := range r.AttributeValues {
+			values[name] = value
+		}
+
+		snapshot.addInstance(modPrefix+resourceAddr(string(r.Mode), r.Type, r.Name), Instance{
+			Key:    instanceKey(r.Index),
+			Values: redact(values, sensitive),
+		})
+	}
+
+	for _, childMod := range mod.ChildModules {
+		addStateModule(snapshot, childMod)
+	}
+}
+
+// rawState represents the (version 4) format of state
+// as persisted by backends, e.g. in terraform.tfstate
+type rawState struct {
+	Version   int           `json:"version"`
+	Resources []rawResource `json:"resources"`
+}
+
+type rawResource struct {
+	Module    string        `json:"module"`
+	Mode      string        `json:"mode"`
+	Type      string        `json:"type"`
+	Name      string        `json:"name"`
+	Instances []rawInstance `json:"instances"`
+}
+
+type rawInstance struct {
+	IndexKey            interface{}     `json:"index_key"`
+	Attributes          interface{}     `json:"attributes"`
+	SensitiveAttributes json.RawMessage `json:"sensitive_attributes"`
+}
+
+type rawPathStep struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+func parseRawState(src []byte) (*Snapshot, error) {
+	var state rawState
+	err := json.Unmarshal(src, &state)
+	if err != nil {
+		return nil, err
+	}
+	if state.Version != 4 {
+		return nil, fmt.Errorf("unsupported state version: %d", state.Version)
+	}
+
+	snapshot := newSnapshot()
+	for _, r := range state.Resources {
+		modPrefix := ""
+		if r.Module != "" {
+			modPrefix = r.Module + "."
+		}
+
+		for _, ri := range r.Instances {
+			values := ri.Attributes
+
+			var paths [][]rawPathStep
+			if len(ri.SensitiveAttributes) > 0 {
+				// invalid paths are treated as no sensitive attributes
+				_ = json.Unmarshal(ri.SensitiveAttributes, &paths)
+			}
+			for _, path := range paths {
+				values = redactPath(values, path)
+			}
+
+			snapshot.addInstance(modPrefix+resourceAddr(r.Mode, r.Type, r.Name), Instance{
+				Key:    instanceKey(ri.IndexKey),
+				Values: values,
+			})
+		}
+	}
+
+	return snapshot, nil
+}
+
+func resourceAddr(mode, rType, name string) string {
+	if mode == "data" {
+		return strings.Join([]string{"data", rType, name}, ".")
+	}
+	return rType + "." + name
+}
+
+func instanceKey(key interface{}) interface{} {
+	switch k := key.(type) {
+	case float64:
+		return int(k)
+	case string:
+		return k
+	}
+	return nil
+}
+
+// redact replaces any values marked as sensitive with [Sensitive],
+// where marks mirror the structure of the value, as in the JSON output
+// of Terraform (e.g. {"password": true})
+func redact(value interface{}, marks interface{}) interface{} {
+	switch m := marks.(type) {
+	case bool:
+		if m {
+			return Sensitive{}
+		}
+	case map[string]interface{}:
+		if v, ok := value.(map[string]interface{}); ok {
+			for key, elemMarks := range m {
+				if elem, ok := v[key]; ok {
+					v[key] = redact(elem, elemMarks)
+				}
+			}
+		}
+	case []interface{}:
+		if v, ok := value.([]interface{}); ok {
+			for i, elemMarks := range m {
+				if i < len(v) {
+					v[i] = redact(v[i], elemMarks)
+				}
+			}
+		}
+	}
+	return value
+}
+
+// redactPath replaces the value at the given path with [Sensitive]
+func redactPath(value interface{}, path []rawPathStep) interface{} {
+	if len(path) == 0 {
+		return Sensitive{}
+	}
+
+	step := path[0]
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var key string
+		switch step.Type {
+		case "get_attr":
+			err := json.Unmarshal(step.Value, &key)
+			if err != nil {
+				return value
+			}
+		case "index":
+			var idx struct {
+				Value string `json:"value"`
+			}
+			err := json.Unmarshal(step.Value, &idx)
+			if err != nil {
+				return value
+			}
+			key = idx.Value
+		default:
+			return value
+		}
+		if elem, ok := v[key]; ok {
+			v[key] = redactPath(elem, path[1:])
+		}
+	case []interface{}:
+		if step.Type != "index" {
+			return value
+		}
+		var idx struct {
+			Value int `json:"value"`
+		}
+		err := json.Unmarshal(step.Value, &idx)
+		if err != nil {
+			return value
+		}
+		if idx.Value >= 0 && idx.Value < len(v) {
+			v[idx.Value] = redactPath(v[idx.Value], path[1:])
+		}
+	}
+	return value
+}
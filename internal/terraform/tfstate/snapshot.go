@@ -0,0 +1,205 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package tfstate reads values of resources from local snapshots
+// of Terraform state, without making any remote calls.
+//
+// Three formats are supported:
+//   - raw state, as stored in terraform.tfstate by the local backend
+//   - output of `terraform show -json`
+//   - saved plan JSON, i.e. output of `terraform show -json <planfile>`
+package tfstate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Sensitive replaces any value marked as sensitive in the snapshot,
+// so that sensitive values are never exposed to the user.
+type Sensitive struct{}
+
+// Snapshot contains values of all resource instances
+// found in a state snapshot, with sensitive values redacted.
+type Snapshot struct {
+	// Resources is keyed by the address of the resource within the
+	// configuration, prefixed by the address of the module instance,
+	// e.g. aws_instance.web or module.vpc.aws_vpc.this
+	Resources map[string]*Resource
+}
+
+type Resource struct {
+	Instances []Instance
+}
+
+// Instance represents an instance of a resource
+type Instance struct {
+	// Key is the instance key when count (int) or for_each (string)
+	// is used, or nil for a single instance resource
+	Key interface{}
+
+	// Values is the redacted object of all attribute values
+	Values interface{}
+}
+
+func newSnapshot() *Snapshot {
+	return &Snapshot{
+		Resources: make(map[string]*Resource, 0),
+	}
+}
+
+func (s *Snapshot) addInstance(resourceAddr string, instance Instance) {
+	r, ok := s.Resources[resourceAddr]
+	if !ok {
+		r = &Resource{}
+		s.Resources[resourceAddr] = r
+	}
+	r.Instances = append(r.Instances, instance)
+}
+
+// Value resolves the given reference address (e.g. aws_instance.web,
+// aws_instance.web[0].id or module.vpc.aws_vpc.this.id) to a value.
+//
+// When a resource with multiple instances is referenced without an instance
+// key, the values of all instances are returned keyed by the instance key.
+func (s *Snapshot) Value(addr lang.Address) (interface{}, bool) {
+	steps := []lang.AddressStep(addr)
+
+	modAddr := ""
+	for len(steps) >= 2 && stepName(steps[0]) == "module" {
+		name := stepName(steps[1])
+		if name == "" {
+			return nil, false
+		}
+		modAddr += "module." + name
+		steps = steps[2:]
+		if len(steps) > 0 {
+			if idx, ok := steps[0].(lang.IndexStep); ok {
+				modAddr += idx.String()
+				steps = steps[1:]
+			}
+		}
+		modAddr += "."
+	}
+
+	resourceSteps := 2
+	if len(steps) > 0 && stepName(steps[0]) == "data" {
+		resourceSteps = 3
+	}
+	if len(steps) < resourceSteps {
+		return nil, false
+	}
+	names := make([]string, 0, resourceSteps)
+	for _, step := range steps[:resourceSteps] {
+		name := stepName(step)
+		if name == "" {
+			return nil, false
+		}
+		names = append(names, name)
+	}
+	steps = steps[resourceSteps:]
+
+	resource, ok := s.Resources[modAddr+strings.Join(names, ".")]
+	if !ok {
+		return nil, false
+	}
+
+	if len(steps) > 0 {
+		if idx, ok := steps[0].(lang.IndexStep); ok {
+			steps = steps[1:]
+			for _, instance := range resource.Instances {
+				if keyMatches(instance.Key, idx.Key) {
+					return traverse(instance.Values, steps)
+				}
+			}
+			return nil, false
+		}
+	}
+
+	if len(resource.Instances) == 1 && resource.Instances[0].Key == nil {
+		return traverse(resource.Instances[0].Values, steps)
+	}
+
+	// The reference is to all instances of the resource
+	values := make(map[string]interface{}, len(resource.Instances))
+	for _, instance := range resource.Instances {
+		v, ok := traverse(instance.Values, steps)
+		if !ok {
+			continue
+		}
+		values[fmt.Sprintf("%v", instance.Key)] = v
+	}
+	return values, len(values) > 0
+}
+
+func stepName(step lang.AddressStep) string {
+	switch s := step.(type) {
+	case lang.RootStep:
+		return s.Name
+	case lang.AttrStep:
+		return s.Name
+	}
+	return ""
+}
+
+func keyMatches(instanceKey interface{}, key cty.Value) bool {
+	if key.IsNull() || !key.IsKnown() {
+		return false
+	}
+	switch k := instanceKey.(type) {
+	case int:
+		if key.Type() != cty.Number {
+			return false
+		}
+		idx, _ := key.AsBigFloat().Int64()
+		return int64(k) == idx
+	case string:
+		return key.Type() == cty.String && key.AsString() == k
+	}
+	return false
+}
+
+func traverse(value interface{}, steps []lang.AddressStep) (interface{}, bool) {
+	if len(steps) == 0 {
+		return value, true
+	}
+
+	switch v := value.(type) {
+	case Sensitive:
+		// anything nested within a sensitive value is sensitive
+		return v, true
+	case map[string]interface{}:
+		var key string
+		switch s := steps[0].(type) {
+		case lang.AttrStep:
+			key = s.Name
+		case lang.IndexStep:
+			if s.Key.Type() != cty.String {
+				return nil, false
+			}
+			key = s.Key.AsString()
+		default:
+			return nil, false
+		}
+		elem, ok := v[key]
+		if !ok {
+			return nil, false
+		}
+		return traverse(elem, steps[1:])
+	case []interface{}:
+		s, ok := steps[0].(lang.IndexStep)
+		if !ok || s.Key.Type() != cty.Number {
+			return nil, false
+		}
+		idx, _ := s.Key.AsBigFloat().Int64()
+		if idx < 0 || int(idx) >= len(v) {
+			return nil, false
+		}
+		return traverse(v[idx], steps[1:])
+	}
+
+	return nil, false
+}
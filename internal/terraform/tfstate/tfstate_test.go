@@ -0,0 +1,333 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfstate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var rawStateSnapshot = `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "serial": 3,
+  "lineage": "e2d1b6e4-0000-0000-0000-000000000000",
+  "outputs": {},
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "web",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {
+          "index_key": 0,
+          "schema_version": 1,
+          "attributes": {"id": "i-0", "tags": {"Name": "web-0"}}
+        },
+        {
+          "index_key": 1,
+          "schema_version": 1,
+          "attributes": {"id": "i-1", "tags": {"Name": "web-1"}}
+        }
+      ]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "db",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {
+          "schema_version": 0,
+          "attributes": {"id": "db-1", "password": "hunter2", "port": 5432},
+          "sensitive_attributes": [[{"type": "get_attr", "value": "password"}]]
+        }
+      ]
+    },
+    {
+      "module": "module.vpc",
+      "mode": "managed",
+      "type": "aws_vpc",
+      "name": "this",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {
+          "index_key": "main",
+          "schema_version": 1,
+          "attributes": {"id": "vpc-1", "cidr_block": "10.0.0.0/16"}
+        }
+      ]
+    },
+    {
+      "mode": "data",
+      "type": "aws_ami",
+      "name": "ubuntu",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {
+          "schema_version": 0,
+          "attributes": {"id": "ami-1"}
+        }
+      ]
+    }
+  ]
+}`
+
+var showJsonSnapshot = `{
+  "format_version": "1.0",
+  "terraform_version": "1.9.0",
+  "values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_db_instance.db",
+          "mode": "managed",
+          "type": "aws_db_instance",
+          "name": "db",
+          "provider_name": "registry.terraform.io/hashicorp/aws",
+          "schema_version": 0,
+          "values": {"id": "db-1", "password": "hunter2", "port": 5432},
+          "sensitive_values": {"password": true}
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.vpc",
+          "resources": [
+            {
+              "address": "module.vpc.aws_vpc.this[\"main\"]",
+              "mode": "managed",
+              "type": "aws_vpc",
+              "name": "this",
+              "index": "main",
+              "provider_name": "registry.terraform.io/hashicorp/aws",
+              "schema_version": 1,
+              "values": {"id": "vpc-1", "cidr_block": "10.0.0.0/16"},
+              "sensitive_values": {}
+            }
+          ]
+        }
+      ]
+    }
+  }
+}`
+
+var planJsonSnapshot = `{
+  "format_version": "1.2",
+  "terraform_version": "1.9.0",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_instance.web[0]",
+          "mode": "managed",
+          "type": "aws_instance",
+          "name": "web",
+          "index": 0,
+          "provider_name": "registry.terraform.io/hashicorp/aws",
+          "schema_version": 1,
+          "values": {"id": "i-planned"},
+          "sensitive_values": {}
+        }
+      ]
+    }
+  },
+  "prior_state": {
+    "format_version": "1.0",
+    "terraform_version": "1.9.0",
+    "values": {
+      "root_module": {
+        "resources": [
+          {
+            "address": "aws_instance.web[0]",
+            "mode": "managed",
+            "type": "aws_instance",
+            "name": "web",
+            "index": 0,
+            "provider_name": "registry.terraform.io/hashicorp/aws",
+            "schema_version": 1,
+            "values": {"id": "i-0"},
+            "sensitive_values": {}
+          }
+        ]
+      }
+    }
+  }
+}`
+
+func TestSnapshot_Value(t *testing.T) {
+	testCases := []struct {
+		snapshot      string
+		addr          lang.Address
+		expectedFound bool
+		expectedValue string
+	}{
+		{
+			rawStateSnapshot,
+			lang.Address{
+				lang.RootStep{Name: "aws_instance"},
+				lang.AttrStep{Name: "web"},
+				lang.IndexStep{Key: cty.NumberIntVal(1)},
+				lang.AttrStep{Name: "id"},
+			},
+			true,
+			`"i-1"`,
+		},
+		{
+			rawStateSnapshot,
+			lang.Address{
+				lang.RootStep{Name: "aws_instance"},
+				lang.AttrStep{Name: "web"},
+			},
+			true,
+			`{
+  "0" = {
+    id   = "i-0"
+    tags = {
+      Name = "web-0"
+    }
+  }
+  "1" = {
+    id   = "i-1"
+    tags = {
+      Name = "web-1"
+    }
+  }
+}`,
+		},
+		{
+			rawStateSnapshot,
+			lang.Address{
+				lang.RootStep{Name: "aws_instance"},
+				lang.AttrStep{Name: "web"},
+				lang.IndexStep{Key: cty.NumberIntVal(2)},
+			},
+			false,
+			``,
+		},
+		{
+			rawStateSnapshot,
+			lang.Address{
+				lang.RootStep{Name: "aws_db_instance"},
+				lang.AttrStep{Name: "db"},
+			},
+			true,
+			`{
+  id       = "db-1"
+  password = (sensitive value)
+  port     = 5432
+}`,
+		},
+		{
+			rawStateSnapshot,
+			lang.Address{
+				lang.RootStep{Name: "module"},
+				lang.AttrStep{Name: "vpc"},
+				lang.AttrStep{Name: "aws_vpc"},
+				lang.AttrStep{Name: "this"},
+				lang.IndexStep{Key: cty.StringVal("main")},
+				lang.AttrStep{Name: "cidr_block"},
+			},
+			true,
+			`"10.0.0.0/16"`,
+		},
+		{
+			rawStateSnapshot,
+			lang.Address{
+				lang.RootStep{Name: "data"},
+				lang.AttrStep{Name: "aws_ami"},
+				lang.AttrStep{Name: "ubuntu"},
+				lang.AttrStep{Name: "id"},
+			},
+			true,
+			`"ami-1"`,
+		},
+		{
+			rawStateSnapshot,
+			lang.Address{
+				lang.RootStep{Name: "var"},
+				lang.AttrStep{Name: "foo"},
+			},
+			false,
+			``,
+		},
+		{
+			showJsonSnapshot,
+			lang.Address{
+				lang.RootStep{Name: "aws_db_instance"},
+				lang.AttrStep{Name: "db"},
+				lang.AttrStep{Name: "password"},
+			},
+			true,
+			`(sensitive value)`,
+		},
+		{
+			showJsonSnapshot,
+			lang.Address{
+				lang.RootStep{Name: "module"},
+				lang.AttrStep{Name: "vpc"},
+				lang.AttrStep{Name: "aws_vpc"},
+				lang.AttrStep{Name: "this"},
+				lang.IndexStep{Key: cty.StringVal("main")},
+				lang.AttrStep{Name: "id"},
+			},
+			true,
+			`"vpc-1"`,
+		},
+		{
+			planJsonSnapshot,
+			lang.Address{
+				lang.RootStep{Name: "aws_instance"},
+				lang.AttrStep{Name: "web"},
+				lang.IndexStep{Key: cty.NumberIntVal(0)},
+				lang.AttrStep{Name: "id"},
+			},
+			true,
+			`"i-0"`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.addr), func(t *testing.T) {
+			snapshot, err := Parse([]byte(tc.snapshot))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			value, found := snapshot.Value(tc.addr)
+			if found != tc.expectedFound {
+				t.Fatalf("expected found: %t, given: %t", tc.expectedFound, found)
+			}
+			if !found {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expectedValue, FormatValue(value)); diff != "" {
+				t.Fatalf("unexpected value: %s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	testCases := []string{
+		`not json`,
+		`{"version": 3, "modules": []}`,
+		`{"foo": "bar"}`,
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			_, err := Parse([]byte(tc))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
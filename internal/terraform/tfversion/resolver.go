@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfversion
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+)
+
+// Resolver looks up pinned versions and caches them per directory,
+// so that the filesystem isn't walked on every request.
+type Resolver struct {
+	fs FS
+
+	mu     sync.RWMutex
+	pinned map[string]pinnedVersion
+}
+
+type pinnedVersion struct {
+	version *version.Version
+	source  Source
+	err     error
+}
+
+func NewResolver(fs FS) *Resolver {
+	return &Resolver{
+		fs:     fs,
+		pinned: make(map[string]pinnedVersion, 0),
+	}
+}
+
+// PinnedVersion returns the version pinned for the given directory
+// (see [FindPinnedVersion]).
+func (r *Resolver) PinnedVersion(dirPath string) (*version.Version, Source, error) {
+	dirPath = filepath.Clean(dirPath)
+
+	r.mu.RLock()
+	pv, ok := r.pinned[dirPath]
+	r.mu.RUnlock()
+	if ok {
+		return pv.version, pv.source, pv.err
+	}
+
+	v, source, err := FindPinnedVersion(r.fs, dirPath)
+
+	r.mu.Lock()
+	r.pinned[dirPath] = pinnedVersion{
+		version: v,
+		source:  source,
+		err:     err,
+	}
+	r.mu.Unlock()
+
+	return v, source, err
+}
+
+// Invalidate forgets pinned versions of the given directory and
+// any directories within it, e.g. when a version file changes there.
+func (r *Resolver) Invalidate(dirPath string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for path := range r.pinned {
+		if IsAffectedBy(path, dirPath) {
+			delete(r.pinned, path)
+		}
+	}
+}
+
+// IsAffectedBy reports whether the version pinned for path
+// may be affected by a version file in dirPath, i.e. whether
+// path is dirPath or any directory within it.
+func IsAffectedBy(path, dirPath string) bool {
+	path = filepath.Clean(path)
+	dirPath = filepath.Clean(dirPath)
+	if path == dirPath {
+		return true
+	}
+	if !strings.HasSuffix(dirPath, string(filepath.Separator)) {
+		dirPath += string(filepath.Separator)
+	}
+	return strings.HasPrefix(path, dirPath)
+}
@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package tfversion resolves the Terraform version which
+// a directory targets, so that the right core schema and functions
+// can be used for it.
+package tfversion
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-ls/internal/terraform/datadir"
+	tfschema "github.com/hashicorp/terraform-schema/schema"
+)
+
+const (
+	// TerraformVersionFile is the file used by tfenv (and compatible tools)
+	// to pin the Terraform version
+	TerraformVersionFile = ".terraform-version"
+	// ToolVersionsFile is the file used by asdf to pin versions of tools
+	ToolVersionsFile = ".tool-versions"
+)
+
+type FS interface {
+	ReadFile(name string) ([]byte, error)
+}
+
+// Source describes where a version was resolved from
+type Source int
+
+const (
+	SourceNone Source = iota
+	SourceTerraformVersionFile
+	SourceToolVersionsFile
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceTerraformVersionFile:
+		return TerraformVersionFile
+	case SourceToolVersionsFile:
+		return ToolVersionsFile
+	}
+	return "none"
+}
+
+// IsVersionFile reports whether the given path is a file
+// which may pin the Terraform version
+func IsVersionFile(path string) bool {
+	name := filepath.Base(path)
+	return name == TerraformVersionFile || name == ToolVersionsFile
+}
+
+// PathGlobPatternsForWatching returns patterns of all files
+// which may pin the Terraform version
+func PathGlobPatternsForWatching() []datadir.WatchPattern {
+	return []datadir.WatchPattern{
+		{
+			Pattern:   "**/" + TerraformVersionFile,
+			EventType: datadir.AnyEventType,
+		},
+		{
+			Pattern:   "**/" + ToolVersionsFile,
+			EventType: datadir.AnyEventType,
+		},
+	}
+}
+
+// FindPinnedVersion looks for the Terraform version pinned via
+// .terraform-version in dirPath or any of its parent directories,
+// and then via .tool-versions in the same directories.
+//
+// It returns nil and SourceNone if no version is pinned.
+func FindPinnedVersion(fs FS, dirPath string) (*version.Version, Source, error) {
+	dirs := parentDirs(dirPath)
+
+	for _, dir := range dirs {
+		src, err := fs.ReadFile(filepath.Join(dir, TerraformVersionFile))
+		if err != nil {
+			continue
+		}
+		v, err := parseTerraformVersionFile(src)
+		if err != nil {
+			return nil, SourceTerraformVersionFile, fmt.Errorf("%s: %w",
+				filepath.Join(dir, TerraformVersionFile), err)
+		}
+		return v, SourceTerraformVersionFile, nil
+	}
+
+	for _, dir := range dirs {
+		src, err := fs.ReadFile(filepath.Join(dir, ToolVersionsFile))
+		if err != nil {
+			continue
+		}
+		v, ok, err := parseToolVersionsFile(src)
+		if err != nil {
+			return nil, SourceToolVersionsFile, fmt.Errorf("%s: %w",
+				filepath.Join(dir, ToolVersionsFile), err)
+		}
+		if !ok {
+			// asdf keeps looking up the tree if the tool isn't listed
+			continue
+		}
+		return v, SourceToolVersionsFile, nil
+	}
+
+	return nil, SourceNone, nil
+}
+
+// Resolve picks the version of Terraform which core schema
+// and functions should be served for, in the following order:
+//
+//  1. pinned version (see [FindPinnedVersion])
+//  2. latest known release matching the required_version constraint
+//  3. installed version
+//
+// The returned version is always one we have schema for.
+func Resolve(pinned *version.Version, constraints version.Constraints, installed *version.Version) *version.Version {
+	if pinned != nil {
+		return tfschema.ResolveVersion(pinned, nil)
+	}
+	if len(constraints) > 0 {
+		return tfschema.ResolveVersion(nil, constraints)
+	}
+	return tfschema.ResolveVersion(installed, nil)
+}
+
+func parentDirs(dirPath string) []string {
+	dirs := make([]string, 0)
+	dir := filepath.Clean(dirPath)
+	for {
+		dirs = append(dirs, dir)
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return dirs
+}
+
+func parseTerraformVersionFile(src []byte) (*version.Version, error) {
+	rawVersion := strings.TrimSpace(string(src))
+	// tfenv supports keywords such as latest or min-required
+	// which we cannot resolve without the registry
+	return version.NewVersion(strings.TrimPrefix(rawVersion, "v"))
+}
+
+func parseToolVersionsFile(src []byte) (*version.Version, bool, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "terraform" {
+			continue
+		}
+
+		// Any further fields are fallback versions,
+		// so we only care about the first one
+		v, err := version.NewVersion(fields[1])
+		if err != nil {
+			return nil, false, err
+		}
+		return v, true, nil
+	}
+
+	return nil, false, scanner.Err()
+}
@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfversion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+type osFs struct{}
+
+func (osFs) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func TestFindPinnedVersion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		files           map[string]string
+		dir             string
+		expectedVersion string
+		expectedSource  Source
+	}{
+		{
+			"no files",
+			map[string]string{},
+			"a/b",
+			"",
+			SourceNone,
+		},
+		{
+			"terraform-version in module dir",
+			map[string]string{
+				"a/b/.terraform-version": "1.5.7\n",
+			},
+			"a/b",
+			"1.5.7",
+			SourceTerraformVersionFile,
+		},
+		{
+			"terraform-version in parent dir",
+			map[string]string{
+				"a/.terraform-version": "v1.7.0",
+			},
+			"a/b",
+			"1.7.0",
+			SourceTerraformVersionFile,
+		},
+		{
+			"terraform-version preferred over tool-versions",
+			map[string]string{
+				"a/.terraform-version": "1.7.0",
+				"a/b/.tool-versions":   "terraform 1.9.0\n",
+			},
+			"a/b",
+			"1.7.0",
+			SourceTerraformVersionFile,
+		},
+		{
+			"tool-versions with other tools and comments",
+			map[string]string{
+				"a/b/.tool-versions": "# tools\nnodejs 20.0.0\nterraform 1.9.2 1.9.1 # pinned\n",
+			},
+			"a/b",
+			"1.9.2",
+			SourceToolVersionsFile,
+		},
+		{
+			"tool-versions without terraform",
+			map[string]string{
+				"a/b/.tool-versions": "nodejs 20.0.0\n",
+				"a/.tool-versions":   "terraform 1.6.0\n",
+			},
+			"a/b",
+			"1.6.0",
+			SourceToolVersionsFile,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			err := os.MkdirAll(filepath.Join(root, "a", "b"), 0o755)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range tc.files {
+				err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			v, source, err := FindPinnedVersion(osFs{}, filepath.Join(root, tc.dir))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if source != tc.expectedSource {
+				t.Fatalf("expected source %s, given: %s", tc.expectedSource, source)
+			}
+			if tc.expectedVersion == "" {
+				if v != nil {
+					t.Fatalf("expected no version, given: %s", v)
+				}
+				return
+			}
+			if v == nil || !v.Equal(version.Must(version.NewVersion(tc.expectedVersion))) {
+				t.Fatalf("expected version %s, given: %s", tc.expectedVersion, v)
+			}
+		})
+	}
+}
+
+func TestFindPinnedVersion_invalid(t *testing.T) {
+	root := t.TempDir()
+	err := os.WriteFile(filepath.Join(root, TerraformVersionFile), []byte("latest"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, source, err := FindPinnedVersion(osFs{}, root)
+	if err == nil {
+		t.Fatal("expected error for unsupported version")
+	}
+	if source != SourceTerraformVersionFile {
+		t.Fatalf("expected source %s, given: %s", SourceTerraformVersionFile, source)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	v1_5 := version.Must(version.NewVersion("1.5.0"))
+	v1_7 := version.Must(version.NewVersion("1.7.0"))
+	cons := version.MustConstraints(version.NewConstraint("~> 1.5.0"))
+
+	// pinned version wins over constraint and installed version
+	if v := Resolve(v1_7, cons, v1_5); !v.Equal(v1_7) {
+		t.Fatalf("expected pinned version %s, given: %s", v1_7, v)
+	}
+
+	// known release matching the constraint wins over installed version
+	if v := Resolve(nil, cons, v1_7); !cons.Check(v) {
+		t.Fatalf("expected version matching %s, given: %s", cons, v)
+	}
+
+	// installed version is used without any constraint
+	if v := Resolve(nil, nil, v1_5); !v.Equal(v1_5) {
+		t.Fatalf("expected installed version %s, given: %s", v1_5, v)
+	}
+}
+
+func TestResolver_Invalidate(t *testing.T) {
+	root := t.TempDir()
+	subDir := filepath.Join(root, "sub")
+	err := os.MkdirAll(subDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewResolver(osFs{})
+	v, _, err := r.PinnedVersion(subDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != nil {
+		t.Fatalf("expected no version, given: %s", v)
+	}
+
+	err = os.WriteFile(filepath.Join(root, TerraformVersionFile), []byte("1.6.0"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// cached until invalidated
+	v, _, _ = r.PinnedVersion(subDir)
+	if v != nil {
+		t.Fatalf("expected cached empty version, given: %s", v)
+	}
+
+	r.Invalidate(root)
+	v, source, err := r.PinnedVersion(subDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v == nil || v.String() != "1.6.0" || source != SourceTerraformVersionFile {
+		t.Fatalf("expected version 1.6.0 from %s, given: %s (%s)", TerraformVersionFile, v, source)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package toolchain finds versioned Terraform binaries in a local
+// directory, such as one managed by tfenv or tfswitch, so that each
+// module can be served by the binary matching its version requirements.
+package toolchain
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// Binary represents a Terraform binary of a known version
+type Binary struct {
+	Version *version.Version
+	Path    string
+}
+
+// FindBinaries returns all Terraform binaries found in dir,
+// sorted from the newest version to the oldest.
+//
+// Two layouts are recognized:
+//
+//   - <dir>/<version>/terraform, as installed by tfenv
+//     (i.e. ~/.tfenv/versions)
+//   - <dir>/terraform_<version>, as installed by tfswitch
+//     (i.e. ~/.terraform.versions)
+func FindBinaries(dir string) ([]Binary, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	binaries := make([]Binary, 0)
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() {
+			v, err := version.NewVersion(strings.TrimPrefix(name, "v"))
+			if err != nil {
+				continue
+			}
+			path := filepath.Join(dir, name, binaryName("terraform"))
+			if !isExecutableFile(path) {
+				continue
+			}
+			binaries = append(binaries, Binary{Version: v, Path: path})
+			continue
+		}
+
+		rawVersion, ok := strings.CutPrefix(strings.TrimSuffix(name, ".exe"), "terraform_")
+		if !ok {
+			continue
+		}
+		v, err := version.NewVersion(rawVersion)
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if !isExecutableFile(path) {
+			continue
+		}
+		binaries = append(binaries, Binary{Version: v, Path: path})
+	}
+
+	sort.SliceStable(binaries, func(i, j int) bool {
+		return binaries[i].Version.GreaterThan(binaries[j].Version)
+	})
+
+	return binaries, nil
+}
+
+// SelectBinary picks the binary for a module from binaries
+// sorted by [FindBinaries].
+//
+// A pinned version (e.g. via .terraform-version) must match exactly.
+// Otherwise the newest binary satisfying constraints is picked.
+func SelectBinary(binaries []Binary, pinned *version.Version, constraints version.Constraints) (Binary, bool) {
+	for _, b := range binaries {
+		if pinned != nil {
+			if b.Version.Equal(pinned) {
+				return b, true
+			}
+			continue
+		}
+		if len(constraints) == 0 || constraints.Check(b.Version) {
+			return b, true
+		}
+	}
+
+	return Binary{}, false
+}
+
+func binaryName(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".exe"
+	}
+	return name
+}
+
+func isExecutableFile(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if fi.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return fi.Mode().Perm()&0o111 != 0
+}
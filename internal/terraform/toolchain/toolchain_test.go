@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package toolchain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+)
+
+func TestFindBinaries(t *testing.T) {
+	dir := t.TempDir()
+
+	// tfenv layout
+	writeBinary(t, filepath.Join(dir, "1.5.7", binaryName("terraform")))
+	writeBinary(t, filepath.Join(dir, "1.9.0", binaryName("terraform")))
+	// tfswitch layout
+	writeBinary(t, filepath.Join(dir, binaryName("terraform_1.7.5")))
+	// ignored entries
+	writeBinary(t, filepath.Join(dir, "latest", binaryName("terraform")))
+	writeBinary(t, filepath.Join(dir, binaryName("tflint_0.50.0")))
+	err := os.MkdirAll(filepath.Join(dir, "1.8.0"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binaries, err := FindBinaries(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths := []string{
+		filepath.Join(dir, "1.9.0", binaryName("terraform")),
+		filepath.Join(dir, binaryName("terraform_1.7.5")),
+		filepath.Join(dir, "1.5.7", binaryName("terraform")),
+	}
+	paths := make([]string, 0, len(binaries))
+	for _, b := range binaries {
+		paths = append(paths, b.Path)
+	}
+	if diff := cmp.Diff(expectedPaths, paths); diff != "" {
+		t.Fatalf("unexpected binaries: %s", diff)
+	}
+}
+
+func TestSelectBinary(t *testing.T) {
+	binaries := []Binary{
+		{Version: version.Must(version.NewVersion("1.9.0")), Path: "1.9.0"},
+		{Version: version.Must(version.NewVersion("1.7.5")), Path: "1.7.5"},
+		{Version: version.Must(version.NewVersion("1.5.7")), Path: "1.5.7"},
+	}
+
+	testCases := []struct {
+		name         string
+		pinned       string
+		constraints  string
+		expectedPath string
+	}{
+		{"no requirements", "", "", "1.9.0"},
+		{"constraint", "", "~> 1.7.0", "1.7.5"},
+		{"upper bound", "", ">= 1.5.0, < 1.8.0", "1.7.5"},
+		{"pinned", "1.5.7", ">= 1.7.0", "1.5.7"},
+		{"unsatisfiable constraint", "", ">= 2.0.0", ""},
+		{"pinned but not installed", "1.6.0", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pinned *version.Version
+			if tc.pinned != "" {
+				pinned = version.Must(version.NewVersion(tc.pinned))
+			}
+			var constraints version.Constraints
+			if tc.constraints != "" {
+				constraints = version.MustConstraints(version.NewConstraint(tc.constraints))
+			}
+
+			b, ok := SelectBinary(binaries, pinned, constraints)
+			if tc.expectedPath == "" {
+				if ok {
+					t.Fatalf("expected no binary, given: %q", b.Path)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("expected %q, given no binary", tc.expectedPath)
+			}
+			if b.Path != tc.expectedPath {
+				t.Fatalf("expected %q, given: %q", tc.expectedPath, b.Path)
+			}
+		})
+	}
+}
+
+func writeBinary(t *testing.T, path string) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, []byte{}, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
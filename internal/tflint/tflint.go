@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package tflint runs tflint for modules and decodes
+// the issues it reports in its JSON format.
+package tflint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ConfigFileName is the name of the tflint config file,
+// which tflint reads from the working directory
+const ConfigFileName = ".tflint.hcl"
+
+var defaultExecTimeout = 30 * time.Second
+
+const tracerName = "github.com/hashicorp/terraform-ls/internal/tflint"
+
+// Linter lints a module
+type Linter interface {
+	Lint(ctx context.Context, modPath string) (*Output, error)
+}
+
+// Executor runs the tflint binary at the given path
+type Executor struct {
+	execPath string
+	timeout  time.Duration
+	logger   *log.Logger
+}
+
+func NewExecutor(execPath string) *Executor {
+	return &Executor{
+		execPath: execPath,
+		timeout:  defaultExecTimeout,
+		logger:   log.New(io.Discard, "", 0),
+	}
+}
+
+// LookPath finds the tflint binary in PATH
+func LookPath() (string, error) {
+	return exec.LookPath("tflint")
+}
+
+func (e *Executor) SetLogger(logger *log.Logger) {
+	e.logger = logger
+}
+
+func (e *Executor) SetTimeout(duration time.Duration) {
+	e.timeout = duration
+}
+
+// Lint runs `tflint --format=json` in the given module directory,
+// such that tflint picks up any .tflint.hcl config file there
+func (e *Executor) Lint(ctx context.Context, modPath string) (*Output, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "tflint:Lint")
+	defer span.End()
+
+	cmd := exec.CommandContext(ctx, e.execPath, "--format=json", "--no-color")
+	cmd.Dir = modPath
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	e.logger.Printf("running tflint in %q", modPath)
+	runErr := cmd.Run()
+	if ctxErr := ctx.Err(); errors.Is(ctxErr, context.DeadlineExceeded) {
+		span.SetStatus(codes.Error, "execution timed out")
+		return nil, fmt.Errorf("tflint timed out after %s", e.timeout)
+	}
+
+	// tflint exits with a non-zero code when it finds issues,
+	// so we only treat it as failure if there is no output
+	output, err := ParseOutput(stdout.Bytes())
+	if err != nil {
+		span.SetStatus(codes.Error, "execution failed")
+		if runErr != nil {
+			return nil, fmt.Errorf("tflint failed: %w: %s", runErr, strings.TrimSpace(stderr.String()))
+		}
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "execution successful")
+	return output, nil
+}
+
+// Output represents the JSON output of tflint
+type Output struct {
+	Issues []Issue `json:"issues"`
+	Errors []Error `json:"errors"`
+}
+
+type Issue struct {
+	Rule    Rule   `json:"rule"`
+	Message string `json:"message"`
+	Range   Range  `json:"range"`
+}
+
+type Rule struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+	Link     string `json:"link"`
+}
+
+// Error is an error tflint ran into, e.g. an invalid config file
+type Error struct {
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	Range    *Range `json:"range,omitempty"`
+}
+
+type Range struct {
+	Filename string `json:"filename"`
+	Start    Pos    `json:"start"`
+	End      Pos    `json:"end"`
+}
+
+type Pos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func ParseOutput(b []byte) (*Output, error) {
+	var output Output
+	err := json.Unmarshal(b, &output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode tflint output: %w", err)
+	}
+	return &output, nil
+}
@@ -11,6 +11,7 @@ import (
 	"io/fs"
 	"log"
 	"path/filepath"
+	"sync"
 
 	"github.com/hashicorp/terraform-ls/internal/document"
 	"github.com/hashicorp/terraform-ls/internal/eventbus"
@@ -47,6 +48,7 @@ type Walker struct {
 
 	cancelFunc context.CancelFunc
 
+	ignoreMu              sync.RWMutex
 	ignoredPaths          map[string]bool
 	ignoredDirectoryNames map[string]bool
 }
@@ -63,7 +65,7 @@ func NewWalker(fs fs.ReadDirFS, pathStore PathStore, eventBus *eventbus.EventBus
 		fs:                    fs,
 		pathStore:             pathStore,
 		logger:                discardLogger,
-		ignoredDirectoryNames: skipDirNames,
+		ignoredDirectoryNames: copyDirNames(skipDirNames),
 		eventBus:              eventBus,
 	}
 }
@@ -72,22 +74,39 @@ func (w *Walker) SetLogger(logger *log.Logger) {
 	w.logger = logger
 }
 
+// SetIgnoredPaths replaces the list of ignored paths.
+// It can be called after walking started, e.g. when settings are reloaded.
 func (w *Walker) SetIgnoredPaths(ignoredPaths []string) {
+	w.ignoreMu.Lock()
+	defer w.ignoreMu.Unlock()
+
 	w.ignoredPaths = make(map[string]bool)
 	for _, path := range ignoredPaths {
 		w.ignoredPaths[path] = true
 	}
 }
 
+// SetIgnoredDirectoryNames replaces the list of ignored directory
+// names, in addition to the names which are always skipped.
+// It can be called after walking started, e.g. when settings are reloaded.
 func (w *Walker) SetIgnoredDirectoryNames(ignoredDirectoryNames []string) {
-	if w.cancelFunc != nil {
-		panic("cannot set ignorelist after walking started")
-	}
+	w.ignoreMu.Lock()
+	defer w.ignoreMu.Unlock()
+
+	w.ignoredDirectoryNames = copyDirNames(skipDirNames)
 	for _, path := range ignoredDirectoryNames {
 		w.ignoredDirectoryNames[path] = true
 	}
 }
 
+func copyDirNames(names map[string]bool) map[string]bool {
+	m := make(map[string]bool, len(names))
+	for name := range names {
+		m[name] = true
+	}
+	return m
+}
+
 func (w *Walker) Stop() {
 	if w.cancelFunc != nil {
 		w.cancelFunc()
@@ -162,12 +181,23 @@ func (w *Walker) collectError(err error) {
 }
 
 func (w *Walker) isSkippableDir(dirName string) bool {
+	w.ignoreMu.RLock()
+	defer w.ignoreMu.RUnlock()
+
 	_, ok := w.ignoredDirectoryNames[dirName]
 	return ok
 }
 
+func (w *Walker) isIgnoredPath(path string) bool {
+	w.ignoreMu.RLock()
+	defer w.ignoreMu.RUnlock()
+
+	_, ok := w.ignoredPaths[path]
+	return ok
+}
+
 func (w *Walker) walk(ctx context.Context, dir document.DirHandle) error {
-	if _, ok := w.ignoredPaths[dir.Path()]; ok {
+	if w.isIgnoredPath(dir.Path()) {
 		w.logger.Printf("skipping walk due to dir being excluded: %s", dir.Path())
 		return nil
 	}